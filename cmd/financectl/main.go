@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/database"
+	"finance-backend/pkg/logger"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gorm.io/gorm"
+)
+
+const usage = `financectl is the admin CLI for the finance backend.
+
+Usage:
+  financectl [-config file] <command> [arguments]
+
+Commands:
+  migrate up|down|status|reset       apply, roll back or inspect migrations
+  migrate create <name>              create a new SQL migration file
+  seed                               load demo users, wallets, budgets and transactions
+  user create -email -name -password create a user
+  user disable -email                disable a user and revoke their sessions
+  user reset-password -email -password
+                                     set a new password and revoke sessions
+  sessions purge-expired             delete expired sessions
+  snapshots capture                  record every wallet's balance as the close of yesterday
+  snapshots rebuild -from YYYY-MM-DD replay transactions to fill in daily balances since a date
+  rates set -currency -rate [-date YYYY-MM-DD]
+                                     set the value of a currency in the base currency
+`
+
+// errUsage signals that the arguments were invalid and usage should be printed
+var errUsage = errors.New("invalid usage")
+
+// app holds what every command needs, the database is opened lazily
+type app struct {
+	config *config.Config
+	db     *gorm.DB
+}
+
+func main() {
+	flags := flag.NewFlagSet("financectl", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "path to an optional YAML config file")
+	_ = flags.Parse(os.Args[1:])
+
+	args := flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(config.Options{
+		File:    *configFile,
+		EnvFile: ".env",
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load configuration:", err)
+		os.Exit(1)
+	}
+
+	logger.InitLogger(cfg.Log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	a := &app{config: cfg}
+	defer a.close()
+
+	if err := a.run(ctx, args); err != nil {
+		if errors.Is(err, errUsage) {
+			flags.Usage()
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		a.close()
+		os.Exit(1)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	command, rest := args[0], args[1:]
+
+	switch command {
+	case "migrate":
+		return a.migrate(ctx, rest)
+	case "seed":
+		return a.seed(ctx, rest)
+	case "user":
+		return a.user(ctx, rest)
+	case "sessions":
+		return a.sessions(ctx, rest)
+	case "snapshots":
+		return a.snapshots(ctx, rest)
+	case "rates":
+		return a.rates(ctx, rest)
+	}
+
+	return errUsage
+}
+
+// database opens the connection on first use
+func (a *app) database() (*gorm.DB, error) {
+	if a.db != nil {
+		return a.db, nil
+	}
+
+	db, err := database.NewConnection(a.config.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	a.db = db
+	return db, nil
+}
+
+func (a *app) close() {
+	if a.db == nil {
+		return
+	}
+
+	if sqlDB, err := database.GetSQLDB(a.db); err == nil {
+		sqlDB.Close()
+	}
+	a.db = nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"finance-backend/pkg/database"
+	"finance-backend/pkg/migration"
+	"fmt"
+)
+
+func (a *app) migrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	dir := a.config.Migration.Dir
+
+	// create only writes a file and needs no database
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return errUsage
+		}
+
+		return migration.MigrateCreate(dir, args[1])
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	db, err := a.database()
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := database.GetSQLDB(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		if err := migration.MigrateUp(sqlDB, dir); err != nil {
+			return err
+		}
+	case "down":
+		if err := migration.MigrateDown(sqlDB, dir); err != nil {
+			return err
+		}
+	case "status":
+		return migration.MigrateStatus(sqlDB, dir)
+	case "reset":
+		if err := migration.MigrateReset(sqlDB, dir); err != nil {
+			return err
+		}
+	default:
+		return errUsage
+	}
+
+	current, latest, err := migration.Versions(ctx, sqlDB, dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Database at version %d (latest %d)\n", current, latest)
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/model"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"fmt"
+	"time"
+)
+
+const (
+	demoEmail    = "demo@example.com"
+	demoPassword = "demo-password"
+	demoDays     = 90
+)
+
+func (a *app) seed(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	db, err := a.database()
+	if err != nil {
+		return err
+	}
+
+	userRepository := repository.NewUserRepository(db)
+	sessionRepository := repository.NewSessionRepository(db)
+	walletRepository := repository.NewWalletRepository(db)
+	budgetRepository := repository.NewBudgetRepository(db)
+	transactionRepository := repository.NewTransactionRepository(db)
+
+	txManager := repository.NewTxManager(db)
+
+	userService := service.NewUserService(txManager, userRepository, sessionRepository)
+	walletService := service.NewWalletService(txManager, walletRepository)
+	budgetService := service.NewBudgetService(txManager, budgetRepository)
+	transactionService := service.NewTransactionService(txManager, transactionRepository, walletRepository, budgetRepository)
+
+	user, err := userService.Create(ctx, "Demo User", demoEmail, demoPassword)
+	if err != nil {
+		if errors.Is(err, apperror.ErrUserAlreadyExists) {
+			fmt.Printf("Demo user %s already exists, nothing to seed\n", demoEmail)
+			return nil
+		}
+		return err
+	}
+	userId := user.ID.String()
+
+	checking, err := walletService.Create(ctx, userId, &model.CreateWalletRequest{
+		Name: "Checking", Type: "personal", Currency: "USD", Balance: 2500,
+	})
+	if err != nil {
+		return err
+	}
+
+	business, err := walletService.Create(ctx, userId, &model.CreateWalletRequest{
+		Name: "Freelance", Type: "business", Currency: "USD", Balance: 800,
+	})
+	if err != nil {
+		return err
+	}
+
+	budgets := map[string]string{}
+	for _, b := range []model.CreateBudgetRequest{
+		{Name: "Groceries", Amount: 600, Type: "monthly", Category: "food"},
+		{Name: "Rent", Amount: 1400, Type: "monthly", Category: "housing"},
+		{Name: "Going out", Amount: 250, Type: "monthly", Category: "entertainment"},
+	} {
+		budget, err := budgetService.Create(ctx, userId, &b)
+		if err != nil {
+			return err
+		}
+		budgets[b.Name] = budget.ID.String()
+	}
+
+	start := time.Now().AddDate(0, 0, -demoDays)
+	count := 0
+
+	create := func(walletId string, budgetName string, transactionType string, amount float64, note string, day time.Time) error {
+		request := &model.CreateTransactionRequest{
+			Amount:          amount,
+			Type:            transactionType,
+			Note:            note,
+			TransactionDate: int(day.Unix()),
+			WalletID:        walletId,
+		}
+		if budgetName != "" {
+			budgetId := budgets[budgetName]
+			request.BudgetID = &budgetId
+		}
+
+		if _, err := transactionService.Create(ctx, userId, request); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+
+	// A predictable pattern so reports and forecasts have something to show
+	for day := start; day.Before(time.Now()); day = day.AddDate(0, 0, 1) {
+		var err error
+
+		switch {
+		case day.Day() == 1:
+			err = create(checking.ID.String(), "", constant.TransactionTypeIncome, 4200, "Salary", day)
+			if err == nil {
+				err = create(checking.ID.String(), "Rent", constant.TransactionTypeExpense, 1400, "Rent", day)
+			}
+		case day.Day() == 15:
+			err = create(business.ID.String(), "", constant.TransactionTypeIncome, 950, "Client invoice", day)
+		case day.Weekday() == time.Saturday:
+			err = create(checking.ID.String(), "Groceries", constant.TransactionTypeExpense, 85+float64(day.Day()%4)*10, "Supermarket", day)
+		case day.Weekday() == time.Friday:
+			err = create(checking.ID.String(), "Going out", constant.TransactionTypeExpense, 35+float64(day.Day()%3)*5, "Dinner", day)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Seeded %s (password %q) with 2 wallets, %d budgets and %d transactions\n", demoEmail, demoPassword, len(budgets), count)
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/service"
+	"flag"
+	"fmt"
+	"time"
+)
+
+func (a *app) snapshotService() (domain.SnapshotService, error) {
+	db, err := a.database()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewSnapshotService(
+		repository.NewTxManager(db),
+		repository.NewSnapshotRepository(db),
+		repository.NewExchangeRateRepository(db),
+		repository.NewWalletRepository(db),
+		repository.NewTransactionRepository(db),
+	), nil
+}
+
+func (a *app) snapshots(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet("snapshots "+args[0], flag.ContinueOnError)
+	from := flags.String("from", "", "first day to rebuild, YYYY-MM-DD in UTC")
+	if err := flags.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "capture":
+		snapshotService, err := a.snapshotService()
+		if err != nil {
+			return err
+		}
+
+		captured, err := snapshotService.Capture(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Captured the balance of %d wallets\n", captured)
+	case "rebuild":
+		start, err := time.Parse(time.DateOnly, *from)
+		if err != nil {
+			return errUsage
+		}
+
+		snapshotService, err := a.snapshotService()
+		if err != nil {
+			return err
+		}
+
+		rebuilt, err := snapshotService.Rebuild(ctx, start, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Rebuilt %d daily balances since %s\n", rebuilt, *from)
+	default:
+		return errUsage
+	}
+
+	return nil
+}
+
+func (a *app) rates(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "set" {
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet("rates set", flag.ContinueOnError)
+	currency := flags.String("currency", "", "currency code, e.g. EUR")
+	rate := flags.Float64("rate", 0, "value of one unit of the currency in the base currency")
+	date := flags.String("date", time.Now().UTC().Format(time.DateOnly), "day the rate takes effect, YYYY-MM-DD in UTC")
+	if err := flags.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	day, err := time.Parse(time.DateOnly, *date)
+	if err != nil || *currency == "" {
+		return errUsage
+	}
+
+	snapshotService, err := a.snapshotService()
+	if err != nil {
+		return err
+	}
+
+	if err := snapshotService.SetRate(ctx, *currency, day, *rate); err != nil {
+		return err
+	}
+
+	fmt.Printf("1 %s = %v %s from %s\n", *currency, *rate, a.config.Report.BaseCurrency, *date)
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/service"
+	"flag"
+	"fmt"
+)
+
+func (a *app) userService() (domain.UserService, error) {
+	db, err := a.database()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewUserService(repository.NewTxManager(db), repository.NewUserRepository(db), repository.NewSessionRepository(db)), nil
+}
+
+func (a *app) user(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+	email := flags.String("email", "", "user email")
+	name := flags.String("name", "", "full name")
+	password := flags.String("password", "", "password, at least 8 characters")
+	if err := flags.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	if *email == "" {
+		return errUsage
+	}
+
+	userService, err := a.userService()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		if *name == "" || *password == "" {
+			return errUsage
+		}
+
+		user, err := userService.Create(ctx, *name, *email, *password)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Created user %s (%s)\n", user.Email, user.ID)
+	case "disable":
+		if err := userService.Disable(ctx, *email); err != nil {
+			return err
+		}
+
+		fmt.Printf("Disabled user %s and revoked their sessions\n", *email)
+	case "reset-password":
+		if *password == "" {
+			return errUsage
+		}
+
+		if err := userService.ResetPassword(ctx, *email, *password); err != nil {
+			return err
+		}
+
+		fmt.Printf("Reset password for %s and revoked their sessions\n", *email)
+	default:
+		return errUsage
+	}
+
+	return nil
+}
+
+func (a *app) sessions(ctx context.Context, args []string) error {
+	if len(args) != 1 || args[0] != "purge-expired" {
+		return errUsage
+	}
+
+	userService, err := a.userService()
+	if err != nil {
+		return err
+	}
+
+	purged, err := userService.PurgeExpiredSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Purged %d expired sessions\n", purged)
+	return nil
+}
@@ -1,72 +1,131 @@
 package main
 
 import (
+	"context"
+	"finance-backend/internal/job"
+	"finance-backend/internal/repository"
 	"finance-backend/internal/routes"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/config"
 	"finance-backend/pkg/database"
+	"finance-backend/pkg/health"
 	"finance-backend/pkg/logger"
-	"os"
-
+	"finance-backend/pkg/metrics"
+	middleware "finance-backend/pkg/midleware"
 	"finance-backend/pkg/migration"
+	"finance-backend/pkg/server"
+	"finance-backend/pkg/tracing"
+	"flag"
+	stdlog "log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	logger.InitLogger()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to an optional YAML config file")
+	skipMigrate := flag.Bool("skip-migrate", false, "do not run pending migrations at startup, use financectl instead")
+	flag.Parse()
+
+	// Load configuration: defaults < YAML file < .env < environment
+	cfg, err := config.Load(config.Options{
+		File:    *configFile,
+		EnvFile: ".env",
+	})
+	if err != nil {
+		stdlog.Fatal("Failed to load configuration: ", err)
+	}
+
+	logger.InitLogger(cfg.Log)
 	log := logger.GetLogger()
 
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Warn("No .env file found")
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatal("Failed to initialize tracing:", err)
 	}
 
 	// Database connection
-	dbConfig := database.GetConfigFromEnv()
-	db, err := database.NewConnection(dbConfig)
+	db, err := database.NewConnection(cfg.Database)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	log.Info("Running database migrations...")
 	sqlDB, err := database.GetSQLDB(db)
 	if err != nil {
 		log.Fatal("Failed to get SQL DB instance:", err)
 	}
 
-	migrationsDir := migration.GetMigrationsDir()
-	if err := migration.MigrateUp(sqlDB, migrationsDir); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	migrationsDir := cfg.Migration.Dir
+	if cfg.Migration.AutoMigrate && !*skipMigrate {
+		log.Info("Running database migrations...")
+		if err := migration.MigrateUp(sqlDB, migrationsDir); err != nil {
+			log.Fatal("Failed to run migrations:", err)
+		}
+		log.Info("Database migrations completed successfully")
+	} else {
+		// Readiness keeps failing until the schema is migrated out of band
+		log.Info("Skipping database migrations")
+	}
+
+	if err := metrics.RegisterDBStats(sqlDB); err != nil {
+		log.Fatal("Failed to register database metrics:", err)
 	}
-	log.Info("Database migrations completed successfully")
+
+	// Metrics are served on a separate admin port so they are never exposed publicly
+	adminApp := metrics.NewAdminApp(cfg.Metrics.Token)
+	go func() {
+		log.WithField("port", cfg.Metrics.Port).Info("Starting metrics server...")
+		if err := adminApp.Listen(":" + cfg.Metrics.Port); err != nil {
+			log.WithError(err).Error("Metrics server stopped")
+		}
+	}()
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			log := logger.WithRequestID(c.Context())
+		ErrorHandler: middleware.ErrorHandler,
+	})
+
+	// Setup routes
+	healthChecker := health.NewChecker(sqlDB, migrationsDir)
+	routes.SetupRoutes(app, cfg, db, healthChecker)
 
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
+	srv := server.New(app, healthChecker, server.Config{
+		ShutdownTimeout: cfg.Server.ShutdownTimeout,
+		DrainDelay:      cfg.Server.DrainDelay,
+	})
+
+	if cfg.Report.SnapshotJob {
+		snapshotService := service.NewSnapshotService(
+			repository.NewTxManager(db),
+			repository.NewSnapshotRepository(db),
+			repository.NewExchangeRateRepository(db),
+			repository.NewWalletRepository(db),
+			repository.NewTransactionRepository(db),
+		)
+		srv.Go(job.DailySnapshots(snapshotService))
+	}
 
-			log.WithError(err).WithField("status_code", code).Error("Request error")
+	srv.Go(job.PurgeIdempotencyKeys(service.NewIdempotencyService(
+		repository.NewIdempotencyRepository(db),
+		cfg.Server.IdempotencyTTL,
+		cfg.Server.IdempotencyLockTimeout,
+	)))
 
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
+	// Hooks run once in-flight requests and workers are done, the pool is closed last
+	srv.OnShutdown(adminApp.ShutdownWithContext)
+	srv.OnShutdown(shutdownTracing)
+	srv.OnShutdown(func(context.Context) error {
+		return sqlDB.Close()
 	})
 
-	// Setup routes
-	routes.SetupRoutes(app, db)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	// Start server
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
+	log.WithField("port", cfg.Server.Port).Info("Starting server...")
+	if err := srv.ListenAndServe(ctx, ":"+cfg.Server.Port); err != nil {
+		log.Fatal("Server stopped with error:", err)
 	}
-
-	log.WithField("port", port).Info("Starting server...")
-	log.Fatal(app.Listen(":" + port))
 }
@@ -4,3 +4,49 @@ const (
 	TransactionTypeIncome  = "income"
 	TransactionTypeExpense = "expense"
 )
+
+const (
+	WalletTypePersonal = "personal"
+	WalletTypeBusiness = "business"
+	// Liability wallets hold what is owed, spending from them increases their balance
+	WalletTypeCredit = "credit"
+	WalletTypeLoan   = "loan"
+)
+
+const (
+	ReportGroupByDay   = "day"
+	ReportGroupByWeek  = "week"
+	ReportGroupByMonth = "month"
+)
+
+const (
+	// ReportCategoryUncategorized groups expenses that are not booked against a budget
+	ReportCategoryUncategorized = "uncategorized"
+
+	ReportDefaultTopNotes = 5
+)
+
+const (
+	ForecastIntervalWeekly   = "weekly"
+	ForecastIntervalBiweekly = "biweekly"
+	ForecastIntervalMonthly  = "monthly"
+
+	ForecastDefaultHorizon = 30
+)
+
+const (
+	// ImportAmountSign* tell whether a negative amount in a single amount column is money out or money in
+	ImportAmountSignNegativeExpense = "negative_expense"
+	ImportAmountSignPositiveExpense = "positive_expense"
+
+	ImportRowStatusNew       = "new"
+	ImportRowStatusDuplicate = "duplicate"
+	ImportRowStatusInvalid   = "invalid"
+
+	ImportMaxRows = 5000
+)
+
+const (
+	// TagDefaultSuggestions is how many tags autocomplete returns when no limit is given
+	TagDefaultSuggestions = 10
+)
@@ -1,38 +1,45 @@
 package domain
 
+//go:generate go run go.uber.org/mock/mockgen -source=auth.go -destination=mocks/auth.go -package=mocks
+
 import (
 	"context"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 	"gorm.io/plugin/soft_delete"
 )
 
 type User struct {
-	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid()"`
-	FullName string    `json:"full_name" gorm:"not null"`
-	Email    string    `json:"email" gorm:"uniqueIndex;not null"`
-	Password string    `json:"-" gorm:"not null"`
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	FullName string    `json:"full_name" gorm:"type:varchar(255);not null"`
+	Email    string    `json:"email" gorm:"type:varchar(255);unique;not null"`
+	Password string    `json:"-" gorm:"type:varchar(255);not null"`
+
+	DisabledAt *int
 
 	CreatedAt int
 	UpdatedAt int
-	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano"`
+	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano;not null;default:0;index"`
 
 	Sessions []Session `gorm:"foreignKey:UserID"`
 }
 
 type Session struct {
-	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid()"`
-	SessionToken string    `gorm:"uniqueIndex;not null"`
-	UserID       uuid.UUID `gorm:"type:uuid;not null"`
-	ExpiresAt    int       `gorm:"not null"`
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	SessionToken string    `gorm:"unique;not null"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	ExpiresAt    int       `gorm:"not null;index"`
+
+	CreatedAt int
+	UpdatedAt int
 
 	User User `gorm:"foreignKey:UserID"`
 }
 
 type UserRepository interface {
-	Create(db *gorm.DB, ctx context.Context, user *User) error
-	GetByEmail(db *gorm.DB, ctx context.Context, email string) (*User, error)
+	Create(ctx context.Context, user *User) error
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	Update(ctx context.Context, user *User) error
 }
 
 type AuthService interface {
@@ -42,9 +49,24 @@ type AuthService interface {
 }
 
 type SessionRepository interface {
-	Create(db *gorm.DB, ctx context.Context, session *Session) error
-	GetByToken(db *gorm.DB, ctx context.Context, token string) (*Session, error)
-	Delete(db *gorm.DB, ctx context.Context, token string) error
+	Create(ctx context.Context, session *Session) error
+	GetByToken(ctx context.Context, token string) (*Session, error)
+	Delete(ctx context.Context, token string) error
+	DeleteByUserID(ctx context.Context, userId string) error
+	DeleteExpired(ctx context.Context, now int) (int64, error)
+}
+
+// UserService covers account administration that is not exposed over HTTP
+type UserService interface {
+	Create(ctx context.Context, fullname, email, password string) (*User, error)
+	Disable(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, email, password string) error
+	PurgeExpiredSessions(ctx context.Context) (int64, error)
+}
+
+// IsDisabled reports whether the account has been disabled by an administrator
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
 }
 
 func (User) TableName() string {
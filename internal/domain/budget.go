@@ -1,30 +1,34 @@
 package domain
 
+//go:generate go run go.uber.org/mock/mockgen -source=budget.go -destination=mocks/budget.go -package=mocks
+
 import (
 	"context"
 	"finance-backend/internal/model"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 	"gorm.io/plugin/soft_delete"
 )
 
 type Budget struct {
-	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid()"`
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 
-	Name     string  `gorm:"type:varchar(100);not null"`
+	Name     string  `gorm:"type:varchar(255);not null"`
 	Amount   float64 `gorm:"type:decimal(15,2);not null;check:amount >= 0"`
-	Type     string  `gorm:"type:varchar(50);not null"`
-	Category string  `gorm:"type:varchar(50);not null"`
+	Type     string  `gorm:"type:varchar(100);not null"`
+	Category string  `gorm:"type:varchar(100);not null"`
+
+	// Version counts the changes to the budget and is served as its ETag
+	Version int `gorm:"type:integer;not null;default:1"`
 
 	CreatedAt int
 	UpdatedAt int
-	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano"`
+	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano;not null;default:0;index"`
 }
 
 type HasBudget struct {
-	UserID   uuid.UUID `gorm:"type:uuid;primaryKey"`
-	BudgetID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID   uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	BudgetID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
 
 	User   User   `gorm:"foreignKey:UserID;references:ID"`
 	Budget Budget `gorm:"foreignKey:BudgetID;references:ID"`
@@ -39,11 +43,17 @@ func (HasBudget) TableName() string {
 }
 
 type BudgetRepository interface {
-	Create(db *gorm.DB, ctx context.Context, userId string, budget *Budget) error
-	GetList(db *gorm.DB, ctx context.Context, userId string) ([]*Budget, error)
+	Create(ctx context.Context, userId string, budget *Budget) error
+	GetList(ctx context.Context, userId string) ([]*Budget, error)
+	GetDetail(ctx context.Context, userId string, budgetId string) (*Budget, error)
+	// Update saves the budget if it is still at version and bumps the version, it reports false when it is not
+	Update(ctx context.Context, budget *Budget, version int) (bool, error)
 }
 
 type BudgetService interface {
 	Create(ctx context.Context, userId string, request *model.CreateBudgetRequest) (*Budget, error)
 	GetList(ctx context.Context, userId string) ([]*Budget, error)
+	GetDetail(ctx context.Context, userId string, budgetId string) (*Budget, error)
+	// Update replaces the budget if it is still at version, zero skips the check
+	Update(ctx context.Context, userId string, budgetId string, version int, request *model.UpdateBudgetRequest) (*Budget, error)
 }
@@ -0,0 +1,24 @@
+package domain
+
+//go:generate go run go.uber.org/mock/mockgen -source=export.go -destination=mocks/export.go -package=mocks
+
+import (
+	"context"
+	"finance-backend/internal/model"
+	"io"
+)
+
+// Export is a file produced while it is sent. Write streams it from an open database cursor and
+// releases the cursor when it returns, so it must be called exactly once.
+type Export struct {
+	Filename    string
+	ContentType string
+	Write       func(w io.Writer) error
+}
+
+type ExportService interface {
+	// Transactions opens an export of the transactions matching the request, oldest first
+	Transactions(ctx context.Context, userId string, request *model.ExportTransactionsRequest) (*Export, error)
+	// Ledger opens an export of the wallets and their transactions as balanced Ledger or Beancount entries
+	Ledger(ctx context.Context, userId string, request *model.ExportLedgerRequest) (*Export, error)
+}
@@ -0,0 +1,60 @@
+package domain
+
+//go:generate go run go.uber.org/mock/mockgen -source=idempotency.go -destination=mocks/idempotency.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey is a client supplied key for a mutating request and the response it got.
+// Status is 0 while the first request with the key is still being handled.
+type IdempotencyKey struct {
+	UserID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Key    string    `gorm:"type:varchar(255);primaryKey"`
+	// Fingerprint hashes the method, path and body the key was first used with
+	Fingerprint string `gorm:"type:varchar(64);not null"`
+
+	Status      int    `gorm:"type:integer;not null;default:0"`
+	ContentType string `gorm:"type:varchar(255);not null;default:''"`
+	ETag        string `gorm:"column:etag;type:varchar(255);not null;default:''"`
+	Location    string `gorm:"type:text;not null;default:''"`
+	Body        []byte `gorm:"type:bytea"`
+
+	CreatedAt int
+	UpdatedAt int
+	ExpiresAt int `gorm:"not null;index"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+type IdempotencyRepository interface {
+	// Reserve stores key unless the user holds it already and it has not expired, it reports whether it did.
+	// A key still pending since before staleBefore is taken over by a request with the same fingerprint,
+	// the request that reserved it died without giving it up.
+	Reserve(ctx context.Context, key *IdempotencyKey, staleBefore int) (bool, error)
+	GetDetail(ctx context.Context, userId string, key string) (*IdempotencyKey, error)
+	// Complete stores the response of a reserved key
+	Complete(ctx context.Context, key *IdempotencyKey) error
+	Delete(ctx context.Context, userId string, key string) error
+	// DeleteExpired removes keys that expired before now and returns how many
+	DeleteExpired(ctx context.Context, now int) (int64, error)
+}
+
+type IdempotencyService interface {
+	// Begin claims key for a request with fingerprint. It returns the stored response when the
+	// key was already used for the same request, and nil when the request should be handled.
+	Begin(ctx context.Context, userId string, key string, fingerprint string) (*IdempotencyKey, error)
+	// Complete stores the status, headers and body of response to replay for the key
+	Complete(ctx context.Context, userId string, key string, response *IdempotencyKey) error
+	// Release gives the key up so the request can be retried, used when it failed on the server
+	Release(ctx context.Context, userId string, key string) error
+	// Purge removes expired keys and returns how many
+	Purge(ctx context.Context, now time.Time) (int64, error)
+}
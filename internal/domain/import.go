@@ -0,0 +1,95 @@
+package domain
+
+//go:generate go run go.uber.org/mock/mockgen -source=import.go -destination=mocks/import.go -package=mocks
+
+import (
+	"context"
+	"finance-backend/internal/model"
+	"io"
+
+	"github.com/google/uuid"
+	"gorm.io/plugin/soft_delete"
+)
+
+// ImportProfile is a saved description of how one bank lays out its CSV statements.
+// Columns are zero-based, nil columns are not present in the file.
+type ImportProfile struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+
+	Name      string `gorm:"type:varchar(255);not null"`
+	Delimiter string `gorm:"type:varchar(1);not null"`
+	HasHeader bool   `gorm:"not null"`
+
+	DateColumn int    `gorm:"not null"`
+	DateFormat string `gorm:"type:varchar(50);not null"`
+	Timezone   string `gorm:"type:varchar(100);not null"`
+
+	AmountColumn *int
+	DebitColumn  *int
+	CreditColumn *int
+	NoteColumn   *int
+
+	AmountSign       string `gorm:"type:varchar(50);not null"`
+	DecimalSeparator string `gorm:"type:varchar(1);not null"`
+
+	CreatedAt int
+	UpdatedAt int
+	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano;not null;default:0;index"`
+}
+
+type HasImportProfile struct {
+	UserID          uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	ImportProfileID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+
+	User          User          `gorm:"foreignKey:UserID;references:ID"`
+	ImportProfile ImportProfile `gorm:"foreignKey:ImportProfileID;references:ID"`
+}
+
+// ImportAccount remembers which wallet a bank account named in statement files is booked to
+type ImportAccount struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	AccountID string    `gorm:"type:varchar(255);primaryKey"`
+	WalletID  uuid.UUID `gorm:"type:uuid;not null;index"`
+
+	CreatedAt int
+	UpdatedAt int
+
+	User   User   `gorm:"foreignKey:UserID;references:ID"`
+	Wallet Wallet `gorm:"foreignKey:WalletID;references:ID"`
+}
+
+func (ImportProfile) TableName() string {
+	return "import_profiles"
+}
+
+func (HasImportProfile) TableName() string {
+	return "has_import_profiles"
+}
+
+func (ImportAccount) TableName() string {
+	return "import_accounts"
+}
+
+type ImportProfileRepository interface {
+	Create(ctx context.Context, userId string, profile *ImportProfile) error
+	GetList(ctx context.Context, userId string) ([]*ImportProfile, error)
+	GetDetail(ctx context.Context, userId string, profileId string) (*ImportProfile, error)
+}
+
+type ImportAccountRepository interface {
+	GetList(ctx context.Context, userId string) ([]*ImportAccount, error)
+	// Upsert saves the mappings, pointing an account that is already mapped at its new wallet
+	Upsert(ctx context.Context, accounts []*ImportAccount) error
+}
+
+type ImportService interface {
+	CreateProfile(ctx context.Context, userId string, request *model.CreateImportProfileRequest) (*ImportProfile, error)
+	GetProfiles(ctx context.Context, userId string) ([]*ImportProfile, error)
+	// ImportCSV previews the statement in file, or books its new rows when the request is not a dry run
+	ImportCSV(ctx context.Context, userId string, request *model.ImportCSVRequest, file io.Reader) (*model.ImportResult, error)
+	// ImportOFX does the same for OFX and QFX files, whose accounts are mapped onto wallets
+	ImportOFX(ctx context.Context, userId string, request *model.ImportStatementRequest, file io.Reader) (*model.ImportResult, error)
+	ImportQIF(ctx context.Context, userId string, request *model.ImportStatementRequest, file io.Reader) (*model.ImportResult, error)
+	// ImportBeancount books a Beancount file, creating the wallets and budgets its accounts name
+	ImportBeancount(ctx context.Context, userId string, request *model.ImportLedgerRequest, file io.Reader) (*model.ImportResult, error)
+}
@@ -0,0 +1,186 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: budget.go
+//
+// Generated by this command:
+//
+//	mockgen -source=budget.go -destination=mocks/budget.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	model "finance-backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBudgetRepository is a mock of BudgetRepository interface.
+type MockBudgetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBudgetRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBudgetRepositoryMockRecorder is the mock recorder for MockBudgetRepository.
+type MockBudgetRepositoryMockRecorder struct {
+	mock *MockBudgetRepository
+}
+
+// NewMockBudgetRepository creates a new mock instance.
+func NewMockBudgetRepository(ctrl *gomock.Controller) *MockBudgetRepository {
+	mock := &MockBudgetRepository{ctrl: ctrl}
+	mock.recorder = &MockBudgetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBudgetRepository) EXPECT() *MockBudgetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBudgetRepository) Create(ctx context.Context, userId string, budget *domain.Budget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBudgetRepositoryMockRecorder) Create(ctx, userId, budget any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBudgetRepository)(nil).Create), ctx, userId, budget)
+}
+
+// GetDetail mocks base method.
+func (m *MockBudgetRepository) GetDetail(ctx context.Context, userId, budgetId string) (*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, budgetId)
+	ret0, _ := ret[0].(*domain.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockBudgetRepositoryMockRecorder) GetDetail(ctx, userId, budgetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockBudgetRepository)(nil).GetDetail), ctx, userId, budgetId)
+}
+
+// GetList mocks base method.
+func (m *MockBudgetRepository) GetList(ctx context.Context, userId string) ([]*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId)
+	ret0, _ := ret[0].([]*domain.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockBudgetRepositoryMockRecorder) GetList(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockBudgetRepository)(nil).GetList), ctx, userId)
+}
+
+// Update mocks base method.
+func (m *MockBudgetRepository) Update(ctx context.Context, budget *domain.Budget, version int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, budget, version)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBudgetRepositoryMockRecorder) Update(ctx, budget, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBudgetRepository)(nil).Update), ctx, budget, version)
+}
+
+// MockBudgetService is a mock of BudgetService interface.
+type MockBudgetService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBudgetServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBudgetServiceMockRecorder is the mock recorder for MockBudgetService.
+type MockBudgetServiceMockRecorder struct {
+	mock *MockBudgetService
+}
+
+// NewMockBudgetService creates a new mock instance.
+func NewMockBudgetService(ctrl *gomock.Controller) *MockBudgetService {
+	mock := &MockBudgetService{ctrl: ctrl}
+	mock.recorder = &MockBudgetServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBudgetService) EXPECT() *MockBudgetServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBudgetService) Create(ctx context.Context, userId string, request *model.CreateBudgetRequest) (*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, request)
+	ret0, _ := ret[0].(*domain.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBudgetServiceMockRecorder) Create(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBudgetService)(nil).Create), ctx, userId, request)
+}
+
+// GetDetail mocks base method.
+func (m *MockBudgetService) GetDetail(ctx context.Context, userId, budgetId string) (*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, budgetId)
+	ret0, _ := ret[0].(*domain.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockBudgetServiceMockRecorder) GetDetail(ctx, userId, budgetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockBudgetService)(nil).GetDetail), ctx, userId, budgetId)
+}
+
+// GetList mocks base method.
+func (m *MockBudgetService) GetList(ctx context.Context, userId string) ([]*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId)
+	ret0, _ := ret[0].([]*domain.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockBudgetServiceMockRecorder) GetList(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockBudgetService)(nil).GetList), ctx, userId)
+}
+
+// Update mocks base method.
+func (m *MockBudgetService) Update(ctx context.Context, userId, budgetId string, version int, request *model.UpdateBudgetRequest) (*domain.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, budgetId, version, request)
+	ret0, _ := ret[0].(*domain.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBudgetServiceMockRecorder) Update(ctx, userId, budgetId, version, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBudgetService)(nil).Update), ctx, userId, budgetId, version, request)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=export.go -destination=mocks/export.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	model "finance-backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExportService is a mock of ExportService interface.
+type MockExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockExportServiceMockRecorder is the mock recorder for MockExportService.
+type MockExportServiceMockRecorder struct {
+	mock *MockExportService
+}
+
+// NewMockExportService creates a new mock instance.
+func NewMockExportService(ctrl *gomock.Controller) *MockExportService {
+	mock := &MockExportService{ctrl: ctrl}
+	mock.recorder = &MockExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportService) EXPECT() *MockExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Ledger mocks base method.
+func (m *MockExportService) Ledger(ctx context.Context, userId string, request *model.ExportLedgerRequest) (*domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ledger", ctx, userId, request)
+	ret0, _ := ret[0].(*domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ledger indicates an expected call of Ledger.
+func (mr *MockExportServiceMockRecorder) Ledger(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ledger", reflect.TypeOf((*MockExportService)(nil).Ledger), ctx, userId, request)
+}
+
+// Transactions mocks base method.
+func (m *MockExportService) Transactions(ctx context.Context, userId string, request *model.ExportTransactionsRequest) (*domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transactions", ctx, userId, request)
+	ret0, _ := ret[0].(*domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transactions indicates an expected call of Transactions.
+func (mr *MockExportServiceMockRecorder) Transactions(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transactions", reflect.TypeOf((*MockExportService)(nil).Transactions), ctx, userId, request)
+}
@@ -0,0 +1,198 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: idempotency.go
+//
+// Generated by this command:
+//
+//	mockgen -source=idempotency.go -destination=mocks/idempotency.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, key *domain.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyRepositoryMockRecorder) Complete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyRepository)(nil).Complete), ctx, key)
+}
+
+// Delete mocks base method.
+func (m *MockIdempotencyRepository) Delete(ctx context.Context, userId, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIdempotencyRepositoryMockRecorder) Delete(ctx, userId, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdempotencyRepository)(nil).Delete), ctx, userId, key)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, now int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// GetDetail mocks base method.
+func (m *MockIdempotencyRepository) GetDetail(ctx context.Context, userId, key string) (*domain.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, key)
+	ret0, _ := ret[0].(*domain.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockIdempotencyRepositoryMockRecorder) GetDetail(ctx, userId, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockIdempotencyRepository)(nil).GetDetail), ctx, userId, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, key *domain.IdempotencyKey, staleBefore int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, key, staleBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyRepositoryMockRecorder) Reserve(ctx, key, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyRepository)(nil).Reserve), ctx, key, staleBefore)
+}
+
+// MockIdempotencyService is a mock of IdempotencyService interface.
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService.
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance.
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdempotencyService) Begin(ctx context.Context, userId, key, fingerprint string) (*domain.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, userId, key, fingerprint)
+	ret0, _ := ret[0].(*domain.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyServiceMockRecorder) Begin(ctx, userId, key, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotencyService)(nil).Begin), ctx, userId, key, fingerprint)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyService) Complete(ctx context.Context, userId, key string, response *domain.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, userId, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyServiceMockRecorder) Complete(ctx, userId, key, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyService)(nil).Complete), ctx, userId, key, response)
+}
+
+// Purge mocks base method.
+func (m *MockIdempotencyService) Purge(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockIdempotencyServiceMockRecorder) Purge(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockIdempotencyService)(nil).Purge), ctx, now)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyService) Release(ctx context.Context, userId, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, userId, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyServiceMockRecorder) Release(ctx, userId, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyService)(nil).Release), ctx, userId, key)
+}
@@ -0,0 +1,255 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: import.go
+//
+// Generated by this command:
+//
+//	mockgen -source=import.go -destination=mocks/import.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	model "finance-backend/internal/model"
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImportProfileRepository is a mock of ImportProfileRepository interface.
+type MockImportProfileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportProfileRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockImportProfileRepositoryMockRecorder is the mock recorder for MockImportProfileRepository.
+type MockImportProfileRepositoryMockRecorder struct {
+	mock *MockImportProfileRepository
+}
+
+// NewMockImportProfileRepository creates a new mock instance.
+func NewMockImportProfileRepository(ctrl *gomock.Controller) *MockImportProfileRepository {
+	mock := &MockImportProfileRepository{ctrl: ctrl}
+	mock.recorder = &MockImportProfileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportProfileRepository) EXPECT() *MockImportProfileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockImportProfileRepository) Create(ctx context.Context, userId string, profile *domain.ImportProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockImportProfileRepositoryMockRecorder) Create(ctx, userId, profile any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockImportProfileRepository)(nil).Create), ctx, userId, profile)
+}
+
+// GetDetail mocks base method.
+func (m *MockImportProfileRepository) GetDetail(ctx context.Context, userId, profileId string) (*domain.ImportProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, profileId)
+	ret0, _ := ret[0].(*domain.ImportProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockImportProfileRepositoryMockRecorder) GetDetail(ctx, userId, profileId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockImportProfileRepository)(nil).GetDetail), ctx, userId, profileId)
+}
+
+// GetList mocks base method.
+func (m *MockImportProfileRepository) GetList(ctx context.Context, userId string) ([]*domain.ImportProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId)
+	ret0, _ := ret[0].([]*domain.ImportProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockImportProfileRepositoryMockRecorder) GetList(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockImportProfileRepository)(nil).GetList), ctx, userId)
+}
+
+// MockImportAccountRepository is a mock of ImportAccountRepository interface.
+type MockImportAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportAccountRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockImportAccountRepositoryMockRecorder is the mock recorder for MockImportAccountRepository.
+type MockImportAccountRepositoryMockRecorder struct {
+	mock *MockImportAccountRepository
+}
+
+// NewMockImportAccountRepository creates a new mock instance.
+func NewMockImportAccountRepository(ctrl *gomock.Controller) *MockImportAccountRepository {
+	mock := &MockImportAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockImportAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportAccountRepository) EXPECT() *MockImportAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetList mocks base method.
+func (m *MockImportAccountRepository) GetList(ctx context.Context, userId string) ([]*domain.ImportAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId)
+	ret0, _ := ret[0].([]*domain.ImportAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockImportAccountRepositoryMockRecorder) GetList(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockImportAccountRepository)(nil).GetList), ctx, userId)
+}
+
+// Upsert mocks base method.
+func (m *MockImportAccountRepository) Upsert(ctx context.Context, accounts []*domain.ImportAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, accounts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockImportAccountRepositoryMockRecorder) Upsert(ctx, accounts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockImportAccountRepository)(nil).Upsert), ctx, accounts)
+}
+
+// MockImportService is a mock of ImportService interface.
+type MockImportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockImportServiceMockRecorder is the mock recorder for MockImportService.
+type MockImportServiceMockRecorder struct {
+	mock *MockImportService
+}
+
+// NewMockImportService creates a new mock instance.
+func NewMockImportService(ctrl *gomock.Controller) *MockImportService {
+	mock := &MockImportService{ctrl: ctrl}
+	mock.recorder = &MockImportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportService) EXPECT() *MockImportServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateProfile mocks base method.
+func (m *MockImportService) CreateProfile(ctx context.Context, userId string, request *model.CreateImportProfileRequest) (*domain.ImportProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProfile", ctx, userId, request)
+	ret0, _ := ret[0].(*domain.ImportProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProfile indicates an expected call of CreateProfile.
+func (mr *MockImportServiceMockRecorder) CreateProfile(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfile", reflect.TypeOf((*MockImportService)(nil).CreateProfile), ctx, userId, request)
+}
+
+// GetProfiles mocks base method.
+func (m *MockImportService) GetProfiles(ctx context.Context, userId string) ([]*domain.ImportProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfiles", ctx, userId)
+	ret0, _ := ret[0].([]*domain.ImportProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfiles indicates an expected call of GetProfiles.
+func (mr *MockImportServiceMockRecorder) GetProfiles(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfiles", reflect.TypeOf((*MockImportService)(nil).GetProfiles), ctx, userId)
+}
+
+// ImportBeancount mocks base method.
+func (m *MockImportService) ImportBeancount(ctx context.Context, userId string, request *model.ImportLedgerRequest, file io.Reader) (*model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBeancount", ctx, userId, request, file)
+	ret0, _ := ret[0].(*model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBeancount indicates an expected call of ImportBeancount.
+func (mr *MockImportServiceMockRecorder) ImportBeancount(ctx, userId, request, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBeancount", reflect.TypeOf((*MockImportService)(nil).ImportBeancount), ctx, userId, request, file)
+}
+
+// ImportCSV mocks base method.
+func (m *MockImportService) ImportCSV(ctx context.Context, userId string, request *model.ImportCSVRequest, file io.Reader) (*model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCSV", ctx, userId, request, file)
+	ret0, _ := ret[0].(*model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCSV indicates an expected call of ImportCSV.
+func (mr *MockImportServiceMockRecorder) ImportCSV(ctx, userId, request, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCSV", reflect.TypeOf((*MockImportService)(nil).ImportCSV), ctx, userId, request, file)
+}
+
+// ImportOFX mocks base method.
+func (m *MockImportService) ImportOFX(ctx context.Context, userId string, request *model.ImportStatementRequest, file io.Reader) (*model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportOFX", ctx, userId, request, file)
+	ret0, _ := ret[0].(*model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportOFX indicates an expected call of ImportOFX.
+func (mr *MockImportServiceMockRecorder) ImportOFX(ctx, userId, request, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportOFX", reflect.TypeOf((*MockImportService)(nil).ImportOFX), ctx, userId, request, file)
+}
+
+// ImportQIF mocks base method.
+func (m *MockImportService) ImportQIF(ctx context.Context, userId string, request *model.ImportStatementRequest, file io.Reader) (*model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportQIF", ctx, userId, request, file)
+	ret0, _ := ret[0].(*model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportQIF indicates an expected call of ImportQIF.
+func (mr *MockImportServiceMockRecorder) ImportQIF(ctx, userId, request, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportQIF", reflect.TypeOf((*MockImportService)(nil).ImportQIF), ctx, userId, request, file)
+}
@@ -0,0 +1,202 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: report.go
+//
+// Generated by this command:
+//
+//	mockgen -source=report.go -destination=mocks/report.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	model "finance-backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReportRepository is a mock of ReportRepository interface.
+type MockReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReportRepositoryMockRecorder is the mock recorder for MockReportRepository.
+type MockReportRepositoryMockRecorder struct {
+	mock *MockReportRepository
+}
+
+// NewMockReportRepository creates a new mock instance.
+func NewMockReportRepository(ctrl *gomock.Controller) *MockReportRepository {
+	mock := &MockReportRepository{ctrl: ctrl}
+	mock.recorder = &MockReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepository) EXPECT() *MockReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FlowHistory mocks base method.
+func (m *MockReportRepository) FlowHistory(ctx context.Context, userId string, filter domain.FlowFilter) ([]*domain.FlowRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlowHistory", ctx, userId, filter)
+	ret0, _ := ret[0].([]*domain.FlowRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlowHistory indicates an expected call of FlowHistory.
+func (mr *MockReportRepositoryMockRecorder) FlowHistory(ctx, userId, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlowHistory", reflect.TypeOf((*MockReportRepository)(nil).FlowHistory), ctx, userId, filter)
+}
+
+// SpendByBudget mocks base method.
+func (m *MockReportRepository) SpendByBudget(ctx context.Context, userId string, filter domain.SpendFilter) ([]*domain.BudgetSpendRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpendByBudget", ctx, userId, filter)
+	ret0, _ := ret[0].([]*domain.BudgetSpendRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpendByBudget indicates an expected call of SpendByBudget.
+func (mr *MockReportRepositoryMockRecorder) SpendByBudget(ctx, userId, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpendByBudget", reflect.TypeOf((*MockReportRepository)(nil).SpendByBudget), ctx, userId, filter)
+}
+
+// SpendByTag mocks base method.
+func (m *MockReportRepository) SpendByTag(ctx context.Context, userId string, filter domain.SpendFilter) ([]*domain.TagSpendRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpendByTag", ctx, userId, filter)
+	ret0, _ := ret[0].([]*domain.TagSpendRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpendByTag indicates an expected call of SpendByTag.
+func (mr *MockReportRepositoryMockRecorder) SpendByTag(ctx, userId, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpendByTag", reflect.TypeOf((*MockReportRepository)(nil).SpendByTag), ctx, userId, filter)
+}
+
+// Summary mocks base method.
+func (m *MockReportRepository) Summary(ctx context.Context, userId string, filter domain.SummaryFilter) ([]*domain.SummaryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summary", ctx, userId, filter)
+	ret0, _ := ret[0].([]*domain.SummaryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summary indicates an expected call of Summary.
+func (mr *MockReportRepositoryMockRecorder) Summary(ctx, userId, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summary", reflect.TypeOf((*MockReportRepository)(nil).Summary), ctx, userId, filter)
+}
+
+// TopNotes mocks base method.
+func (m *MockReportRepository) TopNotes(ctx context.Context, userId string, filter domain.SpendFilter, limit int) ([]*domain.NoteSpendRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopNotes", ctx, userId, filter, limit)
+	ret0, _ := ret[0].([]*domain.NoteSpendRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopNotes indicates an expected call of TopNotes.
+func (mr *MockReportRepositoryMockRecorder) TopNotes(ctx, userId, filter, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopNotes", reflect.TypeOf((*MockReportRepository)(nil).TopNotes), ctx, userId, filter, limit)
+}
+
+// MockReportService is a mock of ReportService interface.
+type MockReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockReportServiceMockRecorder is the mock recorder for MockReportService.
+type MockReportServiceMockRecorder struct {
+	mock *MockReportService
+}
+
+// NewMockReportService creates a new mock instance.
+func NewMockReportService(ctrl *gomock.Controller) *MockReportService {
+	mock := &MockReportService{ctrl: ctrl}
+	mock.recorder = &MockReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportService) EXPECT() *MockReportServiceMockRecorder {
+	return m.recorder
+}
+
+// Categories mocks base method.
+func (m *MockReportService) Categories(ctx context.Context, userId string, request *model.CategoryReportRequest) (*model.CategoryReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Categories", ctx, userId, request)
+	ret0, _ := ret[0].(*model.CategoryReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Categories indicates an expected call of Categories.
+func (mr *MockReportServiceMockRecorder) Categories(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Categories", reflect.TypeOf((*MockReportService)(nil).Categories), ctx, userId, request)
+}
+
+// Forecast mocks base method.
+func (m *MockReportService) Forecast(ctx context.Context, userId string, request *model.ForecastRequest) (*model.ForecastReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forecast", ctx, userId, request)
+	ret0, _ := ret[0].(*model.ForecastReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Forecast indicates an expected call of Forecast.
+func (mr *MockReportServiceMockRecorder) Forecast(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forecast", reflect.TypeOf((*MockReportService)(nil).Forecast), ctx, userId, request)
+}
+
+// NetWorth mocks base method.
+func (m *MockReportService) NetWorth(ctx context.Context, userId string, request *model.NetWorthReportRequest) (*model.NetWorthReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetWorth", ctx, userId, request)
+	ret0, _ := ret[0].(*model.NetWorthReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetWorth indicates an expected call of NetWorth.
+func (mr *MockReportServiceMockRecorder) NetWorth(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetWorth", reflect.TypeOf((*MockReportService)(nil).NetWorth), ctx, userId, request)
+}
+
+// Summary mocks base method.
+func (m *MockReportService) Summary(ctx context.Context, userId string, request *model.SummaryReportRequest) (*model.SummaryReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summary", ctx, userId, request)
+	ret0, _ := ret[0].(*model.SummaryReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summary indicates an expected call of Summary.
+func (mr *MockReportServiceMockRecorder) Summary(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summary", reflect.TypeOf((*MockReportService)(nil).Summary), ctx, userId, request)
+}
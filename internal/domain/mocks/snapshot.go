@@ -0,0 +1,208 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: snapshot.go
+//
+// Generated by this command:
+//
+//	mockgen -source=snapshot.go -destination=mocks/snapshot.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSnapshotRepository is a mock of SnapshotRepository interface.
+type MockSnapshotRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSnapshotRepositoryMockRecorder is the mock recorder for MockSnapshotRepository.
+type MockSnapshotRepositoryMockRecorder struct {
+	mock *MockSnapshotRepository
+}
+
+// NewMockSnapshotRepository creates a new mock instance.
+func NewMockSnapshotRepository(ctrl *gomock.Controller) *MockSnapshotRepository {
+	mock := &MockSnapshotRepository{ctrl: ctrl}
+	mock.recorder = &MockSnapshotRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotRepository) EXPECT() *MockSnapshotRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockSnapshotRepository) Capture(ctx context.Context, date int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, date)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockSnapshotRepositoryMockRecorder) Capture(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockSnapshotRepository)(nil).Capture), ctx, date)
+}
+
+// GetRange mocks base method.
+func (m *MockSnapshotRepository) GetRange(ctx context.Context, userId string, from, to int) ([]*domain.WalletSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRange", ctx, userId, from, to)
+	ret0, _ := ret[0].([]*domain.WalletSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRange indicates an expected call of GetRange.
+func (mr *MockSnapshotRepositoryMockRecorder) GetRange(ctx, userId, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockSnapshotRepository)(nil).GetRange), ctx, userId, from, to)
+}
+
+// Upsert mocks base method.
+func (m *MockSnapshotRepository) Upsert(ctx context.Context, snapshots []*domain.WalletSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, snapshots)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockSnapshotRepositoryMockRecorder) Upsert(ctx, snapshots any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockSnapshotRepository)(nil).Upsert), ctx, snapshots)
+}
+
+// MockExchangeRateRepository is a mock of ExchangeRateRepository interface.
+type MockExchangeRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExchangeRateRepositoryMockRecorder is the mock recorder for MockExchangeRateRepository.
+type MockExchangeRateRepositoryMockRecorder struct {
+	mock *MockExchangeRateRepository
+}
+
+// NewMockExchangeRateRepository creates a new mock instance.
+func NewMockExchangeRateRepository(ctrl *gomock.Controller) *MockExchangeRateRepository {
+	mock := &MockExchangeRateRepository{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateRepository) EXPECT() *MockExchangeRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUntil mocks base method.
+func (m *MockExchangeRateRepository) GetUntil(ctx context.Context, currencies []string, to int) ([]*domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUntil", ctx, currencies, to)
+	ret0, _ := ret[0].([]*domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUntil indicates an expected call of GetUntil.
+func (mr *MockExchangeRateRepositoryMockRecorder) GetUntil(ctx, currencies, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUntil", reflect.TypeOf((*MockExchangeRateRepository)(nil).GetUntil), ctx, currencies, to)
+}
+
+// Upsert mocks base method.
+func (m *MockExchangeRateRepository) Upsert(ctx context.Context, rate *domain.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, rate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockExchangeRateRepositoryMockRecorder) Upsert(ctx, rate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockExchangeRateRepository)(nil).Upsert), ctx, rate)
+}
+
+// MockSnapshotService is a mock of SnapshotService interface.
+type MockSnapshotService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSnapshotServiceMockRecorder is the mock recorder for MockSnapshotService.
+type MockSnapshotServiceMockRecorder struct {
+	mock *MockSnapshotService
+}
+
+// NewMockSnapshotService creates a new mock instance.
+func NewMockSnapshotService(ctrl *gomock.Controller) *MockSnapshotService {
+	mock := &MockSnapshotService{ctrl: ctrl}
+	mock.recorder = &MockSnapshotServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotService) EXPECT() *MockSnapshotServiceMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockSnapshotService) Capture(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockSnapshotServiceMockRecorder) Capture(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockSnapshotService)(nil).Capture), ctx, now)
+}
+
+// Rebuild mocks base method.
+func (m *MockSnapshotService) Rebuild(ctx context.Context, from, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx, from, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockSnapshotServiceMockRecorder) Rebuild(ctx, from, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockSnapshotService)(nil).Rebuild), ctx, from, now)
+}
+
+// SetRate mocks base method.
+func (m *MockSnapshotService) SetRate(ctx context.Context, currency string, date time.Time, rate float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRate", ctx, currency, date, rate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRate indicates an expected call of SetRate.
+func (mr *MockSnapshotServiceMockRecorder) SetRate(ctx, currency, date, rate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRate", reflect.TypeOf((*MockSnapshotService)(nil).SetRate), ctx, currency, date, rate)
+}
@@ -0,0 +1,288 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tag.go
+//
+// Generated by this command:
+//
+//	mockgen -source=tag.go -destination=mocks/tag.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	model "finance-backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagRepository is a mock of TagRepository interface.
+type MockTagRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTagRepositoryMockRecorder is the mock recorder for MockTagRepository.
+type MockTagRepositoryMockRecorder struct {
+	mock *MockTagRepository
+}
+
+// NewMockTagRepository creates a new mock instance.
+func NewMockTagRepository(ctrl *gomock.Controller) *MockTagRepository {
+	mock := &MockTagRepository{ctrl: ctrl}
+	mock.recorder = &MockTagRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagRepository) EXPECT() *MockTagRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTagRepository) Create(ctx context.Context, tag *domain.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagRepositoryMockRecorder) Create(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTagRepository)(nil).Create), ctx, tag)
+}
+
+// Delete mocks base method.
+func (m *MockTagRepository) Delete(ctx context.Context, tag *domain.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagRepositoryMockRecorder) Delete(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagRepository)(nil).Delete), ctx, tag)
+}
+
+// GetByIDs mocks base method.
+func (m *MockTagRepository) GetByIDs(ctx context.Context, userId string, ids []string) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, userId, ids)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockTagRepositoryMockRecorder) GetByIDs(ctx, userId, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockTagRepository)(nil).GetByIDs), ctx, userId, ids)
+}
+
+// GetByName mocks base method.
+func (m *MockTagRepository) GetByName(ctx context.Context, userId, name string) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", ctx, userId, name)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockTagRepositoryMockRecorder) GetByName(ctx, userId, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockTagRepository)(nil).GetByName), ctx, userId, name)
+}
+
+// GetDetail mocks base method.
+func (m *MockTagRepository) GetDetail(ctx context.Context, userId, tagId string) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, tagId)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockTagRepositoryMockRecorder) GetDetail(ctx, userId, tagId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockTagRepository)(nil).GetDetail), ctx, userId, tagId)
+}
+
+// GetList mocks base method.
+func (m *MockTagRepository) GetList(ctx context.Context, userId, prefix string, limit int) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId, prefix, limit)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockTagRepositoryMockRecorder) GetList(ctx, userId, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockTagRepository)(nil).GetList), ctx, userId, prefix, limit)
+}
+
+// Link mocks base method.
+func (m *MockTagRepository) Link(ctx context.Context, transactionIds, tagIds []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Link", ctx, transactionIds, tagIds)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Link indicates an expected call of Link.
+func (mr *MockTagRepositoryMockRecorder) Link(ctx, transactionIds, tagIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*MockTagRepository)(nil).Link), ctx, transactionIds, tagIds)
+}
+
+// Unlink mocks base method.
+func (m *MockTagRepository) Unlink(ctx context.Context, transactionIds, tagIds []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlink", ctx, transactionIds, tagIds)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlink indicates an expected call of Unlink.
+func (mr *MockTagRepositoryMockRecorder) Unlink(ctx, transactionIds, tagIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlink", reflect.TypeOf((*MockTagRepository)(nil).Unlink), ctx, transactionIds, tagIds)
+}
+
+// Update mocks base method.
+func (m *MockTagRepository) Update(ctx context.Context, tag *domain.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagRepositoryMockRecorder) Update(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagRepository)(nil).Update), ctx, tag)
+}
+
+// MockTagService is a mock of TagService interface.
+type MockTagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTagServiceMockRecorder is the mock recorder for MockTagService.
+type MockTagServiceMockRecorder struct {
+	mock *MockTagService
+}
+
+// NewMockTagService creates a new mock instance.
+func NewMockTagService(ctrl *gomock.Controller) *MockTagService {
+	mock := &MockTagService{ctrl: ctrl}
+	mock.recorder = &MockTagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagService) EXPECT() *MockTagServiceMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockTagService) Apply(ctx context.Context, userId string, request *model.TagTransactionsRequest) (*model.TagTransactionsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, userId, request)
+	ret0, _ := ret[0].(*model.TagTransactionsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockTagServiceMockRecorder) Apply(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockTagService)(nil).Apply), ctx, userId, request)
+}
+
+// Create mocks base method.
+func (m *MockTagService) Create(ctx context.Context, userId string, request *model.CreateTagRequest) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, request)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagServiceMockRecorder) Create(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTagService)(nil).Create), ctx, userId, request)
+}
+
+// Delete mocks base method.
+func (m *MockTagService) Delete(ctx context.Context, userId, tagId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, tagId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagServiceMockRecorder) Delete(ctx, userId, tagId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagService)(nil).Delete), ctx, userId, tagId)
+}
+
+// GetDetail mocks base method.
+func (m *MockTagService) GetDetail(ctx context.Context, userId, tagId string) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, tagId)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockTagServiceMockRecorder) GetDetail(ctx, userId, tagId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockTagService)(nil).GetDetail), ctx, userId, tagId)
+}
+
+// GetList mocks base method.
+func (m *MockTagService) GetList(ctx context.Context, userId string, request *model.TagListRequest) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId, request)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockTagServiceMockRecorder) GetList(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockTagService)(nil).GetList), ctx, userId, request)
+}
+
+// Update mocks base method.
+func (m *MockTagService) Update(ctx context.Context, userId, tagId string, request *model.UpdateTagRequest) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, tagId, request)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagServiceMockRecorder) Update(ctx, userId, tagId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagService)(nil).Update), ctx, userId, tagId, request)
+}
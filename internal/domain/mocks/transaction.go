@@ -0,0 +1,371 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: transaction.go
+//
+// Generated by this command:
+//
+//	mockgen -source=transaction.go -destination=mocks/transaction.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	model "finance-backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactionCursor is a mock of TransactionCursor interface.
+type MockTransactionCursor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionCursorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionCursorMockRecorder is the mock recorder for MockTransactionCursor.
+type MockTransactionCursorMockRecorder struct {
+	mock *MockTransactionCursor
+}
+
+// NewMockTransactionCursor creates a new mock instance.
+func NewMockTransactionCursor(ctrl *gomock.Controller) *MockTransactionCursor {
+	mock := &MockTransactionCursor{ctrl: ctrl}
+	mock.recorder = &MockTransactionCursorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionCursor) EXPECT() *MockTransactionCursorMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockTransactionCursor) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockTransactionCursorMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockTransactionCursor)(nil).Close))
+}
+
+// Err mocks base method.
+func (m *MockTransactionCursor) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockTransactionCursorMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockTransactionCursor)(nil).Err))
+}
+
+// Next mocks base method.
+func (m *MockTransactionCursor) Next() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockTransactionCursorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockTransactionCursor)(nil).Next))
+}
+
+// Scan mocks base method.
+func (m *MockTransactionCursor) Scan(row *domain.TransactionExportRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", row)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockTransactionCursorMockRecorder) Scan(row any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockTransactionCursor)(nil).Scan), row)
+}
+
+// MockTransactionRepository is a mock of TransactionRepository interface.
+type MockTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionRepositoryMockRecorder is the mock recorder for MockTransactionRepository.
+type MockTransactionRepositoryMockRecorder struct {
+	mock *MockTransactionRepository
+}
+
+// NewMockTransactionRepository creates a new mock instance.
+func NewMockTransactionRepository(ctrl *gomock.Controller) *MockTransactionRepository {
+	mock := &MockTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionRepository) EXPECT() *MockTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransactionRepository) Create(ctx context.Context, userId string, transaction *domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionRepositoryMockRecorder) Create(ctx, userId, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRepository)(nil).Create), ctx, userId, transaction)
+}
+
+// FlowByWallet mocks base method.
+func (m *MockTransactionRepository) FlowByWallet(ctx context.Context, userId string, since int) ([]*domain.WalletFlowRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlowByWallet", ctx, userId, since)
+	ret0, _ := ret[0].([]*domain.WalletFlowRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlowByWallet indicates an expected call of FlowByWallet.
+func (mr *MockTransactionRepositoryMockRecorder) FlowByWallet(ctx, userId, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlowByWallet", reflect.TypeOf((*MockTransactionRepository)(nil).FlowByWallet), ctx, userId, since)
+}
+
+// GetByExternalIDs mocks base method.
+func (m *MockTransactionRepository) GetByExternalIDs(ctx context.Context, walletId string, externalIds []string) ([]*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalIDs", ctx, walletId, externalIds)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalIDs indicates an expected call of GetByExternalIDs.
+func (mr *MockTransactionRepositoryMockRecorder) GetByExternalIDs(ctx, walletId, externalIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalIDs", reflect.TypeOf((*MockTransactionRepository)(nil).GetByExternalIDs), ctx, walletId, externalIds)
+}
+
+// GetByIDs mocks base method.
+func (m *MockTransactionRepository) GetByIDs(ctx context.Context, userId string, ids []string) ([]*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, userId, ids)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockTransactionRepositoryMockRecorder) GetByIDs(ctx, userId, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockTransactionRepository)(nil).GetByIDs), ctx, userId, ids)
+}
+
+// GetByWalletBetween mocks base method.
+func (m *MockTransactionRepository) GetByWalletBetween(ctx context.Context, walletId string, from, to int) ([]*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByWalletBetween", ctx, walletId, from, to)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByWalletBetween indicates an expected call of GetByWalletBetween.
+func (mr *MockTransactionRepositoryMockRecorder) GetByWalletBetween(ctx, walletId, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByWalletBetween", reflect.TypeOf((*MockTransactionRepository)(nil).GetByWalletBetween), ctx, walletId, from, to)
+}
+
+// GetByWalletSince mocks base method.
+func (m *MockTransactionRepository) GetByWalletSince(ctx context.Context, walletId string, since int) ([]*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByWalletSince", ctx, walletId, since)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByWalletSince indicates an expected call of GetByWalletSince.
+func (mr *MockTransactionRepositoryMockRecorder) GetByWalletSince(ctx, walletId, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByWalletSince", reflect.TypeOf((*MockTransactionRepository)(nil).GetByWalletSince), ctx, walletId, since)
+}
+
+// GetDetail mocks base method.
+func (m *MockTransactionRepository) GetDetail(ctx context.Context, userId, transactionId string) (*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, transactionId)
+	ret0, _ := ret[0].(*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockTransactionRepositoryMockRecorder) GetDetail(ctx, userId, transactionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockTransactionRepository)(nil).GetDetail), ctx, userId, transactionId)
+}
+
+// GetList mocks base method.
+func (m *MockTransactionRepository) GetList(ctx context.Context, userId string, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId, filter)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockTransactionRepositoryMockRecorder) GetList(ctx, userId, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockTransactionRepository)(nil).GetList), ctx, userId, filter)
+}
+
+// Stream mocks base method.
+func (m *MockTransactionRepository) Stream(ctx context.Context, userId string, filter domain.TransactionFilter) (domain.TransactionCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, userId, filter)
+	ret0, _ := ret[0].(domain.TransactionCursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockTransactionRepositoryMockRecorder) Stream(ctx, userId, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockTransactionRepository)(nil).Stream), ctx, userId, filter)
+}
+
+// Update mocks base method.
+func (m *MockTransactionRepository) Update(ctx context.Context, transaction *domain.Transaction, version int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, transaction, version)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransactionRepositoryMockRecorder) Update(ctx, transaction, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransactionRepository)(nil).Update), ctx, transaction, version)
+}
+
+// MockTransactionService is a mock of TransactionService interface.
+type MockTransactionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionServiceMockRecorder is the mock recorder for MockTransactionService.
+type MockTransactionServiceMockRecorder struct {
+	mock *MockTransactionService
+}
+
+// NewMockTransactionService creates a new mock instance.
+func NewMockTransactionService(ctrl *gomock.Controller) *MockTransactionService {
+	mock := &MockTransactionService{ctrl: ctrl}
+	mock.recorder = &MockTransactionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionService) EXPECT() *MockTransactionServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransactionService) Create(ctx context.Context, userId string, request *model.CreateTransactionRequest) (*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, request)
+	ret0, _ := ret[0].(*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionServiceMockRecorder) Create(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionService)(nil).Create), ctx, userId, request)
+}
+
+// CreateBatch mocks base method.
+func (m *MockTransactionService) CreateBatch(ctx context.Context, userId string, requests []*model.CreateTransactionRequest) ([]*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, userId, requests)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockTransactionServiceMockRecorder) CreateBatch(ctx, userId, requests any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockTransactionService)(nil).CreateBatch), ctx, userId, requests)
+}
+
+// GetDetail mocks base method.
+func (m *MockTransactionService) GetDetail(ctx context.Context, userId, transactionId string) (*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, transactionId)
+	ret0, _ := ret[0].(*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockTransactionServiceMockRecorder) GetDetail(ctx, userId, transactionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockTransactionService)(nil).GetDetail), ctx, userId, transactionId)
+}
+
+// GetList mocks base method.
+func (m *MockTransactionService) GetList(ctx context.Context, userId string, request *model.TransactionListRequest) ([]*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId, request)
+	ret0, _ := ret[0].([]*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockTransactionServiceMockRecorder) GetList(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockTransactionService)(nil).GetList), ctx, userId, request)
+}
+
+// Update mocks base method.
+func (m *MockTransactionService) Update(ctx context.Context, userId, transactionId string, version int, request *model.UpdateTransactionRequest) (*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, transactionId, version, request)
+	ret0, _ := ret[0].(*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransactionServiceMockRecorder) Update(ctx, userId, transactionId, version, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransactionService)(nil).Update), ctx, userId, transactionId, version, request)
+}
@@ -0,0 +1,230 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: wallet.go
+//
+// Generated by this command:
+//
+//	mockgen -source=wallet.go -destination=mocks/wallet.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "finance-backend/internal/domain"
+	model "finance-backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWalletRepository is a mock of WalletRepository interface.
+type MockWalletRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWalletRepositoryMockRecorder is the mock recorder for MockWalletRepository.
+type MockWalletRepositoryMockRecorder struct {
+	mock *MockWalletRepository
+}
+
+// NewMockWalletRepository creates a new mock instance.
+func NewMockWalletRepository(ctrl *gomock.Controller) *MockWalletRepository {
+	mock := &MockWalletRepository{ctrl: ctrl}
+	mock.recorder = &MockWalletRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletRepository) EXPECT() *MockWalletRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWalletRepository) Create(ctx context.Context, userId string, wallet *domain.Wallet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, wallet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletRepositoryMockRecorder) Create(ctx, userId, wallet any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletRepository)(nil).Create), ctx, userId, wallet)
+}
+
+// DecreaseBalance mocks base method.
+func (m *MockWalletRepository) DecreaseBalance(ctx context.Context, walletId string, amount float64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecreaseBalance", ctx, walletId, amount)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecreaseBalance indicates an expected call of DecreaseBalance.
+func (mr *MockWalletRepositoryMockRecorder) DecreaseBalance(ctx, walletId, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecreaseBalance", reflect.TypeOf((*MockWalletRepository)(nil).DecreaseBalance), ctx, walletId, amount)
+}
+
+// GetAll mocks base method.
+func (m *MockWalletRepository) GetAll(ctx context.Context) ([]*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWalletRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWalletRepository)(nil).GetAll), ctx)
+}
+
+// GetDetail mocks base method.
+func (m *MockWalletRepository) GetDetail(ctx context.Context, userId, walletId string) (*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, walletId)
+	ret0, _ := ret[0].(*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockWalletRepositoryMockRecorder) GetDetail(ctx, userId, walletId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockWalletRepository)(nil).GetDetail), ctx, userId, walletId)
+}
+
+// GetList mocks base method.
+func (m *MockWalletRepository) GetList(ctx context.Context, userId string) ([]*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId)
+	ret0, _ := ret[0].([]*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockWalletRepositoryMockRecorder) GetList(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockWalletRepository)(nil).GetList), ctx, userId)
+}
+
+// IncreaseBalance mocks base method.
+func (m *MockWalletRepository) IncreaseBalance(ctx context.Context, walletId string, amount float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseBalance", ctx, walletId, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncreaseBalance indicates an expected call of IncreaseBalance.
+func (mr *MockWalletRepositoryMockRecorder) IncreaseBalance(ctx, walletId, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseBalance", reflect.TypeOf((*MockWalletRepository)(nil).IncreaseBalance), ctx, walletId, amount)
+}
+
+// Update mocks base method.
+func (m *MockWalletRepository) Update(ctx context.Context, wallet *domain.Wallet, version int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, wallet, version)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWalletRepositoryMockRecorder) Update(ctx, wallet, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWalletRepository)(nil).Update), ctx, wallet, version)
+}
+
+// MockWalletService is a mock of WalletService interface.
+type MockWalletService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWalletServiceMockRecorder is the mock recorder for MockWalletService.
+type MockWalletServiceMockRecorder struct {
+	mock *MockWalletService
+}
+
+// NewMockWalletService creates a new mock instance.
+func NewMockWalletService(ctrl *gomock.Controller) *MockWalletService {
+	mock := &MockWalletService{ctrl: ctrl}
+	mock.recorder = &MockWalletServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWalletService) Create(ctx context.Context, userId string, request *model.CreateWalletRequest) (*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, request)
+	ret0, _ := ret[0].(*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletServiceMockRecorder) Create(ctx, userId, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletService)(nil).Create), ctx, userId, request)
+}
+
+// GetDetail mocks base method.
+func (m *MockWalletService) GetDetail(ctx context.Context, userId, walletId string) (*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, userId, walletId)
+	ret0, _ := ret[0].(*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockWalletServiceMockRecorder) GetDetail(ctx, userId, walletId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockWalletService)(nil).GetDetail), ctx, userId, walletId)
+}
+
+// GetList mocks base method.
+func (m *MockWalletService) GetList(ctx context.Context, userId string) ([]*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userId)
+	ret0, _ := ret[0].([]*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockWalletServiceMockRecorder) GetList(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockWalletService)(nil).GetList), ctx, userId)
+}
+
+// Update mocks base method.
+func (m *MockWalletService) Update(ctx context.Context, userId, walletId string, version int, request *model.UpdateWalletRequest) (*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, walletId, version, request)
+	ret0, _ := ret[0].(*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWalletServiceMockRecorder) Update(ctx, userId, walletId, version, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWalletService)(nil).Update), ctx, userId, walletId, version, request)
+}
@@ -0,0 +1,102 @@
+package domain
+
+//go:generate go run go.uber.org/mock/mockgen -source=report.go -destination=mocks/report.go -package=mocks
+
+import (
+	"context"
+	"finance-backend/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SummaryFilter selects the transactions aggregated into a summary report
+type SummaryFilter struct {
+	// From and To bound transaction_date as unix seconds, From inclusive and To exclusive
+	From     int
+	To       int
+	GroupBy  string
+	Timezone string
+	WalletID string
+	// BudgetID counts only the lines of split transactions that are booked to the budget
+	BudgetID string
+}
+
+// SummaryRow holds the totals of one non-empty bucket
+type SummaryRow struct {
+	// Bucket is the wall-clock start of the bucket in the requested timezone
+	Bucket  time.Time
+	Income  float64
+	Expense float64
+	Count   int
+}
+
+// SpendFilter selects the expenses aggregated into a category report
+type SpendFilter struct {
+	// From and To bound transaction_date as unix seconds, From inclusive and To exclusive
+	From     int
+	To       int
+	WalletID string
+}
+
+// BudgetSpendRow holds the expenses booked against one budget, BudgetID is nil for unbudgeted spend
+type BudgetSpendRow struct {
+	BudgetID   *uuid.UUID
+	BudgetName string
+	Category   string
+	Amount     float64
+	Count      int
+}
+
+// TagSpendRow holds the expenses carrying one tag, an expense with several tags counts for each
+type TagSpendRow struct {
+	TagID  uuid.UUID
+	Name   string
+	Amount float64
+	Count  int
+}
+
+// NoteSpendRow holds the expenses sharing one note, compared case-insensitively
+type NoteSpendRow struct {
+	Note   string
+	Amount float64
+	Count  int
+}
+
+// FlowFilter selects the transactions a forecast learns from
+type FlowFilter struct {
+	// From and To bound transaction_date as unix seconds, From inclusive and To exclusive
+	From     int
+	To       int
+	WalletID string
+}
+
+// FlowRow is one past transaction with the category of its budget, empty when unbudgeted.
+// A split transaction comes as one row per line with the line's amount and category.
+type FlowRow struct {
+	WalletID        uuid.UUID
+	Type            string
+	Amount          float64
+	Note            string
+	Category        string
+	TransactionDate int
+	// TransactionID is set on the lines of a split transaction, which follow each other
+	TransactionID *uuid.UUID
+}
+
+type ReportRepository interface {
+	Summary(ctx context.Context, userId string, filter SummaryFilter) ([]*SummaryRow, error)
+	// SpendByBudget counts split expenses by their lines, each line is counted as an expense of its budget
+	SpendByBudget(ctx context.Context, userId string, filter SpendFilter) ([]*BudgetSpendRow, error)
+	SpendByTag(ctx context.Context, userId string, filter SpendFilter) ([]*TagSpendRow, error)
+	TopNotes(ctx context.Context, userId string, filter SpendFilter, limit int) ([]*NoteSpendRow, error)
+	// FlowHistory returns the user's live transactions in the filter, oldest first, split ones line by line
+	FlowHistory(ctx context.Context, userId string, filter FlowFilter) ([]*FlowRow, error)
+}
+
+type ReportService interface {
+	Summary(ctx context.Context, userId string, request *model.SummaryReportRequest) (*model.SummaryReport, error)
+	Categories(ctx context.Context, userId string, request *model.CategoryReportRequest) (*model.CategoryReport, error)
+	NetWorth(ctx context.Context, userId string, request *model.NetWorthReportRequest) (*model.NetWorthReport, error)
+	Forecast(ctx context.Context, userId string, request *model.ForecastRequest) (*model.ForecastReport, error)
+}
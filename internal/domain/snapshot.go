@@ -0,0 +1,64 @@
+package domain
+
+//go:generate go run go.uber.org/mock/mockgen -source=snapshot.go -destination=mocks/snapshot.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WalletSnapshot is a wallet's balance at the end of a UTC day
+type WalletSnapshot struct {
+	WalletID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	// SnapshotDate is the unix time of the day's UTC midnight
+	SnapshotDate int     `gorm:"primaryKey"`
+	Balance      float64 `gorm:"type:decimal(15,2);not null"`
+
+	CreatedAt int
+	UpdatedAt int
+
+	Wallet Wallet `gorm:"foreignKey:WalletID;references:ID"`
+}
+
+// ExchangeRate is the value of one unit of Currency in the base currency from RateDate on
+type ExchangeRate struct {
+	Currency string  `gorm:"type:varchar(10);primaryKey"`
+	RateDate int     `gorm:"primaryKey"`
+	Rate     float64 `gorm:"type:decimal(20,10);not null;check:rate > 0"`
+
+	CreatedAt int
+	UpdatedAt int
+}
+
+func (WalletSnapshot) TableName() string {
+	return "wallet_balance_snapshots"
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+type SnapshotRepository interface {
+	// Capture records the current balance of every wallet for date and returns how many were written
+	Capture(ctx context.Context, date int) (int64, error)
+	Upsert(ctx context.Context, snapshots []*WalletSnapshot) error
+	// GetRange returns the user's snapshots in [from, to) plus the latest one before from for each wallet
+	GetRange(ctx context.Context, userId string, from, to int) ([]*WalletSnapshot, error)
+}
+
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rate *ExchangeRate) error
+	// GetUntil returns every rate for currencies effective before to, oldest first
+	GetUntil(ctx context.Context, currencies []string, to int) ([]*ExchangeRate, error)
+}
+
+type SnapshotService interface {
+	// Capture records the current balances as the end of the UTC day before now, so it belongs just
+	// after midnight. It is safe to run more than once a day.
+	Capture(ctx context.Context, now time.Time) (int64, error)
+	// Rebuild replays transactions backwards from the current balances to fill in every day since from
+	Rebuild(ctx context.Context, from time.Time, now time.Time) (int, error)
+	SetRate(ctx context.Context, currency string, date time.Time, rate float64) error
+}
@@ -0,0 +1,76 @@
+package domain
+
+//go:generate go run go.uber.org/mock/mockgen -source=tag.go -destination=mocks/tag.go -package=mocks
+
+import (
+	"context"
+	"finance-backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/plugin/soft_delete"
+)
+
+// Tag is a free-form label put on transactions across wallets and budgets.
+// It names its owner itself, unlike wallets, so names can be unique per user.
+type Tag struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tags_user_name,priority:1,where:deleted_at = 0"`
+	// Name is stored lowercase so tags match whatever case they are typed in
+	Name string `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_user_name,priority:2,where:deleted_at = 0"`
+
+	CreatedAt int
+	UpdatedAt int
+	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano;not null;default:0;index"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+// TransactionTag puts a tag on a transaction
+type TransactionTag struct {
+	TransactionID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagID         uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+
+	CreatedAt int
+
+	Transaction Transaction `gorm:"foreignKey:TransactionID;references:ID"`
+	Tag         Tag         `gorm:"foreignKey:TagID;references:ID"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+func (TransactionTag) TableName() string {
+	return "transaction_tags"
+}
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *Tag) error
+	// GetList returns the user's tags starting with prefix by name, at most limit of them
+	GetList(ctx context.Context, userId string, prefix string, limit int) ([]*Tag, error)
+	GetDetail(ctx context.Context, userId string, tagId string) (*Tag, error)
+	GetByName(ctx context.Context, userId string, name string) (*Tag, error)
+	// GetByIDs returns the user's live tags with any of the ids
+	GetByIDs(ctx context.Context, userId string, ids []string) ([]*Tag, error)
+	Update(ctx context.Context, tag *Tag) error
+	// Delete takes the tag off its transactions and deletes it
+	Delete(ctx context.Context, tag *Tag) error
+	// Link puts every tag on every transaction and returns how many were not there yet.
+	// Transactions that gain a tag move to a new version.
+	Link(ctx context.Context, transactionIds []string, tagIds []string) (int64, error)
+	// Unlink takes every tag off every transaction and returns how many were there.
+	// Transactions that lose a tag move to a new version.
+	Unlink(ctx context.Context, transactionIds []string, tagIds []string) (int64, error)
+}
+
+type TagService interface {
+	Create(ctx context.Context, userId string, request *model.CreateTagRequest) (*Tag, error)
+	// GetList suggests the user's tags starting with the requested prefix
+	GetList(ctx context.Context, userId string, request *model.TagListRequest) ([]*Tag, error)
+	GetDetail(ctx context.Context, userId string, tagId string) (*Tag, error)
+	Update(ctx context.Context, userId string, tagId string, request *model.UpdateTagRequest) (*Tag, error)
+	Delete(ctx context.Context, userId string, tagId string) error
+	// Apply adds and removes tags on transactions in a single unit of work
+	Apply(ctx context.Context, userId string, request *model.TagTransactionsRequest) (*model.TagTransactionsResult, error)
+}
@@ -1,36 +1,63 @@
 package domain
 
+//go:generate go run go.uber.org/mock/mockgen -source=transaction.go -destination=mocks/transaction.go -package=mocks
+
 import (
 	"context"
 	"finance-backend/internal/model"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 	"gorm.io/plugin/soft_delete"
 )
 
 type Transaction struct {
-	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid()"`
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 
 	Amount          float64 `gorm:"type:decimal(15,2);not null;check:amount > 0"`
-	Type            string  `gorm:"type:varchar(50);not null"` // e.g., income, expense, transfer
-	Note            string  `gorm:"type:varchar(255)"`
-	TransactionDate int     `gorm:"not null"`
+	Type            string  `gorm:"type:varchar(100);not null"` // e.g., income, expense, transfer
+	Note            string  `gorm:"type:text;index"`
+	TransactionDate int     `gorm:"not null;index"`
+
+	// Version counts the changes to the transaction and is served as its ETag
+	Version int `gorm:"type:integer;not null;default:1"`
 
 	CreatedAt int
 	UpdatedAt int
-	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano"`
+	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano;not null;default:0;index"`
+
+	WalletID uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_transactions_wallet_external_id,priority:1,where:external_id IS NOT NULL AND deleted_at = 0"`
+	BudgetID *uuid.UUID `gorm:"type:uuid;index"`
+
+	// ExternalID is the bank's id for an imported transaction, such as an OFX FITID, unique per wallet
+	ExternalID *string `gorm:"type:varchar(255);uniqueIndex:idx_transactions_wallet_external_id,priority:2,where:external_id IS NOT NULL AND deleted_at = 0"`
+
+	Wallet Wallet             `gorm:"foreignKey:WalletID;references:ID"`
+	Budget *Budget            `gorm:"foreignKey:BudgetID;references:ID"`
+	Tags   []Tag              `gorm:"many2many:transaction_tags"`
+	Splits []TransactionSplit `gorm:"foreignKey:TransactionID;references:ID"`
+}
+
+// TransactionSplit is one line of a transaction split across budgets, the lines add up to the transaction's amount.
+// Budget spend is counted per line while the wallet balance moves once for the whole transaction,
+// which has no budget of its own.
+type TransactionSplit struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_transaction_splits_position,priority:1"`
+	// Position keeps the lines in the order they were given
+	Position int `gorm:"type:integer;not null;uniqueIndex:idx_transaction_splits_position,priority:2"`
 
-	WalletID uuid.UUID  `gorm:"type:uuid;not null"`
-	BudgetID *uuid.UUID `gorm:"type:uuid"`
+	Amount float64 `gorm:"type:decimal(15,2);not null;check:amount > 0"`
+	Note   string  `gorm:"type:text"`
 
-	Wallet Wallet  `gorm:"foreignKey:WalletID;references:ID"`
-	Budget *Budget `gorm:"foreignKey:BudgetID;references:ID"`
+	CreatedAt int
+
+	BudgetID *uuid.UUID `gorm:"type:uuid;index"`
+	Budget   *Budget    `gorm:"foreignKey:BudgetID;references:ID"`
 }
 
 type HasTransaction struct {
-	UserID        uuid.UUID   `gorm:"type:uuid;primaryKey"`
-	TransactionID uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	UserID        uuid.UUID   `gorm:"type:uuid;primaryKey;index"`
+	TransactionID uuid.UUID   `gorm:"type:uuid;primaryKey;index"`
 	User          User        `gorm:"foreignKey:UserID;references:ID"`
 	Transaction   Transaction `gorm:"foreignKey:TransactionID;references:ID"`
 }
@@ -43,13 +70,89 @@ func (HasTransaction) TableName() string {
 	return "has_transactions"
 }
 
+func (TransactionSplit) TableName() string {
+	return "transaction_splits"
+}
+
+// TransactionFilter selects the transactions listed or exported, empty fields match every transaction
+type TransactionFilter struct {
+	// From and To bound transaction_date as unix seconds, From inclusive and To exclusive, zero leaves them open
+	From     int
+	To       int
+	Type     string
+	WalletID string
+	// BudgetID keeps the transactions booked to the budget, split ones when any of their lines is
+	BudgetID string
+	// TagIDs keeps the transactions carrying every one of the tags, the ids must be distinct
+	TagIDs []string
+}
+
+// TransactionExportRow is a transaction labelled with its wallet and budget, as read by a cursor.
+// A split transaction is read as one row per line, in line order, each with the amount and budget of its line.
+type TransactionExportRow struct {
+	ID              uuid.UUID
+	TransactionDate int
+	Type            string
+	Amount          float64
+	Note            string
+	// SplitNote is the note of the line, empty for transactions that are not split
+	SplitNote string
+	// ExternalID is empty for transactions that were not imported
+	ExternalID string
+	WalletID   uuid.UUID
+	WalletName string
+	WalletType string
+	Currency   string
+	// BudgetName and Category are empty for unbudgeted transactions
+	BudgetName string
+	Category   string
+}
+
+// WalletFlowRow sums the transactions of one wallet, income counted positive and expenses negative
+type WalletFlowRow struct {
+	WalletID uuid.UUID
+	Net      float64
+	// FirstDate is the transaction_date of the wallet's earliest transaction
+	FirstDate int
+}
+
+// TransactionCursor reads transactions one at a time from an open database cursor.
+// It holds a connection until it is closed.
+type TransactionCursor interface {
+	Next() bool
+	Scan(row *TransactionExportRow) error
+	Err() error
+	Close() error
+}
+
 type TransactionRepository interface {
-	Create(db *gorm.DB, ctx context.Context, userId string, transaction *Transaction) error
-	GetDetail(db *gorm.DB, ctx context.Context, userId string, transactionId string) (*Transaction, error)
-	GetList(db *gorm.DB, ctx context.Context, userId string) ([]*Transaction, error)
+	Create(ctx context.Context, userId string, transaction *Transaction) error
+	GetDetail(ctx context.Context, userId string, transactionId string) (*Transaction, error)
+	GetList(ctx context.Context, userId string, filter TransactionFilter) ([]*Transaction, error)
+	// Update saves the transaction if it is still at version and bumps the version, it reports false when it is not.
+	// The transaction's split lines replace the ones it had.
+	Update(ctx context.Context, transaction *Transaction, version int) (bool, error)
+	// Stream opens a cursor over the matching transactions, oldest first
+	Stream(ctx context.Context, userId string, filter TransactionFilter) (TransactionCursor, error)
+	// GetByIDs returns the user's live transactions with any of the ids
+	GetByIDs(ctx context.Context, userId string, ids []string) ([]*Transaction, error)
+	// FlowByWallet sums the user's transactions dated from since on, per wallet
+	FlowByWallet(ctx context.Context, userId string, since int) ([]*WalletFlowRow, error)
+	// GetByWalletSince returns the wallet's live transactions dated from since on, newest first
+	GetByWalletSince(ctx context.Context, walletId string, since int) ([]*Transaction, error)
+	// GetByWalletBetween returns the wallet's live transactions dated in [from, to), oldest first
+	GetByWalletBetween(ctx context.Context, walletId string, from, to int) ([]*Transaction, error)
+	// GetByExternalIDs returns the wallet's live transactions imported with any of the external ids
+	GetByExternalIDs(ctx context.Context, walletId string, externalIds []string) ([]*Transaction, error)
 }
 
 type TransactionService interface {
 	Create(ctx context.Context, userId string, request *model.CreateTransactionRequest) (*Transaction, error)
-	GetList(ctx context.Context, userId string) ([]*Transaction, error)
+	// CreateBatch books every request in a single unit of work, none are kept if one fails
+	CreateBatch(ctx context.Context, userId string, requests []*model.CreateTransactionRequest) ([]*Transaction, error)
+	GetList(ctx context.Context, userId string, request *model.TransactionListRequest) ([]*Transaction, error)
+	GetDetail(ctx context.Context, userId string, transactionId string) (*Transaction, error)
+	// Update replaces the transaction if it is still at version, zero skips the check.
+	// The old amount is taken off its wallet and the new one booked in the same unit of work.
+	Update(ctx context.Context, userId string, transactionId string, version int, request *model.UpdateTransactionRequest) (*Transaction, error)
 }
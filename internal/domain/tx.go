@@ -0,0 +1,16 @@
+package domain
+
+//go:generate go run go.uber.org/mock/mockgen -source=tx.go -destination=mocks/tx.go -package=mocks
+
+import (
+	"context"
+)
+
+// TxManager runs units of work inside a database transaction
+type TxManager interface {
+	// WithinTx runs fn in a transaction carried by the ctx passed to fn, which
+	// repositories pick up automatically. It commits when fn returns nil and
+	// rolls back when fn returns an error or panics. Nested calls run in a
+	// savepoint so an inner failure only undoes the inner work.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
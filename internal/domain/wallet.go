@@ -1,35 +1,45 @@
 package domain
 
+//go:generate go run go.uber.org/mock/mockgen -source=wallet.go -destination=mocks/wallet.go -package=mocks
+
 import (
 	"context"
+	"finance-backend/internal/constant"
 	"finance-backend/internal/model"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 	"gorm.io/plugin/soft_delete"
 )
 
 type Wallet struct {
-	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid()"`
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 
-	Name     string  `gorm:"type:varchar(100);not null"`
-	Type     string  `gorm:"type:varchar(50);not null"`
+	Name     string  `gorm:"type:varchar(255);not null"`
+	Type     string  `gorm:"type:varchar(100);not null"`
 	Currency string  `gorm:"type:varchar(10);not null"`
 	Balance  float64 `gorm:"type:decimal(15,2);not null;check:balance >= 0"`
 
+	// Version counts the changes to the wallet, its balance included, and is served as its ETag
+	Version int `gorm:"type:integer;not null;default:1"`
+
 	CreatedAt int
 	UpdatedAt int
-	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano"`
+	DeletedAt soft_delete.DeletedAt `gorm:"softDelete:nano;not null;default:0;index"`
 }
 
 type HasWallet struct {
-	UserID   uuid.UUID `gorm:"type:uuid;primaryKey"`
-	WalletID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID   uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	WalletID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
 
 	User   User   `gorm:"foreignKey:UserID;references:ID"`
 	Wallet Wallet `gorm:"foreignKey:WalletID;references:ID"`
 }
 
+// IsLiability reports whether the balance is owed rather than held
+func (w Wallet) IsLiability() bool {
+	return w.Type == constant.WalletTypeCredit || w.Type == constant.WalletTypeLoan
+}
+
 func (Wallet) TableName() string {
 	return "wallets"
 }
@@ -39,13 +49,21 @@ func (HasWallet) TableName() string {
 }
 
 type WalletRepository interface {
-	Create(db *gorm.DB, ctx context.Context, userId string, wallet *Wallet) error
-	GetList(db *gorm.DB, ctx context.Context, userId string) ([]*Wallet, error)
-	DecreaseBalance(db *gorm.DB, ctx context.Context, walletId string, amount float64) error
-	IncreaseBalance(db *gorm.DB, ctx context.Context, walletId string, amount float64) error
+	Create(ctx context.Context, userId string, wallet *Wallet) error
+	GetList(ctx context.Context, userId string) ([]*Wallet, error)
+	GetDetail(ctx context.Context, userId string, walletId string) (*Wallet, error)
+	// GetAll returns every live wallet regardless of owner, for background jobs
+	GetAll(ctx context.Context) ([]*Wallet, error)
+	// Update saves the wallet's name if it is still at version and bumps the version, it reports false when it is not
+	Update(ctx context.Context, wallet *Wallet, version int) (bool, error)
+	DecreaseBalance(ctx context.Context, walletId string, amount float64) (bool, error)
+	IncreaseBalance(ctx context.Context, walletId string, amount float64) error
 }
 
 type WalletService interface {
 	Create(ctx context.Context, userId string, request *model.CreateWalletRequest) (*Wallet, error)
 	GetList(ctx context.Context, userId string) ([]*Wallet, error)
+	GetDetail(ctx context.Context, userId string, walletId string) (*Wallet, error)
+	// Update renames the wallet if it is still at version, zero skips the check
+	Update(ctx context.Context, userId string, walletId string, version int, request *model.UpdateWalletRequest) (*Wallet, error)
 }
@@ -0,0 +1,67 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// csvFlushRows is how many rows are buffered before they are sent on
+const csvFlushRows = 500
+
+type csvWriter struct {
+	out     io.Writer
+	csv     *csv.Writer
+	printer *message.Printer
+	rows    int
+}
+
+// NewCSV writes rows as CSV with amounts formatted for locale. Locales that write a decimal
+// comma get semicolon separated columns, as spreadsheets there expect.
+func NewCSV(w io.Writer, locale language.Tag) Writer {
+	printer := message.NewPrinter(locale)
+
+	writer := csv.NewWriter(w)
+	if strings.Contains(printer.Sprint(number.Decimal(0.5, number.Scale(1))), ",") {
+		writer.Comma = ';'
+	}
+	// A failed header write surfaces from the next Write or Close
+	_ = writer.Write(header)
+
+	return &csvWriter{out: w, csv: writer, printer: printer}
+}
+
+func (w *csvWriter) Write(row Row) error {
+	err := w.csv.Write([]string{
+		row.Date.Format("2006-01-02"),
+		row.Type,
+		w.printer.Sprint(number.Decimal(row.Amount, number.Scale(2))),
+		row.Currency,
+		row.Wallet,
+		row.Budget,
+		row.Category,
+		row.Note,
+		row.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if w.rows++; w.rows%csvFlushRows == 0 {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+		return flush(w.out)
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,61 @@
+// Package exporter writes transactions to spreadsheet and data files one row at a time,
+// so an export never holds more than the row being written.
+package exporter
+
+import (
+	"io"
+	"time"
+)
+
+// Formats a transaction export can be written in
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatXLSX  = "xlsx"
+)
+
+// Row is one exported transaction. Amount is signed, expenses are negative.
+type Row struct {
+	ID       string
+	Date     time.Time
+	Type     string
+	Amount   float64
+	Currency string
+	Wallet   string
+	Budget   string
+	Category string
+	Note     string
+}
+
+// Writer writes rows to a file, Close completes the file and must be called once all rows are written
+type Writer interface {
+	Write(row Row) error
+	Close() error
+}
+
+// header names the columns of the tabular formats
+var header = []string{"Date", "Type", "Amount", "Currency", "Wallet", "Budget", "Category", "Note", "ID"}
+
+// ContentType returns the media type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// flusher is implemented by writers that buffer, such as the bufio.Writer of a streamed response
+type flusher interface {
+	Flush() error
+}
+
+// flush pushes what was written so far to the client
+func flush(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
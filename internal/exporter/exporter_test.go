@@ -0,0 +1,190 @@
+package exporter_test
+
+import (
+	"bytes"
+	"finance-backend/internal/exporter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/language"
+)
+
+var rows = []exporter.Row{
+	{ID: "t1", Date: time.Date(2025, 8, 1, 23, 30, 0, 0, time.FixedZone("WIB", 7*3600)), Type: "income", Amount: 1234.5, Currency: "EUR", Wallet: "Giro", Note: "Gehalt"},
+	{ID: "t2", Date: time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC), Type: "expense", Amount: -4.5, Currency: "EUR", Wallet: "Giro", Budget: "Food", Category: "Groceries", Note: `Bäcker "Korn"`},
+}
+
+func write(t *testing.T, writer exporter.Writer) {
+	t.Helper()
+
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		locale language.Tag
+		want   string
+	}{
+		{
+			locale: language.AmericanEnglish,
+			want: "Date,Type,Amount,Currency,Wallet,Budget,Category,Note,ID\n" +
+				"2025-08-01,income,\"1,234.50\",EUR,Giro,,,Gehalt,t1\n" +
+				"2025-08-02,expense,-4.50,EUR,Giro,Food,Groceries,\"Bäcker \"\"Korn\"\"\",t2\n",
+		},
+		{
+			locale: language.German,
+			want: "Date;Type;Amount;Currency;Wallet;Budget;Category;Note;ID\n" +
+				"2025-08-01;income;1.234,50;EUR;Giro;;;Gehalt;t1\n" +
+				"2025-08-02;expense;-4,50;EUR;Giro;Food;Groceries;\"Bäcker \"\"Korn\"\"\";t2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale.String(), func(t *testing.T) {
+			var out bytes.Buffer
+			write(t, exporter.NewCSV(&out, tt.locale))
+			if out.String() != tt.want {
+				t.Errorf("NewCSV() wrote\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	var out bytes.Buffer
+	write(t, exporter.NewJSONL(&out))
+
+	want := `{"id":"t1","date":"2025-08-01","type":"income","amount":1234.5,"currency":"EUR","wallet":"Giro","note":"Gehalt"}` + "\n" +
+		`{"id":"t2","date":"2025-08-02","type":"expense","amount":-4.5,"currency":"EUR","wallet":"Giro","budget":"Food","category":"Groceries","note":"Bäcker \"Korn\""}` + "\n"
+	if out.String() != want {
+		t.Errorf("NewJSONL() wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := exporter.NewXLSX(&out)
+	if err != nil {
+		t.Fatalf("NewXLSX() error = %v", err)
+	}
+	write(t, writer)
+
+	file, err := excelize.OpenReader(&out)
+	if err != nil {
+		t.Fatalf("failed to open workbook: %v", err)
+	}
+	defer file.Close()
+
+	sheet, err := file.GetRows("Transactions", excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatalf("GetRows() error = %v", err)
+	}
+	if len(sheet) != 3 || strings.Join(sheet[0], ",") != "Date,Type,Amount,Currency,Wallet,Budget,Category,Note,ID" {
+		t.Fatalf("sheet = %v, want a header and two rows", sheet)
+	}
+
+	// 45870 is 1 August 2025 as an Excel serial date, the local day is kept
+	if sheet[1][0] != "45870" || sheet[1][2] != "1234.5" || sheet[2][2] != "-4.5" || sheet[2][7] != `Bäcker "Korn"` {
+		t.Errorf("rows = %v, want numeric dates and amounts", sheet[1:])
+	}
+}
+
+func TestLedger(t *testing.T) {
+	entries := []exporter.Entry{
+		{
+			Date:      time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+			Narration: "Opening balance",
+			Postings: []exporter.Posting{
+				{Account: "Assets:Giro", Amount: 100, Currency: "EUR"},
+				{Account: exporter.OpeningBalancesAccount, Amount: -100, Currency: "EUR"},
+			},
+		},
+		{
+			Date:      time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+			Narration: "Bäcker \"Korn\";\nfresh",
+			ID:        "t2",
+			Postings: []exporter.Posting{
+				{Account: "Expenses:Food:Groceries", Amount: 4.5, Currency: "EUR"},
+				{Account: "Assets:Giro", Amount: -4.5, Currency: "EUR"},
+			},
+		},
+	}
+
+	tests := []struct {
+		syntax string
+		want   string
+	}{
+		{
+			syntax: exporter.SyntaxBeancount,
+			want: "2025-08-01 open Assets:Giro EUR\n" +
+				"2025-08-01 open Equity:Opening-Balances EUR\n\n" +
+				"2025-08-01 * \"Opening balance\"\n" +
+				"    Assets:Giro                    100.00 EUR\n" +
+				"    Equity:Opening-Balances       -100.00 EUR\n\n" +
+				"2025-08-02 open Expenses:Food:Groceries EUR\n\n" +
+				"2025-08-02 * \"Bäcker \\\"Korn\\\"; fresh\"\n" +
+				"    id: \"t2\"\n" +
+				"    Expenses:Food:Groceries          4.50 EUR\n" +
+				"    Assets:Giro                     -4.50 EUR\n\n",
+		},
+		{
+			syntax: exporter.SyntaxLedger,
+			want: "account Assets:Giro\n" +
+				"account Equity:Opening-Balances\n\n" +
+				"2025-08-01 * Opening balance\n" +
+				"    Assets:Giro                    100.00 EUR\n" +
+				"    Equity:Opening-Balances       -100.00 EUR\n\n" +
+				"account Expenses:Food:Groceries\n\n" +
+				"2025-08-02 * Bäcker \"Korn\", fresh\n" +
+				"    ; id: t2\n" +
+				"    Expenses:Food:Groceries          4.50 EUR\n" +
+				"    Assets:Giro                     -4.50 EUR\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.syntax, func(t *testing.T) {
+			var out bytes.Buffer
+			writer := exporter.NewLedger(&out, tt.syntax)
+			for _, entry := range entries {
+				if err := writer.Write(entry); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("NewLedger() wrote\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountName(t *testing.T) {
+	tests := []struct {
+		components []string
+		want       string
+	}{
+		{components: []string{"BCA"}, want: "Assets:BCA"},
+		{components: []string{"food", "Weekly groceries"}, want: "Assets:Food:Weekly-groceries"},
+		{components: []string{"  Kartu (kredit) / Mandiri! "}, want: "Assets:Kartu-kredit-Mandiri"},
+		{components: []string{"ümit's café"}, want: "Assets:Ümit-s-café"},
+		{components: []string{"!!!"}, want: "Assets:Unnamed"},
+	}
+
+	for _, tt := range tests {
+		if got := exporter.AccountName(exporter.RootAssets, tt.components...); got != tt.want {
+			t.Errorf("AccountName(%q) = %q, want %q", tt.components, got, tt.want)
+		}
+	}
+}
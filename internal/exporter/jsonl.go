@@ -0,0 +1,59 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonlFlushRows is how many rows are buffered before they are sent on
+const jsonlFlushRows = 500
+
+// jsonlRow is the JSON object written for each row, amounts stay plain numbers
+type jsonlRow struct {
+	ID       string  `json:"id"`
+	Date     string  `json:"date"`
+	Type     string  `json:"type"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	Wallet   string  `json:"wallet"`
+	Budget   string  `json:"budget,omitempty"`
+	Category string  `json:"category,omitempty"`
+	Note     string  `json:"note"`
+}
+
+type jsonlWriter struct {
+	out     io.Writer
+	encoder *json.Encoder
+	rows    int
+}
+
+// NewJSONL writes one JSON object per line. Data files are read by programs, so numbers are not localised.
+func NewJSONL(w io.Writer) Writer {
+	return &jsonlWriter{out: w, encoder: json.NewEncoder(w)}
+}
+
+func (w *jsonlWriter) Write(row Row) error {
+	err := w.encoder.Encode(jsonlRow{
+		ID:       row.ID,
+		Date:     row.Date.Format("2006-01-02"),
+		Type:     row.Type,
+		Amount:   row.Amount,
+		Currency: row.Currency,
+		Wallet:   row.Wallet,
+		Budget:   row.Budget,
+		Category: row.Category,
+		Note:     row.Note,
+	})
+	if err != nil {
+		return err
+	}
+
+	if w.rows++; w.rows%jsonlFlushRows == 0 {
+		return flush(w.out)
+	}
+	return nil
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,152 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Plain-text accounting syntaxes a ledger export can be written in
+const (
+	SyntaxLedger    = "ledger"
+	SyntaxBeancount = "beancount"
+)
+
+// Account roots shared by Ledger and Beancount
+const (
+	RootAssets      = "Assets"
+	RootLiabilities = "Liabilities"
+	RootIncome      = "Income"
+	RootExpenses    = "Expenses"
+	RootEquity      = "Equity"
+)
+
+// OpeningBalancesAccount is the counterpart of the balance a wallet had before its first entry
+const OpeningBalancesAccount = RootEquity + ":Opening-Balances"
+
+// UncategorizedComponent names the income and expense account of transactions without a budget
+const UncategorizedComponent = "Uncategorized"
+
+// Entry is a balanced ledger transaction, the amounts of its postings sum to zero
+type Entry struct {
+	Date      time.Time
+	Narration string
+	// ID is written as metadata so an import can recognise the entry again
+	ID       string
+	Postings []Posting
+}
+
+// Posting moves Amount in Currency on Account, positive amounts are debits
+type Posting struct {
+	Account  string
+	Amount   float64
+	Currency string
+}
+
+// LedgerWriter writes entries in Ledger or Beancount syntax. Accounts are declared the first time
+// they are used, so entries must be written oldest first.
+type LedgerWriter struct {
+	out    io.Writer
+	syntax string
+	opened map[string]bool
+	rows   int
+}
+
+// NewLedger writes entries in syntax, SyntaxLedger output is also read by hledger
+func NewLedger(w io.Writer, syntax string) *LedgerWriter {
+	return &LedgerWriter{out: w, syntax: syntax, opened: make(map[string]bool)}
+}
+
+func (l *LedgerWriter) Write(entry Entry) error {
+	var b strings.Builder
+
+	date := entry.Date.Format("2006-01-02")
+	// Both syntaxes end the header at the line break, notes can span several lines
+	narration := strings.Join(strings.Fields(entry.Narration), " ")
+	for _, posting := range entry.Postings {
+		if l.opened[posting.Account] {
+			continue
+		}
+		l.opened[posting.Account] = true
+
+		if l.syntax == SyntaxBeancount {
+			fmt.Fprintf(&b, "%s open %s %s\n", date, posting.Account, posting.Currency)
+		} else {
+			fmt.Fprintf(&b, "account %s\n", posting.Account)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteByte('\n')
+	}
+
+	if l.syntax == SyntaxBeancount {
+		fmt.Fprintf(&b, "%s * %s\n", date, strconv.Quote(narration))
+		if entry.ID != "" {
+			fmt.Fprintf(&b, "    id: %s\n", strconv.Quote(entry.ID))
+		}
+	} else {
+		fmt.Fprintf(&b, "%s * %s\n", date, strings.ReplaceAll(narration, ";", ","))
+		if entry.ID != "" {
+			fmt.Fprintf(&b, "    ; id: %s\n", entry.ID)
+		}
+	}
+
+	width := 0
+	for _, posting := range entry.Postings {
+		width = max(width, len(posting.Account))
+	}
+	for _, posting := range entry.Postings {
+		fmt.Fprintf(&b, "    %-*s  %12s %s\n", width, posting.Account, strconv.FormatFloat(posting.Amount, 'f', 2, 64), posting.Currency)
+	}
+	b.WriteByte('\n')
+
+	if _, err := io.WriteString(l.out, b.String()); err != nil {
+		return err
+	}
+
+	if l.rows++; l.rows%csvFlushRows == 0 {
+		return flush(l.out)
+	}
+	return nil
+}
+
+func (l *LedgerWriter) Close() error {
+	return nil
+}
+
+// AccountName joins components into an account name both syntaxes accept. Each component keeps its
+// letters and digits, starts with a capital and has dashes where it had spaces or punctuation.
+func AccountName(root string, components ...string) string {
+	name := root
+	for _, component := range components {
+		name += ":" + AccountComponent(component)
+	}
+	return name
+}
+
+// AccountComponent turns a wallet, budget or category name into one account name component
+func AccountComponent(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		} else {
+			dash = true
+		}
+	}
+
+	component := []rune(b.String())
+	if len(component) == 0 {
+		return "Unnamed"
+	}
+	component[0] = unicode.ToUpper(component[0])
+	return string(component)
+}
@@ -0,0 +1,90 @@
+package exporter
+
+import (
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Built-in Excel number formats, shown with the separators and date order of the reader's locale
+const (
+	xlsxFormatDate   = 14 // short date
+	xlsxFormatAmount = 4  // #,##0.00
+)
+
+const xlsxSheet = "Transactions"
+
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	date   int
+	amount int
+	row    int
+}
+
+// NewXLSX writes rows to a worksheet. Dates and amounts are stored as numbers with built-in formats,
+// so the spreadsheet shows them in the reader's locale. The workbook is sent on Close.
+func NewXLSX(w io.Writer) (Writer, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return nil, err
+	}
+
+	date, err := file.NewStyle(&excelize.Style{NumFmt: xlsxFormatDate})
+	if err != nil {
+		return nil, err
+	}
+	amount, err := file.NewStyle(&excelize.Style{NumFmt: xlsxFormatAmount})
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]interface{}, len(header))
+	for i, title := range header {
+		titles[i] = title
+	}
+	if err := stream.SetRow("A1", titles, excelize.RowOpts{}); err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{out: w, file: file, stream: stream, date: date, amount: amount, row: 1}, nil
+}
+
+func (w *xlsxWriter) Write(row Row) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	// Excel dates have no timezone, the local calendar day is kept as is
+	day := time.Date(row.Date.Year(), row.Date.Month(), row.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+	return w.stream.SetRow(cell, []interface{}{
+		excelize.Cell{StyleID: w.date, Value: day},
+		row.Type,
+		excelize.Cell{StyleID: w.amount, Value: row.Amount},
+		row.Currency,
+		row.Wallet,
+		row.Budget,
+		row.Category,
+		row.Note,
+		row.ID,
+	})
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.out)
+}
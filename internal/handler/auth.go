@@ -4,6 +4,7 @@ import (
 	"finance-backend/internal/domain"
 	"finance-backend/internal/model"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,22 +20,18 @@ func NewAuthHandler(authService domain.AuthService) *AuthHandler {
 }
 
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	var req model.RegisterRequest
-	if err := c.BodyParser(&req); err != nil {
+	if err := validator.ParseBody(c, &req); err != nil {
 		log.WithError(err).Error("[handler]: Failed to parse registration request body")
-		return c.Status(fiber.StatusBadRequest).JSON(model.NewResponseError("invalid request body"))
+		return err
 	}
 
-	user, session, err := h.authService.Register(c.Context(), req.Fullname, req.Email, req.Password)
+	user, session, err := h.authService.Register(c.UserContext(), req.Fullname, req.Email, req.Password)
 	if err != nil {
-		if err.Error() == "user already exists" {
-			return c.Status(fiber.StatusConflict).JSON(model.NewResponseError("user already exists"))
-		}
-
 		log.WithError(err).Error("[handler]: Failed to register user")
-		return c.Status(fiber.StatusInternalServerError).JSON(model.NewResponseError("failed to register user"))
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(
@@ -53,22 +50,18 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 }
 
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	var req model.LoginRequest
-	if err := c.BodyParser(&req); err != nil {
+	if err := validator.ParseBody(c, &req); err != nil {
 		log.WithError(err).Error("[handler]: Failed to parse login request body")
-		return c.Status(fiber.StatusBadRequest).JSON(model.NewResponseError("invalid request body"))
+		return err
 	}
 
-	user, session, err := h.authService.Login(c.Context(), req.Email, req.Password)
+	user, session, err := h.authService.Login(c.UserContext(), req.Email, req.Password)
 	if err != nil {
-		if err.Error() == "invalid email or password" {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.NewResponseError("invalid email or password"))
-		}
-
 		log.WithError(err).Error("[handler]: Failed to login user")
-		return c.Status(fiber.StatusInternalServerError).JSON(model.NewResponseError("failed to login user"))
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(
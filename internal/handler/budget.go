@@ -4,6 +4,7 @@ import (
 	"finance-backend/internal/domain"
 	"finance-backend/internal/model"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,56 +20,107 @@ func NewBudgetHandler(budgetService domain.BudgetService) *BudgetHandler {
 }
 
 func (h *BudgetHandler) Create(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	userId := c.Locals("userId").(string)
 
 	var request model.CreateBudgetRequest
-	if err := c.BodyParser(&request); err != nil {
+	if err := validator.ParseBody(c, &request); err != nil {
 		log.WithError(err).Error("[handler - budget - Create]: Failed to parse create budget request body")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
-	budget, err := h.budgetService.Create(c.Context(), userId, &request)
+	budget, err := h.budgetService.Create(c.UserContext(), userId, &request)
 	if err != nil {
 		log.WithError(err).Error("[handler - budget - Create]: Failed to create budget")
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(model.Budget{
-		ID:        budget.ID.String(),
-		Name:      budget.Name,
-		Amount:    budget.Amount,
-		Type:      budget.Type,
-		Category:  budget.Category,
-		CreatedAt: int(budget.CreatedAt),
-		UpdatedAt: int(budget.UpdatedAt),
-	}))
+	setETag(c, budget.Version)
+	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(toBudget(budget)))
 }
 
 func (h *BudgetHandler) GetList(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	userId := c.Locals("userId").(string)
 
-	budgets, err := h.budgetService.GetList(c.Context(), userId)
+	budgets, err := h.budgetService.GetList(c.UserContext(), userId)
 	if err != nil {
 		log.WithError(err).Error("[handler - budget - GetList]: Failed to get budget list")
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	var response []model.Budget
 	for _, budget := range budgets {
-		response = append(response, model.Budget{
-			ID:        budget.ID.String(),
-			Name:      budget.Name,
-			Amount:    budget.Amount,
-			Type:      budget.Type,
-			Category:  budget.Category,
-			CreatedAt: int(budget.CreatedAt),
-			UpdatedAt: int(budget.UpdatedAt),
-		})
+		response = append(response, toBudget(budget))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(response))
 }
+
+func (h *BudgetHandler) GetDetail(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	budgetId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	budget, err := h.budgetService.GetDetail(c.UserContext(), userId, budgetId)
+	if err != nil {
+		log.WithError(err).Error("[handler - budget - GetDetail]: Failed to get budget")
+		return err
+	}
+
+	if notModified(c, budget.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toBudget(budget)))
+}
+
+func (h *BudgetHandler) Update(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	budgetId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	var request model.UpdateBudgetRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - budget - Update]: Failed to parse update budget request body")
+		return err
+	}
+
+	budget, err := h.budgetService.Update(c.UserContext(), userId, budgetId, version, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - budget - Update]: Failed to update budget")
+		return err
+	}
+
+	setETag(c, budget.Version)
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toBudget(budget)))
+}
+
+func toBudget(budget *domain.Budget) model.Budget {
+	return model.Budget{
+		ID:        budget.ID.String(),
+		Name:      budget.Name,
+		Amount:    budget.Amount,
+		Type:      budget.Type,
+		Category:  budget.Category,
+		Version:   budget.Version,
+		CreatedAt: int(budget.CreatedAt),
+		UpdatedAt: int(budget.UpdatedAt),
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bufio"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExportHandler struct {
+	exportService domain.ExportService
+}
+
+func NewExportHandler(exportService domain.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+func (h *ExportHandler) Transactions(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.ExportTransactionsRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - export - Transactions]: Failed to parse export query")
+		return err
+	}
+	if request.Locale == "" {
+		request.Locale = c.Get(fiber.HeaderAcceptLanguage)
+	}
+
+	export, err := h.exportService.Transactions(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - export - Transactions]: Failed to export transactions")
+		return err
+	}
+
+	return stream(c, export)
+}
+
+func (h *ExportHandler) Ledger(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.ExportLedgerRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - export - Ledger]: Failed to parse export query")
+		return err
+	}
+
+	export, err := h.exportService.Ledger(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - export - Ledger]: Failed to export ledger")
+		return err
+	}
+
+	return stream(c, export)
+}
+
+// stream sends an export as a download, rows are written to the connection as they are read
+func stream(c *fiber.Ctx, export *domain.Export) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	c.Set(fiber.HeaderContentType, export.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", export.Filename))
+
+	// The status is already sent when rows are written, a failure can only cut the file short
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := export.Write(w); err != nil {
+			log.WithError(err).Error("[handler - export - stream]: Failed to stream export")
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	importService domain.ImportService
+}
+
+func NewImportHandler(importService domain.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+func (h *ImportHandler) CreateProfile(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.CreateImportProfileRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - import - CreateProfile]: Failed to parse create import profile request body")
+		return err
+	}
+
+	profile, err := h.importService.CreateProfile(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - import - CreateProfile]: Failed to create import profile")
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(importProfile(profile)))
+}
+
+func (h *ImportHandler) GetProfiles(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	profiles, err := h.importService.GetProfiles(c.UserContext(), userId)
+	if err != nil {
+		log.WithError(err).Error("[handler - import - GetProfiles]: Failed to get import profiles")
+		return err
+	}
+
+	response := make([]model.ImportProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		response = append(response, importProfile(profile))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(response))
+}
+
+func (h *ImportHandler) ImportCSV(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.ImportCSVRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - import - ImportCSV]: Failed to parse import request form")
+		return err
+	}
+
+	file, err := openUpload(c)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportCSV(c.UserContext(), userId, &request, file)
+	if err != nil {
+		log.WithError(err).Error("[handler - import - ImportCSV]: Failed to import statement")
+		return err
+	}
+
+	return c.Status(importStatus(result)).JSON(model.NewResponseSuccess(result))
+}
+
+func (h *ImportHandler) ImportOFX(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.ImportStatementRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - import - ImportOFX]: Failed to parse import request form")
+		return err
+	}
+
+	file, err := openUpload(c)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportOFX(c.UserContext(), userId, &request, file)
+	if err != nil {
+		log.WithError(err).Error("[handler - import - ImportOFX]: Failed to import statement")
+		return err
+	}
+
+	return c.Status(importStatus(result)).JSON(model.NewResponseSuccess(result))
+}
+
+func (h *ImportHandler) ImportQIF(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.ImportStatementRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - import - ImportQIF]: Failed to parse import request form")
+		return err
+	}
+
+	file, err := openUpload(c)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportQIF(c.UserContext(), userId, &request, file)
+	if err != nil {
+		log.WithError(err).Error("[handler - import - ImportQIF]: Failed to import statement")
+		return err
+	}
+
+	return c.Status(importStatus(result)).JSON(model.NewResponseSuccess(result))
+}
+
+func (h *ImportHandler) ImportBeancount(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.ImportLedgerRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - import - ImportBeancount]: Failed to parse import request form")
+		return err
+	}
+
+	file, err := openUpload(c)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportBeancount(c.UserContext(), userId, &request, file)
+	if err != nil {
+		log.WithError(err).Error("[handler - import - ImportBeancount]: Failed to import ledger")
+		return err
+	}
+
+	return c.Status(importStatus(result)).JSON(model.NewResponseSuccess(result))
+}
+
+// openUpload opens the statement sent in the file field of a multipart form
+func openUpload(c *fiber.Ctx) (multipart.File, error) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, apperror.NewValidation([]model.FieldError{{Field: "file", Rule: "required", Message: "is required"}})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		logger.WithRequestID(c.UserContext()).WithError(err).Error("[handler - import - openUpload]: Failed to open uploaded file")
+		return nil, apperror.ErrBadRequest.WithMessage("failed to read the uploaded file").Wrap(err)
+	}
+
+	return file, nil
+}
+
+// importStatus is 201 when rows were booked and 200 for a preview
+func importStatus(result *model.ImportResult) int {
+	if result.DryRun {
+		return fiber.StatusOK
+	}
+	return fiber.StatusCreated
+}
+
+func importProfile(profile *domain.ImportProfile) model.ImportProfile {
+	return model.ImportProfile{
+		ID:               profile.ID.String(),
+		Name:             profile.Name,
+		Delimiter:        profile.Delimiter,
+		HasHeader:        profile.HasHeader,
+		DateColumn:       profile.DateColumn,
+		DateFormat:       profile.DateFormat,
+		Timezone:         profile.Timezone,
+		AmountColumn:     profile.AmountColumn,
+		DebitColumn:      profile.DebitColumn,
+		CreditColumn:     profile.CreditColumn,
+		NoteColumn:       profile.NoteColumn,
+		AmountSign:       profile.AmountSign,
+		DecimalSeparator: profile.DecimalSeparator,
+		CreatedAt:        profile.CreatedAt,
+		UpdatedAt:        profile.UpdatedAt,
+	}
+}
@@ -19,16 +19,14 @@ func NewProfileHandler(authService domain.AuthService) *ProfileHandler {
 }
 
 func (h *ProfileHandler) GetProfile(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	token := c.Locals("token").(string)
 
-	user, err := h.authService.GetUserByToken(c.Context(), token)
+	user, err := h.authService.GetUserByToken(c.UserContext(), token)
 	if err != nil {
 		log.WithError(err).Error("[handler]: Failed to get user profile")
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			model.NewResponseError("failed to get user profile"),
-		)
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
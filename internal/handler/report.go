@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportHandler struct {
+	reportService domain.ReportService
+}
+
+func NewReportHandler(reportService domain.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+func (h *ReportHandler) Summary(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.SummaryReportRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - report - Summary]: Failed to parse summary report query")
+		return err
+	}
+
+	report, err := h.reportService.Summary(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - report - Summary]: Failed to build summary report")
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(report))
+}
+
+func (h *ReportHandler) Categories(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.CategoryReportRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - report - Categories]: Failed to parse category report query")
+		return err
+	}
+
+	report, err := h.reportService.Categories(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - report - Categories]: Failed to build category report")
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(report))
+}
+
+func (h *ReportHandler) NetWorth(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.NetWorthReportRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - report - NetWorth]: Failed to parse net worth report query")
+		return err
+	}
+
+	report, err := h.reportService.NetWorth(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - report - NetWorth]: Failed to build net worth report")
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(report))
+}
+
+func (h *ReportHandler) Forecast(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.ForecastRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - report - Forecast]: Failed to parse forecast query")
+		return err
+	}
+
+	report, err := h.reportService.Forecast(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - report - Forecast]: Failed to build forecast")
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(report))
+}
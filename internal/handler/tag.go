@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TagHandler struct {
+	tagService domain.TagService
+}
+
+func NewTagHandler(tagService domain.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+func (h *TagHandler) Create(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.CreateTagRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - tag - Create]: Failed to parse create tag request body")
+		return err
+	}
+
+	tag, err := h.tagService.Create(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - tag - Create]: Failed to create tag")
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(toTag(tag)))
+}
+
+func (h *TagHandler) GetList(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.TagListRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - tag - GetList]: Failed to parse tag list query")
+		return err
+	}
+
+	tags, err := h.tagService.GetList(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - tag - GetList]: Failed to get tag list")
+		return err
+	}
+
+	var response []model.Tag
+	for _, tag := range tags {
+		response = append(response, toTag(tag))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(response))
+}
+
+func (h *TagHandler) GetDetail(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	tagId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	tag, err := h.tagService.GetDetail(c.UserContext(), userId, tagId)
+	if err != nil {
+		log.WithError(err).Error("[handler - tag - GetDetail]: Failed to get tag")
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toTag(tag)))
+}
+
+func (h *TagHandler) Update(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	tagId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var request model.UpdateTagRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - tag - Update]: Failed to parse update tag request body")
+		return err
+	}
+
+	tag, err := h.tagService.Update(c.UserContext(), userId, tagId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - tag - Update]: Failed to update tag")
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toTag(tag)))
+}
+
+func (h *TagHandler) Delete(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	tagId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.tagService.Delete(c.UserContext(), userId, tagId); err != nil {
+		log.WithError(err).Error("[handler - tag - Delete]: Failed to delete tag")
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Apply adds and removes tags on many transactions at once
+func (h *TagHandler) Apply(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	var request model.TagTransactionsRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - tag - Apply]: Failed to parse tag transactions request body")
+		return err
+	}
+
+	result, err := h.tagService.Apply(c.UserContext(), userId, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - tag - Apply]: Failed to tag transactions")
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(result))
+}
+
+func toTag(tag *domain.Tag) model.Tag {
+	return model.Tag{
+		ID:        tag.ID.String(),
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"finance-backend/internal/domain"
 	"finance-backend/internal/model"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,69 +20,148 @@ func NewTransactionHandler(transactionService domain.TransactionService) *Transa
 }
 
 func (h *TransactionHandler) Create(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	userId := c.Locals("userId").(string)
 
 	var request model.CreateTransactionRequest
-	if err := c.BodyParser(&request); err != nil {
+	if err := validator.ParseBody(c, &request); err != nil {
 		log.WithError(err).Error("[handler - transaction - Create]: Failed to parse create transaction request body")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
-	transaction, err := h.transactionService.Create(c.Context(), userId, &request)
+	transaction, err := h.transactionService.Create(c.UserContext(), userId, &request)
 	if err != nil {
 		log.WithError(err).Error("[handler - transaction - Create]: Failed to create transaction")
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(model.Transaction{
-		ID:              transaction.ID.String(),
-		Amount:          transaction.Amount,
-		Type:            transaction.Type,
-		TransactionDate: transaction.TransactionDate,
-		Note:            transaction.Note,
-		Wallet: model.TransactionWallet{
-			ID:   transaction.Wallet.ID.String(),
-			Name: transaction.Wallet.Name,
-		},
-	}))
+	setETag(c, transaction.Version)
+	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(toTransaction(transaction)))
 }
 
 func (h *TransactionHandler) GetList(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	userId := c.Locals("userId").(string)
 
-	transactions, err := h.transactionService.GetList(c.Context(), userId)
+	var request model.TransactionListRequest
+	if err := validator.ParseQuery(c, &request); err != nil {
+		log.WithError(err).Error("[handler - transaction - GetList]: Failed to parse transaction list query")
+		return err
+	}
+
+	transactions, err := h.transactionService.GetList(c.UserContext(), userId, &request)
 	if err != nil {
 		log.WithError(err).Error("[handler - transaction - GetList]: Failed to get transaction list")
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	var response []model.Transaction
 	for _, t := range transactions {
-		tr := model.Transaction{
-			ID:              t.ID.String(),
-			Amount:          t.Amount,
-			Type:            t.Type,
-			TransactionDate: t.TransactionDate,
-			Note:            t.Note,
-			Wallet: model.TransactionWallet{
-				ID:   t.Wallet.ID.String(),
-				Name: t.Wallet.Name,
-			},
+		response = append(response, toTransaction(t))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(response))
+}
+
+func (h *TransactionHandler) GetDetail(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	transactionId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	transaction, err := h.transactionService.GetDetail(c.UserContext(), userId, transactionId)
+	if err != nil {
+		log.WithError(err).Error("[handler - transaction - GetDetail]: Failed to get transaction")
+		return err
+	}
+
+	if notModified(c, transaction.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toTransaction(transaction)))
+}
+
+func (h *TransactionHandler) Update(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	transactionId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	var request model.UpdateTransactionRequest
+	if err := validator.ParseBody(c, &request); err != nil {
+		log.WithError(err).Error("[handler - transaction - Update]: Failed to parse update transaction request body")
+		return err
+	}
+
+	transaction, err := h.transactionService.Update(c.UserContext(), userId, transactionId, version, &request)
+	if err != nil {
+		log.WithError(err).Error("[handler - transaction - Update]: Failed to update transaction")
+		return err
+	}
+
+	setETag(c, transaction.Version)
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toTransaction(transaction)))
+}
+
+func toTransaction(t *domain.Transaction) model.Transaction {
+	transaction := model.Transaction{
+		ID:              t.ID.String(),
+		Amount:          t.Amount,
+		Type:            t.Type,
+		TransactionDate: t.TransactionDate,
+		Note:            t.Note,
+		Version:         t.Version,
+		Wallet: model.TransactionWallet{
+			ID:   t.Wallet.ID.String(),
+			Name: t.Wallet.Name,
+		},
+	}
+
+	if t.Budget != nil {
+		transaction.Budget = &model.TransactionBudget{
+			ID:   t.Budget.ID.String(),
+			Name: t.Budget.Name,
 		}
+	}
 
-		if t.Budget != nil {
-			tr.Budget = &model.TransactionBudget{
-				ID:   t.Budget.ID.String(),
-				Name: t.Budget.Name,
+	transaction.Tags = make([]model.TransactionTag, 0, len(t.Tags))
+	for _, tag := range t.Tags {
+		transaction.Tags = append(transaction.Tags, model.TransactionTag{
+			ID:   tag.ID.String(),
+			Name: tag.Name,
+		})
+	}
+
+	transaction.Splits = make([]model.TransactionSplit, 0, len(t.Splits))
+	for _, split := range t.Splits {
+		line := model.TransactionSplit{
+			ID:     split.ID.String(),
+			Amount: split.Amount,
+			Note:   split.Note,
+		}
+		if split.Budget != nil {
+			line.Budget = &model.TransactionBudget{
+				ID:   split.Budget.ID.String(),
+				Name: split.Budget.Name,
 			}
 		}
-
-		response = append(response, tr)
+		transaction.Splits = append(transaction.Splits, line)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(response))
+	return transaction
 }
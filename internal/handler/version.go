@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"finance-backend/pkg/apperror"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// pathID reads a resource id from the path, ids that are not uuids cannot name any resource
+func pathID(c *fiber.Ctx, name string) (string, error) {
+	id, err := uuid.Parse(c.Params(name))
+	if err != nil {
+		return "", apperror.ErrNotFound
+	}
+	return id.String(), nil
+}
+
+// setETag labels the response with the version of the resource it shows
+func setETag(c *fiber.Ctx, version int) {
+	c.Set(fiber.HeaderETag, strconv.Quote(strconv.Itoa(version)))
+}
+
+// notModified sets the ETag of a resource and reports whether the client's If-None-Match already has it
+func notModified(c *fiber.Ctx, version int) bool {
+	setETag(c, version)
+	return c.Fresh()
+}
+
+// ifMatch reads the version an update was made against from the If-Match header, zero for If-Match: *.
+// Only a single strong ETag as served by setETag can match, weak ones never do.
+func ifMatch(c *fiber.Ctx) (int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return 0, apperror.ErrPreconditionNeeded
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return 0, apperror.ErrPreconditionFailed.WithMessage("If-Match must be a single ETag of the resource")
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, apperror.ErrPreconditionFailed.WithMessage("If-Match must be a single ETag of the resource")
+	}
+
+	return version, nil
+}
@@ -4,6 +4,7 @@ import (
 	"finance-backend/internal/domain"
 	"finance-backend/internal/model"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,58 +20,107 @@ func NewWalletHandler(walletService domain.WalletService) *WalletHandler {
 }
 
 func (h *WalletHandler) Create(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	userId := c.Locals("userId").(string)
 
 	var req model.CreateWalletRequest
-	if err := c.BodyParser(&req); err != nil {
+	if err := validator.ParseBody(c, &req); err != nil {
 		log.WithError(err).Error("[handler - wallet - Create]: Failed to parse create wallet request body")
-		return c.Status(fiber.StatusBadRequest).JSON(model.NewResponseError("invalid request"))
+		return err
 	}
 
-	wallet, err := h.walletService.Create(c.Context(), userId, &req)
+	wallet, err := h.walletService.Create(c.UserContext(), userId, &req)
 	if err != nil {
 		log.WithError(err).Error("[handler - wallet - Create]: Failed to create wallet")
-		return c.Status(fiber.StatusInternalServerError).JSON(model.NewResponseError("failed to create wallet"))
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(
-		model.Wallet{
-			ID:        wallet.ID.String(),
-			Name:      wallet.Name,
-			Type:      wallet.Type,
-			Currency:  wallet.Currency,
-			Balance:   wallet.Balance,
-			CreatedAt: int(wallet.CreatedAt),
-			UpdatedAt: int(wallet.UpdatedAt),
-		},
-	))
+	setETag(c, wallet.Version)
+	return c.Status(fiber.StatusCreated).JSON(model.NewResponseSuccess(toWallet(wallet)))
 }
 
 func (h *WalletHandler) GetList(c *fiber.Ctx) error {
-	log := logger.WithRequestID(c.Context())
+	log := logger.WithRequestID(c.UserContext())
 
 	userId := c.Locals("userId").(string)
 
-	wallets, err := h.walletService.GetList(c.Context(), userId)
+	wallets, err := h.walletService.GetList(c.UserContext(), userId)
 	if err != nil {
 		log.WithError(err).Error("[handler - wallet - GetList]: Failed to get wallet list")
-		return c.Status(fiber.StatusInternalServerError).JSON(model.NewResponseError("failed to get wallet list"))
+		return err
 	}
 
 	var response []model.Wallet
 	for _, wallet := range wallets {
-		response = append(response, model.Wallet{
-			ID:        wallet.ID.String(),
-			Name:      wallet.Name,
-			Type:      wallet.Type,
-			Currency:  wallet.Currency,
-			Balance:   wallet.Balance,
-			CreatedAt: int(wallet.CreatedAt),
-			UpdatedAt: int(wallet.UpdatedAt),
-		})
+		response = append(response, toWallet(wallet))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(response))
 }
+
+func (h *WalletHandler) GetDetail(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	walletId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	wallet, err := h.walletService.GetDetail(c.UserContext(), userId, walletId)
+	if err != nil {
+		log.WithError(err).Error("[handler - wallet - GetDetail]: Failed to get wallet")
+		return err
+	}
+
+	if notModified(c, wallet.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toWallet(wallet)))
+}
+
+func (h *WalletHandler) Update(c *fiber.Ctx) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	userId := c.Locals("userId").(string)
+
+	walletId, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.UpdateWalletRequest
+	if err := validator.ParseBody(c, &req); err != nil {
+		log.WithError(err).Error("[handler - wallet - Update]: Failed to parse update wallet request body")
+		return err
+	}
+
+	wallet, err := h.walletService.Update(c.UserContext(), userId, walletId, version, &req)
+	if err != nil {
+		log.WithError(err).Error("[handler - wallet - Update]: Failed to update wallet")
+		return err
+	}
+
+	setETag(c, wallet.Version)
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(toWallet(wallet)))
+}
+
+func toWallet(wallet *domain.Wallet) model.Wallet {
+	return model.Wallet{
+		ID:        wallet.ID.String(),
+		Name:      wallet.Name,
+		Type:      wallet.Type,
+		Currency:  wallet.Currency,
+		Balance:   wallet.Balance,
+		Version:   wallet.Version,
+		CreatedAt: int(wallet.CreatedAt),
+		UpdatedAt: int(wallet.UpdatedAt),
+	}
+}
@@ -0,0 +1,286 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Ledger is the content of a plain-text accounting file: the accounts it opens and its transactions
+type Ledger struct {
+	Opens   []Open
+	Entries []LedgerEntry
+}
+
+// Open declares an account, Currencies is empty when the file does not restrict them
+type Open struct {
+	Account    string
+	Date       int
+	Currencies []string
+}
+
+// LedgerEntry is one balanced transaction of a ledger file, Error is set when it could not be read
+type LedgerEntry struct {
+	Line int
+	Date int
+	Note string
+	// ID is the id metadata of the transaction, empty when it has none
+	ID       string
+	Postings []Posting
+	Error    string
+}
+
+// Posting moves Amount in Currency on Account, positive amounts are debits
+type Posting struct {
+	Account  string
+	Amount   float64
+	Currency string
+}
+
+// ledgerPosting is a posting as written, the amount of at most one posting may be left out
+type ledgerPosting struct {
+	Posting
+	elided bool
+}
+
+// ParseBeancount reads the open directives and transactions of a Beancount file, at most limit
+// transactions. Dates are midnight in loc. Other directives, options and comments are skipped,
+// and so are the org-mode headings Beancount files are often organised with.
+func ParseBeancount(r io.Reader, loc *time.Location, limit int) (*Ledger, error) {
+	scanner := bufio.NewScanner(r)
+
+	ledger := &Ledger{}
+	var entry *LedgerEntry
+	var postings []ledgerPosting
+
+	finish := func() {
+		if entry == nil {
+			return
+		}
+		if entry.Error == "" {
+			if err := balance(entry, postings); err != nil {
+				entry.Error = err.Error()
+			}
+		}
+		ledger.Entries = append(ledger.Entries, *entry)
+		entry, postings = nil, nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed[0] == ';' {
+			continue
+		}
+
+		// Indented lines belong to the transaction above them
+		if text[0] == ' ' || text[0] == '\t' {
+			if entry == nil || entry.Error != "" {
+				continue
+			}
+			if key, value, ok := beancountMeta(trimmed); ok {
+				if key == "id" && entry.ID == "" {
+					entry.ID = value
+				}
+				continue
+			}
+
+			posting, err := parsePosting(trimmed)
+			if err != nil {
+				entry.Error = err.Error()
+				continue
+			}
+			postings = append(postings, posting)
+			continue
+		}
+
+		finish()
+
+		fields := beancountFields(trimmed)
+		if len(fields) < 2 || !isBeancountDate(fields[0]) {
+			continue
+		}
+
+		date, err := time.ParseInLocation("2006-01-02", fields[0], loc)
+		switch fields[1] {
+		case "open":
+			if err != nil || len(fields) < 3 {
+				continue
+			}
+			open := Open{Account: fields[2], Date: int(date.Unix())}
+			if len(fields) > 3 && !strings.HasPrefix(fields[3], `"`) {
+				for _, currency := range strings.Split(strings.Join(fields[3:], ""), ",") {
+					if currency = strings.Trim(currency, `"`); currency != "" {
+						open.Currencies = append(open.Currencies, currency)
+					}
+				}
+			}
+			ledger.Opens = append(ledger.Opens, open)
+		case "*", "!", "txn":
+			if len(ledger.Entries) == limit {
+				return nil, ErrTooManyRows
+			}
+
+			entry = &LedgerEntry{Line: line}
+			if err != nil {
+				entry.Error = fmt.Sprintf("date %q is not a valid date", fields[0])
+				continue
+			}
+			entry.Date = int(date.Unix())
+
+			var texts []string
+			for _, field := range fields[2:] {
+				if unquoted, err := strconv.Unquote(field); err == nil {
+					texts = append(texts, unquoted)
+				}
+			}
+			switch len(texts) {
+			case 0:
+			case 1:
+				entry.Note = CleanNote(texts[0])
+			default:
+				entry.Note = joinNote(texts[0], texts[1])
+			}
+		}
+	}
+	finish()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(ledger.Entries) == 0 {
+		return nil, errors.New("no transactions found")
+	}
+
+	return ledger, nil
+}
+
+// parsePosting reads an account with an optional amount and currency
+func parsePosting(text string) (ledgerPosting, error) {
+	if i := strings.Index(text, ";"); i >= 0 {
+		text = text[:i]
+	}
+	if strings.ContainsAny(text, "{@") {
+		return ledgerPosting{}, errors.New("prices and costs are not supported")
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) > 0 && (fields[0] == "*" || fields[0] == "!") {
+		fields = fields[1:]
+	}
+
+	switch len(fields) {
+	case 1:
+		return ledgerPosting{Posting: Posting{Account: fields[0]}, elided: true}, nil
+	case 3:
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(fields[1], ",", ""), 64)
+		if err != nil {
+			return ledgerPosting{}, fmt.Errorf("amount %q is not a number", fields[1])
+		}
+		return ledgerPosting{Posting: Posting{Account: fields[0], Amount: amount, Currency: fields[2]}}, nil
+	}
+	return ledgerPosting{}, fmt.Errorf("posting %q is not an account with an amount and currency", strings.TrimSpace(text))
+}
+
+// balance fills in a left out amount and checks that the postings of entry sum to zero
+func balance(entry *LedgerEntry, postings []ledgerPosting) error {
+	if len(postings) < 2 {
+		return errors.New("a transaction needs at least two postings")
+	}
+
+	elided := -1
+	sums := make(map[string]float64)
+	var currencies []string
+	for i, posting := range postings {
+		if posting.elided {
+			if elided >= 0 {
+				return errors.New("only one posting may leave out its amount")
+			}
+			elided = i
+			continue
+		}
+		if _, ok := sums[posting.Currency]; !ok {
+			currencies = append(currencies, posting.Currency)
+		}
+		sums[posting.Currency] += posting.Amount
+	}
+
+	if elided >= 0 {
+		if len(currencies) != 1 {
+			return errors.New("the left out amount is ambiguous across currencies")
+		}
+		postings[elided].Amount = -sums[currencies[0]]
+		postings[elided].Currency = currencies[0]
+	} else {
+		for _, currency := range currencies {
+			if math.Abs(sums[currency]) >= 0.005 {
+				return fmt.Errorf("postings do not balance, %s is off by %.2f", currency, sums[currency])
+			}
+		}
+	}
+
+	entry.Postings = make([]Posting, len(postings))
+	for i, posting := range postings {
+		entry.Postings[i] = posting.Posting
+	}
+	return nil
+}
+
+// beancountMeta reads a key: value metadata line, keys start with a lowercase letter unlike accounts
+func beancountMeta(text string) (string, string, bool) {
+	key, value, ok := strings.Cut(text, ":")
+	if !ok || key == "" || !unicode.IsLower(rune(key[0])) || strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	return key, value, true
+}
+
+// beancountFields splits a directive on spaces, keeping quoted strings whole and dropping a trailing comment
+func beancountFields(text string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted, escaped := false, false
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && r == ';':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+			}
+			return fields
+		case !quoted && unicode.IsSpace(r):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+			continue
+		}
+		field.WriteRune(r)
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+
+	return fields
+}
+
+func isBeancountDate(field string) bool {
+	return len(field) == 10 && field[4] == '-' && field[7] == '-' && unicode.IsDigit(rune(field[0]))
+}
@@ -0,0 +1,151 @@
+package importer_test
+
+import (
+	"bytes"
+	"errors"
+	"finance-backend/internal/exporter"
+	"finance-backend/internal/importer"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBeancount(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+
+	file := `option "title" "Household"
+* Accounts
+2025-08-01 open Assets:Bank:BCA IDR, USD
+2025-08-01 open Expenses:Food
+2025-08-01 commodity IDR
+
+* Transactions
+2025-08-01 * "ACME" "Salary" #work
+  id: "pay-1"
+  Assets:Bank:BCA   5,000,000.00 IDR ; gross
+  Income:Salary
+
+2025-08-02 txn "Market"
+  Expenses:Food          150000 IDR
+  * Assets:Bank:BCA     -150000 IDR
+2025-08-03 balance Assets:Bank:BCA 4850000 IDR
+
+2025-08-04 ! "Unbalanced"
+  Expenses:Food      10 IDR
+  Assets:Bank:BCA   -11 IDR
+
+2025-08-05 * "Shares"
+  Assets:Broker     1 ACME {100 USD}
+  Assets:Bank:BCA
+
+2025-13-01 * "Bad date"
+  Expenses:Food      10 IDR
+  Assets:Bank:BCA
+`
+
+	ledger, err := importer.ParseBeancount(strings.NewReader(file), jakarta, 10)
+	if err != nil {
+		t.Fatalf("ParseBeancount() error = %v", err)
+	}
+
+	wantOpens := []importer.Open{
+		{Account: "Assets:Bank:BCA", Date: 1753981200, Currencies: []string{"IDR", "USD"}},
+		{Account: "Expenses:Food", Date: 1753981200},
+	}
+	if !reflect.DeepEqual(ledger.Opens, wantOpens) {
+		t.Errorf("Opens = %+v, want %+v", ledger.Opens, wantOpens)
+	}
+
+	wantEntries := []importer.LedgerEntry{
+		{
+			Line: 8, Date: 1753981200, Note: "ACME Salary", ID: "pay-1",
+			Postings: []importer.Posting{
+				{Account: "Assets:Bank:BCA", Amount: 5000000, Currency: "IDR"},
+				{Account: "Income:Salary", Amount: -5000000, Currency: "IDR"},
+			},
+		},
+		{
+			Line: 13, Date: 1754067600, Note: "Market",
+			Postings: []importer.Posting{
+				{Account: "Expenses:Food", Amount: 150000, Currency: "IDR"},
+				{Account: "Assets:Bank:BCA", Amount: -150000, Currency: "IDR"},
+			},
+		},
+		{Line: 18, Date: 1754240400, Note: "Unbalanced", Error: "postings do not balance, IDR is off by -1.00"},
+		{Line: 22, Date: 1754326800, Note: "Shares", Error: "prices and costs are not supported"},
+		{Line: 26, Note: "", Error: `date "2025-13-01" is not a valid date`},
+	}
+	if len(ledger.Entries) != len(wantEntries) {
+		t.Fatalf("ParseBeancount() returned %d entries, want %d: %+v", len(ledger.Entries), len(wantEntries), ledger.Entries)
+	}
+	for i, want := range wantEntries {
+		if !reflect.DeepEqual(ledger.Entries[i], want) {
+			t.Errorf("entry %d = %+v, want %+v", i, ledger.Entries[i], want)
+		}
+	}
+}
+
+func TestParseBeancountErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "no transactions", file: "2025-08-01 open Assets:Cash IDR\n", wantErr: "no transactions found"},
+		{
+			name:    "too many transactions",
+			file:    "2025-08-01 * \"a\"\n  Assets:Cash 1 IDR\n  Income:Gift\n2025-08-02 * \"b\"\n  Assets:Cash 1 IDR\n  Income:Gift\n",
+			wantErr: importer.ErrTooManyRows.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.ParseBeancount(strings.NewReader(tt.file), time.UTC, 1)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("ParseBeancount() error = %v, want %s", err, tt.wantErr)
+			}
+			if tt.wantErr == importer.ErrTooManyRows.Error() && !errors.Is(err, importer.ErrTooManyRows) {
+				t.Errorf("ParseBeancount() error = %v, want ErrTooManyRows", err)
+			}
+		})
+	}
+}
+
+// The importer reads back what the exporter writes
+func TestParseBeancountExport(t *testing.T) {
+	var out bytes.Buffer
+	writer := exporter.NewLedger(&out, exporter.SyntaxBeancount)
+	entry := exporter.Entry{
+		Date:      time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+		Narration: `Bäcker "Korn"`,
+		ID:        "3f1c2d4e-0000-4000-8000-000000000001",
+		Postings: []exporter.Posting{
+			{Account: "Expenses:Food:Groceries", Amount: 4.5, Currency: "EUR"},
+			{Account: "Liabilities:Visa", Amount: -4.5, Currency: "EUR"},
+		},
+	}
+	if err := writer.Write(entry); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	ledger, err := importer.ParseBeancount(&out, time.UTC, 10)
+	if err != nil {
+		t.Fatalf("ParseBeancount() error = %v", err)
+	}
+
+	want := importer.LedgerEntry{
+		Line: 4, Date: 1754092800, Note: `Bäcker "Korn"`, ID: entry.ID,
+		Postings: []importer.Posting{
+			{Account: "Expenses:Food:Groceries", Amount: 4.5, Currency: "EUR"},
+			{Account: "Liabilities:Visa", Amount: -4.5, Currency: "EUR"},
+		},
+	}
+	if len(ledger.Entries) != 1 || !reflect.DeepEqual(ledger.Entries[0], want) {
+		t.Errorf("Entries = %+v, want %+v", ledger.Entries, want)
+	}
+	if len(ledger.Opens) != 2 {
+		t.Errorf("Opens = %+v, want both accounts", ledger.Opens)
+	}
+}
@@ -0,0 +1,256 @@
+// Package importer turns bank statement files into entries ready to be booked as transactions.
+//
+// Parsers never fail on a single bad row, they report it on the entry so the whole file
+// can be previewed before anything is saved.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"finance-backend/internal/constant"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxNoteLength matches the limit on notes entered through the API
+const maxNoteLength = 255
+
+// ErrTooManyRows is returned when a file holds more rows than a single import may book
+var ErrTooManyRows = errors.New("too many rows")
+
+// Entry is one statement line, Error is set when it could not be read.
+// Line is the row in the file, or the position of the transaction for formats without rows.
+type Entry struct {
+	Line   int
+	Date   int
+	Type   string
+	Amount float64
+	Note   string
+	// ExternalID is the bank's own id for the transaction, when the format has one
+	ExternalID string
+	Error      string
+}
+
+// CSVMapping describes where a bank puts each field and how it writes them.
+// Columns are zero-based, a negative column is not present in the file.
+type CSVMapping struct {
+	Delimiter rune
+	HasHeader bool
+
+	DateColumn int
+	DateFormat string
+	Location   *time.Location
+
+	// Either AmountColumn, or DebitColumn and CreditColumn, hold the amount
+	AmountColumn int
+	DebitColumn  int
+	CreditColumn int
+	NoteColumn   int
+
+	AmountSign       string
+	DecimalSeparator string
+}
+
+// ParseCSV reads the statement rows of r, at most limit of them
+func ParseCSV(r io.Reader, mapping CSVMapping, limit int) ([]Entry, error) {
+	layout, err := DateLayout(mapping.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = mapping.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var entries []Entry
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if first && mapping.HasHeader {
+			continue
+		}
+		if blank(record) {
+			continue
+		}
+		if len(entries) == limit {
+			return nil, ErrTooManyRows
+		}
+
+		// Lines are counted in the file, the reader skips empty ones
+		line, _ := reader.FieldPos(0)
+
+		entry := Entry{Line: line}
+		if err := mapping.read(record, layout, &entry); err != nil {
+			entry.Error = err.Error()
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// read fills entry from one record
+func (m CSVMapping) read(record []string, layout string, entry *Entry) error {
+	field := func(column int) (string, error) {
+		if column < 0 {
+			return "", nil
+		}
+		if column >= len(record) {
+			return "", fmt.Errorf("row has %d columns, column %d is missing", len(record), column)
+		}
+		return strings.TrimSpace(record[column]), nil
+	}
+
+	rawDate, err := field(m.DateColumn)
+	if err != nil {
+		return err
+	}
+	date, err := time.ParseInLocation(layout, rawDate, m.Location)
+	if err != nil {
+		return fmt.Errorf("date %q does not match the format %s", rawDate, m.DateFormat)
+	}
+	entry.Date = int(date.Unix())
+
+	note, err := field(m.NoteColumn)
+	if err != nil {
+		return err
+	}
+	entry.Note = CleanNote(note)
+
+	if m.AmountColumn >= 0 {
+		raw, err := field(m.AmountColumn)
+		if err != nil {
+			return err
+		}
+		amount, err := ParseAmount(raw, m.DecimalSeparator)
+		if err != nil {
+			return err
+		}
+		if m.AmountSign == constant.ImportAmountSignPositiveExpense {
+			amount = -amount
+		}
+		return setAmount(entry, amount)
+	}
+
+	rawDebit, err := field(m.DebitColumn)
+	if err != nil {
+		return err
+	}
+	rawCredit, err := field(m.CreditColumn)
+	if err != nil {
+		return err
+	}
+
+	var debit, credit float64
+	if rawDebit != "" {
+		if debit, err = ParseAmount(rawDebit, m.DecimalSeparator); err != nil {
+			return err
+		}
+	}
+	if rawCredit != "" {
+		if credit, err = ParseAmount(rawCredit, m.DecimalSeparator); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case debit != 0 && credit != 0:
+		return errors.New("row has both a debit and a credit")
+	case debit != 0:
+		entry.Type, entry.Amount = constant.TransactionTypeExpense, math.Abs(debit)
+	case credit != 0:
+		entry.Type, entry.Amount = constant.TransactionTypeIncome, math.Abs(credit)
+	default:
+		return errors.New("row has neither a debit nor a credit")
+	}
+
+	return nil
+}
+
+// ParseAmount reads a number written with the given decimal separator. The other separator
+// is taken as grouping, currency symbols are ignored and (12.50) or 12.50- are negative.
+func ParseAmount(raw string, decimalSeparator string) (float64, error) {
+	grouping := ","
+	if decimalSeparator == "," {
+		grouping = "."
+	}
+
+	negative := false
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case string(r) == decimalSeparator:
+			digits.WriteByte('.')
+		case string(r) == grouping:
+		case r == '-' || r == '(' || r == ')' || r == '−':
+			negative = true
+		}
+	}
+
+	value, err := strconv.ParseFloat(digits.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("amount %q is not a number", raw)
+	}
+	if negative {
+		value = -value
+	}
+	return math.Round(value*100) / 100, nil
+}
+
+// DateLayout converts a format such as DD/MM/YYYY or D MMM YY into a Go time layout
+func DateLayout(format string) (string, error) {
+	if !strings.Contains(format, "YY") || !strings.Contains(format, "M") || !strings.Contains(format, "D") {
+		return "", fmt.Errorf("date format %q needs a day, a month and a year", format)
+	}
+
+	layout := strings.NewReplacer(
+		"YYYY", "2006",
+		"YY", "06",
+		"MMM", "Jan",
+		"MM", "01",
+		"M", "1",
+		"DD", "02",
+		"D", "2",
+	).Replace(format)
+
+	// Anything left over besides the month name is an unknown token
+	if strings.IndexFunc(strings.Replace(layout, "Jan", "", 1), unicode.IsLetter) >= 0 {
+		return "", fmt.Errorf("date format %q has unknown tokens, use YYYY, YY, MMM, MM, M, DD and D", format)
+	}
+
+	return layout, nil
+}
+
+// CleanNote collapses whitespace and trims the note to the length the API accepts
+func CleanNote(note string) string {
+	note = strings.Join(strings.Fields(note), " ")
+
+	runes := []rune(note)
+	if len(runes) > maxNoteLength {
+		note = strings.TrimSpace(string(runes[:maxNoteLength]))
+	}
+	return note
+}
+
+func blank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
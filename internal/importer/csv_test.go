@@ -0,0 +1,178 @@
+package importer_test
+
+import (
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/importer"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	signed := importer.CSVMapping{
+		Delimiter: ',', HasHeader: true,
+		DateColumn: 0, DateFormat: "YYYY-MM-DD", Location: time.UTC,
+		AmountColumn: 2, DebitColumn: -1, CreditColumn: -1, NoteColumn: 1,
+		AmountSign: constant.ImportAmountSignNegativeExpense, DecimalSeparator: ".",
+	}
+	european := importer.CSVMapping{
+		Delimiter:  ';',
+		DateColumn: 0, DateFormat: "DD.MM.YYYY", Location: jakarta,
+		AmountColumn: -1, DebitColumn: 2, CreditColumn: 3, NoteColumn: 1,
+		DecimalSeparator: ",",
+	}
+	card := signed
+	card.AmountSign = constant.ImportAmountSignPositiveExpense
+
+	tests := []struct {
+		name    string
+		mapping importer.CSVMapping
+		file    string
+		want    []importer.Entry
+	}{
+		{
+			name:    "signed amounts",
+			mapping: signed,
+			file:    "Date,Description,Amount\n2025-08-01,  Salary   August ,\"3,000.00\"\n\n2025-08-02,Coffee,-4.50\n",
+			want: []importer.Entry{
+				{Line: 2, Date: 1754006400, Type: constant.TransactionTypeIncome, Amount: 3000, Note: "Salary August"},
+				{Line: 4, Date: 1754092800, Type: constant.TransactionTypeExpense, Amount: 4.5, Note: "Coffee"},
+			},
+		},
+		{
+			name:    "card statement where charges are positive",
+			mapping: card,
+			file:    "Date,Description,Amount\n2025-08-02,Coffee,4.50\n2025-08-03,Refund,(10.00)\n",
+			want: []importer.Entry{
+				{Line: 2, Date: 1754092800, Type: constant.TransactionTypeExpense, Amount: 4.5, Note: "Coffee"},
+				{Line: 3, Date: 1754179200, Type: constant.TransactionTypeIncome, Amount: 10, Note: "Refund"},
+			},
+		},
+		{
+			name:    "debit and credit columns in a local timezone",
+			mapping: european,
+			file:    "01.08.2025;Miete;1.250,00;\n02.08.2025;Gehalt;;2.000,50\n",
+			want: []importer.Entry{
+				{Line: 1, Date: 1753981200, Type: constant.TransactionTypeExpense, Amount: 1250, Note: "Miete"},
+				{Line: 2, Date: 1754067600, Type: constant.TransactionTypeIncome, Amount: 2000.5, Note: "Gehalt"},
+			},
+		},
+		{
+			name:    "bad rows are reported, not fatal",
+			mapping: signed,
+			file:    "Date,Description,Amount\n08/01/2025,Coffee,-4.50\n2025-08-02,Coffee,n/a\n2025-08-03,Short\n2025-08-04,Nothing,0\n",
+			want: []importer.Entry{
+				{Line: 2, Note: "", Error: `date "08/01/2025" does not match the format YYYY-MM-DD`},
+				{Line: 3, Date: 1754092800, Note: "Coffee", Error: `amount "n/a" is not a number`},
+				{Line: 4, Date: 1754179200, Note: "Short", Error: "row has 2 columns, column 2 is missing"},
+				{Line: 5, Date: 1754265600, Note: "Nothing", Error: "amount is zero"},
+			},
+		},
+		{
+			name:    "debit and credit on the same row",
+			mapping: european,
+			file:    "01.08.2025;Both;1,00;2,00\n01.08.2025;Neither;;\n",
+			want: []importer.Entry{
+				{Line: 1, Date: 1753981200, Note: "Both", Error: "row has both a debit and a credit"},
+				{Line: 2, Date: 1753981200, Note: "Neither", Error: "row has neither a debit nor a credit"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := importer.ParseCSV(strings.NewReader(tt.file), tt.mapping, 10)
+			if err != nil {
+				t.Fatalf("ParseCSV() error = %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("ParseCSV() returned %d entries, want %d: %+v", len(entries), len(tt.want), entries)
+			}
+			for i, want := range tt.want {
+				if entries[i] != want {
+					t.Errorf("entry %d = %+v, want %+v", i, entries[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSVLimits(t *testing.T) {
+	mapping := importer.CSVMapping{
+		Delimiter: ',', DateColumn: 0, DateFormat: "YYYY-MM-DD", Location: time.UTC,
+		AmountColumn: 1, DebitColumn: -1, CreditColumn: -1, NoteColumn: -1, DecimalSeparator: ".",
+	}
+
+	if _, err := importer.ParseCSV(strings.NewReader("2025-08-01,1\n2025-08-02,2\n2025-08-03,3\n"), mapping, 2); !errors.Is(err, importer.ErrTooManyRows) {
+		t.Errorf("ParseCSV() over the limit error = %v, want %v", err, importer.ErrTooManyRows)
+	}
+
+	mapping.DateFormat = "YYYY-QQ-DD"
+	if _, err := importer.ParseCSV(strings.NewReader("2025-08-01,1\n"), mapping, 2); err == nil {
+		t.Error("ParseCSV() with an unknown date token succeeded")
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		raw       string
+		separator string
+		want      float64
+		wantErr   bool
+	}{
+		{raw: "1,234.56", separator: ".", want: 1234.56},
+		{raw: "1.234,56", separator: ",", want: 1234.56},
+		{raw: "-12.5", separator: ".", want: -12.5},
+		{raw: "12.50-", separator: ".", want: -12.5},
+		{raw: "(7.25)", separator: ".", want: -7.25},
+		{raw: "$ 1 000.10", separator: ".", want: 1000.1},
+		{raw: "€ 99,999", separator: ",", want: 100},
+		{raw: "", separator: ".", wantErr: true},
+		{raw: "abc", separator: ".", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := importer.ParseAmount(tt.raw, tt.separator)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAmount() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{format: "YYYY-MM-DD", want: "2006-01-02"},
+		{format: "DD/MM/YY", want: "02/01/06"},
+		{format: "D MMM YYYY", want: "2 Jan 2006"},
+		{format: "M/D/YYYY", want: "1/2/2006"},
+		{format: "MM/YYYY", wantErr: true},
+		{format: "YYYY-MM-DD hh:mm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := importer.DateLayout(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DateLayout() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DateLayout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NoteSimilarity scores from 0 to 1 how alike two notes are by the words they share.
+// Banks pad descriptions with references, so words are compared against the shorter note:
+// "Coffee" and "POS 4411 COFFEE" are the same. Two empty notes are identical.
+func NoteSimilarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA))
+}
+
+// words returns the distinct lowercase words of s, numbers such as card references are left out
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if strings.IndexFunc(word, unicode.IsLetter) >= 0 {
+			set[word] = true
+		}
+	}
+	return set
+}
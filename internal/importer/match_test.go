@@ -0,0 +1,28 @@
+package importer_test
+
+import (
+	"finance-backend/internal/importer"
+	"testing"
+)
+
+func TestNoteSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "", b: "", want: 1},
+		{a: "Coffee", b: "", want: 0},
+		{a: "Coffee", b: "POS 4411 COFFEE", want: 1},
+		{a: "Corner Coffee", b: "coffee shop", want: 0.5},
+		{a: "Rent", b: "Salary", want: 0},
+		{a: "Card 1234", b: "Card 9876", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"|"+tt.b, func(t *testing.T) {
+			if got := importer.NoteSimilarity(tt.a, tt.b); got != tt.want {
+				t.Errorf("NoteSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
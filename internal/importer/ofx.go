@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ofxNode is an element of an OFX document, leaves carry a value and aggregates children
+type ofxNode struct {
+	name     string
+	value    string
+	parent   *ofxNode
+	children []*ofxNode
+}
+
+// ParseOFX reads the bank and credit card statements of an OFX or QFX file, at most limit
+// transactions across them. It accepts both OFX 1.x SGML, where leaf elements are not
+// closed, and OFX 2.x XML.
+func ParseOFX(r io.Reader, limit int) ([]Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseOFXTree(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []Statement
+	total := 0
+	for _, response := range root.findAll("STMTRS", "CCSTMTRS") {
+		statement := Statement{
+			Account:  response.first("ACCTID").text(),
+			Currency: strings.ToUpper(response.first("CURDEF").text()),
+		}
+
+		if balance := response.first("LEDGERBAL").first("BALAMT"); balance != nil {
+			amount, err := parseLooseAmount(balance.value)
+			if err != nil {
+				return nil, fmt.Errorf("ledger balance: %w", err)
+			}
+			statement.LedgerBalance = &amount
+		}
+
+		for i, transaction := range response.findAll("STMTTRN") {
+			if total == limit {
+				return nil, ErrTooManyRows
+			}
+			total++
+
+			entry := Entry{
+				Line:       i + 1,
+				ExternalID: transaction.first("FITID").text(),
+				Note:       joinNote(transaction.first("NAME").text(), transaction.first("MEMO").text()),
+			}
+			if err := readOFXTransaction(transaction, &entry); err != nil {
+				entry.Error = err.Error()
+			}
+			statement.Entries = append(statement.Entries, entry)
+		}
+
+		statements = append(statements, statement)
+	}
+
+	if len(statements) == 0 {
+		return nil, errors.New("no bank or credit card statement found")
+	}
+
+	return statements, nil
+}
+
+func readOFXTransaction(transaction *ofxNode, entry *Entry) error {
+	date, err := parseOFXDate(transaction.first("DTPOSTED").text())
+	if err != nil {
+		return err
+	}
+	entry.Date = date
+
+	amount, err := parseLooseAmount(transaction.first("TRNAMT").text())
+	if err != nil {
+		return err
+	}
+	return setAmount(entry, amount)
+}
+
+// parseOFXDate reads YYYYMMDD[HHMMSS[.XXX]][[offset:TZ]], dates without an offset are in GMT
+func parseOFXDate(raw string) (int, error) {
+	value, zone := raw, ""
+	if i := strings.IndexByte(raw, '['); i >= 0 {
+		value, zone = raw[:i], strings.Trim(raw[i:], "[]")
+	}
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return 0, fmt.Errorf("date %q is not an OFX date", raw)
+	}
+
+	location := time.UTC
+	if zone != "" {
+		hours, err := strconv.ParseFloat(strings.SplitN(zone, ":", 2)[0], 64)
+		if err != nil {
+			return 0, fmt.Errorf("date %q has an unknown time zone", raw)
+		}
+		location = time.FixedZone("", int(hours*3600))
+	}
+
+	date, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return 0, fmt.Errorf("date %q is not an OFX date", raw)
+	}
+	return int(date.Unix()), nil
+}
+
+// parseOFXTree builds the element tree below <OFX>. An element followed by text is a leaf
+// whether or not it is closed, closing tags pop back to the element they name.
+func parseOFXTree(document string) (*ofxNode, error) {
+	start := strings.Index(strings.ToUpper(document), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("file is not an OFX document")
+	}
+
+	root := &ofxNode{}
+	current := root
+	rest := document[start:]
+
+	for {
+		open := strings.IndexByte(rest, '<')
+		if open < 0 {
+			break
+		}
+		if text := strings.TrimSpace(rest[:open]); text != "" && current != root {
+			current.value = html.UnescapeString(text)
+			current = current.parent
+		}
+
+		end := strings.IndexByte(rest[open:], '>')
+		if end < 0 {
+			return nil, errors.New("file ends inside a tag")
+		}
+		tag := strings.TrimSpace(rest[open+1 : open+end])
+		rest = rest[open+end+1:]
+
+		switch {
+		case tag == "" || tag[0] == '?' || tag[0] == '!':
+			continue
+		case tag[0] == '/':
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for node := current; node != root; node = node.parent {
+				if node.name == name {
+					current = node.parent
+					break
+				}
+			}
+		default:
+			name := strings.ToUpper(strings.Fields(tag)[0])
+			node := &ofxNode{name: name, parent: current}
+			current.children = append(current.children, node)
+			current = node
+		}
+	}
+
+	return root, nil
+}
+
+// findAll returns every descendant with one of the names, in document order
+func (n *ofxNode) findAll(names ...string) []*ofxNode {
+	var found []*ofxNode
+	if n == nil {
+		return found
+	}
+	for _, child := range n.children {
+		for _, name := range names {
+			if child.name == name {
+				found = append(found, child)
+				break
+			}
+		}
+		found = append(found, child.findAll(names...)...)
+	}
+	return found
+}
+
+// first returns the first descendant with the name, or nil
+func (n *ofxNode) first(name string) *ofxNode {
+	if n == nil {
+		return nil
+	}
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+		if found := child.first(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (n *ofxNode) text() string {
+	if n == nil {
+		return ""
+	}
+	return n.value
+}
@@ -0,0 +1,130 @@
+package importer_test
+
+import (
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/importer"
+	"strings"
+	"testing"
+)
+
+// OFX 1.x SGML as most banks still send it, leaf elements are never closed
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+ENCODING:USASCII
+CHARSET:1252
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20250805</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>1<STMTRS>
+<CURDEF>usd
+<BANKACCTFROM><BANKID>121000248<ACCTID>000123<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST><DTSTART>20250801<DTEND>20250805
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20250801<TRNAMT>3000.00<FITID>F1<NAME>ACME PAYROLL<MEMO>Salary</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20250802120000.000[-5:EST]<TRNAMT>-4,50<FITID>F2<NAME>Coffee &amp; Co<MEMO>Coffee &amp; Co</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>yesterday<TRNAMT>-1.00<FITID>F3<NAME>Bad date</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>2995.50<DTASOF>20250805</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+// OFX 2.x XML with a credit card statement
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <CCSTMTRS>
+        <CURDEF>EUR</CURDEF>
+        <CCACCTFROM><ACCTID>4111XXXX1111</ACCTID></CCACCTFROM>
+        <BANKTRANLIST>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20250803</DTPOSTED>
+            <TRNAMT>-12.30</TRNAMT>
+            <FITID>C1</FITID>
+            <NAME>Bookshop</NAME>
+          </STMTTRN>
+        </BANKTRANLIST>
+        <LEDGERBAL><BALAMT>-12.30</BALAMT><DTASOF>20250805</DTASOF></LEDGERBAL>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		wantAccount string
+		wantCur     string
+		wantBalance float64
+		want        []importer.Entry
+	}{
+		{
+			name:        "sgml bank statement",
+			file:        sgmlStatement,
+			wantAccount: "000123",
+			wantCur:     "USD",
+			wantBalance: 2995.5,
+			want: []importer.Entry{
+				{Line: 1, Date: 1754006400, Type: constant.TransactionTypeIncome, Amount: 3000, Note: "ACME PAYROLL Salary", ExternalID: "F1"},
+				{Line: 2, Date: 1754154000, Type: constant.TransactionTypeExpense, Amount: 4.5, Note: "Coffee & Co", ExternalID: "F2"},
+				{Line: 3, Note: "Bad date", ExternalID: "F3", Error: `date "yesterday" is not an OFX date`},
+			},
+		},
+		{
+			name:        "xml credit card statement",
+			file:        xmlStatement,
+			wantAccount: "4111XXXX1111",
+			wantCur:     "EUR",
+			wantBalance: -12.3,
+			want: []importer.Entry{
+				{Line: 1, Date: 1754179200, Type: constant.TransactionTypeExpense, Amount: 12.3, Note: "Bookshop", ExternalID: "C1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := importer.ParseOFX(strings.NewReader(tt.file), 10)
+			if err != nil {
+				t.Fatalf("ParseOFX() error = %v", err)
+			}
+			if len(statements) != 1 {
+				t.Fatalf("ParseOFX() returned %d statements, want 1", len(statements))
+			}
+
+			statement := statements[0]
+			if statement.Account != tt.wantAccount || statement.Currency != tt.wantCur {
+				t.Errorf("statement is %s in %s, want %s in %s", statement.Account, statement.Currency, tt.wantAccount, tt.wantCur)
+			}
+			if statement.LedgerBalance == nil || *statement.LedgerBalance != tt.wantBalance {
+				t.Errorf("ledger balance = %v, want %v", statement.LedgerBalance, tt.wantBalance)
+			}
+			if len(statement.Entries) != len(tt.want) {
+				t.Fatalf("ParseOFX() returned %d entries, want %d: %+v", len(statement.Entries), len(tt.want), statement.Entries)
+			}
+			for i, want := range tt.want {
+				if statement.Entries[i] != want {
+					t.Errorf("entry %d = %+v, want %+v", i, statement.Entries[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	if _, err := importer.ParseOFX(strings.NewReader(sgmlStatement), 2); !errors.Is(err, importer.ErrTooManyRows) {
+		t.Errorf("ParseOFX() over the limit error = %v, want %v", err, importer.ErrTooManyRows)
+	}
+	if _, err := importer.ParseOFX(strings.NewReader("Date,Amount\n2025-08-01,1\n"), 10); err == nil {
+		t.Error("ParseOFX() of a CSV file succeeded")
+	}
+	if _, err := importer.ParseOFX(strings.NewReader("<OFX><SIGNONMSGSRSV1></SIGNONMSGSRSV1></OFX>"), 10); err == nil {
+		t.Error("ParseOFX() without a statement succeeded")
+	}
+}
@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QIF dates carry no marker of their field order, the caller says which one the bank uses
+const (
+	QIFDateOrderMDY = "mdy"
+	QIFDateOrderDMY = "dmy"
+	QIFDateOrderYMD = "ymd"
+)
+
+// qifRecord collects the fields of one transaction until its ^ terminator
+type qifRecord struct {
+	line   int
+	fields map[byte]string
+}
+
+// ParseQIF reads the cash, bank and credit card transactions of a QIF file, at most limit
+// of them. Files exported with several accounts get one statement per !Account block.
+// Investment, category and memorized lists are skipped.
+func ParseQIF(r io.Reader, dateOrder string, limit int) ([]Statement, error) {
+	scanner := bufio.NewScanner(r)
+
+	var statements []*Statement
+	byAccount := make(map[string]*Statement)
+	statementFor := func(account string) *Statement {
+		if statement, ok := byAccount[account]; ok {
+			return statement
+		}
+		statement := &Statement{Account: account}
+		byAccount[account] = statement
+		statements = append(statements, statement)
+		return statement
+	}
+
+	account := ""
+	inAccount, inTransactions := false, false
+	record := qifRecord{fields: make(map[byte]string)}
+	total := 0
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if text[0] == '!' {
+			header := strings.ToLower(strings.TrimSpace(text))
+			switch {
+			case header == "!account":
+				inAccount, inTransactions = true, false
+			case strings.HasPrefix(header, "!type:"):
+				switch strings.TrimSpace(strings.TrimPrefix(header, "!type:")) {
+				case "bank", "cash", "ccard", "oth a", "oth l":
+					inTransactions = true
+				default:
+					inTransactions = false
+				}
+			}
+			record = qifRecord{fields: make(map[byte]string)}
+			continue
+		}
+
+		if text[0] == '^' {
+			switch {
+			case inAccount:
+				account = strings.TrimSpace(record.fields['N'])
+				inAccount = false
+			case inTransactions && len(record.fields) > 0:
+				if total == limit {
+					return nil, ErrTooManyRows
+				}
+				total++
+
+				statement := statementFor(account)
+				statement.Entries = append(statement.Entries, readQIFRecord(record, dateOrder))
+			}
+			record = qifRecord{fields: make(map[byte]string)}
+			continue
+		}
+
+		if len(record.fields) == 0 {
+			record.line = line
+		}
+		// Split lines repeat S, E and $, only the first of each code is kept and the total T wins
+		if _, seen := record.fields[text[0]]; !seen {
+			record.fields[text[0]] = strings.TrimSpace(text[1:])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]Statement, 0, len(statements))
+	for _, statement := range statements {
+		result = append(result, *statement)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no cash, bank or credit card transactions found")
+	}
+
+	return result, nil
+}
+
+func readQIFRecord(record qifRecord, dateOrder string) Entry {
+	entry := Entry{
+		Line: record.line,
+		Note: joinNote(record.fields['P'], record.fields['M']),
+	}
+
+	date, err := parseQIFDate(record.fields['D'], dateOrder)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Date = date
+
+	raw, ok := record.fields['T']
+	if !ok {
+		raw = record.fields['U']
+	}
+	amount, err := parseLooseAmount(raw)
+	if err == nil {
+		err = setAmount(&entry, amount)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// parseQIFDate reads dates such as 8/1/2025, 08/01'25 or 2025-08-01 as UTC midnight.
+// Two digit years after an apostrophe are in the 2000s, otherwise 70 to 99 are in the 1900s.
+func parseQIFDate(raw string, dateOrder string) (int, error) {
+	parts := strings.FieldsFunc(raw, func(r rune) bool { return !unicode.IsDigit(r) })
+	if len(parts) == 1 && len(parts[0]) == 8 {
+		parts, dateOrder = []string{parts[0][:4], parts[0][4:6], parts[0][6:]}, QIFDateOrderYMD
+	}
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("date %q is not a QIF date", raw)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		numbers[i], _ = strconv.Atoi(part)
+	}
+
+	var year, month, day int
+	switch dateOrder {
+	case QIFDateOrderDMY:
+		day, month, year = numbers[0], numbers[1], numbers[2]
+	case QIFDateOrderYMD:
+		year, month, day = numbers[0], numbers[1], numbers[2]
+	default:
+		month, day, year = numbers[0], numbers[1], numbers[2]
+	}
+
+	if year < 100 {
+		if year >= 70 && !strings.Contains(raw, "'") {
+			year += 1900
+		} else {
+			year += 2000
+		}
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return 0, fmt.Errorf("date %q is not a valid %s date", raw, dateOrder)
+	}
+	return int(date.Unix()), nil
+}
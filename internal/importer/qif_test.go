@@ -0,0 +1,89 @@
+package importer_test
+
+import (
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/importer"
+	"strings"
+	"testing"
+)
+
+func TestParseQIF(t *testing.T) {
+	tests := []struct {
+		name      string
+		dateOrder string
+		file      string
+		want      map[string][]importer.Entry
+	}{
+		{
+			name:      "single bank account",
+			dateOrder: importer.QIFDateOrderMDY,
+			file: "!Type:Bank\n" +
+				"D8/1/2025\nT3,000.00\nPACME Payroll\nMSalary\n^\n" +
+				"D08/02'25\nU-4.50\nT-4.50\nPCoffee\n^\n" +
+				"D13/40/2025\nT-1.00\nPBad date\n^\n",
+			want: map[string][]importer.Entry{
+				"": {
+					{Line: 2, Date: 1754006400, Type: constant.TransactionTypeIncome, Amount: 3000, Note: "ACME Payroll Salary"},
+					{Line: 7, Date: 1754092800, Type: constant.TransactionTypeExpense, Amount: 4.5, Note: "Coffee"},
+					{Line: 12, Note: "Bad date", Error: `date "13/40/2025" is not a valid mdy date`},
+				},
+			},
+		},
+		{
+			name:      "accounts with splits and an investment section",
+			dateOrder: importer.QIFDateOrderDMY,
+			file: "!Account\nNChecking\nTBank\n^\n" +
+				"!Type:Bank\n" +
+				"D02/08/2025\nT-100,00\nPMarket\nSFood\n$-60,00\nSHome\n$-40,00\n^\n" +
+				"!Account\nNBrokerage\nTInvst\n^\n" +
+				"!Type:Invst\nD03/08/2025\nNBuy\nT-500.00\n^\n" +
+				"!Account\nNVisa\nTCCard\n^\n" +
+				"!Type:CCard\nD20250803\nT-12.30\nPBookshop\n^\n",
+			want: map[string][]importer.Entry{
+				"Checking": {
+					{Line: 6, Date: 1754092800, Type: constant.TransactionTypeExpense, Amount: 100, Note: "Market"},
+				},
+				"Visa": {
+					{Line: 28, Date: 1754179200, Type: constant.TransactionTypeExpense, Amount: 12.3, Note: "Bookshop"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := importer.ParseQIF(strings.NewReader(tt.file), tt.dateOrder, 10)
+			if err != nil {
+				t.Fatalf("ParseQIF() error = %v", err)
+			}
+			if len(statements) != len(tt.want) {
+				t.Fatalf("ParseQIF() returned %d statements, want %d: %+v", len(statements), len(tt.want), statements)
+			}
+			for _, statement := range statements {
+				want, ok := tt.want[statement.Account]
+				if !ok {
+					t.Fatalf("unexpected account %q", statement.Account)
+				}
+				if len(statement.Entries) != len(want) {
+					t.Fatalf("account %q has %d entries, want %d: %+v", statement.Account, len(statement.Entries), len(want), statement.Entries)
+				}
+				for i := range want {
+					if statement.Entries[i] != want[i] {
+						t.Errorf("account %q entry %d = %+v, want %+v", statement.Account, i, statement.Entries[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseQIFErrors(t *testing.T) {
+	file := "!Type:Cash\nD8/1/2025\nT-1\n^\nD8/2/2025\nT-2\n^\n"
+	if _, err := importer.ParseQIF(strings.NewReader(file), importer.QIFDateOrderMDY, 1); !errors.Is(err, importer.ErrTooManyRows) {
+		t.Errorf("ParseQIF() over the limit error = %v, want %v", err, importer.ErrTooManyRows)
+	}
+	if _, err := importer.ParseQIF(strings.NewReader("!Type:Cat\nNFood\nE\n^\n"), importer.QIFDateOrderMDY, 10); err == nil {
+		t.Error("ParseQIF() of a category list succeeded")
+	}
+}
@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"errors"
+	"finance-backend/internal/constant"
+	"math"
+	"strings"
+)
+
+// Statement is the activity of one account in a statement file
+type Statement struct {
+	// Account identifies the bank account, empty when the file does not say
+	Account  string
+	Currency string
+	Entries  []Entry
+	// LedgerBalance is the closing balance the bank reported, nil when the file has none
+	LedgerBalance *float64
+}
+
+// setAmount books a signed amount on entry, money out is negative
+func setAmount(entry *Entry, amount float64) error {
+	if amount == 0 {
+		return errors.New("amount is zero")
+	}
+
+	entry.Type = constant.TransactionTypeIncome
+	if amount < 0 {
+		entry.Type = constant.TransactionTypeExpense
+	}
+	entry.Amount = math.Abs(amount)
+	return nil
+}
+
+// parseLooseAmount reads an amount from a format that does not declare its decimal separator.
+// The last dot or comma is the decimal separator when one or two digits follow it.
+func parseLooseAmount(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+
+	separator := "."
+	if i := strings.LastIndexAny(raw, ".,"); i >= 0 {
+		digits := strings.TrimRight(raw[i+1:], ")- ")
+		if raw[i] == ',' && len(digits) >= 1 && len(digits) <= 2 {
+			separator = ","
+		}
+	}
+	return ParseAmount(raw, separator)
+}
+
+// joinNote combines a payee and a memo into one note, leaving out a memo that repeats the payee
+func joinNote(payee, memo string) string {
+	payee, memo = CleanNote(payee), CleanNote(memo)
+	switch {
+	case memo == "" || strings.EqualFold(payee, memo):
+		return payee
+	case payee == "":
+		return memo
+	}
+	return CleanNote(payee + " " + memo)
+}
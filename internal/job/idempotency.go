@@ -0,0 +1,26 @@
+package job
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/pkg/logger"
+	"time"
+)
+
+// PurgeIdempotencyKeys deletes expired idempotency keys at startup and once a day. Expired keys
+// are already free to reuse, purging only keeps the table small.
+func PurgeIdempotencyKeys(idempotencyService domain.IdempotencyService) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		runDaily(ctx, time.Now, time.After, func(ctx context.Context, now time.Time) {
+			log := logger.GetLogger()
+
+			purged, err := idempotencyService.Purge(ctx, now)
+			if err != nil {
+				log.WithError(err).Error("[job - idempotency - PurgeIdempotencyKeys]: Failed to purge expired idempotency keys")
+				return
+			}
+
+			log.WithField("keys", purged).Info("[job - idempotency - PurgeIdempotencyKeys]: Purged expired idempotency keys")
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package job
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/pkg/logger"
+	"time"
+)
+
+// captureDelay leaves a margin after midnight so late writes of the previous day are settled
+const captureDelay = time.Minute
+
+// DailySnapshots records every wallet's balance as the close of the previous day shortly after each
+// UTC midnight. It does not capture at startup, a balance taken mid-day is not the end of any day.
+// Capturing is idempotent, so multiple replicas only overwrite the same rows.
+func DailySnapshots(snapshotService domain.SnapshotService) func(ctx context.Context) {
+	return dailySnapshots(snapshotService, time.Now, time.After)
+}
+
+func dailySnapshots(snapshotService domain.SnapshotService, now func() time.Time, after func(time.Duration) <-chan time.Time) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		runAfterMidnight(ctx, now, after, func(ctx context.Context, now time.Time) {
+			log := logger.GetLogger()
+
+			captured, err := snapshotService.Capture(ctx, now)
+			if err != nil {
+				log.WithError(err).Error("[job - snapshot - DailySnapshots]: Failed to capture wallet balances")
+				return
+			}
+
+			log.WithField("wallets", captured).Info("[job - snapshot - DailySnapshots]: Captured wallet balances")
+		})
+	}
+}
+
+// runDaily calls run immediately and then once per UTC day until ctx is cancelled
+func runDaily(ctx context.Context, now func() time.Time, after func(time.Duration) <-chan time.Time, run func(ctx context.Context, now time.Time)) {
+	run(ctx, now())
+	runAfterMidnight(ctx, now, after, run)
+}
+
+// runAfterMidnight calls run shortly after each UTC midnight until ctx is cancelled
+func runAfterMidnight(ctx context.Context, now func() time.Time, after func(time.Duration) <-chan time.Time, run func(ctx context.Context, now time.Time)) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		current := now().UTC()
+		year, month, day := current.Date()
+		next := time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC).Add(captureDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-after(next.Sub(current)):
+		}
+
+		run(ctx, now())
+	}
+}
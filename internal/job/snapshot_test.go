@@ -0,0 +1,90 @@
+package job
+
+import (
+	"context"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/logger"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunDaily(t *testing.T) {
+	start := time.Date(2025, 3, 5, 22, 30, 0, 0, time.UTC)
+	clock := start
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var waits []time.Duration
+	after := func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		clock = clock.Add(d)
+
+		fired := make(chan time.Time, 1)
+		fired <- clock
+		return fired
+	}
+
+	var runs []time.Time
+	runDaily(ctx, func() time.Time { return clock }, after, func(ctx context.Context, now time.Time) {
+		runs = append(runs, now)
+		if len(runs) == 3 {
+			cancel()
+		}
+	})
+
+	want := []time.Time{
+		start,
+		time.Date(2025, 3, 6, 0, 1, 0, 0, time.UTC),
+		time.Date(2025, 3, 7, 0, 1, 0, 0, time.UTC),
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("ran %d times, want %d", len(runs), len(want))
+	}
+	for i := range want {
+		if !runs[i].Equal(want[i]) {
+			t.Errorf("run %d at %s, want %s", i, runs[i], want[i])
+		}
+	}
+	if waits[0] != 91*time.Minute {
+		t.Errorf("first wait = %s, want 1h31m", waits[0])
+	}
+}
+
+func TestDailySnapshots(t *testing.T) {
+	logger.InitLogger(config.LogConfig{Level: "ERROR", Format: "text"})
+
+	// Started mid-day, the job waits for midnight instead of storing a partial balance
+	clock := time.Date(2025, 3, 5, 22, 30, 0, 0, time.UTC)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	after := func(d time.Duration) <-chan time.Time {
+		clock = clock.Add(d)
+
+		fired := make(chan time.Time, 1)
+		fired <- clock
+		return fired
+	}
+
+	ctrl := gomock.NewController(t)
+	snapshots := mocks.NewMockSnapshotRepository(ctrl)
+	snapshotService := service.NewSnapshotService(nil, snapshots, nil, nil, nil)
+
+	// Each run closes the day that ended at the midnight before it
+	gomock.InOrder(
+		snapshots.EXPECT().Capture(gomock.Any(), int(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC).Unix())).Return(int64(2), nil),
+		snapshots.EXPECT().Capture(gomock.Any(), int(time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC).Unix())).DoAndReturn(
+			func(context.Context, int) (int64, error) {
+				cancel()
+				return 2, nil
+			}),
+	)
+
+	dailySnapshots(snapshotService, func() time.Time { return clock }, after)(ctx)
+}
@@ -0,0 +1,19 @@
+package model
+
+type ExportTransactionsRequest struct {
+	TransactionListRequest
+	Format string `query:"format" json:"format" validate:"required,oneof=csv jsonl xlsx"`
+	// Locale formats CSV amounts, the Accept-Language header is used when it is empty
+	Locale   string `query:"locale" json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Timezone string `query:"tz" json:"tz" validate:"omitempty,timezone"`
+}
+
+// ExportLedgerRequest selects the period and wallet of a plain-text accounting export. Filtering by
+// type or budget would leave the wallets unbalanced, so the ledger takes fewer filters than the list.
+type ExportLedgerRequest struct {
+	From     int    `query:"from" json:"from" validate:"omitempty,min=0"`
+	To       int    `query:"to" json:"to" validate:"omitempty,gtfield=From"`
+	WalletID string `query:"wallet_id" json:"wallet_id" validate:"omitempty,uuid"`
+	Syntax   string `query:"syntax" json:"syntax" validate:"required,oneof=ledger beancount"`
+	Timezone string `query:"tz" json:"tz" validate:"omitempty,timezone"`
+}
@@ -0,0 +1,13 @@
+package model
+
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Message   string  `json:"message,omitempty"`
+}
+
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
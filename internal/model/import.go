@@ -0,0 +1,136 @@
+package model
+
+type CreateImportProfileRequest struct {
+	Name      string `json:"name" validate:"required,max=255"`
+	Delimiter string `json:"delimiter" validate:"omitempty,len=1"`
+	HasHeader bool   `json:"has_header"`
+
+	DateColumn int    `json:"date_column" validate:"min=0"`
+	DateFormat string `json:"date_format" validate:"omitempty,max=50"`
+	Timezone   string `json:"timezone" validate:"omitempty,timezone"`
+
+	AmountColumn *int `json:"amount_column" validate:"required_without_all=DebitColumn CreditColumn,omitempty,min=0"`
+	DebitColumn  *int `json:"debit_column" validate:"required_with=CreditColumn,omitempty,min=0"`
+	CreditColumn *int `json:"credit_column" validate:"required_with=DebitColumn,omitempty,min=0"`
+	NoteColumn   *int `json:"note_column" validate:"omitempty,min=0"`
+
+	AmountSign       string `json:"amount_sign" validate:"omitempty,oneof=negative_expense positive_expense"`
+	DecimalSeparator string `json:"decimal_separator" validate:"omitempty,oneof=. ,"`
+}
+
+type ImportProfile struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Delimiter string `json:"delimiter"`
+	HasHeader bool   `json:"has_header"`
+
+	DateColumn int    `json:"date_column"`
+	DateFormat string `json:"date_format"`
+	Timezone   string `json:"timezone"`
+
+	AmountColumn *int `json:"amount_column"`
+	DebitColumn  *int `json:"debit_column"`
+	CreditColumn *int `json:"credit_column"`
+	NoteColumn   *int `json:"note_column"`
+
+	AmountSign       string `json:"amount_sign"`
+	DecimalSeparator string `json:"decimal_separator"`
+
+	CreatedAt int `json:"created_at"`
+	UpdatedAt int `json:"updated_at"`
+}
+
+// ImportCSVRequest holds the form fields sent alongside the statement file
+type ImportCSVRequest struct {
+	ProfileID string `form:"profile_id" json:"profile_id" validate:"required,uuid"`
+	WalletID  string `form:"wallet_id" json:"wallet_id" validate:"required,uuid"`
+	// DryRun previews the import without booking anything
+	DryRun bool `form:"dry_run" json:"dry_run"`
+}
+
+// ImportStatementRequest holds the form fields sent alongside an OFX, QFX or QIF file.
+// Each account in the file is booked to the wallet named in AccountMap, then to the wallet it
+// was booked to last time, then to WalletID when the file holds a single account.
+type ImportStatementRequest struct {
+	WalletID string `form:"wallet_id" json:"wallet_id" validate:"omitempty,uuid"`
+	// AccountMap is a JSON object from account ids in the file to wallet ids, it is remembered on commit
+	AccountMap string `form:"account_map" json:"account_map" validate:"omitempty,json"`
+	// DateOrder is how QIF dates are written, OFX dates are unambiguous
+	DateOrder string `form:"date_order" json:"date_order" validate:"omitempty,oneof=mdy dmy ymd"`
+	DryRun    bool   `form:"dry_run" json:"dry_run"`
+}
+
+// ImportLedgerRequest holds the form fields sent alongside a Beancount file
+type ImportLedgerRequest struct {
+	// Timezone is where the dates in the file fall at midnight, UTC when empty
+	Timezone string `form:"timezone" json:"timezone" validate:"omitempty,timezone"`
+	DryRun   bool   `form:"dry_run" json:"dry_run"`
+}
+
+type ImportResult struct {
+	DryRun     bool `json:"dry_run"`
+	Total      int  `json:"total"`
+	New        int  `json:"new"`
+	Duplicates int  `json:"duplicates"`
+	Invalid    int  `json:"invalid"`
+	// Imported is how many rows were booked, always 0 on a dry run
+	Imported int             `json:"imported"`
+	Accounts []ImportAccount `json:"accounts"`
+	Rows     []ImportRow     `json:"rows"`
+	// Wallets and Budgets are created for accounts of a ledger file that match none of the user's
+	Wallets []ImportWallet `json:"wallets,omitempty"`
+	Budgets []ImportBudget `json:"budgets,omitempty"`
+}
+
+// ImportWallet is a wallet an import creates, ID is set once it is created
+type ImportWallet struct {
+	Account  string  `json:"account"`
+	ID       *string `json:"id"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Currency string  `json:"currency"`
+	// Balance is the opening balance booked against equity in the file
+	Balance float64 `json:"balance"`
+}
+
+// ImportBudget is a budget an import creates with no amount, ID is set once it is created
+type ImportBudget struct {
+	Account  string  `json:"account"`
+	ID       *string `json:"id"`
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+}
+
+// ImportAccount is one account of the file and how its closing balance compares to the wallet
+type ImportAccount struct {
+	Account  string  `json:"account"`
+	WalletID *string `json:"wallet_id"`
+	Currency string  `json:"currency,omitempty"`
+	Rows     int     `json:"rows"`
+	// LedgerBalance is the closing balance in the file, nil when the format has none
+	LedgerBalance *float64 `json:"ledger_balance"`
+	// ProjectedBalance is the wallet balance once the new rows are booked
+	ProjectedBalance *float64 `json:"projected_balance"`
+	Difference       *float64 `json:"difference"`
+	Reconciled       *bool    `json:"reconciled"`
+}
+
+type ImportRow struct {
+	Line            int     `json:"line"`
+	Account         string  `json:"account,omitempty"`
+	WalletID        string  `json:"wallet_id,omitempty"`
+	ExternalID      string  `json:"external_id,omitempty"`
+	Status          string  `json:"status"`
+	TransactionDate int     `json:"transaction_date"`
+	Type            string  `json:"type"`
+	Amount          float64 `json:"amount"`
+	Note            string  `json:"note"`
+	Error           string  `json:"error,omitempty"`
+	// DuplicateOf is the existing transaction the row matched
+	DuplicateOf *string `json:"duplicate_of,omitempty"`
+	// TransactionID is set once the row is booked
+	TransactionID *string `json:"transaction_id,omitempty"`
+	// Counterpart is the income or expense account of a ledger entry, booked to BudgetID
+	Counterpart string `json:"counterpart,omitempty"`
+	BudgetID    string `json:"budget_id,omitempty"`
+}
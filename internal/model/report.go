@@ -0,0 +1,170 @@
+package model
+
+type SummaryReportRequest struct {
+	From     int    `query:"from" json:"from" validate:"required"`
+	To       int    `query:"to" json:"to" validate:"required,gtfield=From"`
+	GroupBy  string `query:"group_by" json:"group_by" validate:"required,oneof=day week month"`
+	Timezone string `query:"tz" json:"tz" validate:"omitempty,timezone"`
+	WalletID string `query:"wallet_id" json:"wallet_id" validate:"omitempty,uuid"`
+	BudgetID string `query:"budget_id" json:"budget_id" validate:"omitempty,uuid"`
+}
+
+type SummaryReport struct {
+	From     int             `json:"from"`
+	To       int             `json:"to"`
+	GroupBy  string          `json:"group_by"`
+	Timezone string          `json:"tz"`
+	Totals   SummaryTotals   `json:"totals"`
+	Buckets  []SummaryBucket `json:"buckets"`
+}
+
+type SummaryTotals struct {
+	Income  float64 `json:"income"`
+	Expense float64 `json:"expense"`
+	Net     float64 `json:"net"`
+	Count   int     `json:"count"`
+}
+
+type SummaryBucket struct {
+	Start   int     `json:"start"`
+	End     int     `json:"end"`
+	Label   string  `json:"label"`
+	Income  float64 `json:"income"`
+	Expense float64 `json:"expense"`
+	Net     float64 `json:"net"`
+	Count   int     `json:"count"`
+}
+
+type CategoryReportRequest struct {
+	From     int    `query:"from" json:"from" validate:"required"`
+	To       int    `query:"to" json:"to" validate:"required,gtfield=From"`
+	WalletID string `query:"wallet_id" json:"wallet_id" validate:"omitempty,uuid"`
+	Top      int    `query:"top" json:"top" validate:"omitempty,min=1,max=50"`
+}
+
+// CategoryReport breaks expenses down by category and budget, sorted by amount so it can be charted as is
+type CategoryReport struct {
+	From         int             `json:"from"`
+	To           int             `json:"to"`
+	PreviousFrom int             `json:"previous_from"`
+	PreviousTo   int             `json:"previous_to"`
+	Total        SpendChange     `json:"total"`
+	Categories   []CategorySpend `json:"categories"`
+	Tags         []TagSpend      `json:"tags"`
+	TopNotes     []NoteSpend     `json:"top_notes"`
+}
+
+// SpendChange compares the spend of a period with the previous period of the same length
+type SpendChange struct {
+	Amount         float64 `json:"amount"`
+	Count          int     `json:"count"`
+	PreviousAmount float64 `json:"previous_amount"`
+	Change         float64 `json:"change"`
+	// ChangePercent is null when nothing was spent in the previous period
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+type CategorySpend struct {
+	Category string `json:"category"`
+	SpendChange
+	Share   float64       `json:"share"`
+	Budgets []BudgetSpend `json:"budgets"`
+}
+
+type BudgetSpend struct {
+	BudgetID *string `json:"budget_id"`
+	Name     string  `json:"name"`
+	SpendChange
+	Share float64 `json:"share"`
+}
+
+// TagSpend is the spend carrying one tag. Tags overlap, an expense with several tags counts for each,
+// so their shares can add up to more than 100.
+type TagSpend struct {
+	TagID string `json:"tag_id"`
+	Name  string `json:"name"`
+	SpendChange
+	Share float64 `json:"share"`
+}
+
+type NoteSpend struct {
+	Note   string  `json:"note"`
+	Amount float64 `json:"amount"`
+	Count  int     `json:"count"`
+	Share  float64 `json:"share"`
+}
+
+type NetWorthReportRequest struct {
+	From int `query:"from" json:"from" validate:"required"`
+	To   int `query:"to" json:"to" validate:"required,gtfield=From"`
+}
+
+// NetWorthReport is a daily series of balances converted to the base currency
+type NetWorthReport struct {
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Currency string `json:"currency"`
+	// MissingRates lists currencies left out of some days for lack of an exchange rate
+	MissingRates []string        `json:"missing_rates"`
+	Points       []NetWorthPoint `json:"points"`
+}
+
+type NetWorthPoint struct {
+	Date        int     `json:"date"`
+	Label       string  `json:"label"`
+	Assets      float64 `json:"assets"`
+	Liabilities float64 `json:"liabilities"`
+	NetWorth    float64 `json:"net_worth"`
+}
+
+type ForecastRequest struct {
+	Horizon  int    `query:"horizon" json:"horizon" validate:"omitempty,oneof=30 60 90"`
+	WalletID string `query:"wallet_id" json:"wallet_id" validate:"omitempty,uuid"`
+}
+
+type ForecastReport struct {
+	GeneratedAt int `json:"generated_at"`
+	Horizon     int `json:"horizon"`
+	// Confidence is the probability the balance stays within each point's lower and upper bound
+	Confidence float64          `json:"confidence"`
+	Wallets    []WalletForecast `json:"wallets"`
+}
+
+type WalletForecast struct {
+	WalletID  string  `json:"wallet_id"`
+	Name      string  `json:"name"`
+	Currency  string  `json:"currency"`
+	Liability bool    `json:"liability"`
+	Balance   float64 `json:"balance"`
+	// FirstNegativeDate is the first day an asset wallet is projected below zero
+	FirstNegativeDate *int              `json:"first_negative_date"`
+	Recurring         []RecurringItem   `json:"recurring"`
+	Categories        []CategoryAverage `json:"categories"`
+	Points            []ForecastPoint   `json:"points"`
+}
+
+// RecurringItem is a series detected in past transactions and projected forward
+type RecurringItem struct {
+	Note     string  `json:"note"`
+	Type     string  `json:"type"`
+	Amount   float64 `json:"amount"`
+	Interval string  `json:"interval"`
+	NextDate int     `json:"next_date"`
+}
+
+// CategoryAverage is the learned daily effect of non-recurring transactions in a category
+type CategoryAverage struct {
+	Category     string  `json:"category"`
+	DailyAverage float64 `json:"daily_average"`
+}
+
+type ForecastPoint struct {
+	Date    int     `json:"date"`
+	Label   string  `json:"label"`
+	Balance float64 `json:"balance"`
+	Lower   float64 `json:"lower"`
+	Upper   float64 `json:"upper"`
+	// Negative and AtRisk are only set for asset wallets, when the projection or its lower bound drops below zero
+	Negative bool `json:"negative"`
+	AtRisk   bool `json:"at_risk"`
+}
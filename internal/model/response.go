@@ -2,11 +2,20 @@ package model
 
 import "time"
 
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
 type Response struct {
-	Success   bool        `json:"success"`
-	Message   string      `json:"message"`
-	Data      interface{} `json:"data,omitempty"`
-	Timestamp string      `json:"timestamp"`
+	Success   bool         `json:"success"`
+	Message   string       `json:"message"`
+	Code      string       `json:"code,omitempty"`
+	Data      interface{}  `json:"data,omitempty"`
+	Details   interface{}  `json:"details,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Timestamp string       `json:"timestamp"`
 }
 
 func NewResponseSuccess(data interface{}) *Response {
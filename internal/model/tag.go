@@ -0,0 +1,35 @@
+package model
+
+type CreateTagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// TagListRequest autocompletes tag names, every tag is listed when Prefix is empty
+type TagListRequest struct {
+	Prefix string `query:"prefix" json:"prefix" validate:"max=50"`
+	Limit  int    `query:"limit" json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// TagTransactionsRequest adds and removes tags on many transactions at once
+type TagTransactionsRequest struct {
+	TransactionIDs []string `json:"transaction_ids" validate:"required,min=1,max=500,dive,uuid"`
+	Add            []string `json:"add" validate:"required_without=Remove,dive,uuid"`
+	Remove         []string `json:"remove" validate:"required_without=Add,dive,uuid"`
+}
+
+// TagTransactionsResult counts the tags that were put on or taken off, pairs that were already so are not counted
+type TagTransactionsResult struct {
+	Added   int64 `json:"added"`
+	Removed int64 `json:"removed"`
+}
+
+type Tag struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt int    `json:"created_at"`
+	UpdatedAt int    `json:"updated_at"`
+}
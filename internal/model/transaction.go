@@ -1,12 +1,46 @@
 package model
 
 type CreateTransactionRequest struct {
-	Amount          float64 `json:"amount"`
-	Type            string  `json:"type"`
-	Note            string  `json:"note"`
-	TransactionDate int     `json:"transaction_date"`
-	WalletID        string  `json:"wallet_id"`
-	BudgetID        *string `json:"budget_id"`
+	Amount          float64 `json:"amount" validate:"required,gt=0"`
+	Type            string  `json:"type" validate:"required,transaction_type"`
+	Note            string  `json:"note" validate:"max=255"`
+	TransactionDate int     `json:"transaction_date" validate:"required"`
+	WalletID        string  `json:"wallet_id" validate:"required,uuid"`
+	BudgetID        *string `json:"budget_id" validate:"omitempty,uuid,excluded_with=Splits"`
+	// Splits books the amount to several budgets, the lines must add up to Amount
+	Splits []TransactionSplitRequest `json:"splits" validate:"omitempty,min=2,max=50,dive"`
+	// ExternalID is only set by statement imports
+	ExternalID string `json:"-"`
+}
+
+// UpdateTransactionRequest replaces every field of a transaction, moving it to another wallet is allowed
+type UpdateTransactionRequest struct {
+	Amount          float64 `json:"amount" validate:"required,gt=0"`
+	Type            string  `json:"type" validate:"required,transaction_type"`
+	Note            string  `json:"note" validate:"max=255"`
+	TransactionDate int     `json:"transaction_date" validate:"required"`
+	WalletID        string  `json:"wallet_id" validate:"required,uuid"`
+	BudgetID        *string `json:"budget_id" validate:"omitempty,uuid,excluded_with=Splits"`
+	// Splits books the amount to several budgets, the lines must add up to Amount
+	Splits []TransactionSplitRequest `json:"splits" validate:"omitempty,min=2,max=50,dive"`
+}
+
+// TransactionSplitRequest is one line of a split transaction, a line without a budget is unbudgeted spend
+type TransactionSplitRequest struct {
+	Amount   float64 `json:"amount" validate:"required,gt=0"`
+	Note     string  `json:"note" validate:"max=255"`
+	BudgetID *string `json:"budget_id" validate:"omitempty,uuid"`
+}
+
+// TransactionListRequest filters the transaction list and exports, every field is optional
+type TransactionListRequest struct {
+	From     int    `query:"from" json:"from" validate:"omitempty,min=0"`
+	To       int    `query:"to" json:"to" validate:"omitempty,gtfield=From"`
+	Type     string `query:"type" json:"type" validate:"omitempty,transaction_type"`
+	WalletID string `query:"wallet_id" json:"wallet_id" validate:"omitempty,uuid"`
+	BudgetID string `query:"budget_id" json:"budget_id" validate:"omitempty,uuid"`
+	// TagIDs keeps the transactions carrying every listed tag, the parameter is repeated for each
+	TagIDs []string `query:"tag_id" json:"tag_id" validate:"omitempty,max=10,dive,uuid"`
 }
 
 type Transaction struct {
@@ -15,8 +49,11 @@ type Transaction struct {
 	Type            string             `json:"type"`
 	Note            string             `json:"note"`
 	TransactionDate int                `json:"transaction_date"`
+	Version         int                `json:"version"`
 	Wallet          TransactionWallet  `json:"wallet"`
 	Budget          *TransactionBudget `json:"budget"`
+	Tags            []TransactionTag   `json:"tags"`
+	Splits          []TransactionSplit `json:"splits"`
 }
 
 type TransactionWallet struct {
@@ -28,3 +65,15 @@ type TransactionBudget struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
+
+type TransactionTag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TransactionSplit struct {
+	ID     string             `json:"id"`
+	Amount float64            `json:"amount"`
+	Note   string             `json:"note"`
+	Budget *TransactionBudget `json:"budget"`
+}
@@ -2,9 +2,14 @@ package model
 
 type CreateWalletRequest struct {
 	Name     string  `json:"name" validate:"required"`
-	Type     string  `json:"type" validate:"required,oneof=personal business"`
-	Currency string  `json:"currency" validate:"required,len=3"`
-	Balance  float64 `json:"balance" validate:"required,numeric"`
+	Type     string  `json:"type" validate:"required,oneof=personal business credit loan"`
+	Currency string  `json:"currency" validate:"required,currency"`
+	Balance  float64 `json:"balance" validate:"min=0"`
+}
+
+// UpdateWalletRequest renames a wallet, its currency is fixed and its balance moves with transactions
+type UpdateWalletRequest struct {
+	Name string `json:"name" validate:"required"`
 }
 
 type Wallet struct {
@@ -13,6 +18,7 @@ type Wallet struct {
 	Type      string  `json:"type"`
 	Currency  string  `json:"currency"`
 	Balance   float64 `json:"balance"`
+	Version   int     `json:"version"`
 	CreatedAt int     `json:"created_at"`
 	UpdatedAt int     `json:"updated_at"`
 }
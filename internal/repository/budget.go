@@ -8,14 +8,18 @@ import (
 	"gorm.io/gorm"
 )
 
-type budgetRepository struct{}
+type budgetRepository struct {
+	db *gorm.DB
+}
 
-func NewBudgetRepository() domain.BudgetRepository {
-	return &budgetRepository{}
+func NewBudgetRepository(db *gorm.DB) domain.BudgetRepository {
+	return &budgetRepository{
+		db: db,
+	}
 }
 
-func (r *budgetRepository) Create(db *gorm.DB, ctx context.Context, userId string, budget *domain.Budget) error {
-	if err := db.WithContext(ctx).Create(budget).Error; err != nil {
+func (r *budgetRepository) Create(ctx context.Context, userId string, budget *domain.Budget) error {
+	if err := conn(ctx, r.db).Create(budget).Error; err != nil {
 		return err
 	}
 
@@ -24,17 +28,17 @@ func (r *budgetRepository) Create(db *gorm.DB, ctx context.Context, userId strin
 		BudgetID: budget.ID,
 	}
 
-	if err := db.WithContext(ctx).Create(&hasBudget).Error; err != nil {
+	if err := conn(ctx, r.db).Create(&hasBudget).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *budgetRepository) GetList(db *gorm.DB, ctx context.Context, userId string) ([]*domain.Budget, error) {
+func (r *budgetRepository) GetList(ctx context.Context, userId string) ([]*domain.Budget, error) {
 	var budgets []*domain.Budget
 
-	err := db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Joins("JOIN has_budgets ON has_budgets.budget_id = budgets.id").
 		Where("has_budgets.user_id = ?", userId).
 		Find(&budgets).Error
@@ -44,3 +48,34 @@ func (r *budgetRepository) GetList(db *gorm.DB, ctx context.Context, userId stri
 
 	return budgets, nil
 }
+
+func (r *budgetRepository) GetDetail(ctx context.Context, userId string, budgetId string) (*domain.Budget, error) {
+	var budget domain.Budget
+
+	err := conn(ctx, r.db).
+		Joins("JOIN has_budgets ON has_budgets.budget_id = budgets.id").
+		Where("has_budgets.user_id = ? AND budgets.id = ?", userId, budgetId).
+		First(&budget).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+func (r *budgetRepository) Update(ctx context.Context, budget *domain.Budget, version int) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Budget{}).
+		Where("id = ? AND version = ?", budget.ID, version).
+		Updates(map[string]interface{}{
+			"name":     budget.Name,
+			"amount":   budget.Amount,
+			"type":     budget.Type,
+			"category": budget.Category,
+			"version":  gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,122 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestBudgetRepositoryCreate(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewBudgetRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "budget@example.com")
+
+	tests := []struct {
+		name    string
+		budget  *domain.Budget
+		wantErr bool
+	}{
+		{
+			name:   "valid budget",
+			budget: &domain.Budget{Name: "Groceries", Amount: 500000, Type: "monthly", Category: "food"},
+		},
+		{
+			name:   "zero amount",
+			budget: &domain.Budget{Name: "Placeholder", Amount: 0, Type: "monthly", Category: "misc"},
+		},
+		{
+			name:    "negative amount violates check",
+			budget:  &domain.Budget{Name: "Broken", Amount: -1, Type: "monthly", Category: "misc"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.Create(ctx, user.ID.String(), tt.budget)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBudgetRepositoryGetList(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewBudgetRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	createBudget(t, db, owner, "Food")
+	createBudget(t, db, owner, "Transport")
+	createBudget(t, db, other, "Rent")
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		wantNames []string
+	}{
+		{name: "owner sees own budgets", user: owner, wantNames: []string{"Food", "Transport"}},
+		{name: "other user is isolated", user: other, wantNames: []string{"Rent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budgets, err := repo.GetList(ctx, tt.user.ID.String())
+			if err != nil {
+				t.Fatalf("GetList() error = %v", err)
+			}
+
+			names := make(map[string]bool, len(budgets))
+			for _, budget := range budgets {
+				names[budget.Name] = true
+			}
+			if len(budgets) != len(tt.wantNames) {
+				t.Fatalf("GetList() returned %d budgets, want %d", len(budgets), len(tt.wantNames))
+			}
+			for _, name := range tt.wantNames {
+				if !names[name] {
+					t.Fatalf("GetList() is missing budget %q", name)
+				}
+			}
+		})
+	}
+}
+
+func TestBudgetRepositoryUpdate(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewBudgetRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	budget := createBudget(t, db, owner, "Food")
+
+	if _, err := repo.GetDetail(ctx, other.ID.String(), budget.ID.String()); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("GetDetail() for another user error = %v, want not found", err)
+	}
+
+	budget.Amount = 750
+	if saved, err := repo.Update(ctx, budget, 1); err != nil || !saved {
+		t.Fatalf("Update() = %v, %v, want saved", saved, err)
+	}
+	if saved, err := repo.Update(ctx, budget, 1); err != nil || saved {
+		t.Fatalf("Update() of a stale version = %v, %v, want not saved", saved, err)
+	}
+
+	got, err := repo.GetDetail(ctx, owner.ID.String(), budget.ID.String())
+	if err != nil {
+		t.Fatalf("GetDetail() error = %v", err)
+	}
+	if got.Amount != 750 || got.Version != 2 {
+		t.Fatalf("GetDetail() = %.2f at version %d, want 750.00 at version 2", got.Amount, got.Version)
+	}
+}
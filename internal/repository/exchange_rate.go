@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) domain.ExchangeRateRepository {
+	return &exchangeRateRepository{
+		db: db,
+	}
+}
+
+func (r *exchangeRateRepository) Upsert(ctx context.Context, rate *domain.ExchangeRate) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "currency"}, {Name: "rate_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+		}).
+		Create(rate).Error
+}
+
+func (r *exchangeRateRepository) GetUntil(ctx context.Context, currencies []string, to int) ([]*domain.ExchangeRate, error) {
+	var rates []*domain.ExchangeRate
+
+	if len(currencies) == 0 {
+		return rates, nil
+	}
+
+	err := conn(ctx, r.db).
+		Where("currency IN ? AND rate_date < ?", currencies, to).
+		Order("currency, rate_date").
+		Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
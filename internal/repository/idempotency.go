@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) domain.IdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, key *domain.IdempotencyKey, staleBefore int) (bool, error) {
+	now := time.Now().Unix()
+
+	// An expired key is taken over as if it had never been used
+	result := conn(ctx, r.db).Exec(`INSERT INTO idempotency_keys (user_id, key, fingerprint, status, content_type, body, created_at, updated_at, expires_at)
+		VALUES (?, ?, ?, 0, '', NULL, ?, ?, ?)
+		ON CONFLICT (user_id, key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status = 0, content_type = '', etag = '', location = '', body = NULL,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= ?
+			OR (idempotency_keys.status = 0 AND idempotency_keys.updated_at <= ? AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)`,
+		key.UserID, key.Key, key.Fingerprint, now, now, key.ExpiresAt, now, staleBefore)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *idempotencyRepository) GetDetail(ctx context.Context, userId string, key string) (*domain.IdempotencyKey, error) {
+	var record domain.IdempotencyKey
+
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND key = ?", userId, key).
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, key *domain.IdempotencyKey) error {
+	return conn(ctx, r.db).
+		Model(&domain.IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", key.UserID, key.Key).
+		Updates(map[string]interface{}{
+			"status":       key.Status,
+			"content_type": key.ContentType,
+			"etag":         key.ETag,
+			"location":     key.Location,
+			"body":         key.Body,
+			"updated_at":   time.Now().Unix(),
+		}).Error
+}
+
+func (r *idempotencyRepository) Delete(ctx context.Context, userId string, key string) error {
+	return conn(ctx, r.db).
+		Where("user_id = ? AND key = ?", userId, key).
+		Delete(&domain.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now int) (int64, error) {
+	result := conn(ctx, r.db).
+		Where("expires_at <= ?", now).
+		Delete(&domain.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
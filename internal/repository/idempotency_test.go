@@ -0,0 +1,82 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestIdempotencyRepository(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewIdempotencyRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "idempotency@example.com")
+	other := createUser(t, db, "idempotency-other@example.com")
+	later := int(time.Now().Add(time.Hour).Unix())
+	lockedSince := int(time.Now().Add(-time.Minute).Unix())
+
+	reserve := func(key *domain.IdempotencyKey, want bool) {
+		t.Helper()
+		reserved, err := repo.Reserve(ctx, key, lockedSince)
+		if err != nil {
+			t.Fatalf("Reserve() error = %v", err)
+		}
+		if reserved != want {
+			t.Fatalf("Reserve(%s) = %v, want %v", key.Key, reserved, want)
+		}
+	}
+
+	reserve(&domain.IdempotencyKey{UserID: user.ID, Key: "k1", Fingerprint: "a", ExpiresAt: later}, true)
+	reserve(&domain.IdempotencyKey{UserID: user.ID, Key: "k1", Fingerprint: "b", ExpiresAt: later}, false)
+	reserve(&domain.IdempotencyKey{UserID: other.ID, Key: "k1", Fingerprint: "b", ExpiresAt: later}, true)
+
+	err := repo.Complete(ctx, &domain.IdempotencyKey{UserID: user.ID, Key: "k1", Status: 201, ContentType: "application/json", ETag: `"1"`, Location: "/v1/wallet/w1", Body: []byte(`{"ok":true}`)})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	stored, err := repo.GetDetail(ctx, user.ID.String(), "k1")
+	if err != nil {
+		t.Fatalf("GetDetail() error = %v", err)
+	}
+	if stored.Fingerprint != "a" || stored.Status != 201 || stored.ETag != `"1"` || stored.Location != "/v1/wallet/w1" || string(stored.Body) != `{"ok":true}` {
+		t.Fatalf("GetDetail() = %+v, want the stored 201 of the first request", stored)
+	}
+
+	// An expired key is taken over by the next request
+	reserve(&domain.IdempotencyKey{UserID: user.ID, Key: "old", Fingerprint: "a", ExpiresAt: int(time.Now().Add(-time.Minute).Unix())}, true)
+	reserve(&domain.IdempotencyKey{UserID: user.ID, Key: "old", Fingerprint: "c", ExpiresAt: later}, true)
+	if stored, err := repo.GetDetail(ctx, user.ID.String(), "old"); err != nil || stored.Fingerprint != "c" || stored.Status != 0 || stored.ETag != "" {
+		t.Fatalf("GetDetail() = %+v, %v, want the new request's pending key", stored, err)
+	}
+
+	// A key left pending by a request that died is taken over by its retry once the lock times out
+	reserve(&domain.IdempotencyKey{UserID: user.ID, Key: "crashed", Fingerprint: "a", ExpiresAt: later}, true)
+	if reserved, err := repo.Reserve(ctx, &domain.IdempotencyKey{UserID: user.ID, Key: "crashed", Fingerprint: "b", ExpiresAt: later}, int(time.Now().Unix())+1); err != nil || reserved {
+		t.Fatalf("Reserve() of a timed out key with another fingerprint = %v, %v, want refused", reserved, err)
+	}
+	reserve(&domain.IdempotencyKey{UserID: user.ID, Key: "crashed", Fingerprint: "a", ExpiresAt: later}, false)
+	if reserved, err := repo.Reserve(ctx, &domain.IdempotencyKey{UserID: user.ID, Key: "crashed", Fingerprint: "a", ExpiresAt: later}, int(time.Now().Unix())+1); err != nil || !reserved {
+		t.Fatalf("Reserve() of a timed out key = %v, %v, want taken over", reserved, err)
+	}
+
+	reserve(&domain.IdempotencyKey{UserID: other.ID, Key: "stale", Fingerprint: "a", ExpiresAt: int(time.Now().Add(-time.Minute).Unix())}, true)
+	purged, err := repo.DeleteExpired(ctx, int(time.Now().Unix()))
+	if err != nil || purged != 1 {
+		t.Fatalf("DeleteExpired() = %d, %v, want the stale key only", purged, err)
+	}
+
+	if err := repo.Delete(ctx, user.ID.String(), "k1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetDetail(ctx, user.ID.String(), "k1"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("GetDetail() after Delete() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type importAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewImportAccountRepository(db *gorm.DB) domain.ImportAccountRepository {
+	return &importAccountRepository{
+		db: db,
+	}
+}
+
+func (r *importAccountRepository) GetList(ctx context.Context, userId string) ([]*domain.ImportAccount, error) {
+	var accounts []*domain.ImportAccount
+
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userId).
+		Order("account_id").
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (r *importAccountRepository) Upsert(ctx context.Context, accounts []*domain.ImportAccount) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+
+	return conn(ctx, r.db).
+		Omit("User", "Wallet").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "account_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"wallet_id", "updated_at"}),
+		}).
+		Create(&accounts).Error
+}
@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+)
+
+func TestImportAccountRepository(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewImportAccountRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	checking := createWallet(t, db, owner, 1000)
+	savings := createWallet(t, db, owner, 1000)
+	foreign := createWallet(t, db, other, 1000)
+
+	err := repo.Upsert(ctx, []*domain.ImportAccount{
+		{UserID: owner.ID, AccountID: "222", WalletID: savings.ID},
+		{UserID: owner.ID, AccountID: "111", WalletID: savings.ID},
+		{UserID: other.ID, AccountID: "111", WalletID: foreign.ID},
+	})
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	t.Run("upsert moves a known account", func(t *testing.T) {
+		if err := repo.Upsert(ctx, []*domain.ImportAccount{{UserID: owner.ID, AccountID: "111", WalletID: checking.ID}}); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+
+		accounts, err := repo.GetList(ctx, owner.ID.String())
+		if err != nil {
+			t.Fatalf("GetList() error = %v", err)
+		}
+		if len(accounts) != 2 || accounts[0].AccountID != "111" || accounts[0].WalletID != checking.ID || accounts[1].WalletID != savings.ID {
+			t.Fatalf("GetList() = %+v, want 111 on checking and 222 on savings", accounts)
+		}
+	})
+
+	t.Run("list is scoped to the owner", func(t *testing.T) {
+		accounts, err := repo.GetList(ctx, other.ID.String())
+		if err != nil {
+			t.Fatalf("GetList() error = %v", err)
+		}
+		if len(accounts) != 1 || accounts[0].WalletID != foreign.ID {
+			t.Fatalf("GetList() = %+v, want the other user's account only", accounts)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type importProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewImportProfileRepository(db *gorm.DB) domain.ImportProfileRepository {
+	return &importProfileRepository{
+		db: db,
+	}
+}
+
+func (r *importProfileRepository) Create(ctx context.Context, userId string, profile *domain.ImportProfile) error {
+	if err := conn(ctx, r.db).Create(profile).Error; err != nil {
+		return err
+	}
+
+	hasImportProfile := domain.HasImportProfile{
+		UserID:          uuid.MustParse(userId),
+		ImportProfileID: profile.ID,
+	}
+
+	if err := conn(ctx, r.db).Create(&hasImportProfile).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *importProfileRepository) GetList(ctx context.Context, userId string) ([]*domain.ImportProfile, error) {
+	var profiles []*domain.ImportProfile
+
+	err := conn(ctx, r.db).
+		Joins("JOIN has_import_profiles ON has_import_profiles.import_profile_id = import_profiles.id").
+		Where("has_import_profiles.user_id = ?", userId).
+		Order("import_profiles.name").
+		Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+func (r *importProfileRepository) GetDetail(ctx context.Context, userId string, profileId string) (*domain.ImportProfile, error) {
+	var profile domain.ImportProfile
+
+	err := conn(ctx, r.db).
+		Joins("JOIN has_import_profiles ON has_import_profiles.import_profile_id = import_profiles.id").
+		Where("has_import_profiles.user_id = ? AND import_profiles.id = ?", userId, profileId).
+		First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
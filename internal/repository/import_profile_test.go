@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func createImportProfile(t *testing.T, db *gorm.DB, user *domain.User, name string) *domain.ImportProfile {
+	t.Helper()
+
+	amount, note := 2, 1
+	profile := &domain.ImportProfile{
+		Name: name, Delimiter: ",", HasHeader: true,
+		DateColumn: 0, DateFormat: "YYYY-MM-DD", Timezone: "UTC",
+		AmountColumn: &amount, NoteColumn: &note,
+		AmountSign: "negative_expense", DecimalSeparator: ".",
+	}
+	if err := repository.NewImportProfileRepository(db).Create(context.Background(), user.ID.String(), profile); err != nil {
+		t.Fatalf("failed to create import profile: %v", err)
+	}
+	return profile
+}
+
+func TestImportProfileRepository(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewImportProfileRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	checking := createImportProfile(t, db, owner, "Checking")
+	createImportProfile(t, db, owner, "Card")
+	foreign := createImportProfile(t, db, other, "Other bank")
+
+	t.Run("list is scoped to the owner", func(t *testing.T) {
+		profiles, err := repo.GetList(ctx, owner.ID.String())
+		if err != nil {
+			t.Fatalf("GetList() error = %v", err)
+		}
+		if len(profiles) != 2 || profiles[0].Name != "Card" || profiles[1].Name != "Checking" {
+			t.Fatalf("GetList() = %+v, want both profiles by name", profiles)
+		}
+	})
+
+	t.Run("detail keeps absent columns empty", func(t *testing.T) {
+		profile, err := repo.GetDetail(ctx, owner.ID.String(), checking.ID.String())
+		if err != nil {
+			t.Fatalf("GetDetail() error = %v", err)
+		}
+		if profile.AmountColumn == nil || *profile.AmountColumn != 2 || profile.DebitColumn != nil || !profile.HasHeader {
+			t.Fatalf("GetDetail() = %+v", profile)
+		}
+	})
+
+	t.Run("detail of another user", func(t *testing.T) {
+		if _, err := repo.GetDetail(ctx, owner.ID.String(), foreign.ID.String()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetDetail() error = %v, want %v", err, gorm.ErrRecordNotFound)
+		}
+	})
+
+	t.Run("profile without any amount column violates check", func(t *testing.T) {
+		profile := &domain.ImportProfile{Name: "Broken", Delimiter: ",", DateFormat: "YYYY-MM-DD", Timezone: "UTC", AmountSign: "negative_expense", DecimalSeparator: "."}
+		if err := repo.Create(ctx, owner.ID.String(), profile); err == nil {
+			t.Fatal("Create() without an amount column succeeded")
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	testdb.Main(m)
+}
+
+func createUser(t *testing.T, db *gorm.DB, email string) *domain.User {
+	t.Helper()
+
+	user := &domain.User{
+		ID:       uuid.New(),
+		FullName: "Test User",
+		Email:    email,
+		Password: "hashed",
+	}
+	if err := repository.NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+func createWallet(t *testing.T, db *gorm.DB, user *domain.User, balance float64) *domain.Wallet {
+	t.Helper()
+
+	wallet := &domain.Wallet{
+		Name:     "Main",
+		Type:     "personal",
+		Currency: "IDR",
+		Balance:  balance,
+	}
+	if err := repository.NewWalletRepository(db).Create(context.Background(), user.ID.String(), wallet); err != nil {
+		t.Fatalf("failed to create wallet: %v", err)
+	}
+	return wallet
+}
+
+func createBudget(t *testing.T, db *gorm.DB, user *domain.User, name string) *domain.Budget {
+	t.Helper()
+
+	budget := &domain.Budget{
+		Name:     name,
+		Amount:   1000000,
+		Type:     "monthly",
+		Category: "food",
+	}
+	if err := repository.NewBudgetRepository(db).Create(context.Background(), user.ID.String(), budget); err != nil {
+		t.Fatalf("failed to create budget: %v", err)
+	}
+	return budget
+}
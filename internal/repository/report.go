@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) domain.ReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+func (r *reportRepository) Summary(ctx context.Context, userId string, filter domain.SummaryFilter) ([]*domain.SummaryRow, error) {
+	var rows []*domain.SummaryRow
+
+	// Buckets are truncated on the local wall clock so days and months follow the user's timezone
+	query := conn(ctx, r.db).Table("transactions").
+		Select(`date_trunc(?, to_timestamp(transactions.transaction_date) AT TIME ZONE ?) AS bucket,
+			COALESCE(SUM(`+lineAmount+`) FILTER (WHERE transactions.type = ?), 0) AS income,
+			COALESCE(SUM(`+lineAmount+`) FILTER (WHERE transactions.type = ?), 0) AS expense,
+			COUNT(DISTINCT transactions.id) AS count`,
+			filter.GroupBy, filter.Timezone, constant.TransactionTypeIncome, constant.TransactionTypeExpense).
+		Joins("JOIN has_transactions ON has_transactions.transaction_id = transactions.id").
+		Joins(joinSplitLines).
+		Where("has_transactions.user_id = ? AND transactions.deleted_at = 0", userId).
+		Where("transactions.transaction_date >= ? AND transactions.transaction_date < ?", filter.From, filter.To)
+
+	if filter.WalletID != "" {
+		query = query.Where("transactions.wallet_id = ?", filter.WalletID)
+	}
+	if filter.BudgetID != "" {
+		query = query.Where(lineBudget+" = ?", filter.BudgetID)
+	}
+
+	if err := query.Group("bucket").Order("bucket").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *reportRepository) SpendByBudget(ctx context.Context, userId string, filter domain.SpendFilter) ([]*domain.BudgetSpendRow, error) {
+	var rows []*domain.BudgetSpendRow
+
+	// Deleted budgets still label the spend that was booked against them
+	query := r.expenses(ctx, userId, filter).
+		Select(lineBudget + ` AS budget_id,
+			COALESCE(budgets.name, '') AS budget_name,
+			COALESCE(budgets.category, '') AS category,
+			SUM(` + lineAmount + `) AS amount,
+			COUNT(*) AS count`).
+		Joins(joinSplitLines).
+		Joins("LEFT JOIN budgets ON budgets.id = " + lineBudget).
+		Group(lineBudget + ", budgets.name, budgets.category")
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *reportRepository) SpendByTag(ctx context.Context, userId string, filter domain.SpendFilter) ([]*domain.TagSpendRow, error) {
+	var rows []*domain.TagSpendRow
+
+	query := r.expenses(ctx, userId, filter).
+		Select(`tags.id AS tag_id,
+			tags.name,
+			SUM(transactions.amount) AS amount,
+			COUNT(*) AS count`).
+		Joins("JOIN transaction_tags ON transaction_tags.transaction_id = transactions.id").
+		Joins("JOIN tags ON tags.id = transaction_tags.tag_id AND tags.deleted_at = 0").
+		Group("tags.id, tags.name")
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *reportRepository) TopNotes(ctx context.Context, userId string, filter domain.SpendFilter, limit int) ([]*domain.NoteSpendRow, error) {
+	var rows []*domain.NoteSpendRow
+
+	query := r.expenses(ctx, userId, filter).
+		Select(`MIN(btrim(transactions.note)) AS note,
+			SUM(transactions.amount) AS amount,
+			COUNT(*) AS count`).
+		Where("btrim(transactions.note) <> ''").
+		Group("lower(btrim(transactions.note))").
+		Order("amount DESC, note").
+		Limit(limit)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *reportRepository) FlowHistory(ctx context.Context, userId string, filter domain.FlowFilter) ([]*domain.FlowRow, error) {
+	var rows []*domain.FlowRow
+
+	query := conn(ctx, r.db).Table("transactions").
+		Select(`transactions.wallet_id, transactions.type, `+lineAmount+` AS amount, transactions.note,
+			COALESCE(budgets.category, '') AS category, transactions.transaction_date,
+			transaction_splits.transaction_id`).
+		Joins("JOIN has_transactions ON has_transactions.transaction_id = transactions.id").
+		Joins(joinSplitLines).
+		Joins("LEFT JOIN budgets ON budgets.id = "+lineBudget).
+		Where("has_transactions.user_id = ? AND transactions.deleted_at = 0", userId).
+		Where("transactions.transaction_date >= ? AND transactions.transaction_date < ?", filter.From, filter.To)
+
+	if filter.WalletID != "" {
+		query = query.Where("transactions.wallet_id = ?", filter.WalletID)
+	}
+
+	if err := query.Order("transactions.transaction_date, transactions.id, transaction_splits.position").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// expenses scopes a query to the user's live expenses within the filter
+func (r *reportRepository) expenses(ctx context.Context, userId string, filter domain.SpendFilter) *gorm.DB {
+	query := conn(ctx, r.db).Table("transactions").
+		Joins("JOIN has_transactions ON has_transactions.transaction_id = transactions.id").
+		Where("has_transactions.user_id = ? AND transactions.deleted_at = 0", userId).
+		Where("transactions.type = ?", constant.TransactionTypeExpense).
+		Where("transactions.transaction_date >= ? AND transactions.transaction_date < ?", filter.From, filter.To)
+
+	if filter.WalletID != "" {
+		query = query.Where("transactions.wallet_id = ?", filter.WalletID)
+	}
+
+	return query
+}
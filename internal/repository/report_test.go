@@ -0,0 +1,381 @@
+package repository_test
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportRepositorySummary(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewReportRepository(db)
+	transactions := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	wallet := createWallet(t, db, owner, 1000)
+	savings := createWallet(t, db, owner, 1000)
+	otherWallet := createWallet(t, db, other, 1000)
+	budget := createBudget(t, db, owner, "Food")
+
+	// 2025-08-01 20:00 UTC is already 2025-08-02 in Asia/Jakarta (UTC+7)
+	lateEvening := int(time.Date(2025, 8, 1, 20, 0, 0, 0, time.UTC).Unix())
+	noon := int(time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC).Unix())
+
+	seed := []struct {
+		user        *domain.User
+		transaction *domain.Transaction
+	}{
+		{owner, &domain.Transaction{Amount: 500, Type: "income", TransactionDate: noon, WalletID: wallet.ID}},
+		{owner, &domain.Transaction{Amount: 200, Type: "expense", TransactionDate: lateEvening, WalletID: wallet.ID, BudgetID: &budget.ID}},
+		{owner, &domain.Transaction{Amount: 30, Type: "expense", TransactionDate: lateEvening, WalletID: savings.ID}},
+		{other, &domain.Transaction{Amount: 999, Type: "income", TransactionDate: noon, WalletID: otherWallet.ID}},
+	}
+	for _, s := range seed {
+		if err := transactions.Create(ctx, s.user.ID.String(), s.transaction); err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+	}
+
+	from := int(time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC).Unix())
+	to := int(time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC).Unix())
+
+	type row struct {
+		date    string
+		income  float64
+		expense float64
+		count   int
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.SummaryFilter
+		want   []row
+	}{
+		{
+			name:   "grouped by UTC day",
+			filter: domain.SummaryFilter{From: from, To: to, GroupBy: "day", Timezone: "UTC"},
+			want:   []row{{"2025-08-01", 500, 230, 3}},
+		},
+		{
+			name:   "grouped by Jakarta day",
+			filter: domain.SummaryFilter{From: from, To: to, GroupBy: "day", Timezone: "Asia/Jakarta"},
+			want:   []row{{"2025-08-01", 500, 0, 1}, {"2025-08-02", 0, 230, 2}},
+		},
+		{
+			name:   "filtered by wallet",
+			filter: domain.SummaryFilter{From: from, To: to, GroupBy: "month", Timezone: "UTC", WalletID: savings.ID.String()},
+			want:   []row{{"2025-08-01", 0, 30, 1}},
+		},
+		{
+			name:   "filtered by budget",
+			filter: domain.SummaryFilter{From: from, To: to, GroupBy: "week", Timezone: "UTC", BudgetID: budget.ID.String()},
+			want:   []row{{"2025-07-28", 0, 200, 1}},
+		},
+		{
+			name:   "range end is exclusive",
+			filter: domain.SummaryFilter{From: from, To: noon, GroupBy: "day", Timezone: "UTC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.Summary(ctx, owner.ID.String(), tt.filter)
+			if err != nil {
+				t.Fatalf("Summary() error = %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("Summary() returned %d rows, want %d", len(rows), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := rows[i]
+				if got.Bucket.Format(time.DateOnly) != want.date || got.Income != want.income || got.Expense != want.expense || got.Count != want.count {
+					t.Errorf("row %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReportRepositorySpend(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewReportRepository(db)
+	transactions := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	wallet := createWallet(t, db, owner, 1000)
+	savings := createWallet(t, db, owner, 1000)
+	otherWallet := createWallet(t, db, other, 1000)
+	budget := createBudget(t, db, owner, "Food")
+
+	seed := []struct {
+		user        *domain.User
+		transaction *domain.Transaction
+	}{
+		{owner, &domain.Transaction{Amount: 120, Type: "expense", Note: "Coffee", TransactionDate: 1000, WalletID: wallet.ID, BudgetID: &budget.ID}},
+		{owner, &domain.Transaction{Amount: 80, Type: "expense", Note: " coffee ", TransactionDate: 1100, WalletID: wallet.ID, BudgetID: &budget.ID}},
+		{owner, &domain.Transaction{Amount: 150, Type: "expense", Note: "Rent", TransactionDate: 1200, WalletID: savings.ID}},
+		{owner, &domain.Transaction{Amount: 40, Type: "expense", TransactionDate: 1300, WalletID: wallet.ID}},
+		{owner, &domain.Transaction{Amount: 999, Type: "income", Note: "Salary", TransactionDate: 1400, WalletID: wallet.ID}},
+		{owner, &domain.Transaction{Amount: 70, Type: "expense", Note: "Coffee", TransactionDate: 2000, WalletID: wallet.ID}},
+		{other, &domain.Transaction{Amount: 500, Type: "expense", Note: "Coffee", TransactionDate: 1000, WalletID: otherWallet.ID}},
+	}
+	for _, s := range seed {
+		if err := transactions.Create(ctx, s.user.ID.String(), s.transaction); err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+	}
+
+	t.Run("by budget", func(t *testing.T) {
+		rows, err := repo.SpendByBudget(ctx, owner.ID.String(), domain.SpendFilter{From: 1000, To: 2000})
+		if err != nil {
+			t.Fatalf("SpendByBudget() error = %v", err)
+		}
+
+		got := make(map[string]domain.BudgetSpendRow)
+		for _, row := range rows {
+			key := ""
+			if row.BudgetID != nil {
+				key = row.BudgetID.String()
+			}
+			got[key] = *row
+		}
+		if len(got) != 2 {
+			t.Fatalf("SpendByBudget() returned %d rows, want 2: %+v", len(rows), rows)
+		}
+		if food := got[budget.ID.String()]; food.Amount != 200 || food.Count != 2 || food.Category != "food" || food.BudgetName != "Food" {
+			t.Errorf("budgeted row = %+v", food)
+		}
+		if unbudgeted := got[""]; unbudgeted.Amount != 190 || unbudgeted.Count != 2 || unbudgeted.Category != "" {
+			t.Errorf("unbudgeted row = %+v", unbudgeted)
+		}
+	})
+
+	t.Run("by budget for one wallet", func(t *testing.T) {
+		rows, err := repo.SpendByBudget(ctx, owner.ID.String(), domain.SpendFilter{From: 1000, To: 2000, WalletID: savings.ID.String()})
+		if err != nil {
+			t.Fatalf("SpendByBudget() error = %v", err)
+		}
+		if len(rows) != 1 || rows[0].Amount != 150 {
+			t.Fatalf("SpendByBudget() = %+v, want only the rent", rows)
+		}
+	})
+
+	t.Run("by tag", func(t *testing.T) {
+		tags := repository.NewTagRepository(db)
+		coffee := createTag(t, db, owner, "coffee")
+		work := createTag(t, db, owner, "work")
+		ids := func(indexes ...int) []string {
+			var ids []string
+			for _, i := range indexes {
+				ids = append(ids, seed[i].transaction.ID.String())
+			}
+			return ids
+		}
+		// Income and spending outside the period carry tags too but do not count
+		if _, err := tags.Link(ctx, ids(0, 1, 5), []string{coffee.ID.String()}); err != nil {
+			t.Fatalf("Link() error = %v", err)
+		}
+		if _, err := tags.Link(ctx, ids(0, 2, 4), []string{work.ID.String()}); err != nil {
+			t.Fatalf("Link() error = %v", err)
+		}
+
+		rows, err := repo.SpendByTag(ctx, owner.ID.String(), domain.SpendFilter{From: 1000, To: 2000})
+		if err != nil {
+			t.Fatalf("SpendByTag() error = %v", err)
+		}
+
+		got := make(map[string]domain.TagSpendRow)
+		for _, row := range rows {
+			got[row.Name] = *row
+		}
+		if len(got) != 2 {
+			t.Fatalf("SpendByTag() returned %d rows, want 2: %+v", len(rows), rows)
+		}
+		if row := got["coffee"]; row.TagID != coffee.ID || row.Amount != 200 || row.Count != 2 {
+			t.Errorf("coffee row = %+v", row)
+		}
+		// A transaction with both tags counts towards each of them
+		if row := got["work"]; row.TagID != work.ID || row.Amount != 270 || row.Count != 2 {
+			t.Errorf("work row = %+v", row)
+		}
+	})
+
+	t.Run("top notes", func(t *testing.T) {
+		rows, err := repo.TopNotes(ctx, owner.ID.String(), domain.SpendFilter{From: 1000, To: 2000}, 5)
+		if err != nil {
+			t.Fatalf("TopNotes() error = %v", err)
+		}
+		if len(rows) != 2 {
+			t.Fatalf("TopNotes() returned %d rows, want 2: %+v", len(rows), rows)
+		}
+		if !strings.EqualFold(rows[0].Note, "coffee") || rows[0].Amount != 200 || rows[0].Count != 2 {
+			t.Errorf("first note = %+v, want both coffees", rows[0])
+		}
+		if rows[1].Note != "Rent" || rows[1].Amount != 150 {
+			t.Errorf("second note = %+v, want the rent", rows[1])
+		}
+	})
+
+	t.Run("top notes limit", func(t *testing.T) {
+		rows, err := repo.TopNotes(ctx, owner.ID.String(), domain.SpendFilter{From: 1000, To: 2000}, 1)
+		if err != nil {
+			t.Fatalf("TopNotes() error = %v", err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("TopNotes() returned %d rows, want 1", len(rows))
+		}
+	})
+}
+
+func TestReportRepositoryFlowHistory(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewReportRepository(db)
+	transactions := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	wallet := createWallet(t, db, owner, 1000)
+	savings := createWallet(t, db, owner, 1000)
+	otherWallet := createWallet(t, db, other, 1000)
+	budget := createBudget(t, db, owner, "Food")
+
+	seed := []struct {
+		user        *domain.User
+		transaction *domain.Transaction
+	}{
+		{owner, &domain.Transaction{Amount: 3000, Type: "income", Note: "Salary", TransactionDate: 1200, WalletID: wallet.ID}},
+		{owner, &domain.Transaction{Amount: 25, Type: "expense", Note: "Lunch", TransactionDate: 1000, WalletID: wallet.ID, BudgetID: &budget.ID}},
+		{owner, &domain.Transaction{Amount: 50, Type: "expense", TransactionDate: 1100, WalletID: savings.ID}},
+		{owner, &domain.Transaction{Amount: 70, Type: "expense", TransactionDate: 2000, WalletID: wallet.ID}},
+		{other, &domain.Transaction{Amount: 500, Type: "expense", TransactionDate: 1000, WalletID: otherWallet.ID}},
+	}
+	for _, s := range seed {
+		if err := transactions.Create(ctx, s.user.ID.String(), s.transaction); err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+	}
+
+	t.Run("oldest first", func(t *testing.T) {
+		rows, err := repo.FlowHistory(ctx, owner.ID.String(), domain.FlowFilter{From: 1000, To: 2000})
+		if err != nil {
+			t.Fatalf("FlowHistory() error = %v", err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("FlowHistory() returned %d rows, want 3: %+v", len(rows), rows)
+		}
+		if rows[0].Amount != 25 || rows[0].Category != "food" || rows[0].Note != "Lunch" || rows[0].WalletID != wallet.ID {
+			t.Errorf("first row = %+v, want the lunch", rows[0])
+		}
+		if rows[1].Amount != 50 || rows[1].Category != "" {
+			t.Errorf("second row = %+v, want the unbudgeted expense", rows[1])
+		}
+		if rows[2].Type != "income" || rows[2].TransactionDate != 1200 {
+			t.Errorf("third row = %+v, want the salary", rows[2])
+		}
+	})
+
+	t.Run("for one wallet", func(t *testing.T) {
+		rows, err := repo.FlowHistory(ctx, owner.ID.String(), domain.FlowFilter{From: 1000, To: 2000, WalletID: savings.ID.String()})
+		if err != nil {
+			t.Fatalf("FlowHistory() error = %v", err)
+		}
+		if len(rows) != 1 || rows[0].Amount != 50 {
+			t.Fatalf("FlowHistory() = %+v, want only the savings expense", rows)
+		}
+	})
+}
+
+func TestReportRepositorySplits(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewReportRepository(db)
+	transactions := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "splits@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	groceries := createBudget(t, db, user, "Groceries")
+	household := createBudget(t, db, user, "Household")
+
+	supermarket := &domain.Transaction{Amount: 150, Type: "expense", Note: "Supermarket", TransactionDate: 1000, WalletID: wallet.ID, Splits: []domain.TransactionSplit{
+		{Position: 1, Amount: 90, BudgetID: &groceries.ID},
+		{Position: 2, Amount: 40, BudgetID: &household.ID},
+		{Position: 3, Amount: 20, BudgetID: &groceries.ID},
+	}}
+	for _, transaction := range []*domain.Transaction{
+		supermarket,
+		{Amount: 60, Type: "expense", Note: "Bakery", TransactionDate: 1100, WalletID: wallet.ID, BudgetID: &groceries.ID},
+		{Amount: 500, Type: "income", TransactionDate: 1200, WalletID: wallet.ID},
+	} {
+		if err := transactions.Create(ctx, user.ID.String(), transaction); err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+	}
+
+	t.Run("spend by budget counts the lines", func(t *testing.T) {
+		rows, err := repo.SpendByBudget(ctx, user.ID.String(), domain.SpendFilter{From: 1000, To: 2000})
+		if err != nil {
+			t.Fatalf("SpendByBudget() error = %v", err)
+		}
+
+		got := make(map[string]domain.BudgetSpendRow)
+		for _, row := range rows {
+			got[row.BudgetName] = *row
+		}
+		if len(got) != 2 {
+			t.Fatalf("SpendByBudget() = %+v, want groceries and household", rows)
+		}
+		if row := got["Groceries"]; row.Amount != 170 || row.Count != 3 {
+			t.Errorf("groceries row = %+v, want 170 over two lines and the bakery", row)
+		}
+		if row := got["Household"]; row.Amount != 40 || row.Count != 1 {
+			t.Errorf("household row = %+v, want 40", row)
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		for _, tt := range []struct {
+			name      string
+			budget    string
+			wantSpend float64
+			wantCount int
+		}{
+			{name: "every budget", wantSpend: 210, wantCount: 3},
+			{name: "one budget", budget: household.ID.String(), wantSpend: 40, wantCount: 1},
+		} {
+			rows, err := repo.Summary(ctx, user.ID.String(), domain.SummaryFilter{From: 1000, To: 2000, GroupBy: "day", Timezone: "UTC", BudgetID: tt.budget})
+			if err != nil {
+				t.Fatalf("Summary() error = %v", err)
+			}
+			if len(rows) != 1 || rows[0].Expense != tt.wantSpend || rows[0].Count != tt.wantCount {
+				t.Errorf("%s: Summary() = %+v, want %v spent over %d transactions", tt.name, rows, tt.wantSpend, tt.wantCount)
+			}
+		}
+	})
+
+	t.Run("flow history has a row per line", func(t *testing.T) {
+		rows, err := repo.FlowHistory(ctx, user.ID.String(), domain.FlowFilter{From: 1000, To: 2000})
+		if err != nil {
+			t.Fatalf("FlowHistory() error = %v", err)
+		}
+		if len(rows) != 5 {
+			t.Fatalf("FlowHistory() returned %d rows, want 5: %+v", len(rows), rows)
+		}
+		for _, row := range rows[:3] {
+			if row.TransactionID == nil || *row.TransactionID != supermarket.ID || row.Category != "food" {
+				t.Errorf("line = %+v, want a line of the supermarket receipt", row)
+			}
+		}
+		if rows[1].Amount != 40 || rows[3].TransactionID != nil || rows[3].Amount != 60 {
+			t.Errorf("rows = %+v, want the lines in order then whole transactions", rows)
+		}
+	})
+}
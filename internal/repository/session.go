@@ -8,25 +8,37 @@ import (
 )
 
 type sessionRepository struct {
+	db *gorm.DB
 }
 
-func NewSessionRepository() domain.SessionRepository {
-	return &sessionRepository{}
+func NewSessionRepository(db *gorm.DB) domain.SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
 }
 
-func (r *sessionRepository) Create(db *gorm.DB, ctx context.Context, session *domain.Session) error {
-	return db.Create(session).Error
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	return conn(ctx, r.db).Create(session).Error
 }
 
-func (r *sessionRepository) GetByToken(db *gorm.DB, ctx context.Context, token string) (*domain.Session, error) {
+func (r *sessionRepository) GetByToken(ctx context.Context, token string) (*domain.Session, error) {
 	var session domain.Session
-	err := db.WithContext(ctx).Preload("User").Where("session_token = ?", token).First(&session).Error
+	err := conn(ctx, r.db).Preload("User").Where("session_token = ?", token).First(&session).Error
 	if err != nil {
 		return nil, err
 	}
 	return &session, nil
 }
 
-func (r *sessionRepository) Delete(db *gorm.DB, ctx context.Context, token string) error {
-	return db.WithContext(ctx).Where("session_token = ?", token).Delete(&domain.Session{}).Error
+func (r *sessionRepository) Delete(ctx context.Context, token string) error {
+	return conn(ctx, r.db).Where("session_token = ?", token).Delete(&domain.Session{}).Error
+}
+
+func (r *sessionRepository) DeleteByUserID(ctx context.Context, userId string) error {
+	return conn(ctx, r.db).Where("user_id = ?", userId).Delete(&domain.Session{}).Error
+}
+
+func (r *sessionRepository) DeleteExpired(ctx context.Context, now int) (int64, error) {
+	result := conn(ctx, r.db).Where("expires_at < ?", now).Delete(&domain.Session{})
+	return result.RowsAffected, result.Error
 }
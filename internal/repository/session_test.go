@@ -0,0 +1,146 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func createSession(t *testing.T, db *gorm.DB, user *domain.User, token string, expiresAt int) *domain.Session {
+	t.Helper()
+
+	session := &domain.Session{
+		ID:           uuid.New(),
+		SessionToken: token,
+		UserID:       user.ID,
+		ExpiresAt:    expiresAt,
+	}
+	if err := repository.NewSessionRepository(db).Create(context.Background(), session); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	return session
+}
+
+func countSessions(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&domain.Session{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count sessions: %v", err)
+	}
+	return count
+}
+
+func TestSessionRepositoryGetByToken(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewSessionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "session@example.com")
+	createSession(t, db, user, "token-a", 2000000000)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "existing token", token: "token-a"},
+		{name: "unknown token", token: "token-b", wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := repo.GetByToken(ctx, tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetByToken() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByToken() error = %v", err)
+			}
+			if session.User.Email != user.Email {
+				t.Fatalf("preloaded user email = %q, want %q", session.User.Email, user.Email)
+			}
+			if session.CreatedAt == 0 {
+				t.Fatal("CreatedAt was not set")
+			}
+		})
+	}
+}
+
+func TestSessionRepositoryDelete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		run       func(repo domain.SessionRepository, owner *domain.User) (int64, error)
+		wantCount int64
+	}{
+		{
+			name: "by token",
+			run: func(repo domain.SessionRepository, owner *domain.User) (int64, error) {
+				return 0, repo.Delete(ctx, "owner-current")
+			},
+			wantCount: 2,
+		},
+		{
+			name: "by user",
+			run: func(repo domain.SessionRepository, owner *domain.User) (int64, error) {
+				return 0, repo.DeleteByUserID(ctx, owner.ID.String())
+			},
+			wantCount: 1,
+		},
+		{
+			name: "expired only",
+			run: func(repo domain.SessionRepository, owner *domain.User) (int64, error) {
+				return repo.DeleteExpired(ctx, 1500000000)
+			},
+			wantCount: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.New(t)
+
+			owner := createUser(t, db, "owner@example.com")
+			other := createUser(t, db, "other@example.com")
+			createSession(t, db, owner, "owner-current", 2000000000)
+			createSession(t, db, owner, "owner-expired", 1000000000)
+			createSession(t, db, other, "other-current", 2000000000)
+
+			if _, err := tt.run(repository.NewSessionRepository(db), owner); err != nil {
+				t.Fatalf("delete error = %v", err)
+			}
+			if count := countSessions(t, db); count != tt.wantCount {
+				t.Fatalf("sessions left = %d, want %d", count, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestSessionRepositoryDeleteExpiredReportsCount(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewSessionRepository(db)
+
+	user := createUser(t, db, "expired@example.com")
+	createSession(t, db, user, "expired-1", 1000000000)
+	createSession(t, db, user, "expired-2", 1000000001)
+	createSession(t, db, user, "current", 2000000000)
+
+	deleted, err := repo.DeleteExpired(context.Background(), 1500000000)
+	if err != nil {
+		t.Fatalf("DeleteExpired() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("DeleteExpired() = %d, want 2", deleted)
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type snapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewSnapshotRepository(db *gorm.DB) domain.SnapshotRepository {
+	return &snapshotRepository{
+		db: db,
+	}
+}
+
+func (r *snapshotRepository) Capture(ctx context.Context, date int) (int64, error) {
+	now := time.Now().Unix()
+
+	result := conn(ctx, r.db).Exec(`INSERT INTO wallet_balance_snapshots (wallet_id, snapshot_date, balance, created_at, updated_at)
+		SELECT id, ?, balance, ?, ? FROM wallets WHERE deleted_at = 0
+		ON CONFLICT (wallet_id, snapshot_date) DO UPDATE SET balance = EXCLUDED.balance, updated_at = EXCLUDED.updated_at`,
+		date, now, now)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *snapshotRepository) Upsert(ctx context.Context, snapshots []*domain.WalletSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	return conn(ctx, r.db).
+		Omit("Wallet").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "wallet_id"}, {Name: "snapshot_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"balance", "updated_at"}),
+		}).
+		CreateInBatches(snapshots, 500).Error
+}
+
+func (r *snapshotRepository) GetRange(ctx context.Context, userId string, from, to int) ([]*domain.WalletSnapshot, error) {
+	var snapshots []*domain.WalletSnapshot
+
+	// The latest snapshot before the range carries each wallet's balance into its first days
+	err := conn(ctx, r.db).
+		Joins("JOIN has_wallets ON has_wallets.wallet_id = wallet_balance_snapshots.wallet_id").
+		Where("has_wallets.user_id = ? AND wallet_balance_snapshots.snapshot_date < ?", userId, to).
+		Where(`wallet_balance_snapshots.snapshot_date >= ? OR wallet_balance_snapshots.snapshot_date = (
+			SELECT MAX(previous.snapshot_date) FROM wallet_balance_snapshots previous
+			WHERE previous.wallet_id = wallet_balance_snapshots.wallet_id AND previous.snapshot_date < ?)`, from, from).
+		Order("wallet_balance_snapshots.snapshot_date, wallet_balance_snapshots.wallet_id").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+)
+
+const oneDay = 86400
+
+func TestSnapshotRepositoryCapture(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewSnapshotRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "capture@example.com")
+	wallet := createWallet(t, db, user, 100)
+	createWallet(t, db, user, 200)
+
+	captured, err := repo.Capture(ctx, 10*oneDay)
+	if err != nil || captured != 2 {
+		t.Fatalf("Capture() = %d, %v, want 2 wallets", captured, err)
+	}
+
+	// Capturing the same oneDay again overwrites the earlier balance
+	if err := repository.NewWalletRepository(db).IncreaseBalance(ctx, wallet.ID.String(), 50); err != nil {
+		t.Fatalf("IncreaseBalance() error = %v", err)
+	}
+	if _, err := repo.Capture(ctx, 10*oneDay); err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+
+	var snapshot domain.WalletSnapshot
+	if err := db.First(&snapshot, "wallet_id = ? AND snapshot_date = ?", wallet.ID, 10*oneDay).Error; err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+	if snapshot.Balance != 150 {
+		t.Fatalf("snapshot balance = %v, want 150", snapshot.Balance)
+	}
+
+	var count int64
+	if err := db.Model(&domain.WalletSnapshot{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count snapshots: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("stored %d snapshots, want 2", count)
+	}
+}
+
+func TestSnapshotRepositoryGetRange(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewSnapshotRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	wallet := createWallet(t, db, owner, 100)
+	savings := createWallet(t, db, owner, 100)
+	otherWallet := createWallet(t, db, other, 100)
+
+	err := repo.Upsert(ctx, []*domain.WalletSnapshot{
+		{WalletID: wallet.ID, SnapshotDate: 1 * oneDay, Balance: 10},
+		{WalletID: wallet.ID, SnapshotDate: 2 * oneDay, Balance: 20},
+		{WalletID: wallet.ID, SnapshotDate: 5 * oneDay, Balance: 50},
+		{WalletID: wallet.ID, SnapshotDate: 9 * oneDay, Balance: 90},
+		{WalletID: savings.ID, SnapshotDate: 6 * oneDay, Balance: 60},
+		{WalletID: otherWallet.ID, SnapshotDate: 5 * oneDay, Balance: 500},
+	})
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	snapshots, err := repo.GetRange(ctx, owner.ID.String(), 4*oneDay, 8*oneDay)
+	if err != nil {
+		t.Fatalf("GetRange() error = %v", err)
+	}
+
+	// The oneDay 2 snapshot carries the wallet into the range, later and foreign snapshots are left out
+	want := []float64{20, 50, 60}
+	if len(snapshots) != len(want) {
+		t.Fatalf("GetRange() returned %d snapshots, want %d: %+v", len(snapshots), len(want), snapshots)
+	}
+	for i, balance := range want {
+		if snapshots[i].Balance != balance {
+			t.Errorf("snapshot %d balance = %v, want %v", i, snapshots[i].Balance, balance)
+		}
+	}
+}
+
+func TestExchangeRateRepository(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewExchangeRateRepository(db)
+	ctx := context.Background()
+
+	for _, rate := range []*domain.ExchangeRate{
+		{Currency: "EUR", RateDate: 1 * oneDay, Rate: 1.1},
+		{Currency: "EUR", RateDate: 3 * oneDay, Rate: 1.2},
+		{Currency: "EUR", RateDate: 3 * oneDay, Rate: 1.25},
+		{Currency: "GBP", RateDate: 1 * oneDay, Rate: 1.3},
+		{Currency: "JPY", RateDate: 1 * oneDay, Rate: 0.007},
+		{Currency: "EUR", RateDate: 9 * oneDay, Rate: 1.4},
+	} {
+		if err := repo.Upsert(ctx, rate); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+	}
+
+	rates, err := repo.GetUntil(ctx, []string{"EUR", "GBP"}, 5*oneDay)
+	if err != nil {
+		t.Fatalf("GetUntil() error = %v", err)
+	}
+
+	want := []domain.ExchangeRate{
+		{Currency: "EUR", RateDate: 1 * oneDay, Rate: 1.1},
+		{Currency: "EUR", RateDate: 3 * oneDay, Rate: 1.25},
+		{Currency: "GBP", RateDate: 1 * oneDay, Rate: 1.3},
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("GetUntil() returned %d rates, want %d: %+v", len(rates), len(want), rates)
+	}
+	for i := range want {
+		if rates[i].Currency != want[i].Currency || rates[i].RateDate != want[i].RateDate || rates[i].Rate != want[i].Rate {
+			t.Errorf("rate %d = %+v, want %+v", i, rates[i], want[i])
+		}
+	}
+}
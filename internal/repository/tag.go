@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) domain.TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *domain.Tag) error {
+	return conn(ctx, r.db).Create(tag).Error
+}
+
+func (r *tagRepository) GetList(ctx context.Context, userId string, prefix string, limit int) ([]*domain.Tag, error) {
+	var tags []*domain.Tag
+
+	query := conn(ctx, r.db).Where("user_id = ?", userId)
+	if prefix != "" {
+		query = query.Where("name LIKE ?", likePrefix(prefix))
+	}
+
+	if err := query.Order("name").Limit(limit).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepository) GetDetail(ctx context.Context, userId string, tagId string) (*domain.Tag, error) {
+	var tag domain.Tag
+
+	if err := conn(ctx, r.db).Where("user_id = ? AND id = ?", userId, tagId).First(&tag).Error; err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *tagRepository) GetByName(ctx context.Context, userId string, name string) (*domain.Tag, error) {
+	var tag domain.Tag
+
+	if err := conn(ctx, r.db).Where("user_id = ? AND name = ?", userId, name).First(&tag).Error; err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *tagRepository) GetByIDs(ctx context.Context, userId string, ids []string) ([]*domain.Tag, error) {
+	var tags []*domain.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+
+	if err := conn(ctx, r.db).Where("user_id = ? AND id IN ?", userId, ids).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *domain.Tag) error {
+	return conn(ctx, r.db).Save(tag).Error
+}
+
+func (r *tagRepository) Delete(ctx context.Context, tag *domain.Tag) error {
+	err := conn(ctx, r.db).Model(&domain.Transaction{}).
+		Where("id IN (SELECT transaction_id FROM transaction_tags WHERE tag_id = ?)", tag.ID).
+		Update("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Where("tag_id = ?", tag.ID).Delete(&domain.TransactionTag{}).Error; err != nil {
+		return err
+	}
+
+	return conn(ctx, r.db).Delete(tag).Error
+}
+
+func (r *tagRepository) Link(ctx context.Context, transactionIds []string, tagIds []string) (int64, error) {
+	if len(transactionIds) == 0 || len(tagIds) == 0 {
+		return 0, nil
+	}
+
+	// Bump the transactions that lack any of the tags before they gain them
+	err := conn(ctx, r.db).Model(&domain.Transaction{}).
+		Where("id IN ?", transactionIds).
+		Where(`(SELECT COUNT(*) FROM transaction_tags
+			WHERE transaction_tags.transaction_id = transactions.id AND transaction_tags.tag_id IN ?) < ?`, tagIds, len(tagIds)).
+		Update("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+
+	links := make([]domain.TransactionTag, 0, len(transactionIds)*len(tagIds))
+	for _, transactionId := range transactionIds {
+		for _, tagId := range tagIds {
+			links = append(links, domain.TransactionTag{
+				TransactionID: uuid.MustParse(transactionId),
+				TagID:         uuid.MustParse(tagId),
+			})
+		}
+	}
+
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&links)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *tagRepository) Unlink(ctx context.Context, transactionIds []string, tagIds []string) (int64, error) {
+	if len(transactionIds) == 0 || len(tagIds) == 0 {
+		return 0, nil
+	}
+
+	err := conn(ctx, r.db).Model(&domain.Transaction{}).
+		Where("id IN (SELECT transaction_id FROM transaction_tags WHERE transaction_id IN ? AND tag_id IN ?)", transactionIds, tagIds).
+		Update("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+
+	result := conn(ctx, r.db).
+		Where("transaction_id IN ? AND tag_id IN ?", transactionIds, tagIds).
+		Delete(&domain.TransactionTag{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// likePrefix builds a LIKE pattern matching values that start with prefix, wildcards in it match literally
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
+}
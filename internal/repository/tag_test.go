@@ -0,0 +1,157 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func createTag(t *testing.T, db *gorm.DB, user *domain.User, name string) *domain.Tag {
+	t.Helper()
+
+	tag := &domain.Tag{UserID: user.ID, Name: name}
+	if err := repository.NewTagRepository(db).Create(context.Background(), tag); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	return tag
+}
+
+func TestTagRepositoryGetList(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTagRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	for _, name := range []string{"vacation", "vat", "van_rental", "vans", "groceries"} {
+		createTag(t, db, owner, name)
+	}
+	createTag(t, db, other, "vacuum")
+
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{name: "all in name order", limit: 10, want: []string{"groceries", "vacation", "van_rental", "vans", "vat"}},
+		{name: "prefix", prefix: "va", limit: 10, want: []string{"vacation", "van_rental", "vans", "vat"}},
+		{name: "wildcards match literally", prefix: "van_", limit: 10, want: []string{"van_rental"}},
+		{name: "limit", prefix: "va", limit: 2, want: []string{"vacation", "van_rental"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := repo.GetList(ctx, owner.ID.String(), tt.prefix, tt.limit)
+			if err != nil {
+				t.Fatalf("GetList() error = %v", err)
+			}
+			got := make([]string, 0, len(tags))
+			for _, tag := range tags {
+				got = append(got, tag.Name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetList() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("GetList() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTagRepositoryLink(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTagRepository(db)
+	transactions := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "link@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	vacation := createTag(t, db, user, "vacation")
+	reimbursable := createTag(t, db, user, "reimbursable")
+
+	var ids []string
+	for _, amount := range []float64{10, 20, 30} {
+		transaction := &domain.Transaction{Amount: amount, Type: "expense", TransactionDate: 1000, WalletID: wallet.ID}
+		if err := transactions.Create(ctx, user.ID.String(), transaction); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ids = append(ids, transaction.ID.String())
+	}
+
+	version := func(id string) int {
+		t.Helper()
+		transaction, err := transactions.GetDetail(ctx, user.ID.String(), id)
+		if err != nil {
+			t.Fatalf("GetDetail() error = %v", err)
+		}
+		return transaction.Version
+	}
+
+	if added, err := repo.Link(ctx, ids[:1], []string{vacation.ID.String()}); err != nil || added != 1 {
+		t.Fatalf("Link() = %d, %v, want 1", added, err)
+	}
+	// Links that already exist are kept and not counted
+	added, err := repo.Link(ctx, ids, []string{vacation.ID.String(), reimbursable.ID.String()})
+	if err != nil || added != 5 {
+		t.Fatalf("Link() = %d, %v, want 5", added, err)
+	}
+	if v := version(ids[0]); v != 3 {
+		t.Fatalf("version after two links = %d, want 3", v)
+	}
+	if added, err := repo.Link(ctx, ids, []string{vacation.ID.String()}); err != nil || added != 0 {
+		t.Fatalf("Link() again = %d, %v, want 0", added, err)
+	}
+	if v := version(ids[1]); v != 2 {
+		t.Fatalf("version after a link that changed nothing = %d, want 2", v)
+	}
+
+	tagged, err := transactions.GetList(ctx, user.ID.String(), domain.TransactionFilter{TagIDs: []string{vacation.ID.String(), reimbursable.ID.String()}})
+	if err != nil || len(tagged) != 3 {
+		t.Fatalf("GetList() with both tags = %d, %v, want 3", len(tagged), err)
+	}
+	if tags := tagged[0].Tags; len(tags) != 2 || tags[0].Name != "reimbursable" || tags[1].Name != "vacation" {
+		t.Fatalf("preloaded tags = %+v, want both by name", tags)
+	}
+
+	removed, err := repo.Unlink(ctx, ids[1:], []string{reimbursable.ID.String()})
+	if err != nil || removed != 2 {
+		t.Fatalf("Unlink() = %d, %v, want 2", removed, err)
+	}
+	if v := version(ids[1]); v != 3 {
+		t.Fatalf("version after unlink = %d, want 3", v)
+	}
+
+	// Every requested tag has to be on a transaction for it to match
+	tagged, err = transactions.GetList(ctx, user.ID.String(), domain.TransactionFilter{TagIDs: []string{vacation.ID.String(), reimbursable.ID.String()}})
+	if err != nil || len(tagged) != 1 || tagged[0].ID.String() != ids[0] {
+		t.Fatalf("GetList() with both tags = %v, %v, want only the first transaction", tagged, err)
+	}
+	tagged, err = transactions.GetList(ctx, user.ID.String(), domain.TransactionFilter{TagIDs: []string{vacation.ID.String()}})
+	if err != nil || len(tagged) != 3 {
+		t.Fatalf("GetList() with one tag = %d, %v, want 3", len(tagged), err)
+	}
+
+	if err := repo.Delete(ctx, vacation); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetDetail(ctx, user.ID.String(), vacation.ID.String()); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("GetDetail() after Delete() error = %v, want not found", err)
+	}
+	detail, err := transactions.GetDetail(ctx, user.ID.String(), ids[2])
+	if err != nil || len(detail.Tags) != 0 || detail.Version != 4 {
+		t.Fatalf("GetDetail() after tag delete = %+v, %v, want no tags at version 4", detail, err)
+	}
+
+	// A deleted name can be taken again
+	createTag(t, db, user, "vacation")
+}
@@ -2,20 +2,26 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"finance-backend/internal/constant"
 	"finance-backend/internal/domain"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-type transactionRepository struct{}
+type transactionRepository struct {
+	db *gorm.DB
+}
 
-func NewTransactionRepository() domain.TransactionRepository {
-	return &transactionRepository{}
+func NewTransactionRepository(db *gorm.DB) domain.TransactionRepository {
+	return &transactionRepository{
+		db: db,
+	}
 }
 
-func (r *transactionRepository) Create(db *gorm.DB, ctx context.Context, userId string, transaction *domain.Transaction) error {
-	if err := db.WithContext(ctx).Create(transaction).Error; err != nil {
+func (r *transactionRepository) Create(ctx context.Context, userId string, transaction *domain.Transaction) error {
+	if err := conn(ctx, r.db).Create(transaction).Error; err != nil {
 		return err
 	}
 
@@ -24,21 +30,22 @@ func (r *transactionRepository) Create(db *gorm.DB, ctx context.Context, userId
 		TransactionID: transaction.ID,
 	}
 
-	if err := db.WithContext(ctx).Create(&hasTransaction).Error; err != nil {
+	if err := conn(ctx, r.db).Create(&hasTransaction).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *transactionRepository) GetList(db *gorm.DB, ctx context.Context, userId string) ([]*domain.Transaction, error) {
+func (r *transactionRepository) GetList(ctx context.Context, userId string, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
 	var transactions []*domain.Transaction
 
-	err := db.WithContext(ctx).
-		Joins("JOIN has_transactions ON has_transactions.transaction_id = transactions.id").
-		Where("has_transactions.user_id = ?", userId).
+	err := r.filtered(ctx, userId, filter).
 		Preload("Wallet").
 		Preload("Budget").
+		Preload("Tags", orderTags).
+		Preload("Splits", orderSplits).
+		Preload("Splits.Budget").
 		Find(&transactions).Error
 	if err != nil {
 		return nil, err
@@ -47,14 +54,186 @@ func (r *transactionRepository) GetList(db *gorm.DB, ctx context.Context, userId
 	return transactions, nil
 }
 
-func (r *transactionRepository) GetDetail(db *gorm.DB, ctx context.Context, userId string, transactionId string) (*domain.Transaction, error) {
+func (r *transactionRepository) Update(ctx context.Context, transaction *domain.Transaction, version int) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Transaction{}).
+		Where("id = ? AND version = ?", transaction.ID, version).
+		Updates(map[string]interface{}{
+			"amount":           transaction.Amount,
+			"type":             transaction.Type,
+			"note":             transaction.Note,
+			"transaction_date": transaction.TransactionDate,
+			"wallet_id":        transaction.WalletID,
+			"budget_id":        transaction.BudgetID,
+			"version":          gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return false, nil
+	}
+
+	if err := conn(ctx, r.db).Where("transaction_id = ?", transaction.ID).Delete(&domain.TransactionSplit{}).Error; err != nil {
+		return false, err
+	}
+	if len(transaction.Splits) > 0 {
+		for i := range transaction.Splits {
+			transaction.Splits[i].TransactionID = transaction.ID
+		}
+		if err := conn(ctx, r.db).Create(&transaction.Splits).Error; err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (r *transactionRepository) Stream(ctx context.Context, userId string, filter domain.TransactionFilter) (domain.TransactionCursor, error) {
+	// Deleted wallets and budgets still label the transactions that were booked against them
+	rows, err := r.filtered(ctx, userId, filter).
+		Select(`transactions.id,
+			transactions.transaction_date,
+			transactions.type,
+			` + lineAmount + ` AS amount,
+			transactions.note,
+			COALESCE(transaction_splits.note, '') AS split_note,
+			COALESCE(transactions.external_id, '') AS external_id,
+			transactions.wallet_id,
+			wallets.name AS wallet_name,
+			wallets.type AS wallet_type,
+			wallets.currency,
+			COALESCE(budgets.name, '') AS budget_name,
+			COALESCE(budgets.category, '') AS category`).
+		Joins("JOIN wallets ON wallets.id = transactions.wallet_id").
+		Joins(joinSplitLines).
+		Joins("LEFT JOIN budgets ON budgets.id = " + lineBudget).
+		Order("transactions.transaction_date, transactions.id, transaction_splits.position").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionCursor{rows: rows, db: r.db}, nil
+}
+
+func (r *transactionRepository) GetByIDs(ctx context.Context, userId string, ids []string) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	if len(ids) == 0 {
+		return transactions, nil
+	}
+
+	err := conn(ctx, r.db).
+		Joins("JOIN has_transactions ON has_transactions.transaction_id = transactions.id").
+		Where("has_transactions.user_id = ? AND transactions.id IN ?", userId, ids).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionRepository) FlowByWallet(ctx context.Context, userId string, since int) ([]*domain.WalletFlowRow, error) {
+	var rows []*domain.WalletFlowRow
+
+	err := r.filtered(ctx, userId, domain.TransactionFilter{From: since}).
+		Select(`transactions.wallet_id,
+			SUM(CASE WHEN transactions.type = ? THEN transactions.amount ELSE -transactions.amount END) AS net,
+			MIN(transactions.transaction_date) AS first_date`, constant.TransactionTypeIncome).
+		Group("transactions.wallet_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// filtered selects the user's live transactions matching filter
+func (r *transactionRepository) filtered(ctx context.Context, userId string, filter domain.TransactionFilter) *gorm.DB {
+	query := conn(ctx, r.db).Model(&domain.Transaction{}).
+		Joins("JOIN has_transactions ON has_transactions.transaction_id = transactions.id").
+		Where("has_transactions.user_id = ?", userId)
+
+	if filter.From != 0 {
+		query = query.Where("transactions.transaction_date >= ?", filter.From)
+	}
+	if filter.To != 0 {
+		query = query.Where("transactions.transaction_date < ?", filter.To)
+	}
+	if filter.Type != "" {
+		query = query.Where("transactions.type = ?", filter.Type)
+	}
+	if filter.WalletID != "" {
+		query = query.Where("transactions.wallet_id = ?", filter.WalletID)
+	}
+	if filter.BudgetID != "" {
+		query = query.Where(`(transactions.budget_id = ? OR transactions.id IN (
+			SELECT transaction_splits.transaction_id FROM transaction_splits
+			WHERE transaction_splits.budget_id = ?))`, filter.BudgetID, filter.BudgetID)
+	}
+	if len(filter.TagIDs) > 0 {
+		query = query.Where(`transactions.id IN (
+			SELECT transaction_tags.transaction_id FROM transaction_tags
+			WHERE transaction_tags.tag_id IN ?
+			GROUP BY transaction_tags.transaction_id
+			HAVING COUNT(*) = ?)`, filter.TagIDs, len(filter.TagIDs))
+	}
+
+	return query
+}
+
+// orderTags lists the tags preloaded on transactions by name
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name")
+}
+
+// orderSplits lists the split lines preloaded on transactions in the order they were given
+func orderSplits(db *gorm.DB) *gorm.DB {
+	return db.Order("transaction_splits.position")
+}
+
+// Budget spend is counted per line. Queries joining joinSplitLines get a row for every line of a split
+// transaction and a single row for any other transaction, lineBudget and lineAmount read that row.
+const (
+	joinSplitLines = "LEFT JOIN transaction_splits ON transaction_splits.transaction_id = transactions.id"
+	lineBudget     = "CASE WHEN transaction_splits.id IS NULL THEN transactions.budget_id ELSE transaction_splits.budget_id END"
+	lineAmount     = "COALESCE(transaction_splits.amount, transactions.amount)"
+)
+
+// transactionCursor scans export rows from an open result set
+type transactionCursor struct {
+	rows *sql.Rows
+	db   *gorm.DB
+}
+
+func (c *transactionCursor) Next() bool {
+	return c.rows.Next()
+}
+
+func (c *transactionCursor) Scan(row *domain.TransactionExportRow) error {
+	return c.db.ScanRows(c.rows, row)
+}
+
+func (c *transactionCursor) Err() error {
+	return c.rows.Err()
+}
+
+func (c *transactionCursor) Close() error {
+	return c.rows.Close()
+}
+
+func (r *transactionRepository) GetDetail(ctx context.Context, userId string, transactionId string) (*domain.Transaction, error) {
 	var transaction domain.Transaction
 
-	err := db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Joins("JOIN has_transactions ON has_transactions.transaction_id = transactions.id").
 		Where("has_transactions.user_id = ? AND transactions.id = ?", userId, transactionId).
 		Preload("Wallet").
 		Preload("Budget").
+		Preload("Tags", orderTags).
+		Preload("Splits", orderSplits).
+		Preload("Splits.Budget").
 		First(&transaction).Error
 	if err != nil {
 		return nil, err
@@ -62,3 +241,47 @@ func (r *transactionRepository) GetDetail(db *gorm.DB, ctx context.Context, user
 
 	return &transaction, nil
 }
+
+func (r *transactionRepository) GetByWalletSince(ctx context.Context, walletId string, since int) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+
+	err := conn(ctx, r.db).
+		Where("wallet_id = ? AND transaction_date >= ?", walletId, since).
+		Order("transaction_date DESC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionRepository) GetByWalletBetween(ctx context.Context, walletId string, from, to int) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+
+	err := conn(ctx, r.db).
+		Where("wallet_id = ? AND transaction_date >= ? AND transaction_date < ?", walletId, from, to).
+		Order("transaction_date, id").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionRepository) GetByExternalIDs(ctx context.Context, walletId string, externalIds []string) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	if len(externalIds) == 0 {
+		return transactions, nil
+	}
+
+	err := conn(ctx, r.db).
+		Where("wallet_id = ? AND external_id IN ?", walletId, externalIds).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,482 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestTransactionRepositoryCreate(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "transaction@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	budget := createBudget(t, db, user, "Food")
+
+	tests := []struct {
+		name        string
+		transaction *domain.Transaction
+		wantErr     bool
+	}{
+		{
+			name:        "without budget",
+			transaction: &domain.Transaction{Amount: 100, Type: "income", TransactionDate: 1756450000, WalletID: wallet.ID},
+		},
+		{
+			name:        "with budget",
+			transaction: &domain.Transaction{Amount: 50, Type: "expense", TransactionDate: 1756450000, WalletID: wallet.ID, BudgetID: &budget.ID},
+		},
+		{
+			name:        "zero amount violates check",
+			transaction: &domain.Transaction{Amount: 0, Type: "expense", TransactionDate: 1756450000, WalletID: wallet.ID},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.Create(ctx, user.ID.String(), tt.transaction)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransactionRepositoryGetDetail(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	wallet := createWallet(t, db, owner, 1000)
+	budget := createBudget(t, db, owner, "Food")
+
+	transaction := &domain.Transaction{Amount: 75, Type: "expense", Note: "lunch", TransactionDate: 1756450000, WalletID: wallet.ID, BudgetID: &budget.ID}
+	if err := repo.Create(ctx, owner.ID.String(), transaction); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		user    *domain.User
+		wantErr error
+	}{
+		{name: "owner", user: owner},
+		{name: "other user", user: other, wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, err := repo.GetDetail(ctx, tt.user.ID.String(), transaction.ID.String())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetDetail() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDetail() error = %v", err)
+			}
+			if detail.Wallet.ID != wallet.ID {
+				t.Fatalf("preloaded wallet = %s, want %s", detail.Wallet.ID, wallet.ID)
+			}
+			if detail.Budget == nil || detail.Budget.ID != budget.ID {
+				t.Fatalf("preloaded budget = %v, want %s", detail.Budget, budget.ID)
+			}
+		})
+	}
+}
+
+func TestTransactionRepositoryGetList(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	ownerWallet := createWallet(t, db, owner, 1000)
+	otherWallet := createWallet(t, db, other, 1000)
+
+	for _, transaction := range []*domain.Transaction{
+		{Amount: 10, Type: "income", TransactionDate: 1756450000, WalletID: ownerWallet.ID},
+		{Amount: 20, Type: "expense", TransactionDate: 1756460000, WalletID: ownerWallet.ID},
+		{Amount: 30, Type: "expense", TransactionDate: 1756470000, WalletID: ownerWallet.ID},
+	} {
+		if err := repo.Create(ctx, owner.ID.String(), transaction); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	transaction := &domain.Transaction{Amount: 40, Type: "income", TransactionDate: 1756450000, WalletID: otherWallet.ID}
+	if err := repo.Create(ctx, other.ID.String(), transaction); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		filter    domain.TransactionFilter
+		wantCount int
+	}{
+		{name: "owner", user: owner, wantCount: 3},
+		{name: "other user", user: other, wantCount: 1},
+		{name: "expenses in range", user: owner, filter: domain.TransactionFilter{From: 1756460000, To: 1756470000, Type: "expense"}, wantCount: 1},
+		{name: "wallet of another user", user: owner, filter: domain.TransactionFilter{WalletID: otherWallet.ID.String()}, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions, err := repo.GetList(ctx, tt.user.ID.String(), tt.filter)
+			if err != nil {
+				t.Fatalf("GetList() error = %v", err)
+			}
+			if len(transactions) != tt.wantCount {
+				t.Fatalf("GetList() returned %d transactions, want %d", len(transactions), tt.wantCount)
+			}
+			for _, transaction := range transactions {
+				if transaction.Wallet.ID != transaction.WalletID {
+					t.Fatalf("wallet was not preloaded for transaction %s", transaction.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionRepositoryStream(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	wallet := createWallet(t, db, owner, 1000)
+	budget := createBudget(t, db, owner, "Groceries")
+
+	for _, transaction := range []*domain.Transaction{
+		{Amount: 20, Type: "expense", Note: "Market", TransactionDate: 2000, WalletID: wallet.ID, BudgetID: &budget.ID},
+		{Amount: 10, Type: "income", Note: "Refund", TransactionDate: 1000, WalletID: wallet.ID},
+		{Amount: 30, Type: "expense", TransactionDate: 3000, WalletID: wallet.ID},
+	} {
+		if err := repo.Create(ctx, owner.ID.String(), transaction); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if err := repo.Create(ctx, other.ID.String(), &domain.Transaction{Amount: 40, Type: "income", TransactionDate: 1500, WalletID: createWallet(t, db, other, 0).ID}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	cursor, err := repo.Stream(ctx, owner.ID.String(), domain.TransactionFilter{To: 3000})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer cursor.Close()
+
+	var rows []domain.TransactionExportRow
+	for cursor.Next() {
+		var row domain.TransactionExportRow
+		if err := cursor.Scan(&row); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if len(rows) != 2 || rows[0].Note != "Refund" || rows[1].Note != "Market" {
+		t.Fatalf("Stream() = %+v, want the owner's two earliest oldest first", rows)
+	}
+	if rows[0].WalletName != "Main" || rows[0].Currency != "IDR" || rows[0].BudgetName != "" {
+		t.Errorf("unbudgeted row = %+v, want the wallet labels only", rows[0])
+	}
+	if rows[1].BudgetName != "Groceries" || rows[1].Category != "food" || rows[1].Amount != 20 {
+		t.Errorf("budgeted row = %+v, want the budget labels", rows[1])
+	}
+}
+
+func TestTransactionRepositoryGetByWalletSince(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "replay@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	other := createWallet(t, db, user, 1000)
+
+	for _, transaction := range []*domain.Transaction{
+		{Amount: 10, Type: "expense", TransactionDate: 1000, WalletID: wallet.ID},
+		{Amount: 20, Type: "expense", TransactionDate: 3000, WalletID: wallet.ID},
+		{Amount: 30, Type: "income", TransactionDate: 2000, WalletID: wallet.ID},
+		{Amount: 40, Type: "income", TransactionDate: 2500, WalletID: other.ID},
+	} {
+		if err := repo.Create(ctx, user.ID.String(), transaction); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	transactions, err := repo.GetByWalletSince(ctx, wallet.ID.String(), 2000)
+	if err != nil {
+		t.Fatalf("GetByWalletSince() error = %v", err)
+	}
+	if len(transactions) != 2 || transactions[0].TransactionDate != 3000 || transactions[1].TransactionDate != 2000 {
+		t.Fatalf("GetByWalletSince() = %+v, want the two latest newest first", transactions)
+	}
+}
+
+func TestTransactionRepositoryGetByWalletBetween(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "between@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	other := createWallet(t, db, user, 1000)
+
+	for _, transaction := range []*domain.Transaction{
+		{Amount: 10, Type: "expense", TransactionDate: 1000, WalletID: wallet.ID},
+		{Amount: 20, Type: "expense", TransactionDate: 3000, WalletID: wallet.ID},
+		{Amount: 30, Type: "income", TransactionDate: 2000, WalletID: wallet.ID},
+		{Amount: 40, Type: "income", TransactionDate: 2500, WalletID: other.ID},
+	} {
+		if err := repo.Create(ctx, user.ID.String(), transaction); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	transactions, err := repo.GetByWalletBetween(ctx, wallet.ID.String(), 1000, 3000)
+	if err != nil {
+		t.Fatalf("GetByWalletBetween() error = %v", err)
+	}
+	if len(transactions) != 2 || transactions[0].TransactionDate != 1000 || transactions[1].TransactionDate != 2000 {
+		t.Fatalf("GetByWalletBetween() = %+v, want the two earliest oldest first", transactions)
+	}
+}
+
+func TestTransactionRepositoryGetByExternalIDs(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "fitid@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	other := createWallet(t, db, user, 1000)
+
+	fitid := func(id string) *string { return &id }
+	for _, transaction := range []*domain.Transaction{
+		{Amount: 10, Type: "expense", TransactionDate: 1000, WalletID: wallet.ID, ExternalID: fitid("A1")},
+		{Amount: 20, Type: "expense", TransactionDate: 2000, WalletID: wallet.ID, ExternalID: fitid("A2")},
+		{Amount: 30, Type: "income", TransactionDate: 3000, WalletID: wallet.ID},
+		{Amount: 40, Type: "income", TransactionDate: 2500, WalletID: other.ID, ExternalID: fitid("A1")},
+	} {
+		if err := repo.Create(ctx, user.ID.String(), transaction); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	transactions, err := repo.GetByExternalIDs(ctx, wallet.ID.String(), []string{"A1", "B7"})
+	if err != nil {
+		t.Fatalf("GetByExternalIDs() error = %v", err)
+	}
+	if len(transactions) != 1 || transactions[0].Amount != 10 {
+		t.Fatalf("GetByExternalIDs() = %+v, want the wallet's A1 only", transactions)
+	}
+
+	duplicate := &domain.Transaction{Amount: 10, Type: "expense", TransactionDate: 1000, WalletID: wallet.ID, ExternalID: fitid("A1")}
+	if err := repo.Create(ctx, user.ID.String(), duplicate); err == nil {
+		t.Fatal("Create() with a booked external id succeeded")
+	}
+}
+
+func TestTransactionRepositoryGetByIDs(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "ids@example.com")
+	stranger := createUser(t, db, "ids-other@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	theirs := createWallet(t, db, stranger, 1000)
+
+	mine := &domain.Transaction{Amount: 10, Type: "expense", TransactionDate: 1000, WalletID: wallet.ID}
+	other := &domain.Transaction{Amount: 20, Type: "expense", TransactionDate: 1000, WalletID: theirs.ID}
+	if err := repo.Create(ctx, user.ID.String(), mine); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Create(ctx, stranger.ID.String(), other); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	transactions, err := repo.GetByIDs(ctx, user.ID.String(), []string{mine.ID.String(), other.ID.String()})
+	if err != nil {
+		t.Fatalf("GetByIDs() error = %v", err)
+	}
+	if len(transactions) != 1 || transactions[0].ID != mine.ID {
+		t.Fatalf("GetByIDs() = %+v, want only the user's own transaction", transactions)
+	}
+}
+
+func TestTransactionRepositoryFlowByWallet(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "flow@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	other := createWallet(t, db, user, 1000)
+
+	for _, transaction := range []*domain.Transaction{
+		{Amount: 100, Type: "income", TransactionDate: 1000, WalletID: wallet.ID},
+		{Amount: 30, Type: "expense", TransactionDate: 2000, WalletID: wallet.ID},
+		{Amount: 5, Type: "expense", TransactionDate: 3000, WalletID: wallet.ID},
+		{Amount: 40, Type: "expense", TransactionDate: 500, WalletID: other.ID},
+	} {
+		if err := repo.Create(ctx, user.ID.String(), transaction); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	rows, err := repo.FlowByWallet(ctx, user.ID.String(), 1500)
+	if err != nil {
+		t.Fatalf("FlowByWallet() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].WalletID != wallet.ID || rows[0].Net != -35 || rows[0].FirstDate != 2000 {
+		t.Fatalf("FlowByWallet() = %+v, want -35 on the first wallet from 2000", rows)
+	}
+
+	rows, err = repo.FlowByWallet(ctx, user.ID.String(), 0)
+	if err != nil {
+		t.Fatalf("FlowByWallet() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("FlowByWallet() = %+v, want both wallets", rows)
+	}
+}
+
+func TestTransactionRepositoryUpdate(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "update@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	budget := createBudget(t, db, user, "Food")
+
+	transaction := &domain.Transaction{Amount: 10, Type: "expense", TransactionDate: 1000, WalletID: wallet.ID, BudgetID: &budget.ID}
+	if err := repo.Create(ctx, user.ID.String(), transaction); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	transaction.Amount = 12.5
+	transaction.Note = "Kopi"
+	transaction.BudgetID = nil
+	if saved, err := repo.Update(ctx, transaction, 1); err != nil || !saved {
+		t.Fatalf("Update() = %v, %v, want saved", saved, err)
+	}
+	if saved, err := repo.Update(ctx, transaction, 1); err != nil || saved {
+		t.Fatalf("Update() of a stale version = %v, %v, want not saved", saved, err)
+	}
+
+	got, err := repo.GetDetail(ctx, user.ID.String(), transaction.ID.String())
+	if err != nil {
+		t.Fatalf("GetDetail() error = %v", err)
+	}
+	if got.Amount != 12.5 || got.Note != "Kopi" || got.BudgetID != nil || got.Version != 2 {
+		t.Fatalf("GetDetail() = %+v, want the update at version 2", got)
+	}
+}
+
+func TestTransactionRepositorySplits(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "splits@example.com")
+	wallet := createWallet(t, db, user, 1000)
+	groceries := createBudget(t, db, user, "Groceries")
+	household := createBudget(t, db, user, "Household")
+
+	transaction := &domain.Transaction{Amount: 150, Type: "expense", Note: "Supermarket", TransactionDate: 1000, WalletID: wallet.ID, Splits: []domain.TransactionSplit{
+		{Position: 1, Amount: 100, Note: "Food", BudgetID: &groceries.ID},
+		{Position: 2, Amount: 30, Note: "Soap", BudgetID: &household.ID},
+		{Position: 3, Amount: 20, Note: "Magazine"},
+	}}
+	if err := repo.Create(ctx, user.ID.String(), transaction); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Create(ctx, user.ID.String(), &domain.Transaction{Amount: 40, Type: "expense", TransactionDate: 2000, WalletID: wallet.ID, BudgetID: &groceries.ID}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := repo.GetDetail(ctx, user.ID.String(), transaction.ID.String())
+	if err != nil {
+		t.Fatalf("GetDetail() error = %v", err)
+	}
+	if len(got.Splits) != 3 || got.Splits[0].Note != "Food" || got.Splits[2].Note != "Magazine" {
+		t.Fatalf("splits = %+v, want the three lines in order", got.Splits)
+	}
+	if got.Splits[1].Budget == nil || got.Splits[1].Budget.Name != "Household" || got.Splits[2].Budget != nil {
+		t.Errorf("split budgets = %+v, %+v, want household then none", got.Splits[1].Budget, got.Splits[2].Budget)
+	}
+
+	// A split transaction is listed under the budget of any of its lines
+	for _, tt := range []struct {
+		budget *domain.Budget
+		want   int
+	}{{groceries, 2}, {household, 1}} {
+		listed, err := repo.GetList(ctx, user.ID.String(), domain.TransactionFilter{BudgetID: tt.budget.ID.String()})
+		if err != nil || len(listed) != tt.want {
+			t.Fatalf("GetList() for %s = %d, %v, want %d", tt.budget.Name, len(listed), err, tt.want)
+		}
+	}
+
+	cursor, err := repo.Stream(ctx, user.ID.String(), domain.TransactionFilter{To: 2000})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	var rows []domain.TransactionExportRow
+	for cursor.Next() {
+		var row domain.TransactionExportRow
+		if err := cursor.Scan(&row); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		rows = append(rows, row)
+	}
+	cursor.Close()
+	if len(rows) != 3 || rows[0].Amount != 100 || rows[0].BudgetName != "Groceries" || rows[0].SplitNote != "Food" || rows[0].Note != "Supermarket" {
+		t.Fatalf("Stream() = %+v, want a row per line starting with the food", rows)
+	}
+	if rows[1].BudgetName != "Household" || rows[2].BudgetName != "" || rows[2].Amount != 20 || rows[2].ID != transaction.ID {
+		t.Errorf("Stream() = %+v, want household then unbudgeted lines of the same transaction", rows)
+	}
+
+	// Updating replaces the lines
+	transaction.Splits = []domain.TransactionSplit{
+		{Position: 1, Amount: 120, BudgetID: &groceries.ID},
+		{Position: 2, Amount: 30, BudgetID: &household.ID},
+	}
+	if saved, err := repo.Update(ctx, transaction, 1); err != nil || !saved {
+		t.Fatalf("Update() = %v, %v, want saved", saved, err)
+	}
+	got, err = repo.GetDetail(ctx, user.ID.String(), transaction.ID.String())
+	if err != nil || len(got.Splits) != 2 || got.Splits[0].Amount != 120 {
+		t.Fatalf("splits after Update() = %+v, %v, want the two new lines", got.Splits, err)
+	}
+
+	transaction.Splits = nil
+	transaction.BudgetID = &groceries.ID
+	if saved, err := repo.Update(ctx, transaction, 2); err != nil || !saved {
+		t.Fatalf("Update() = %v, %v, want saved", saved, err)
+	}
+	got, err = repo.GetDetail(ctx, user.ID.String(), transaction.ID.String())
+	if err != nil || len(got.Splits) != 0 || got.Budget == nil {
+		t.Fatalf("GetDetail() after unsplitting = %+v, %v, want the budget and no lines", got, err)
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// txState is the transaction carried by a context and how deeply WithinTx is nested
+type txState struct {
+	tx    *gorm.DB
+	depth int
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) domain.TxManager {
+	return &txManager{
+		db: db,
+	}
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return withinSavepoint(ctx, state, fn)
+	}
+
+	tx := m.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, &txState{tx: tx})); err != nil {
+		if rollbackErr := tx.Rollback().Error; rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// withinSavepoint runs a nested unit of work so its failure leaves the outer transaction usable.
+// Siblings at the same depth share a savepoint name, so each savepoint is released when its unit
+// ends rather than left shadowed for the rest of the transaction.
+func withinSavepoint(ctx context.Context, state *txState, fn func(ctx context.Context) error) error {
+	nested := &txState{tx: state.tx, depth: state.depth + 1}
+	name := fmt.Sprintf("sp_%d", nested.depth)
+
+	if err := state.tx.SavePoint(name).Error; err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			state.tx.RollbackTo(name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, nested)); err != nil {
+		if rollbackErr := state.tx.RollbackTo(name).Error; rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back to savepoint: %w", rollbackErr))
+		}
+		if releaseErr := releaseSavepoint(state.tx, name); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+
+	return releaseSavepoint(state.tx, name)
+}
+
+// releaseSavepoint drops the savepoint name, keeping any work done since it was created
+func releaseSavepoint(tx *gorm.DB, name string) error {
+	if err := tx.Exec("RELEASE SAVEPOINT " + name).Error; err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// conn returns the transaction carried by ctx, or db when there is none
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,177 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func countUsers(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&domain.User{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+	return count
+}
+
+func newUser(email string) *domain.User {
+	return &domain.User{ID: uuid.New(), FullName: "Tx User", Email: email, Password: "hashed"}
+}
+
+func TestTxManagerWithinTx(t *testing.T) {
+	errAbort := errors.New("abort")
+
+	tests := []struct {
+		name      string
+		work      func(ctx context.Context, txManager domain.TxManager, users domain.UserRepository) error
+		wantErr   error
+		wantPanic bool
+		wantUsers int64
+	}{
+		{
+			name: "commits on success",
+			work: func(ctx context.Context, txManager domain.TxManager, users domain.UserRepository) error {
+				return users.Create(ctx, newUser("a@example.com"))
+			},
+			wantUsers: 1,
+		},
+		{
+			name: "rolls back on error",
+			work: func(ctx context.Context, txManager domain.TxManager, users domain.UserRepository) error {
+				if err := users.Create(ctx, newUser("a@example.com")); err != nil {
+					return err
+				}
+				return errAbort
+			},
+			wantErr: errAbort,
+		},
+		{
+			name: "rolls back on panic",
+			work: func(ctx context.Context, txManager domain.TxManager, users domain.UserRepository) error {
+				if err := users.Create(ctx, newUser("a@example.com")); err != nil {
+					return err
+				}
+				uuid.MustParse("not-a-uuid")
+				return nil
+			},
+			wantPanic: true,
+		},
+		{
+			name: "failed savepoint keeps the outer work",
+			work: func(ctx context.Context, txManager domain.TxManager, users domain.UserRepository) error {
+				if err := users.Create(ctx, newUser("outer@example.com")); err != nil {
+					return err
+				}
+				err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+					if err := users.Create(ctx, newUser("inner@example.com")); err != nil {
+						return err
+					}
+					return errAbort
+				})
+				if !errors.Is(err, errAbort) {
+					return err
+				}
+				// The transaction is still usable after rolling back to the savepoint
+				return users.Create(ctx, newUser("after@example.com"))
+			},
+			wantUsers: 2,
+		},
+		{
+			name: "failed sibling keeps the work of the one before it",
+			work: func(ctx context.Context, txManager domain.TxManager, users domain.UserRepository) error {
+				err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+					return users.Create(ctx, newUser("first@example.com"))
+				})
+				if err != nil {
+					return err
+				}
+				err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+					if err := users.Create(ctx, newUser("second@example.com")); err != nil {
+						return err
+					}
+					return errAbort
+				})
+				if !errors.Is(err, errAbort) {
+					return err
+				}
+				return nil
+			},
+			wantUsers: 1,
+		},
+		{
+			name: "failed outer work undoes a completed savepoint",
+			work: func(ctx context.Context, txManager domain.TxManager, users domain.UserRepository) error {
+				err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+					return users.Create(ctx, newUser("inner@example.com"))
+				})
+				if err != nil {
+					return err
+				}
+				return errAbort
+			},
+			wantErr: errAbort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.New(t)
+			txManager := repository.NewTxManager(db)
+			users := repository.NewUserRepository(db)
+
+			var err error
+			panicked := func() (panicked bool) {
+				defer func() {
+					panicked = recover() != nil
+				}()
+				err = txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+					return tt.work(ctx, txManager, users)
+				})
+				return false
+			}()
+
+			if panicked != tt.wantPanic {
+				t.Fatalf("panicked = %t, want %t", panicked, tt.wantPanic)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WithinTx() error = %v, want %v", err, tt.wantErr)
+			}
+			if count := countUsers(t, db); count != tt.wantUsers {
+				t.Fatalf("users after WithinTx = %d, want %d", count, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestTxManagerSurfacesCommitErrors(t *testing.T) {
+	db := testdb.New(t)
+	txManager := repository.NewTxManager(db)
+	users := repository.NewUserRepository(db)
+
+	// A deferred constraint is only checked when the transaction commits
+	err := db.Exec("ALTER TABLE users DROP CONSTRAINT users_email_key, ADD CONSTRAINT users_email_key UNIQUE (email) DEFERRABLE INITIALLY DEFERRED").Error
+	if err != nil {
+		t.Fatalf("failed to defer the email constraint: %v", err)
+	}
+
+	err = txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := users.Create(ctx, newUser("commit@example.com")); err != nil {
+			return err
+		}
+		return users.Create(ctx, newUser("commit@example.com"))
+	})
+	if err == nil {
+		t.Fatal("WithinTx() succeeded, want the commit error")
+	}
+	if count := countUsers(t, db); count != 0 {
+		t.Fatalf("users after failed commit = %d, want 0", count)
+	}
+}
@@ -8,22 +8,29 @@ import (
 )
 
 type userRepository struct {
+	db *gorm.DB
 }
 
-func NewUserRepository() domain.UserRepository {
-	return &userRepository{}
+func NewUserRepository(db *gorm.DB) domain.UserRepository {
+	return &userRepository{
+		db: db,
+	}
 }
 
-func (r *userRepository) Create(db *gorm.DB, ctx context.Context, user *domain.User) error {
-	return db.WithContext(ctx).Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	return conn(ctx, r.db).Create(user).Error
 }
 
-func (r *userRepository) GetByEmail(db *gorm.DB, ctx context.Context, email string) (*domain.User, error) {
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
+
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	return conn(ctx, r.db).Save(user).Error
+}
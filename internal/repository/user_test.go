@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestUserRepositoryCreate(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	createUser(t, db, "taken@example.com")
+
+	tests := []struct {
+		name    string
+		user    *domain.User
+		wantErr bool
+	}{
+		{
+			name: "new email",
+			user: &domain.User{ID: uuid.New(), FullName: "New", Email: "new@example.com", Password: "hashed"},
+		},
+		{
+			name:    "duplicate email",
+			user:    &domain.User{ID: uuid.New(), FullName: "Dup", Email: "taken@example.com", Password: "hashed"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.Create(ctx, tt.user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepositoryGetByEmail(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	active := createUser(t, db, "active@example.com")
+	deleted := createUser(t, db, "deleted@example.com")
+	if err := db.Delete(deleted).Error; err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		wantID  uuid.UUID
+		wantErr error
+	}{
+		{name: "existing user", email: "active@example.com", wantID: active.ID},
+		{name: "unknown email", email: "missing@example.com", wantErr: gorm.ErrRecordNotFound},
+		{name: "soft deleted user", email: "deleted@example.com", wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := repo.GetByEmail(ctx, tt.email)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetByEmail() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByEmail() error = %v", err)
+			}
+			if user.ID != tt.wantID {
+				t.Fatalf("GetByEmail() id = %s, want %s", user.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestUserRepositoryUpdate(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "update@example.com")
+
+	disabledAt := 1760000000
+	user.DisabledAt = &disabledAt
+	user.Password = "rehashed"
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	stored, err := repo.GetByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("GetByEmail() error = %v", err)
+	}
+	if !stored.IsDisabled() || *stored.DisabledAt != disabledAt {
+		t.Fatalf("DisabledAt = %v, want %d", stored.DisabledAt, disabledAt)
+	}
+	if stored.Password != "rehashed" {
+		t.Fatalf("Password = %q, want %q", stored.Password, "rehashed")
+	}
+}
@@ -8,15 +8,19 @@ import (
 	"gorm.io/gorm"
 )
 
-type walletRepository struct{}
+type walletRepository struct {
+	db *gorm.DB
+}
 
-func NewWalletRepository() domain.WalletRepository {
-	return &walletRepository{}
+func NewWalletRepository(db *gorm.DB) domain.WalletRepository {
+	return &walletRepository{
+		db: db,
+	}
 }
 
-func (r *walletRepository) Create(db *gorm.DB, ctx context.Context, userId string, wallet *domain.Wallet) error {
+func (r *walletRepository) Create(ctx context.Context, userId string, wallet *domain.Wallet) error {
 	// create wallet
-	if err := db.WithContext(ctx).Create(wallet).Error; err != nil {
+	if err := conn(ctx, r.db).Create(wallet).Error; err != nil {
 		return err
 	}
 
@@ -26,17 +30,17 @@ func (r *walletRepository) Create(db *gorm.DB, ctx context.Context, userId strin
 	}
 
 	// create has_wallet
-	if err := db.WithContext(ctx).Create(&hasWallet).Error; err != nil {
+	if err := conn(ctx, r.db).Create(&hasWallet).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *walletRepository) GetList(db *gorm.DB, ctx context.Context, userId string) ([]*domain.Wallet, error) {
+func (r *walletRepository) GetList(ctx context.Context, userId string) ([]*domain.Wallet, error) {
 	var wallets []*domain.Wallet
 
-	err := db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Joins("JOIN has_wallets ON has_wallets.wallet_id = wallets.id").
 		Where("has_wallets.user_id = ?", userId).
 		Find(&wallets).Error
@@ -47,14 +51,65 @@ func (r *walletRepository) GetList(db *gorm.DB, ctx context.Context, userId stri
 	return wallets, nil
 }
 
-func (r *walletRepository) DecreaseBalance(db *gorm.DB, ctx context.Context, walletId string, amount float64) error {
-	return db.WithContext(ctx).Model(&domain.Wallet{}).
+func (r *walletRepository) GetDetail(ctx context.Context, userId string, walletId string) (*domain.Wallet, error) {
+	var wallet domain.Wallet
+
+	err := conn(ctx, r.db).
+		Joins("JOIN has_wallets ON has_wallets.wallet_id = wallets.id").
+		Where("has_wallets.user_id = ? AND wallets.id = ?", userId, walletId).
+		First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+func (r *walletRepository) GetAll(ctx context.Context) ([]*domain.Wallet, error) {
+	var wallets []*domain.Wallet
+
+	if err := conn(ctx, r.db).Order("id").Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+func (r *walletRepository) Update(ctx context.Context, wallet *domain.Wallet, version int) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Wallet{}).
+		Where("id = ? AND version = ?", wallet.ID, version).
+		Updates(map[string]interface{}{
+			"name":    wallet.Name,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// DecreaseBalance bumps the version like IncreaseBalance, the balance is part of what clients read.
+// It reports false and leaves the wallet alone when the balance is less than the amount.
+func (r *walletRepository) DecreaseBalance(ctx context.Context, walletId string, amount float64) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Wallet{}).
 		Where("id = ? AND balance >= ?", walletId, amount).
-		Update("balance", gorm.Expr("balance - ?", amount)).Error
+		Updates(map[string]interface{}{
+			"balance": gorm.Expr("balance - ?", amount),
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
 }
 
-func (r *walletRepository) IncreaseBalance(db *gorm.DB, ctx context.Context, walletId string, amount float64) error {
-	return db.WithContext(ctx).Model(&domain.Wallet{}).
+func (r *walletRepository) IncreaseBalance(ctx context.Context, walletId string, amount float64) error {
+	return conn(ctx, r.db).Model(&domain.Wallet{}).
 		Where("id = ?", walletId).
-		Update("balance", gorm.Expr("balance + ?", amount)).Error
+		Updates(map[string]interface{}{
+			"balance": gorm.Expr("balance + ?", amount),
+			"version": gorm.Expr("version + 1"),
+		}).Error
 }
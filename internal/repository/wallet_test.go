@@ -0,0 +1,187 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/repository"
+	"finance-backend/internal/testdb"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestWalletRepositoryGetList(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewWalletRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	empty := createUser(t, db, "empty@example.com")
+	createWallet(t, db, owner, 100)
+	createWallet(t, db, owner, 200)
+	createWallet(t, db, other, 300)
+
+	tests := []struct {
+		name      string
+		user      *domain.User
+		wantCount int
+	}{
+		{name: "owner sees own wallets", user: owner, wantCount: 2},
+		{name: "other user is isolated", user: other, wantCount: 1},
+		{name: "user without wallets", user: empty, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallets, err := repo.GetList(ctx, tt.user.ID.String())
+			if err != nil {
+				t.Fatalf("GetList() error = %v", err)
+			}
+			if len(wallets) != tt.wantCount {
+				t.Fatalf("GetList() returned %d wallets, want %d", len(wallets), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestWalletRepositoryGetDetail(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewWalletRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	other := createUser(t, db, "other@example.com")
+	wallet := createWallet(t, db, owner, 100)
+
+	tests := []struct {
+		name    string
+		user    *domain.User
+		wantErr error
+	}{
+		{name: "owner", user: owner},
+		{name: "other user", user: other, wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, err := repo.GetDetail(ctx, tt.user.ID.String(), wallet.ID.String())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetDetail() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && detail.ID != wallet.ID {
+				t.Fatalf("GetDetail() = %s, want %s", detail.ID, wallet.ID)
+			}
+		})
+	}
+}
+
+func TestWalletRepositoryGetAll(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewWalletRepository(db)
+	ctx := context.Background()
+
+	createWallet(t, db, createUser(t, db, "first@example.com"), 100)
+	createWallet(t, db, createUser(t, db, "second@example.com"), 200)
+	deleted := createWallet(t, db, createUser(t, db, "third@example.com"), 300)
+	if err := db.Delete(deleted).Error; err != nil {
+		t.Fatalf("failed to delete wallet: %v", err)
+	}
+
+	wallets, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(wallets) != 2 {
+		t.Fatalf("GetAll() returned %d wallets, want the 2 live ones", len(wallets))
+	}
+}
+
+func TestWalletRepositoryBalance(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		balance     float64
+		increase    bool
+		amount      float64
+		wantBalance float64
+		wantVersion int
+	}{
+		{name: "increase", balance: 100, increase: true, amount: 50.25, wantBalance: 150.25, wantVersion: 2},
+		{name: "decrease", balance: 100, amount: 40, wantBalance: 60, wantVersion: 2},
+		{name: "decrease to zero", balance: 100, amount: 100, wantBalance: 0, wantVersion: 2},
+		{name: "decrease below zero is refused", balance: 100, amount: 150, wantBalance: 100, wantVersion: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.New(t)
+			user := createUser(t, db, "balance@example.com")
+			wallet := createWallet(t, db, user, tt.balance)
+			repo := repository.NewWalletRepository(db)
+
+			moved := true
+			var err error
+			if tt.increase {
+				err = repo.IncreaseBalance(ctx, wallet.ID.String(), tt.amount)
+			} else {
+				moved, err = repo.DecreaseBalance(ctx, wallet.ID.String(), tt.amount)
+			}
+			if err != nil {
+				t.Fatalf("balance update error = %v", err)
+			}
+			if wantMoved := tt.wantVersion == 2; moved != wantMoved {
+				t.Fatalf("DecreaseBalance() = %v, want %v", moved, wantMoved)
+			}
+
+			var stored domain.Wallet
+			if err := db.First(&stored, "id = ?", wallet.ID).Error; err != nil {
+				t.Fatalf("failed to reload wallet: %v", err)
+			}
+			if stored.Balance != tt.wantBalance || stored.Version != tt.wantVersion {
+				t.Fatalf("balance = %v at version %d, want %v at version %d", stored.Balance, stored.Version, tt.wantBalance, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestWalletRepositoryUpdate(t *testing.T) {
+	db := testdb.New(t)
+	repo := repository.NewWalletRepository(db)
+	ctx := context.Background()
+
+	owner := createUser(t, db, "owner@example.com")
+	wallet := createWallet(t, db, owner, 100)
+	if wallet.Version != 1 {
+		t.Fatalf("new wallet at version %d, want 1", wallet.Version)
+	}
+
+	wallet.Name = "Household"
+	if saved, err := repo.Update(ctx, wallet, 1); err != nil || !saved {
+		t.Fatalf("Update() = %v, %v, want saved", saved, err)
+	}
+
+	// A second writer that read version 1 is turned away
+	wallet.Name = "Overwritten"
+	if saved, err := repo.Update(ctx, wallet, 1); err != nil || saved {
+		t.Fatalf("Update() of a stale version = %v, %v, want not saved", saved, err)
+	}
+
+	// Balance moves are changes too
+	if err := repo.IncreaseBalance(ctx, wallet.ID.String(), 50); err != nil {
+		t.Fatalf("IncreaseBalance() error = %v", err)
+	}
+	if moved, err := repo.DecreaseBalance(ctx, wallet.ID.String(), 20); err != nil || !moved {
+		t.Fatalf("DecreaseBalance() = %v, %v, want moved", moved, err)
+	}
+
+	got, err := repo.GetDetail(ctx, owner.ID.String(), wallet.ID.String())
+	if err != nil {
+		t.Fatalf("GetDetail() error = %v", err)
+	}
+	if got.Name != "Household" || got.Balance != 130 || got.Version != 4 {
+		t.Fatalf("GetDetail() = %s with %.2f at version %d, want Household with 130.00 at version 4", got.Name, got.Balance, got.Version)
+	}
+}
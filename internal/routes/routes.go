@@ -4,62 +4,116 @@ import (
 	"finance-backend/internal/handler"
 	"finance-backend/internal/repository"
 	"finance-backend/internal/service"
+	"finance-backend/pkg/auth"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/health"
+	"finance-backend/pkg/metrics"
 	middleware "finance-backend/pkg/midleware"
-	"time"
+	"finance-backend/pkg/tracing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(app *fiber.App, db *gorm.DB) {
-	userRepository := repository.NewUserRepository()
-	sessionRepository := repository.NewSessionRepository()
-	walletRepository := repository.NewWalletRepository()
-	budgetRepository := repository.NewBudgetRepository()
-	transactionRepository := repository.NewTransactionRepository()
+func SetupRoutes(app *fiber.App, config *config.Config, db *gorm.DB, healthChecker *health.Checker) {
+	userRepository := repository.NewUserRepository(db)
+	sessionRepository := repository.NewSessionRepository(db)
+	walletRepository := repository.NewWalletRepository(db)
+	budgetRepository := repository.NewBudgetRepository(db)
+	transactionRepository := repository.NewTransactionRepository(db)
+	tagRepository := repository.NewTagRepository(db)
+	reportRepository := repository.NewReportRepository(db)
+	snapshotRepository := repository.NewSnapshotRepository(db)
+	exchangeRateRepository := repository.NewExchangeRateRepository(db)
+	importProfileRepository := repository.NewImportProfileRepository(db)
+	importAccountRepository := repository.NewImportAccountRepository(db)
+	idempotencyRepository := repository.NewIdempotencyRepository(db)
 
-	authService := service.NewAuthService(db, userRepository, sessionRepository)
-	walletService := service.NewWalletService(db, walletRepository)
-	budgetService := service.NewBudgetService(db, budgetRepository)
-	transactionService := service.NewTransactionService(db, transactionRepository, walletRepository)
+	txManager := repository.NewTxManager(db)
+	tokenManager := auth.NewTokenManager(config.JWT)
+
+	authService := service.NewAuthService(txManager, tokenManager, userRepository, sessionRepository)
+	walletService := service.NewWalletService(txManager, walletRepository)
+	budgetService := service.NewBudgetService(txManager, budgetRepository)
+	transactionService := service.NewTransactionService(txManager, transactionRepository, walletRepository, budgetRepository)
+	tagService := service.NewTagService(txManager, tagRepository, transactionRepository)
+	importService := service.NewImportService(txManager, importProfileRepository, importAccountRepository, walletRepository, budgetRepository, transactionRepository, transactionService)
+	exportService := service.NewExportService(walletRepository, transactionRepository)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepository, config.Server.IdempotencyTTL, config.Server.IdempotencyLockTimeout)
+	reportService := service.NewReportService(reportRepository, walletRepository, snapshotRepository, exchangeRateRepository, config.Report.BaseCurrency)
 
 	authHandler := handler.NewAuthHandler(authService)
 	profileHandler := handler.NewProfileHandler(authService)
 	walletHandler := handler.NewWalletHandler(walletService)
 	budgetHandler := handler.NewBudgetHandler(budgetService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
+	tagHandler := handler.NewTagHandler(tagService)
+	importHandler := handler.NewImportHandler(importService)
+	exportHandler := handler.NewExportHandler(exportService)
+	reportHandler := handler.NewReportHandler(reportService)
 
 	app.Use(middleware.RequestIDMiddleware())
+
+	// Probes are registered before logging and tracing to keep them out of request logs
+	app.Get("/livez", healthChecker.Liveness)
+	app.Get("/readyz", healthChecker.Readiness)
+
+	app.Use(tracing.Middleware())
+	app.Use(middleware.TimeoutMiddleware(config.Server.RequestTimeout))
 	app.Use(middleware.LoggingMiddleware())
+	app.Use(metrics.Middleware())
 	app.Use(recover.New())
 
 	// API v1 routes
 	v1 := app.Group("/v1")
 
 	// Health check route (public)
-	v1.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"success":   true,
-			"message":   "success",
-			"timestamp": time.Now().Format(time.RFC3339),
-		})
-	})
+	v1.Get("/health", healthChecker.Readiness)
 
 	// Additional routes can be added here
 	v1.Post("/auth/register", authHandler.Register)
 	v1.Post("/auth/login", authHandler.Login)
 
-	protected := v1.Group("/", middleware.AuthMiddleware(authService))
+	// Retried writes carrying an Idempotency-Key get the first response back instead of running twice
+	protected := v1.Group("/", middleware.AuthMiddleware(authService), middleware.IdempotencyMiddleware(idempotencyService))
 
 	protected.Get("/profile", profileHandler.GetProfile)
 
 	protected.Get("/wallet", walletHandler.GetList)
 	protected.Post("/wallet", walletHandler.Create)
+	protected.Get("/wallet/:id", walletHandler.GetDetail)
+	protected.Put("/wallet/:id", walletHandler.Update)
 
 	protected.Get("/budget", budgetHandler.GetList)
 	protected.Post("/budget", budgetHandler.Create)
+	protected.Get("/budget/:id", budgetHandler.GetDetail)
+	protected.Put("/budget/:id", budgetHandler.Update)
 
 	protected.Post("/transaction", transactionHandler.Create)
 	protected.Get("/transaction", transactionHandler.GetList)
+	protected.Post("/transaction/tags", tagHandler.Apply)
+	protected.Get("/transaction/:id", transactionHandler.GetDetail)
+	protected.Put("/transaction/:id", transactionHandler.Update)
+
+	protected.Get("/tag", tagHandler.GetList)
+	protected.Post("/tag", tagHandler.Create)
+	protected.Get("/tag/:id", tagHandler.GetDetail)
+	protected.Put("/tag/:id", tagHandler.Update)
+	protected.Delete("/tag/:id", tagHandler.Delete)
+
+	protected.Post("/import/profiles", importHandler.CreateProfile)
+	protected.Get("/import/profiles", importHandler.GetProfiles)
+	protected.Post("/import/csv", importHandler.ImportCSV)
+	protected.Post("/import/ofx", importHandler.ImportOFX)
+	protected.Post("/import/qif", importHandler.ImportQIF)
+	protected.Post("/import/beancount", importHandler.ImportBeancount)
+
+	protected.Get("/export/transactions", exportHandler.Transactions)
+	protected.Get("/export/ledger", exportHandler.Ledger)
+
+	protected.Get("/reports/summary", reportHandler.Summary)
+	protected.Get("/reports/categories", reportHandler.Categories)
+	protected.Get("/reports/net-worth", reportHandler.NetWorth)
+	protected.Get("/reports/forecast", reportHandler.Forecast)
 }
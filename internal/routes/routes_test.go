@@ -0,0 +1,1513 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"finance-backend/internal/routes"
+	"finance-backend/internal/testdb"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/health"
+	"finance-backend/pkg/logger"
+	middleware "finance-backend/pkg/midleware"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(config.LogConfig{Level: "ERROR", Format: "text"})
+	testdb.Main(m)
+}
+
+// envelope mirrors model.Response but keeps Data raw so tests can decode it into any shape
+type envelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Code    string          `json:"code"`
+	Data    json.RawMessage `json:"data"`
+	Errors  []struct {
+		Field string `json:"field"`
+		Rule  string `json:"rule"`
+	} `json:"errors"`
+}
+
+func newApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	db := testdb.New(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql db: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.JWT.Secret = "test-secret-that-is-long-enough-for-hs256"
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+	})
+	routes.SetupRoutes(app, cfg, db, health.NewChecker(sqlDB, testdb.MigrationsDir()))
+
+	return app
+}
+
+// call sends a JSON request through the app and decodes the response envelope
+func call(t *testing.T, app *fiber.App, method, path, token string, body interface{}) (int, envelope) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var result envelope
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("%s %s returned an undecodable body: %v", method, path, err)
+	}
+
+	return resp.StatusCode, result
+}
+
+func decode(t *testing.T, result envelope, out interface{}) {
+	t.Helper()
+
+	// Empty lists are omitted from the envelope
+	if len(result.Data) == 0 {
+		return
+	}
+
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		t.Fatalf("failed to decode data %s: %v", result.Data, err)
+	}
+}
+
+// download sends a GET request and returns the raw response, for endpoints that answer with files
+func download(t *testing.T, app *fiber.App, path, token string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s returned an unreadable body: %v", path, err)
+	}
+
+	return resp, string(body)
+}
+
+// upload posts a multipart form with the file under "file" and decodes the response envelope
+func upload(t *testing.T, app *fiber.App, path, token string, fields map[string]string, file string) (int, envelope) {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			t.Fatalf("failed to write field: %v", err)
+		}
+	}
+	part, err := form.CreateFormFile("file", "statement.csv")
+	if err != nil {
+		t.Fatalf("failed to create file part: %v", err)
+	}
+	if _, err := io.WriteString(part, file); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatalf("failed to close form: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set(fiber.HeaderContentType, form.FormDataContentType())
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	var result envelope
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp.StatusCode, result
+}
+
+// register creates an account and returns its session token
+func register(t *testing.T, app *fiber.App, email string) string {
+	t.Helper()
+
+	status, result := call(t, app, http.MethodPost, "/v1/auth/register", "", map[string]string{
+		"full_name": "Test User",
+		"email":     email,
+		"password":  "correct-password",
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("register returned %d: %+v", status, result)
+	}
+
+	var auth struct {
+		Token string `json:"token"`
+	}
+	decode(t, result, &auth)
+	return auth.Token
+}
+
+func TestProbes(t *testing.T) {
+	app := newApp(t)
+
+	for _, path := range []string{"/livez", "/readyz", "/v1/health"} {
+		t.Run(path, func(t *testing.T) {
+			status, result := call(t, app, http.MethodGet, path, "", nil)
+			if status != fiber.StatusOK || !result.Success {
+				t.Fatalf("%s returned %d: %+v", path, status, result)
+			}
+		})
+	}
+}
+
+func TestAuth(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "user@example.com")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       interface{}
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "duplicate registration",
+			method:     http.MethodPost,
+			path:       "/v1/auth/register",
+			body:       map[string]string{"full_name": "Again", "email": "user@example.com", "password": "another-password"},
+			wantStatus: fiber.StatusConflict,
+			wantCode:   "USER_ALREADY_EXISTS",
+		},
+		{
+			name:       "invalid registration",
+			method:     http.MethodPost,
+			path:       "/v1/auth/register",
+			body:       map[string]string{"full_name": "Bad", "email": "not-an-email", "password": "short"},
+			wantStatus: fiber.StatusUnprocessableEntity,
+			wantCode:   "VALIDATION_FAILED",
+		},
+		{
+			name:       "login",
+			method:     http.MethodPost,
+			path:       "/v1/auth/login",
+			body:       map[string]string{"email": "user@example.com", "password": "correct-password"},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "login with wrong password",
+			method:     http.MethodPost,
+			path:       "/v1/auth/login",
+			body:       map[string]string{"email": "user@example.com", "password": "wrong-password"},
+			wantStatus: fiber.StatusUnauthorized,
+			wantCode:   "INVALID_CREDENTIALS",
+		},
+		{
+			name:       "profile without token",
+			method:     http.MethodGet,
+			path:       "/v1/profile",
+			wantStatus: fiber.StatusUnauthorized,
+		},
+		{
+			name:       "profile with invalid token",
+			method:     http.MethodGet,
+			path:       "/v1/profile",
+			token:      "not-a-token",
+			wantStatus: fiber.StatusUnauthorized,
+		},
+		{
+			name:       "profile",
+			method:     http.MethodGet,
+			path:       "/v1/profile",
+			token:      token,
+			wantStatus: fiber.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := call(t, app, tt.method, tt.path, tt.token, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %+v", status, tt.wantStatus, result)
+			}
+			if tt.wantCode != "" && result.Code != tt.wantCode {
+				t.Fatalf("code = %q, want %q", result.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestTransactionsUpdateWalletBalance(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "ledger@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Main", "type": "personal", "currency": "IDR", "balance": 1000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	status, result = call(t, app, http.MethodPost, "/v1/budget", token, map[string]interface{}{
+		"name": "Food", "amount": 500, "type": "monthly", "category": "food",
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create budget returned %d: %+v", status, result)
+	}
+	var budget struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &budget)
+
+	tests := []struct {
+		name        string
+		body        map[string]interface{}
+		wantStatus  int
+		wantBalance float64
+	}{
+		{
+			name:        "income",
+			body:        map[string]interface{}{"amount": 500, "type": "income", "transaction_date": 1756450000, "wallet_id": wallet.ID},
+			wantStatus:  fiber.StatusCreated,
+			wantBalance: 1500,
+		},
+		{
+			name:        "expense against a budget",
+			body:        map[string]interface{}{"amount": 200, "type": "expense", "note": "groceries", "transaction_date": 1756450000, "wallet_id": wallet.ID, "budget_id": budget.ID},
+			wantStatus:  fiber.StatusCreated,
+			wantBalance: 1300,
+		},
+		{
+			name:        "zero amount is rejected",
+			body:        map[string]interface{}{"amount": 0, "type": "expense", "transaction_date": 1756450000, "wallet_id": wallet.ID},
+			wantStatus:  fiber.StatusUnprocessableEntity,
+			wantBalance: 1300,
+		},
+		{
+			name:        "unknown type is rejected",
+			body:        map[string]interface{}{"amount": 10, "type": "gift", "transaction_date": 1756450000, "wallet_id": wallet.ID},
+			wantStatus:  fiber.StatusUnprocessableEntity,
+			wantBalance: 1300,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := call(t, app, http.MethodPost, "/v1/transaction", token, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %+v", status, tt.wantStatus, result)
+			}
+
+			status, result = call(t, app, http.MethodGet, "/v1/wallet", token, nil)
+			if status != fiber.StatusOK {
+				t.Fatalf("list wallets returned %d: %+v", status, result)
+			}
+			var wallets []struct {
+				ID      string  `json:"id"`
+				Balance float64 `json:"balance"`
+			}
+			decode(t, result, &wallets)
+			if len(wallets) != 1 || wallets[0].Balance != tt.wantBalance {
+				t.Fatalf("wallets = %+v, want balance %v", wallets, tt.wantBalance)
+			}
+		})
+	}
+
+	status, result = call(t, app, http.MethodGet, "/v1/transaction", token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("list transactions returned %d: %+v", status, result)
+	}
+	var transactions []map[string]interface{}
+	decode(t, result, &transactions)
+	if len(transactions) != 2 {
+		t.Fatalf("listed %d transactions, want 2", len(transactions))
+	}
+}
+
+func TestUsersCannotSeeEachOthersData(t *testing.T) {
+	app := newApp(t)
+	alice := register(t, app, "alice@example.com")
+	bob := register(t, app, "bob@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", alice, map[string]interface{}{
+		"name": "Alice", "type": "personal", "currency": "USD", "balance": 10,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		wantCount int
+	}{
+		{name: "owner", token: alice, wantCount: 1},
+		{name: "other user", token: bob, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := call(t, app, http.MethodGet, "/v1/wallet", tt.token, nil)
+			if status != fiber.StatusOK {
+				t.Fatalf("list wallets returned %d: %+v", status, result)
+			}
+			var wallets []map[string]interface{}
+			decode(t, result, &wallets)
+			if len(wallets) != tt.wantCount {
+				t.Fatalf("listed %d wallets, want %d", len(wallets), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestReportsSummary(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "reports@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Main", "type": "personal", "currency": "IDR", "balance": 1000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	// 2025-08-01 12:00 and 2025-08-03 12:00 UTC
+	for _, body := range []map[string]interface{}{
+		{"amount": 500, "type": "income", "transaction_date": 1754049600, "wallet_id": wallet.ID},
+		{"amount": 200, "type": "expense", "transaction_date": 1754222400, "wallet_id": wallet.ID},
+	} {
+		if status, result := call(t, app, http.MethodPost, "/v1/transaction", token, body); status != fiber.StatusCreated {
+			t.Fatalf("create transaction returned %d: %+v", status, result)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		wantStatus  int
+		wantBuckets int
+		wantNet     float64
+	}{
+		{name: "daily", query: "from=1753977600&to=1754236800&group_by=day", wantStatus: fiber.StatusOK, wantBuckets: 4, wantNet: 300},
+		{name: "monthly", query: "from=1753977600&to=1754236800&group_by=month&tz=Asia/Jakarta", wantStatus: fiber.StatusOK, wantBuckets: 1, wantNet: 300},
+		{name: "unknown group", query: "from=1753977600&to=1754236800&group_by=year", wantStatus: fiber.StatusUnprocessableEntity},
+		{name: "reversed range", query: "from=1754236800&to=1753977600&group_by=day", wantStatus: fiber.StatusUnprocessableEntity},
+		{name: "unknown timezone", query: "from=1753977600&to=1754236800&group_by=day&tz=Mars/Olympus", wantStatus: fiber.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := call(t, app, http.MethodGet, "/v1/reports/summary?"+tt.query, token, nil)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %+v", status, tt.wantStatus, result)
+			}
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var report struct {
+				Totals struct {
+					Net float64 `json:"net"`
+				} `json:"totals"`
+				Buckets []map[string]interface{} `json:"buckets"`
+			}
+			decode(t, result, &report)
+			if len(report.Buckets) != tt.wantBuckets || report.Totals.Net != tt.wantNet {
+				t.Fatalf("report = %+v, want %d buckets and net %v", report, tt.wantBuckets, tt.wantNet)
+			}
+		})
+	}
+}
+
+func TestReportsCategories(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "categories@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Main", "type": "personal", "currency": "IDR", "balance": 1000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	status, result = call(t, app, http.MethodPost, "/v1/budget", token, map[string]interface{}{
+		"name": "Food", "amount": 500, "type": "monthly", "category": "food",
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create budget returned %d: %+v", status, result)
+	}
+	var budget struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &budget)
+
+	for _, body := range []map[string]interface{}{
+		{"amount": 100, "type": "expense", "note": "Lunch", "transaction_date": 1500, "wallet_id": wallet.ID, "budget_id": budget.ID},
+		{"amount": 300, "type": "expense", "note": "Lunch", "transaction_date": 2500, "wallet_id": wallet.ID, "budget_id": budget.ID},
+		{"amount": 100, "type": "expense", "note": "Taxi", "transaction_date": 2600, "wallet_id": wallet.ID},
+	} {
+		if status, result := call(t, app, http.MethodPost, "/v1/transaction", token, body); status != fiber.StatusCreated {
+			t.Fatalf("create transaction returned %d: %+v", status, result)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{name: "period", query: "from=2000&to=3000", wantStatus: fiber.StatusOK},
+		{name: "missing range", query: "from=2000", wantStatus: fiber.StatusUnprocessableEntity},
+		{name: "top out of range", query: "from=2000&to=3000&top=500", wantStatus: fiber.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := call(t, app, http.MethodGet, "/v1/reports/categories?"+tt.query, token, nil)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %+v", status, tt.wantStatus, result)
+			}
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var report struct {
+				Total struct {
+					Amount        float64 `json:"amount"`
+					ChangePercent float64 `json:"change_percent"`
+				} `json:"total"`
+				Categories []struct {
+					Category string  `json:"category"`
+					Share    float64 `json:"share"`
+				} `json:"categories"`
+				TopNotes []struct {
+					Note string `json:"note"`
+				} `json:"top_notes"`
+			}
+			decode(t, result, &report)
+			if report.Total.Amount != 400 || report.Total.ChangePercent != 300 {
+				t.Fatalf("total = %+v, want 400 up 300%%", report.Total)
+			}
+			if len(report.Categories) != 2 || report.Categories[0].Category != "food" || report.Categories[0].Share != 75 {
+				t.Fatalf("categories = %+v", report.Categories)
+			}
+			if len(report.TopNotes) != 2 || report.TopNotes[0].Note != "Lunch" {
+				t.Fatalf("top notes = %+v", report.TopNotes)
+			}
+		})
+	}
+}
+
+func TestReportsNetWorth(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "networth@example.com")
+
+	var walletIds []string
+	for _, body := range []map[string]interface{}{
+		{"name": "Checking", "type": "personal", "currency": "USD", "balance": 1000},
+		{"name": "Card", "type": "credit", "currency": "USD", "balance": 0},
+	} {
+		status, result := call(t, app, http.MethodPost, "/v1/wallet", token, body)
+		if status != fiber.StatusCreated {
+			t.Fatalf("create wallet returned %d: %+v", status, result)
+		}
+		var wallet struct {
+			ID string `json:"id"`
+		}
+		decode(t, result, &wallet)
+		walletIds = append(walletIds, wallet.ID)
+	}
+
+	// Spending on the card raises what is owed instead of failing for lack of funds
+	status, result := call(t, app, http.MethodPost, "/v1/transaction", token, map[string]interface{}{
+		"amount": 200, "type": "expense", "transaction_date": time.Now().Unix(), "wallet_id": walletIds[1],
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create transaction returned %d: %+v", status, result)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{name: "today", query: fmt.Sprintf("from=%d&to=%d", today.Unix(), today.AddDate(0, 0, 1).Unix()), wantStatus: fiber.StatusOK},
+		{name: "missing end", query: fmt.Sprintf("from=%d", today.Unix()), wantStatus: fiber.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := call(t, app, http.MethodGet, "/v1/reports/net-worth?"+tt.query, token, nil)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %+v", status, tt.wantStatus, result)
+			}
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var report struct {
+				Points []struct {
+					Assets      float64 `json:"assets"`
+					Liabilities float64 `json:"liabilities"`
+					NetWorth    float64 `json:"net_worth"`
+				} `json:"points"`
+			}
+			decode(t, result, &report)
+			if len(report.Points) != 1 || report.Points[0].Assets != 1000 || report.Points[0].Liabilities != 200 || report.Points[0].NetWorth != 800 {
+				t.Fatalf("points = %+v, want 1000 in assets and 200 owed", report.Points)
+			}
+		})
+	}
+}
+
+func TestReportsForecast(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "forecast@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Checking", "type": "personal", "currency": "USD", "balance": 500,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantPoints int
+	}{
+		{name: "default horizon", query: "", wantStatus: fiber.StatusOK, wantPoints: 31},
+		{name: "one wallet", query: "horizon=90&wallet_id=" + wallet.ID, wantStatus: fiber.StatusOK, wantPoints: 91},
+		{name: "unsupported horizon", query: "horizon=45", wantStatus: fiber.StatusUnprocessableEntity},
+		{name: "unknown wallet", query: "wallet_id=" + uuid.NewString(), wantStatus: fiber.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := call(t, app, http.MethodGet, "/v1/reports/forecast?"+tt.query, token, nil)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %+v", status, tt.wantStatus, result)
+			}
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+
+			var report struct {
+				Wallets []struct {
+					Points []struct {
+						Balance float64 `json:"balance"`
+					} `json:"points"`
+				} `json:"wallets"`
+			}
+			decode(t, result, &report)
+			if len(report.Wallets) != 1 || len(report.Wallets[0].Points) != tt.wantPoints || report.Wallets[0].Points[0].Balance != 500 {
+				t.Fatalf("report = %+v, want %d points starting at 500", report, tt.wantPoints)
+			}
+		})
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "import@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Checking", "type": "personal", "currency": "USD", "balance": 100,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	status, result = call(t, app, http.MethodPost, "/v1/import/profiles", token, map[string]interface{}{"name": "No amount", "date_column": 0})
+	if status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("profile without an amount column returned %d: %+v", status, result)
+	}
+
+	status, result = call(t, app, http.MethodPost, "/v1/import/profiles", token, map[string]interface{}{
+		"name": "Bank", "delimiter": ";", "has_header": true, "date_column": 0, "date_format": "DD.MM.YYYY",
+		"debit_column": 2, "credit_column": 3, "note_column": 1, "decimal_separator": ",",
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create profile returned %d: %+v", status, result)
+	}
+	var profile struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &profile)
+
+	statement := "Datum;Text;Soll;Haben\n01.08.2025;Gehalt;;1.000,00\n02.08.2025;Miete;750,50;\n"
+	fields := map[string]string{"profile_id": profile.ID, "wallet_id": wallet.ID}
+
+	type summary struct {
+		New        int `json:"new"`
+		Duplicates int `json:"duplicates"`
+		Imported   int `json:"imported"`
+	}
+
+	fields["dry_run"] = "true"
+	status, result = upload(t, app, "/v1/import/csv", token, fields, statement)
+	var preview summary
+	decode(t, result, &preview)
+	if status != fiber.StatusOK || preview.New != 2 || preview.Imported != 0 {
+		t.Fatalf("preview returned %d: %+v", status, preview)
+	}
+
+	fields["dry_run"] = "false"
+	status, result = upload(t, app, "/v1/import/csv", token, fields, statement)
+	var committed summary
+	decode(t, result, &committed)
+	if status != fiber.StatusCreated || committed.Imported != 2 {
+		t.Fatalf("commit returned %d: %+v", status, committed)
+	}
+
+	status, result = call(t, app, http.MethodGet, "/v1/wallet", token, nil)
+	var wallets []struct {
+		Balance float64 `json:"balance"`
+	}
+	decode(t, result, &wallets)
+	if status != fiber.StatusOK || len(wallets) != 1 || wallets[0].Balance != 349.5 {
+		t.Fatalf("wallets = %+v, want a balance of 349.5", wallets)
+	}
+
+	// Importing the same statement again books nothing
+	status, result = upload(t, app, "/v1/import/csv", token, fields, statement)
+	var again summary
+	decode(t, result, &again)
+	if status != fiber.StatusCreated || again.Duplicates != 2 || again.Imported != 0 {
+		t.Fatalf("second import returned %d: %+v", status, again)
+	}
+
+	status, result = upload(t, app, "/v1/import/csv", token, map[string]string{"wallet_id": wallet.ID}, statement)
+	if status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("import without a profile returned %d: %+v", status, result)
+	}
+}
+
+func TestImportOFX(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "ofx@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Checking", "type": "personal", "currency": "USD", "balance": 100,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	statement := `<OFX><STMTRS><CURDEF>USD<BANKACCTFROM><ACCTID>000123</BANKACCTFROM>
+<STMTTRN><DTPOSTED>20250801<TRNAMT>50.00<FITID>F1<NAME>Refund</STMTTRN>
+<STMTTRN><DTPOSTED>20250802<TRNAMT>-20.00<FITID>F2<NAME>Groceries</STMTTRN>
+<LEDGERBAL><BALAMT>130.00</LEDGERBAL></STMTRS></OFX>`
+
+	type summary struct {
+		New        int `json:"new"`
+		Duplicates int `json:"duplicates"`
+		Imported   int `json:"imported"`
+		Accounts   []struct {
+			WalletID   *string `json:"wallet_id"`
+			Reconciled *bool   `json:"reconciled"`
+		} `json:"accounts"`
+	}
+
+	status, result = upload(t, app, "/v1/import/ofx", token, map[string]string{"dry_run": "true"}, statement)
+	var unmapped summary
+	decode(t, result, &unmapped)
+	if status != fiber.StatusOK || unmapped.New != 0 || unmapped.Accounts[0].WalletID != nil {
+		t.Fatalf("preview of an unknown account returned %d: %+v", status, unmapped)
+	}
+
+	status, result = upload(t, app, "/v1/import/ofx", token, map[string]string{"account_map": `{"000123":"` + wallet.ID + `"}`}, statement)
+	var committed summary
+	decode(t, result, &committed)
+	if status != fiber.StatusCreated || committed.Imported != 2 || !*committed.Accounts[0].Reconciled {
+		t.Fatalf("commit returned %d: %+v", status, committed)
+	}
+
+	// The account is remembered and the bank ids recognised
+	status, result = upload(t, app, "/v1/import/ofx", token, map[string]string{}, statement)
+	var again summary
+	decode(t, result, &again)
+	if status != fiber.StatusCreated || again.Duplicates != 2 || again.Imported != 0 || *again.Accounts[0].WalletID != wallet.ID {
+		t.Fatalf("second import returned %d: %+v", status, again)
+	}
+
+	status, result = upload(t, app, "/v1/import/qif", token, map[string]string{"wallet_id": wallet.ID, "date_order": "ydm"}, "!Type:Bank\n^\n")
+	if status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("import with an unknown date order returned %d: %+v", status, result)
+	}
+}
+
+func TestExportTransactions(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "export@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Giro", "type": "personal", "currency": "EUR", "balance": 5000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	for _, body := range []map[string]interface{}{
+		{"amount": 1234.5, "type": "expense", "note": "Miete", "transaction_date": 1754006400, "wallet_id": wallet.ID},
+		{"amount": 3000, "type": "income", "note": "Gehalt", "transaction_date": 1753920000, "wallet_id": wallet.ID},
+	} {
+		if status, result := call(t, app, http.MethodPost, "/v1/transaction", token, body); status != fiber.StatusCreated {
+			t.Fatalf("create transaction returned %d: %+v", status, result)
+		}
+	}
+
+	resp, body := download(t, app, "/v1/export/transactions?format=csv&type=expense", token, map[string]string{fiber.HeaderAcceptLanguage: "de-DE"})
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderContentDisposition) != `attachment; filename="transactions.csv"` {
+		t.Fatalf("csv export returned %d with %v", resp.StatusCode, resp.Header)
+	}
+	want := "Date;Type;Amount;Currency;Wallet;Budget;Category;Note;ID\n2025-08-01;expense;-1.234,50;EUR;Giro;;;Miete;"
+	if len(body) < len(want) || body[:len(want)] != want {
+		t.Fatalf("csv export = %q, want the rent in German notation", body)
+	}
+
+	resp, body = download(t, app, "/v1/export/transactions?format=jsonl", token, nil)
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(body)))
+	for decoder.More() {
+		var line map[string]interface{}
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatalf("jsonl export has an undecodable line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	if resp.StatusCode != fiber.StatusOK || len(lines) != 2 || lines[0]["note"] != "Gehalt" || lines[1]["amount"] != -1234.5 {
+		t.Fatalf("jsonl export returned %d: %v", resp.StatusCode, lines)
+	}
+
+	resp, body = download(t, app, "/v1/export/transactions?format=xlsx", token, nil)
+	if resp.StatusCode != fiber.StatusOK || len(body) < 4 || body[:2] != "PK" {
+		t.Fatalf("xlsx export returned %d and %d bytes", resp.StatusCode, len(body))
+	}
+
+	if status, result := call(t, app, http.MethodGet, "/v1/export/transactions?format=pdf", token, nil); status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("export in an unknown format returned %d: %+v", status, result)
+	}
+}
+
+func TestLedgerRoundTrip(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "ledger@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Giro", "type": "personal", "currency": "EUR", "balance": 5000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	status, result = call(t, app, http.MethodPost, "/v1/budget", token, map[string]interface{}{
+		"name": "Groceries", "amount": 400, "type": "monthly", "category": "food",
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create budget returned %d: %+v", status, result)
+	}
+	var budget struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &budget)
+
+	for _, body := range []map[string]interface{}{
+		{"amount": 3000, "type": "income", "note": "Gehalt", "transaction_date": 1753920000, "wallet_id": wallet.ID},
+		{"amount": 42.5, "type": "expense", "note": "Markt", "transaction_date": 1754006400, "wallet_id": wallet.ID, "budget_id": budget.ID},
+	} {
+		if status, result := call(t, app, http.MethodPost, "/v1/transaction", token, body); status != fiber.StatusCreated {
+			t.Fatalf("create transaction returned %d: %+v", status, result)
+		}
+	}
+
+	resp, exported := download(t, app, "/v1/export/ledger?syntax=beancount", token, nil)
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderContentDisposition) != `attachment; filename="transactions.beancount"` {
+		t.Fatalf("beancount export returned %d with %v", resp.StatusCode, resp.Header)
+	}
+	for _, want := range []string{"open Assets:Giro EUR", "Equity:Opening-Balances      -5000.00 EUR", "Expenses:Food:Groceries"} {
+		if !strings.Contains(exported, want) {
+			t.Fatalf("beancount export = %q, want it to contain %q", exported, want)
+		}
+	}
+
+	// Another user seeds their books from the file and exports the same ledger back
+	other := register(t, app, "ledger-other@example.com")
+
+	type summary struct {
+		Imported   int `json:"imported"`
+		Duplicates int `json:"duplicates"`
+		Wallets    []struct {
+			Balance float64 `json:"balance"`
+		} `json:"wallets"`
+		Budgets []struct {
+			Name string `json:"name"`
+		} `json:"budgets"`
+	}
+
+	status, result = upload(t, app, "/v1/import/beancount", other, map[string]string{}, exported)
+	var seeded summary
+	decode(t, result, &seeded)
+	if status != fiber.StatusCreated || seeded.Imported != 2 || len(seeded.Wallets) != 1 || seeded.Wallets[0].Balance != 5000 || len(seeded.Budgets) != 1 {
+		t.Fatalf("seeding import returned %d: %+v", status, seeded)
+	}
+
+	if _, reexported := download(t, app, "/v1/export/ledger?syntax=beancount", other, nil); reexported != exported {
+		t.Fatalf("export of the seeded books = %q, want %q", reexported, exported)
+	}
+
+	status, result = upload(t, app, "/v1/import/beancount", other, map[string]string{}, exported)
+	var again summary
+	decode(t, result, &again)
+	if status != fiber.StatusCreated || again.Duplicates != 2 || again.Imported != 0 || len(again.Wallets) != 0 {
+		t.Fatalf("second import returned %d: %+v", status, again)
+	}
+
+	resp, exported = download(t, app, "/v1/export/ledger?syntax=ledger&from=1754006400", token, nil)
+	if resp.StatusCode != fiber.StatusOK || !strings.Contains(exported, "2025-08-01 * Opening balance") {
+		t.Fatalf("ledger export returned %d: %q", resp.StatusCode, exported)
+	}
+
+	if status, result := call(t, app, http.MethodGet, "/v1/export/ledger?syntax=gnucash", token, nil); status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("export in an unknown syntax returned %d: %+v", status, result)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "idempotent@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Cash", "type": "personal", "currency": "IDR", "balance": 1000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	post := func(key string, body map[string]interface{}) (*http.Response, string) {
+		t.Helper()
+
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction", bytes.NewReader(payload))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("POST /v1/transaction failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("POST /v1/transaction returned an unreadable body: %v", err)
+		}
+		return resp, string(raw)
+	}
+
+	coffee := map[string]interface{}{"amount": 25, "type": "expense", "note": "Kopi", "transaction_date": 1754006400, "wallet_id": wallet.ID}
+
+	first, firstBody := post("retry-1", coffee)
+	if first.StatusCode != fiber.StatusCreated || first.Header.Get(middleware.IdempotentReplayedHeader) != "" {
+		t.Fatalf("first request returned %d with %v", first.StatusCode, first.Header)
+	}
+
+	// The retry gets the same response and the wallet is only charged once
+	retry, retryBody := post("retry-1", coffee)
+	if retry.StatusCode != fiber.StatusCreated || retryBody != firstBody || retry.Header.Get(middleware.IdempotentReplayedHeader) != "true" {
+		t.Fatalf("retry returned %d %q, want the first response %q replayed", retry.StatusCode, retryBody, firstBody)
+	}
+	if etag := retry.Header.Get(fiber.HeaderETag); etag == "" || etag != first.Header.Get(fiber.HeaderETag) {
+		t.Fatalf("retry returned ETag %q, want the first response's %q", etag, first.Header.Get(fiber.HeaderETag))
+	}
+
+	status, result = call(t, app, http.MethodGet, "/v1/wallet", token, nil)
+	var wallets []struct {
+		Balance float64 `json:"balance"`
+	}
+	decode(t, result, &wallets)
+	if status != fiber.StatusOK || len(wallets) != 1 || wallets[0].Balance != 975 {
+		t.Fatalf("wallets after a retried charge = %+v, want 975 left", wallets)
+	}
+
+	coffee["amount"] = 30
+	if reused, body := post("retry-1", coffee); reused.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("key reused for another payload returned %d: %s", reused.StatusCode, body)
+	}
+
+	// Client errors are the outcome of the request and are replayed too
+	invalid := map[string]interface{}{"amount": 25, "type": "expense", "transaction_date": 1754006400, "wallet_id": uuid.NewString()}
+	if missing, _ := post("retry-2", invalid); missing.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("charge on an unknown wallet returned %d", missing.StatusCode)
+	}
+	if again, _ := post("retry-2", invalid); again.StatusCode != fiber.StatusNotFound || again.Header.Get(middleware.IdempotentReplayedHeader) != "true" {
+		t.Fatalf("retried failure returned %d with %v", again.StatusCode, again.Header)
+	}
+}
+
+func TestOptimisticConcurrency(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "etag@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Cash", "type": "personal", "currency": "IDR", "balance": 1000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID      string  `json:"id"`
+		Balance float64 `json:"balance"`
+		Version int     `json:"version"`
+	}
+	decode(t, result, &wallet)
+
+	put := func(path, ifMatch string, body interface{}) *http.Response {
+		t.Helper()
+
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPut, path, bytes.NewReader(payload))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		if ifMatch != "" {
+			req.Header.Set(fiber.HeaderIfMatch, ifMatch)
+		}
+
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("PUT %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	walletPath := "/v1/wallet/" + wallet.ID
+	resp, _ := download(t, app, walletPath, token, nil)
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderETag) != `"1"` {
+		t.Fatalf("GET wallet returned %d with ETag %q, want version 1", resp.StatusCode, resp.Header.Get(fiber.HeaderETag))
+	}
+	if resp, _ := download(t, app, walletPath, token, map[string]string{fiber.HeaderIfNoneMatch: `"1"`}); resp.StatusCode != fiber.StatusNotModified {
+		t.Fatalf("GET wallet with its current ETag returned %d, want 304", resp.StatusCode)
+	}
+
+	rename := map[string]interface{}{"name": "Household"}
+	if resp := put(walletPath, "", rename); resp.StatusCode != fiber.StatusPreconditionRequired {
+		t.Fatalf("PUT wallet without If-Match returned %d, want 428", resp.StatusCode)
+	}
+	if resp := put(walletPath, `"1"`, rename); resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderETag) != `"2"` {
+		t.Fatalf("PUT wallet returned %d with ETag %q, want version 2", resp.StatusCode, resp.Header.Get(fiber.HeaderETag))
+	}
+	// The other device still holds version 1
+	if resp := put(walletPath, `"1"`, map[string]interface{}{"name": "Mine"}); resp.StatusCode != fiber.StatusPreconditionFailed {
+		t.Fatalf("PUT wallet with a stale ETag returned %d, want 412", resp.StatusCode)
+	}
+
+	status, result = call(t, app, http.MethodPost, "/v1/transaction", token, map[string]interface{}{
+		"amount": 25, "type": "expense", "note": "Kopi", "transaction_date": 1754006400, "wallet_id": wallet.ID,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create transaction returned %d: %+v", status, result)
+	}
+	var transaction struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &transaction)
+
+	// Correcting the amount books the difference and the wallet's balance change is a new version
+	transactionPath := "/v1/transaction/" + transaction.ID
+	correction := map[string]interface{}{"amount": 40, "type": "expense", "note": "Kopi", "transaction_date": 1754006400, "wallet_id": wallet.ID}
+	if resp := put(transactionPath, `"1"`, correction); resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderETag) != `"2"` {
+		t.Fatalf("PUT transaction returned %d with ETag %q, want version 2", resp.StatusCode, resp.Header.Get(fiber.HeaderETag))
+	}
+	if resp := put(transactionPath, `"1"`, correction); resp.StatusCode != fiber.StatusPreconditionFailed {
+		t.Fatalf("PUT transaction with a stale ETag returned %d, want 412", resp.StatusCode)
+	}
+
+	resp, body := download(t, app, walletPath, token, map[string]string{fiber.HeaderIfNoneMatch: `"2"`})
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("GET wallet after its balance moved returned %d, want 200", resp.StatusCode)
+	}
+	var detail envelope
+	if err := json.Unmarshal([]byte(body), &detail); err != nil {
+		t.Fatalf("GET wallet returned an undecodable body: %v", err)
+	}
+	decode(t, detail, &wallet)
+	if wallet.Balance != 960 || wallet.Version != 4 || resp.Header.Get(fiber.HeaderETag) != `"4"` {
+		t.Fatalf("wallet = %+v with ETag %q, want 960 left at version 4", wallet, resp.Header.Get(fiber.HeaderETag))
+	}
+}
+
+func TestTransactionsCannotOverdraw(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "overdraw@example.com")
+
+	createWallet := func(name string, balance float64) string {
+		t.Helper()
+
+		status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+			"name": name, "type": "personal", "currency": "IDR", "balance": balance,
+		})
+		if status != fiber.StatusCreated {
+			t.Fatalf("create wallet returned %d: %+v", status, result)
+		}
+		var wallet struct {
+			ID string `json:"id"`
+		}
+		decode(t, result, &wallet)
+		return wallet.ID
+	}
+	cash := createWallet("Cash", 100)
+	savings := createWallet("Savings", 0)
+
+	status, result := call(t, app, http.MethodPost, "/v1/transaction", token, map[string]interface{}{
+		"amount": 60, "type": "expense", "transaction_date": 1754006400, "wallet_id": cash,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create transaction returned %d: %+v", status, result)
+	}
+	var transaction struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &transaction)
+
+	status, result = call(t, app, http.MethodPost, "/v1/transaction", token, map[string]interface{}{
+		"amount": 50, "type": "expense", "transaction_date": 1754006400, "wallet_id": cash,
+	})
+	if status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("expense above the balance returned %d, want 422: %+v", status, result)
+	}
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{
+			name: "amount raised above the balance",
+			body: map[string]interface{}{"amount": 200, "type": "expense", "transaction_date": 1754006400, "wallet_id": cash},
+		},
+		{
+			name: "moved to a wallet that cannot pay",
+			body: map[string]interface{}{"amount": 60, "type": "expense", "transaction_date": 1754006400, "wallet_id": savings},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPut, "/v1/transaction/"+transaction.ID, bytes.NewReader(payload))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+			req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("PUT transaction failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != fiber.StatusUnprocessableEntity {
+				t.Fatalf("PUT transaction returned %d, want 422", resp.StatusCode)
+			}
+
+			// Nothing of the update is kept, the transaction and both balances are as before
+			status, result := call(t, app, http.MethodGet, "/v1/transaction/"+transaction.ID, token, nil)
+			if status != fiber.StatusOK {
+				t.Fatalf("get transaction returned %d: %+v", status, result)
+			}
+			var stored struct {
+				Amount  float64 `json:"amount"`
+				Version int     `json:"version"`
+				Wallet  struct {
+					ID string `json:"id"`
+				} `json:"wallet"`
+			}
+			decode(t, result, &stored)
+			if stored.Amount != 60 || stored.Version != 1 || stored.Wallet.ID != cash {
+				t.Fatalf("transaction = %+v, want 60 on cash at version 1", stored)
+			}
+
+			status, result = call(t, app, http.MethodGet, "/v1/wallet", token, nil)
+			if status != fiber.StatusOK {
+				t.Fatalf("list wallets returned %d: %+v", status, result)
+			}
+			var wallets []struct {
+				ID      string  `json:"id"`
+				Balance float64 `json:"balance"`
+			}
+			decode(t, result, &wallets)
+			for _, wallet := range wallets {
+				if want := map[string]float64{cash: 40, savings: 0}[wallet.ID]; wallet.Balance != want {
+					t.Errorf("wallet %s balance = %v, want %v", wallet.ID, wallet.Balance, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "tags@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Main", "type": "personal", "currency": "IDR", "balance": 1000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &wallet)
+
+	var transactionIds []string
+	for _, body := range []map[string]interface{}{
+		{"amount": 100, "type": "expense", "note": "Hotel", "transaction_date": 1500, "wallet_id": wallet.ID},
+		{"amount": 60, "type": "expense", "note": "Train", "transaction_date": 1600, "wallet_id": wallet.ID},
+		{"amount": 40, "type": "expense", "note": "Lunch", "transaction_date": 1700, "wallet_id": wallet.ID},
+	} {
+		status, result := call(t, app, http.MethodPost, "/v1/transaction", token, body)
+		if status != fiber.StatusCreated {
+			t.Fatalf("create transaction returned %d: %+v", status, result)
+		}
+		var transaction struct {
+			ID string `json:"id"`
+		}
+		decode(t, result, &transaction)
+		transactionIds = append(transactionIds, transaction.ID)
+	}
+
+	createTag := func(name string) string {
+		t.Helper()
+		status, result := call(t, app, http.MethodPost, "/v1/tag", token, map[string]interface{}{"name": name})
+		if status != fiber.StatusCreated {
+			t.Fatalf("create tag %q returned %d: %+v", name, status, result)
+		}
+		var tag struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		decode(t, result, &tag)
+		if tag.Name != strings.ToLower(strings.TrimSpace(name)) {
+			t.Fatalf("tag name = %q, want it normalised", tag.Name)
+		}
+		return tag.ID
+	}
+	vacation := createTag(" Vacation-2026")
+	createTag("Vat")
+	reimbursable := createTag("reimbursable")
+
+	if status, result := call(t, app, http.MethodPost, "/v1/tag", token, map[string]interface{}{"name": "VACATION-2026"}); status != fiber.StatusConflict {
+		t.Fatalf("create duplicate tag returned %d, want 409: %+v", status, result)
+	}
+
+	status, result = call(t, app, http.MethodGet, "/v1/tag?prefix=VA", token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("autocomplete returned %d: %+v", status, result)
+	}
+	var suggestions []struct {
+		Name string `json:"name"`
+	}
+	decode(t, result, &suggestions)
+	if len(suggestions) != 2 || suggestions[0].Name != "vacation-2026" || suggestions[1].Name != "vat" {
+		t.Fatalf("suggestions = %+v", suggestions)
+	}
+
+	apply := func(body map[string]interface{}) (int, envelope) {
+		t.Helper()
+		return call(t, app, http.MethodPost, "/v1/transaction/tags", token, body)
+	}
+	status, result = apply(map[string]interface{}{"transaction_ids": transactionIds[:2], "add": []string{vacation, reimbursable}})
+	if status != fiber.StatusOK {
+		t.Fatalf("tag transactions returned %d: %+v", status, result)
+	}
+	var applied struct {
+		Added   int `json:"added"`
+		Removed int `json:"removed"`
+	}
+	decode(t, result, &applied)
+	if applied.Added != 4 || applied.Removed != 0 {
+		t.Fatalf("tagging = %+v, want 4 added", applied)
+	}
+	if status, _ := apply(map[string]interface{}{"transaction_ids": transactionIds[1:2], "remove": []string{reimbursable}}); status != fiber.StatusOK {
+		t.Fatalf("untag transaction returned %d", status)
+	}
+	if status, _ := apply(map[string]interface{}{"transaction_ids": transactionIds}); status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("tagging without tags returned %d, want 422", status)
+	}
+
+	type listed struct {
+		Note string `json:"note"`
+		Tags []struct {
+			Name string `json:"name"`
+		} `json:"tags"`
+	}
+	list := func(query string) []listed {
+		t.Helper()
+		status, result := call(t, app, http.MethodGet, "/v1/transaction?"+query, token, nil)
+		if status != fiber.StatusOK {
+			t.Fatalf("list transactions returned %d: %+v", status, result)
+		}
+		var transactions []listed
+		decode(t, result, &transactions)
+		return transactions
+	}
+	if tagged := list("tag_id=" + vacation); len(tagged) != 2 {
+		t.Fatalf("listed %d vacation transactions, want 2", len(tagged))
+	}
+	tagged := list("tag_id=" + vacation + "&tag_id=" + reimbursable)
+	if len(tagged) != 1 || tagged[0].Note != "Hotel" || len(tagged[0].Tags) != 2 || tagged[0].Tags[0].Name != "reimbursable" {
+		t.Fatalf("transactions with both tags = %+v, want the hotel", tagged)
+	}
+
+	status, result = call(t, app, http.MethodGet, "/v1/reports/categories?from=1000&to=2000", token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("categories report returned %d: %+v", status, result)
+	}
+	var report struct {
+		Tags []struct {
+			Name   string  `json:"name"`
+			Amount float64 `json:"amount"`
+			Share  float64 `json:"share"`
+		} `json:"tags"`
+	}
+	decode(t, result, &report)
+	if len(report.Tags) != 2 || report.Tags[0].Name != "vacation-2026" || report.Tags[0].Amount != 160 || report.Tags[0].Share != 80 {
+		t.Fatalf("report tags = %+v, want vacation first at 160", report.Tags)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/tag/"+vacation, nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("DELETE tag failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Fatalf("delete tag returned %d, want 204", resp.StatusCode)
+	}
+	if tagged := list("tag_id=" + vacation); len(tagged) != 0 {
+		t.Fatalf("listed %d transactions with a deleted tag, want none", len(tagged))
+	}
+}
+
+func TestSplitTransactions(t *testing.T) {
+	app := newApp(t)
+	token := register(t, app, "splits@example.com")
+
+	status, result := call(t, app, http.MethodPost, "/v1/wallet", token, map[string]interface{}{
+		"name": "Main", "type": "personal", "currency": "IDR", "balance": 1000,
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create wallet returned %d: %+v", status, result)
+	}
+	var wallet struct {
+		ID      string  `json:"id"`
+		Balance float64 `json:"balance"`
+	}
+	decode(t, result, &wallet)
+
+	createBudget := func(name, category string) string {
+		t.Helper()
+		status, result := call(t, app, http.MethodPost, "/v1/budget", token, map[string]interface{}{
+			"name": name, "amount": 500, "type": "monthly", "category": category,
+		})
+		if status != fiber.StatusCreated {
+			t.Fatalf("create budget returned %d: %+v", status, result)
+		}
+		var budget struct {
+			ID string `json:"id"`
+		}
+		decode(t, result, &budget)
+		return budget.ID
+	}
+	groceries := createBudget("Groceries", "food")
+	household := createBudget("Household", "home")
+
+	receipt := func(amount float64, splits ...map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"amount": amount, "type": "expense", "note": "Supermarket", "transaction_date": 1500, "wallet_id": wallet.ID, "splits": splits,
+		}
+	}
+	groceryLine := map[string]interface{}{"amount": 100, "note": "Vegetables", "budget_id": groceries}
+	householdLine := map[string]interface{}{"amount": 50, "note": "Detergent", "budget_id": household}
+
+	invalid := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{name: "lines miss the amount", body: receipt(160, groceryLine, householdLine)},
+		{name: "a single line", body: receipt(100, groceryLine)},
+		{name: "budget next to lines", body: func() map[string]interface{} {
+			body := receipt(150, groceryLine, householdLine)
+			body["budget_id"] = groceries
+			return body
+		}()},
+	}
+	for _, tt := range invalid {
+		if status, result := call(t, app, http.MethodPost, "/v1/transaction", token, tt.body); status != fiber.StatusUnprocessableEntity {
+			t.Fatalf("%s: create transaction returned %d, want 422: %+v", tt.name, status, result)
+		}
+	}
+
+	// A line can only be booked to a budget of the user
+	other := register(t, app, "splits-other@example.com")
+	status, result = call(t, app, http.MethodPost, "/v1/budget", other, map[string]interface{}{
+		"name": "Theirs", "amount": 500, "type": "monthly", "category": "food",
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("create budget returned %d: %+v", status, result)
+	}
+	var theirs struct {
+		ID string `json:"id"`
+	}
+	decode(t, result, &theirs)
+	for _, budgetId := range []string{theirs.ID, "6f1c1d53-7a55-4a55-9a3b-0c2b8a5b1f10"} {
+		line := map[string]interface{}{"amount": 50, "budget_id": budgetId}
+		if status, result := call(t, app, http.MethodPost, "/v1/transaction", token, receipt(150, groceryLine, line)); status != fiber.StatusNotFound {
+			t.Fatalf("line on budget %s: create transaction returned %d, want 404: %+v", budgetId, status, result)
+		}
+	}
+
+	status, result = call(t, app, http.MethodPost, "/v1/transaction", token, receipt(150, groceryLine, householdLine))
+	if status != fiber.StatusCreated {
+		t.Fatalf("create split transaction returned %d: %+v", status, result)
+	}
+	var transaction struct {
+		Amount float64 `json:"amount"`
+		Budget *struct {
+			ID string `json:"id"`
+		} `json:"budget"`
+		Splits []struct {
+			Amount float64 `json:"amount"`
+			Note   string  `json:"note"`
+			Budget *struct {
+				Name string `json:"name"`
+			} `json:"budget"`
+		} `json:"splits"`
+	}
+	decode(t, result, &transaction)
+	if transaction.Budget != nil || len(transaction.Splits) != 2 || transaction.Splits[1].Budget == nil || transaction.Splits[1].Budget.Name != "Household" {
+		t.Fatalf("transaction = %+v, want two budgeted lines", transaction)
+	}
+
+	// The wallet pays the receipt once
+	status, result = call(t, app, http.MethodGet, "/v1/wallet", token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("list wallets returned %d: %+v", status, result)
+	}
+	var wallets []struct {
+		Balance float64 `json:"balance"`
+	}
+	decode(t, result, &wallets)
+	if len(wallets) != 1 || wallets[0].Balance != 850 {
+		t.Fatalf("wallets = %+v, want 850 left", wallets)
+	}
+
+	status, result = call(t, app, http.MethodGet, "/v1/transaction?budget_id="+household, token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("list transactions returned %d: %+v", status, result)
+	}
+	var listed []map[string]interface{}
+	decode(t, result, &listed)
+	if len(listed) != 1 {
+		t.Fatalf("listed %d household transactions, want the receipt", len(listed))
+	}
+
+	status, result = call(t, app, http.MethodGet, "/v1/reports/categories?from=1000&to=2000", token, nil)
+	if status != fiber.StatusOK {
+		t.Fatalf("categories report returned %d: %+v", status, result)
+	}
+	var report struct {
+		Total struct {
+			Amount float64 `json:"amount"`
+		} `json:"total"`
+		Categories []struct {
+			Category string  `json:"category"`
+			Amount   float64 `json:"amount"`
+		} `json:"categories"`
+	}
+	decode(t, result, &report)
+	if report.Total.Amount != 150 || len(report.Categories) != 2 || report.Categories[0].Category != "food" || report.Categories[0].Amount != 100 || report.Categories[1].Amount != 50 {
+		t.Fatalf("report = %+v, want the receipt split between food and home", report)
+	}
+}
@@ -4,121 +4,138 @@ import (
 	"context"
 	"errors"
 	"finance-backend/internal/domain"
+	"finance-backend/pkg/apperror"
 	"finance-backend/pkg/auth"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/metrics"
+	"finance-backend/pkg/tracing"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type authService struct {
-	db *gorm.DB
+	txManager    domain.TxManager
+	tokenManager *auth.TokenManager
 
 	userRepo    domain.UserRepository
 	sessionRepo domain.SessionRepository
 }
 
-func NewAuthService(db *gorm.DB, userRepo domain.UserRepository, sessionRepo domain.SessionRepository) domain.AuthService {
+func NewAuthService(txManager domain.TxManager, tokenManager *auth.TokenManager, userRepo domain.UserRepository, sessionRepo domain.SessionRepository) domain.AuthService {
 	return &authService{
-		db:          db,
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
+		txManager:    txManager,
+		tokenManager: tokenManager,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
 	}
 }
 
 func (s *authService) Register(ctx context.Context, fullname, email, password string) (*domain.User, *domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "authService.Register")
+	defer span.End()
+
 	log := logger.WithRequestID(ctx)
 
-	tx := s.db.Begin()
+	var (
+		user    *domain.User
+		session *domain.Session
+	)
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		existingUser, err := s.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.WithError(err).Error("[service - Register]: Error checking existing user")
+				return err
+			}
+		}
 
-	existingUser, err := s.userRepo.GetByEmail(tx, ctx, email)
-	if err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			log.WithError(err).Error("[service - Register]: Error checking existing user")
-			tx.Rollback()
-			return nil, nil, err
+		if existingUser != nil {
+			log.Infof("[service - Register]: User with email %s already exists", email)
+			return apperror.ErrUserAlreadyExists
 		}
-	}
 
-	if existingUser != nil {
-		log.Infof("[service - Register]: User with email %s already exists", email)
+		// Hash password
+		hashedPassword, err := auth.HashPassword(password)
+		if err != nil {
+			log.WithError(err).Error("[service - Register]: Error hashing password")
+			return err
+		}
 
-		tx.Rollback()
-		return nil, nil, errors.New("user already exists")
-	}
+		user = &domain.User{
+			FullName: fullname,
+			Email:    email,
+			Password: hashedPassword,
+		}
 
-	// Hash password
-	hashedPassword, err := auth.HashPassword(password)
-	if err != nil {
-		log.WithError(err).Error("[service - Register]: Error hashing password")
-		return nil, nil, err
-	}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			log.WithError(err).Error("[service - Register]: Failed to create user")
+			return err
+		}
 
-	user := &domain.User{
-		FullName: fullname,
-		Email:    email,
-		Password: hashedPassword,
-	}
+		token, expiresAt, err := s.tokenManager.GenerateToken(user.ID.String(), user.Email)
+		if err != nil {
+			log.WithError(err).Error("[service - Register]: Failed to generate token")
+			return err
+		}
 
-	if err := s.userRepo.Create(tx, ctx, user); err != nil {
-		log.WithError(err).Error("[service - Register]: Failed to create user")
+		session = &domain.Session{
+			ID:           uuid.New(),
+			SessionToken: token,
+			UserID:       user.ID,
+			ExpiresAt:    int(expiresAt.Unix()),
+		}
 
-		tx.Rollback()
-		return nil, nil, err
-	}
+		if err := s.sessionRepo.Create(ctx, session); err != nil {
+			log.WithError(err).Error("[service - Register]: Failed to create session")
+			return err
+		}
 
-	token, expiresAt, err := auth.GenerateToken(user.ID.String(), user.Email)
+		return nil
+	})
 	if err != nil {
-		log.WithError(err).Error("[service - Register]: Failed to generate token")
-
-		tx.Rollback()
 		return nil, nil, err
 	}
 
-	session := &domain.Session{
-		ID:           uuid.New(),
-		SessionToken: token,
-		UserID:       user.ID,
-		ExpiresAt:    int(expiresAt.Unix()),
-	}
-
-	if err := s.sessionRepo.Create(tx, ctx, session); err != nil {
-		log.WithError(err).Error("[service - Register]: Failed to create session")
-
-		tx.Rollback()
-		return nil, nil, err
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		log.WithError(err).Error("[service - Register]: Failed to commit transaction")
-		return nil, nil, errors.New("internal server error")
-	}
+	metrics.Registrations.Inc()
 
 	return user, session, nil
 }
 
 func (s *authService) Login(ctx context.Context, email, password string) (*domain.User, *domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "authService.Login")
+	defer span.End()
+
 	log := logger.WithRequestID(ctx)
 
-	user, err := s.userRepo.GetByEmail(s.db, ctx, email)
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Infof("[service - Login]: No user found with email %s", email)
-			return nil, nil, errors.New("invalid email or password")
+			metrics.FailedLogins.WithLabelValues("unknown_email").Inc()
+			return nil, nil, apperror.ErrInvalidCredentials
 		}
 		log.WithError(err).Error("[service - Login]: Error fetching user by email")
-		return nil, nil, errors.New("internal server error")
+		return nil, nil, apperror.ErrInternal.Wrap(err)
 	}
 
 	if !auth.CheckPassword(password, user.Password) {
 		log.Infof("[service - Login]: Invalid password for email %s", email)
-		return nil, nil, errors.New("invalid email or password")
+		metrics.FailedLogins.WithLabelValues("invalid_password").Inc()
+		return nil, nil, apperror.ErrInvalidCredentials
 	}
 
-	token, expiresAt, err := auth.GenerateToken(user.ID.String(), user.Email)
+	if user.IsDisabled() {
+		log.Infof("[service - Login]: Login attempt for disabled account %s", email)
+		metrics.FailedLogins.WithLabelValues("account_disabled").Inc()
+		return nil, nil, apperror.ErrAccountDisabled
+	}
+
+	token, expiresAt, err := s.tokenManager.GenerateToken(user.ID.String(), user.Email)
 	if err != nil {
 		log.WithError(err).Error("[service - Login]: Error generating token")
-		return nil, nil, errors.New("internal server error")
+		return nil, nil, apperror.ErrInternal.Wrap(err)
 	}
 
 	session := &domain.Session{
@@ -128,7 +145,7 @@ func (s *authService) Login(ctx context.Context, email, password string) (*domai
 		ExpiresAt:    int(expiresAt.Unix()),
 	}
 
-	if err := s.sessionRepo.Create(s.db, ctx, session); err != nil {
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
 		log.WithError(err).Error("[service - Login]: Error creating session")
 		return nil, nil, err
 	}
@@ -137,26 +154,34 @@ func (s *authService) Login(ctx context.Context, email, password string) (*domai
 }
 
 func (s *authService) GetUserByToken(ctx context.Context, token string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "authService.GetUserByToken")
+	defer span.End()
+
 	log := logger.WithRequestID(ctx)
 
-	session, err := s.sessionRepo.GetByToken(s.db, ctx, token)
+	session, err := s.sessionRepo.GetByToken(ctx, token)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Infof("[service - GetUserByToken]: No session found with token %s", token)
-			return nil, errors.New("invalid token")
+			return nil, apperror.ErrInvalidToken
 		}
 		log.WithError(err).Error("[service - GetUserByToken]: Error fetching session by token")
-		return nil, errors.New("internal server error")
+		return nil, apperror.ErrInternal.Wrap(err)
 	}
 
-	user, err := s.userRepo.GetByEmail(s.db, ctx, session.User.Email)
+	user, err := s.userRepo.GetByEmail(ctx, session.User.Email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Infof("[service - GetUserByToken]: No user found with ID %s", session.UserID)
-			return nil, errors.New("user not found")
+			return nil, apperror.ErrInvalidToken
 		}
 		log.WithError(err).Error("[service - GetUserByToken]: Error fetching user by ID")
-		return nil, errors.New("internal server error")
+		return nil, apperror.ErrInternal.Wrap(err)
+	}
+
+	if user.IsDisabled() {
+		log.Infof("[service - GetUserByToken]: User %s is disabled", user.ID)
+		return nil, apperror.ErrAccountDisabled
 	}
 
 	return user, nil
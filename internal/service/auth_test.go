@@ -0,0 +1,295 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/auth"
+	"finance-backend/pkg/config"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func newTokenManager() *auth.TokenManager {
+	return auth.NewTokenManager(config.JWTConfig{
+		Secret: "test-secret-that-is-long-enough-for-hs256",
+		TTL:    time.Hour,
+		Issuer: "finance-api",
+	})
+}
+
+// assignID mimics the database filling in the primary key on insert
+func assignID(_ context.Context, user *domain.User) error {
+	user.ID = uuid.New()
+	return nil
+}
+
+func TestAuthServiceRegister(t *testing.T) {
+	tests := []struct {
+		name          string
+		password      string
+		setup         func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository)
+		wantErr       error
+		wantAnyErr    bool
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{
+			name:     "registered",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+				users.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(assignID)
+				sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:     "email already registered",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "new@example.com").Return(&domain.User{ID: uuid.New()}, nil)
+			},
+			wantErr:       apperror.ErrUserAlreadyExists,
+			wantRollbacks: 1,
+		},
+		{
+			name:     "lookup failure",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "new@example.com").Return(nil, errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+		{
+			name:     "hashing failure rolls back",
+			password: "",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantAnyErr:    true,
+			wantRollbacks: 1,
+		},
+		{
+			name:     "user insert failure rolls back",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+				users.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+		{
+			name:     "session insert failure rolls back",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+				users.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(assignID)
+				sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.setup(users, sessions)
+
+			authService := service.NewAuthService(txManager, newTokenManager(), users, sessions)
+			user, session, err := authService.Register(context.Background(), "New User", "new@example.com", tt.password)
+
+			switch {
+			case tt.wantAnyErr:
+				if err == nil {
+					t.Fatal("Register() succeeded, want an error")
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				if user.Password == tt.password {
+					t.Fatal("password was stored in plain text")
+				}
+				if session.UserID != user.ID || session.SessionToken == "" {
+					t.Fatalf("session %+v does not belong to user %s", session, user.ID)
+				}
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
+
+func TestAuthServiceLogin(t *testing.T) {
+	hashed, err := auth.HashPassword("correct-password")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	disabledAt := 1760000000
+
+	activeUser := func() *domain.User {
+		return &domain.User{ID: uuid.New(), Email: "user@example.com", Password: hashed}
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		setup    func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository)
+		wantErr  error
+	}{
+		{
+			name:     "logged in",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(activeUser(), nil)
+				sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "unknown email",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperror.ErrInvalidCredentials,
+		},
+		{
+			name:     "wrong password",
+			password: "wrong-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(activeUser(), nil)
+			},
+			wantErr: apperror.ErrInvalidCredentials,
+		},
+		{
+			name:     "disabled account",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				user := activeUser()
+				user.DisabledAt = &disabledAt
+				users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(user, nil)
+			},
+			wantErr: apperror.ErrAccountDisabled,
+		},
+		{
+			name:     "lookup failure",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(nil, errDB)
+			},
+			wantErr: apperror.ErrInternal,
+		},
+		{
+			name:     "session insert failure",
+			password: "correct-password",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(activeUser(), nil)
+				sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, _ := newTxManager(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.setup(users, sessions)
+
+			authService := service.NewAuthService(txManager, newTokenManager(), users, sessions)
+			_, session, err := authService.Login(context.Background(), "user@example.com", tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && session.SessionToken == "" {
+				t.Fatal("Login() returned an empty token")
+			}
+		})
+	}
+}
+
+func TestAuthServiceGetUserByToken(t *testing.T) {
+	disabledAt := 1760000000
+	user := &domain.User{ID: uuid.New(), Email: "user@example.com"}
+	disabledUser := &domain.User{ID: uuid.New(), Email: "user@example.com", DisabledAt: &disabledAt}
+	session := &domain.Session{UserID: user.ID, User: *user}
+
+	tests := []struct {
+		name    string
+		setup   func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository)
+		wantErr error
+	}{
+		{
+			name: "valid session",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				sessions.EXPECT().GetByToken(gomock.Any(), "token").Return(session, nil)
+				users.EXPECT().GetByEmail(gomock.Any(), user.Email).Return(user, nil)
+			},
+		},
+		{
+			name: "unknown session",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				sessions.EXPECT().GetByToken(gomock.Any(), "token").Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperror.ErrInvalidToken,
+		},
+		{
+			name: "session lookup failure",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				sessions.EXPECT().GetByToken(gomock.Any(), "token").Return(nil, errDB)
+			},
+			wantErr: apperror.ErrInternal,
+		},
+		{
+			name: "deleted user",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				sessions.EXPECT().GetByToken(gomock.Any(), "token").Return(session, nil)
+				users.EXPECT().GetByEmail(gomock.Any(), user.Email).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperror.ErrInvalidToken,
+		},
+		{
+			name: "disabled user",
+			setup: func(users *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
+				sessions.EXPECT().GetByToken(gomock.Any(), "token").Return(session, nil)
+				users.EXPECT().GetByEmail(gomock.Any(), user.Email).Return(disabledUser, nil)
+			},
+			wantErr: apperror.ErrAccountDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, _ := newTxManager(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.setup(users, sessions)
+
+			authService := service.NewAuthService(txManager, newTokenManager(), users, sessions)
+			got, err := authService.GetUserByToken(context.Background(), "token")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetUserByToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.ID != user.ID {
+				t.Fatalf("GetUserByToken() = %s, want %s", got.ID, user.ID)
+			}
+		})
+	}
+}
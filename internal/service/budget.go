@@ -2,30 +2,34 @@ package service
 
 import (
 	"context"
+	"errors"
 	"finance-backend/internal/domain"
 	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
 
 	"gorm.io/gorm"
 )
 
 type budgetService struct {
-	db *gorm.DB
+	txManager domain.TxManager
 
 	budgetRepo domain.BudgetRepository
 }
 
-func NewBudgetService(db *gorm.DB, budgetRepo domain.BudgetRepository) domain.BudgetService {
+func NewBudgetService(txManager domain.TxManager, budgetRepo domain.BudgetRepository) domain.BudgetService {
 	return &budgetService{
-		db:         db,
+		txManager:  txManager,
 		budgetRepo: budgetRepo,
 	}
 }
 
 func (s *budgetService) Create(ctx context.Context, userId string, request *model.CreateBudgetRequest) (*domain.Budget, error) {
-	log := logger.WithRequestID(ctx)
+	ctx, span := tracing.Start(ctx, "budgetService.Create")
+	defer span.End()
 
-	tx := s.db.Begin()
+	log := logger.WithRequestID(ctx)
 
 	budget := &domain.Budget{
 		Name:     request.Name,
@@ -34,21 +38,27 @@ func (s *budgetService) Create(ctx context.Context, userId string, request *mode
 		Category: request.Category,
 	}
 
-	if err := s.budgetRepo.Create(tx, ctx, userId, budget); err != nil {
-		log.WithError(err).Error("[service - budget - Create]: Failed to create budget")
-		tx.Rollback()
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.budgetRepo.Create(ctx, userId, budget); err != nil {
+			log.WithError(err).Error("[service - budget - Create]: Failed to create budget")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	tx.Commit()
-
 	return budget, nil
 }
 
 func (s *budgetService) GetList(ctx context.Context, userId string) ([]*domain.Budget, error) {
+	ctx, span := tracing.Start(ctx, "budgetService.GetList")
+	defer span.End()
+
 	log := logger.WithRequestID(ctx)
 
-	budgets, err := s.budgetRepo.GetList(s.db, ctx, userId)
+	budgets, err := s.budgetRepo.GetList(ctx, userId)
 	if err != nil {
 		log.WithError(err).Error("[service - budget - GetList]: Failed to get budget list")
 		return nil, err
@@ -56,3 +66,62 @@ func (s *budgetService) GetList(ctx context.Context, userId string) ([]*domain.B
 
 	return budgets, nil
 }
+
+func (s *budgetService) GetDetail(ctx context.Context, userId string, budgetId string) (*domain.Budget, error) {
+	ctx, span := tracing.Start(ctx, "budgetService.GetDetail")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	budget, err := s.budgetRepo.GetDetail(ctx, userId, budgetId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("budget not found")
+		}
+		log.WithError(err).Error("[service - budget - GetDetail]: Failed to get budget")
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+func (s *budgetService) Update(ctx context.Context, userId string, budgetId string, version int, request *model.UpdateBudgetRequest) (*domain.Budget, error) {
+	ctx, span := tracing.Start(ctx, "budgetService.Update")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	var budget *domain.Budget
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.GetDetail(ctx, userId, budgetId)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, current.Version); err != nil {
+			return err
+		}
+
+		current.Name = request.Name
+		current.Amount = request.Amount
+		current.Type = request.Type
+		current.Category = request.Category
+
+		saved, err := s.budgetRepo.Update(ctx, current, current.Version)
+		if err != nil {
+			log.WithError(err).Error("[service - budget - Update]: Failed to update budget")
+			return err
+		}
+		if !saved {
+			return apperror.ErrPreconditionFailed
+		}
+
+		budget, err = s.GetDetail(ctx, userId, budgetId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
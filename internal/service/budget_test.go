@@ -0,0 +1,141 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBudgetServiceCreate(t *testing.T) {
+	userId := uuid.NewString()
+	request := &model.CreateBudgetRequest{Name: "Food", Amount: 500, Type: "monthly", Category: "food"}
+
+	tests := []struct {
+		name          string
+		createErr     error
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{name: "created", wantCommits: 1},
+		{name: "repository failure rolls back", createErr: errDB, wantErr: errDB, wantRollbacks: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			budgetRepo := mocks.NewMockBudgetRepository(ctrl)
+
+			budgetRepo.EXPECT().Create(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, budget *domain.Budget) error {
+					if budget.Name != request.Name || budget.Amount != request.Amount || budget.Category != request.Category {
+						t.Errorf("unexpected budget %+v", budget)
+					}
+					return tt.createErr
+				},
+			)
+
+			budget, err := service.NewBudgetService(txManager, budgetRepo).Create(context.Background(), userId, request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && budget == nil {
+				t.Fatal("Create() returned no budget")
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
+
+func TestBudgetServiceGetList(t *testing.T) {
+	userId := uuid.NewString()
+
+	tests := []struct {
+		name      string
+		budgets   []*domain.Budget
+		listErr   error
+		wantCount int
+	}{
+		{name: "budgets", budgets: []*domain.Budget{{Name: "A"}, {Name: "B"}}, wantCount: 2},
+		{name: "repository failure", listErr: errDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, _ := newTxManager(ctrl)
+			budgetRepo := mocks.NewMockBudgetRepository(ctrl)
+
+			budgetRepo.EXPECT().GetList(gomock.Any(), userId).Return(tt.budgets, tt.listErr)
+
+			budgets, err := service.NewBudgetService(txManager, budgetRepo).GetList(context.Background(), userId)
+			if !errors.Is(err, tt.listErr) {
+				t.Fatalf("GetList() error = %v, want %v", err, tt.listErr)
+			}
+			if len(budgets) != tt.wantCount {
+				t.Fatalf("GetList() returned %d budgets, want %d", len(budgets), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestBudgetServiceUpdate(t *testing.T) {
+	userId := uuid.NewString()
+	budgetId := uuid.New()
+	request := &model.UpdateBudgetRequest{Name: "Groceries", Amount: 750, Type: "monthly", Category: "food"}
+
+	tests := []struct {
+		name          string
+		version       int
+		saved         bool
+		wantUpdate    bool
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{name: "replaced", version: 1, saved: true, wantUpdate: true, wantCommits: 1},
+		{name: "stale version", version: 5, wantErr: apperror.ErrPreconditionFailed, wantRollbacks: 1},
+		{name: "changed in between", version: 1, wantUpdate: true, wantErr: apperror.ErrPreconditionFailed, wantRollbacks: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			budgetRepo := mocks.NewMockBudgetRepository(ctrl)
+
+			budgetRepo.EXPECT().GetDetail(gomock.Any(), userId, budgetId.String()).Return(&domain.Budget{ID: budgetId, Name: "Food", Amount: 500, Version: 1}, nil)
+			if tt.wantUpdate {
+				budgetRepo.EXPECT().Update(gomock.Any(), gomock.Any(), 1).DoAndReturn(
+					func(_ context.Context, budget *domain.Budget, _ int) (bool, error) {
+						if budget.Name != request.Name || budget.Amount != request.Amount || budget.Category != request.Category {
+							t.Errorf("Update() budget = %+v, want the request applied", budget)
+						}
+						return tt.saved, nil
+					},
+				)
+			}
+			if tt.saved {
+				budgetRepo.EXPECT().GetDetail(gomock.Any(), userId, budgetId.String()).Return(&domain.Budget{ID: budgetId, Version: 2}, nil)
+			}
+
+			budget, err := service.NewBudgetService(txManager, budgetRepo).Update(context.Background(), userId, budgetId.String(), tt.version, request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && budget.Version != 2 {
+				t.Fatalf("Update() version = %d, want 2", budget.Version)
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/exporter"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+)
+
+const (
+	// ledgerOpeningNarration describes the entry that carries a wallet's balance from before the export
+	ledgerOpeningNarration = "Opening balance"
+	// exportTimeout is how long an export may hold its database cursor open
+	exportTimeout = 15 * time.Minute
+)
+
+type exportService struct {
+	walletRepo      domain.WalletRepository
+	transactionRepo domain.TransactionRepository
+}
+
+func NewExportService(walletRepo domain.WalletRepository, transactionRepo domain.TransactionRepository) domain.ExportService {
+	return &exportService{
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+func (s *exportService) Transactions(ctx context.Context, userId string, request *model.ExportTransactionsRequest) (*domain.Export, error) {
+	ctx, span := tracing.Start(ctx, "exportService.Transactions")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	timezone := request.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apperror.ErrBadRequest.WithMessage("unknown timezone").Wrap(err)
+	}
+
+	cursor, err := s.stream(ctx, userId, transactionFilter(&request.TransactionListRequest))
+	if err != nil {
+		log.WithError(err).Error("[service - export - Stream]: Failed to query transactions")
+		return nil, err
+	}
+
+	locale := exportLocale(request.Locale)
+
+	return &domain.Export{
+		Filename:    "transactions." + request.Format,
+		ContentType: exporter.ContentType(request.Format),
+		Write: func(w io.Writer) error {
+			defer cursor.Close()
+
+			var writer exporter.Writer
+			switch request.Format {
+			case exporter.FormatJSONL:
+				writer = exporter.NewJSONL(w)
+			case exporter.FormatXLSX:
+				xlsx, err := exporter.NewXLSX(w)
+				if err != nil {
+					return err
+				}
+				writer = xlsx
+			default:
+				writer = exporter.NewCSV(w, locale)
+			}
+
+			for cursor.Next() {
+				var row domain.TransactionExportRow
+				if err := cursor.Scan(&row); err != nil {
+					return err
+				}
+				if err := writer.Write(exportRow(&row, loc)); err != nil {
+					return err
+				}
+			}
+			if err := cursor.Err(); err != nil {
+				return err
+			}
+
+			return writer.Close()
+		},
+	}, nil
+}
+
+func (s *exportService) Ledger(ctx context.Context, userId string, request *model.ExportLedgerRequest) (*domain.Export, error) {
+	ctx, span := tracing.Start(ctx, "exportService.Ledger")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	timezone := request.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apperror.ErrBadRequest.WithMessage("unknown timezone").Wrap(err)
+	}
+
+	wallets, err := s.walletRepo.GetList(ctx, userId)
+	if err != nil {
+		log.WithError(err).Error("[service - export - GetList]: Failed to get wallets")
+		return nil, err
+	}
+
+	flows, err := s.transactionRepo.FlowByWallet(ctx, userId, request.From)
+	if err != nil {
+		log.WithError(err).Error("[service - export - FlowByWallet]: Failed to sum wallet transactions")
+		return nil, err
+	}
+
+	openings := ledgerOpenings(wallets, flows, request, loc)
+
+	filter := domain.TransactionFilter{From: request.From, To: request.To, WalletID: request.WalletID}
+	cursor, err := s.stream(ctx, userId, filter)
+	if err != nil {
+		log.WithError(err).Error("[service - export - Stream]: Failed to query transactions")
+		return nil, err
+	}
+
+	return &domain.Export{
+		Filename:    "transactions." + request.Syntax,
+		ContentType: "text/plain; charset=utf-8",
+		Write: func(w io.Writer) error {
+			defer cursor.Close()
+
+			writer := exporter.NewLedger(w, request.Syntax)
+			for _, opening := range openings {
+				if err := writer.Write(opening); err != nil {
+					return err
+				}
+			}
+
+			// The lines of a split transaction follow each other and make up a single entry
+			var entry *exporter.Entry
+			var entryID uuid.UUID
+			for cursor.Next() {
+				var row domain.TransactionExportRow
+				if err := cursor.Scan(&row); err != nil {
+					return err
+				}
+				if entry != nil && row.ID == entryID {
+					ledgerAddLine(entry, &row)
+					continue
+				}
+
+				if entry != nil {
+					if err := writer.Write(*entry); err != nil {
+						return err
+					}
+				}
+				next := ledgerEntry(&row, loc)
+				entry, entryID = &next, row.ID
+			}
+			if err := cursor.Err(); err != nil {
+				return err
+			}
+			if entry != nil {
+				if err := writer.Write(*entry); err != nil {
+					return err
+				}
+			}
+
+			return writer.Close()
+		},
+	}, nil
+}
+
+// stream opens the cursor of an export. The query runs before anything is sent so a failure still
+// gets an error response. Rows are read after the handler returns and its deadline is cancelled,
+// so the cursor gets a deadline of its own: an export that is never written, or whose client stops
+// reading, gives its connection back once it runs out.
+func (s *exportService) stream(ctx context.Context, userId string, filter domain.TransactionFilter) (domain.TransactionCursor, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportTimeout)
+
+	cursor, err := s.transactionRepo.Stream(ctx, userId, filter)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &deadlineCursor{TransactionCursor: cursor, cancel: cancel}, nil
+}
+
+// deadlineCursor releases the deadline of its query when it is closed
+type deadlineCursor struct {
+	domain.TransactionCursor
+	cancel context.CancelFunc
+}
+
+func (c *deadlineCursor) Close() error {
+	defer c.cancel()
+	return c.TransactionCursor.Close()
+}
+
+// ledgerOpenings books the balance each wallet had when the export starts against the opening
+// balances account. Without a start that is the balance before the wallet's first transaction.
+func ledgerOpenings(wallets []*domain.Wallet, flows []*domain.WalletFlowRow, request *model.ExportLedgerRequest, loc *time.Location) []exporter.Entry {
+	byWallet := make(map[uuid.UUID]*domain.WalletFlowRow, len(flows))
+	for _, flow := range flows {
+		byWallet[flow.WalletID] = flow
+	}
+
+	var openings []exporter.Entry
+	for _, wallet := range wallets {
+		if request.WalletID != "" && wallet.ID.String() != request.WalletID {
+			continue
+		}
+
+		date := request.From
+		opening := wallet.Balance
+		if flow, ok := byWallet[wallet.ID]; ok {
+			opening = roundCents(wallet.Balance - balanceEffect(wallet, constant.TransactionTypeIncome, flow.Net))
+			if date == 0 {
+				date = min(wallet.CreatedAt, flow.FirstDate)
+			}
+		} else if date == 0 {
+			date = wallet.CreatedAt
+		}
+		if opening == 0 {
+			continue
+		}
+
+		// What a wallet owes is a credit balance
+		if wallet.IsLiability() {
+			opening = -opening
+		}
+
+		openings = append(openings, exporter.Entry{
+			Date:      time.Unix(int64(date), 0).In(loc),
+			Narration: ledgerOpeningNarration,
+			Postings: []exporter.Posting{
+				{Account: ledgerWalletAccount(wallet.Name, wallet.Type), Amount: opening, Currency: wallet.Currency},
+				{Account: exporter.OpeningBalancesAccount, Amount: -opening, Currency: wallet.Currency},
+			},
+		})
+	}
+
+	sort.SliceStable(openings, func(i, j int) bool {
+		return openings[i].Date.Before(openings[j].Date)
+	})
+
+	return openings
+}
+
+// ledgerEntry balances a transaction between its wallet and the income or expense account of its budget
+func ledgerEntry(row *domain.TransactionExportRow, loc *time.Location) exporter.Entry {
+	amount := row.Amount
+	if row.Type == constant.TransactionTypeExpense {
+		amount = -amount
+	}
+
+	// Transactions imported from elsewhere keep the id they were imported with
+	id := row.ExternalID
+	if id == "" {
+		id = row.ID.String()
+	}
+
+	return exporter.Entry{
+		Date:      time.Unix(int64(row.TransactionDate), 0).In(loc),
+		Narration: row.Note,
+		ID:        id,
+		Postings: []exporter.Posting{
+			{Account: ledgerCategoryAccount(row.Type, row.Category, row.BudgetName), Amount: -amount, Currency: row.Currency},
+			{Account: ledgerWalletAccount(row.WalletName, row.WalletType), Amount: amount, Currency: row.Currency},
+		},
+	}
+}
+
+// ledgerAddLine books another line of a split transaction to its entry, ahead of the wallet posting
+func ledgerAddLine(entry *exporter.Entry, row *domain.TransactionExportRow) {
+	amount := row.Amount
+	if row.Type == constant.TransactionTypeExpense {
+		amount = -amount
+	}
+
+	last := len(entry.Postings) - 1
+	entry.Postings[last].Amount = roundCents(entry.Postings[last].Amount + amount)
+	entry.Postings = slices.Insert(entry.Postings, last, exporter.Posting{
+		Account:  ledgerCategoryAccount(row.Type, row.Category, row.BudgetName),
+		Amount:   -amount,
+		Currency: row.Currency,
+	})
+}
+
+// ledgerWalletAccount names a wallet as an asset, or a liability for credit cards and loans
+func ledgerWalletAccount(name string, walletType string) string {
+	if (domain.Wallet{Type: walletType}).IsLiability() {
+		return exporter.AccountName(exporter.RootLiabilities, name)
+	}
+	return exporter.AccountName(exporter.RootAssets, name)
+}
+
+// ledgerCategoryAccount names the income or expense account of a budget, unbudgeted transactions share one
+func ledgerCategoryAccount(transactionType string, category string, budget string) string {
+	root := exporter.RootExpenses
+	if transactionType == constant.TransactionTypeIncome {
+		root = exporter.RootIncome
+	}
+	if budget == "" {
+		return exporter.AccountName(root, exporter.UncategorizedComponent)
+	}
+	return exporter.AccountName(root, category, budget)
+}
+
+// exportLocale reads a language tag or an Accept-Language header, falling back to English
+func exportLocale(raw string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(raw)
+	if err != nil || len(tags) == 0 {
+		return language.English
+	}
+	return tags[0]
+}
+
+// exportRow dates a transaction on the user's calendar and signs the amount, expenses are negative
+func exportRow(row *domain.TransactionExportRow, loc *time.Location) exporter.Row {
+	amount := row.Amount
+	if row.Type == constant.TransactionTypeExpense {
+		amount = -amount
+	}
+
+	return exporter.Row{
+		ID:       row.ID.String(),
+		Date:     time.Unix(int64(row.TransactionDate), 0).In(loc),
+		Type:     row.Type,
+		Amount:   amount,
+		Currency: row.Currency,
+		Wallet:   row.WalletName,
+		Budget:   row.BudgetName,
+		Category: row.Category,
+		Note:     exportNote(row),
+	}
+}
+
+// exportNote describes a row by the note of its split line, or the transaction's when the line has none
+func exportNote(row *domain.TransactionExportRow) string {
+	if row.SplitNote != "" {
+		return row.SplitNote
+	}
+	return row.Note
+}
@@ -0,0 +1,338 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// expectCursor makes cursor return rows and then fail with err, a nil err ends cleanly
+func expectCursor(cursor *mocks.MockTransactionCursor, rows []domain.TransactionExportRow, err error) {
+	next := 0
+	cursor.EXPECT().Next().DoAndReturn(func() bool { return next < len(rows) }).AnyTimes()
+	cursor.EXPECT().Scan(gomock.Any()).DoAndReturn(func(row *domain.TransactionExportRow) error {
+		if next == len(rows)-1 && err != nil {
+			return err
+		}
+		*row = rows[next]
+		next++
+		return nil
+	}).AnyTimes()
+	cursor.EXPECT().Err().Return(nil).AnyTimes()
+	cursor.EXPECT().Close().Return(nil)
+}
+
+func TestExportServiceTransactions(t *testing.T) {
+	userId := uuid.NewString()
+	walletId := uuid.NewString()
+
+	salary, rent := uuid.New(), uuid.New()
+	rows := []domain.TransactionExportRow{
+		// 31 July 2025 20:00 UTC is already 1 August in Jakarta
+		{ID: salary, TransactionDate: 1753992000, Type: constant.TransactionTypeIncome, Amount: 2500, Note: "Gaji", WalletName: "BCA", Currency: "IDR"},
+		{ID: rent, TransactionDate: 1754092800, Type: constant.TransactionTypeExpense, Amount: 1250.5, Note: "Sewa", WalletName: "BCA", Currency: "IDR", BudgetName: "Rumah", Category: "Housing"},
+	}
+
+	t.Run("csv in the user's locale and timezone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		transactions := mocks.NewMockTransactionRepository(ctrl)
+		cursor := mocks.NewMockTransactionCursor(ctrl)
+
+		filter := domain.TransactionFilter{From: 1753920000, WalletID: walletId}
+		transactions.EXPECT().Stream(gomock.Any(), userId, filter).Return(cursor, nil)
+		expectCursor(cursor, rows, nil)
+
+		request := &model.ExportTransactionsRequest{
+			TransactionListRequest: model.TransactionListRequest{From: 1753920000, WalletID: walletId},
+			Format:                 "csv",
+			Locale:                 "id-ID,id;q=0.9",
+			Timezone:               "Asia/Jakarta",
+		}
+		export, err := service.NewExportService(nil, transactions).Transactions(context.Background(), userId, request)
+		if err != nil {
+			t.Fatalf("Transactions() error = %v", err)
+		}
+		if export.Filename != "transactions.csv" || export.ContentType != "text/csv; charset=utf-8" {
+			t.Errorf("export = %s as %s, want transactions.csv as CSV", export.Filename, export.ContentType)
+		}
+
+		var out bytes.Buffer
+		if err := export.Write(&out); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := "Date;Type;Amount;Currency;Wallet;Budget;Category;Note;ID\n" +
+			"2025-08-01;income;2.500,00;IDR;BCA;;;Gaji;" + salary.String() + "\n" +
+			"2025-08-02;expense;-1.250,50;IDR;BCA;Rumah;Housing;Sewa;" + rent.String() + "\n"
+		if out.String() != want {
+			t.Errorf("Write() wrote\n%s\nwant\n%s", out.String(), want)
+		}
+	})
+
+	t.Run("split lines are rows of their own", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		transactions := mocks.NewMockTransactionRepository(ctrl)
+		cursor := mocks.NewMockTransactionCursor(ctrl)
+
+		transactions.EXPECT().Stream(gomock.Any(), userId, domain.TransactionFilter{}).Return(cursor, nil)
+		expectCursor(cursor, []domain.TransactionExportRow{
+			{ID: rent, TransactionDate: 1754092800, Type: constant.TransactionTypeExpense, Amount: 900, Note: "Sewa", SplitNote: "Kamar", WalletName: "BCA", Currency: "IDR", BudgetName: "Rumah", Category: "Housing"},
+			{ID: rent, TransactionDate: 1754092800, Type: constant.TransactionTypeExpense, Amount: 250.5, Note: "Sewa", WalletName: "BCA", Currency: "IDR"},
+		}, nil)
+
+		export, err := service.NewExportService(nil, transactions).Transactions(context.Background(), userId, &model.ExportTransactionsRequest{Format: "csv", Locale: "en"})
+		if err != nil {
+			t.Fatalf("Transactions() error = %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := export.Write(&out); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		// Lines without a note of their own keep the transaction's
+		want := "Date,Type,Amount,Currency,Wallet,Budget,Category,Note,ID\n" +
+			"2025-08-02,expense,-900.00,IDR,BCA,Rumah,Housing,Kamar," + rent.String() + "\n" +
+			"2025-08-02,expense,-250.50,IDR,BCA,,,Sewa," + rent.String() + "\n"
+		if out.String() != want {
+			t.Errorf("Write() wrote\n%s\nwant\n%s", out.String(), want)
+		}
+	})
+
+	t.Run("a failed row ends the export and releases the cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		transactions := mocks.NewMockTransactionRepository(ctrl)
+		cursor := mocks.NewMockTransactionCursor(ctrl)
+
+		transactions.EXPECT().Stream(gomock.Any(), userId, gomock.Any()).Return(cursor, nil)
+		expectCursor(cursor, rows, errDB)
+
+		export, err := service.NewExportService(nil, transactions).Transactions(context.Background(), userId, &model.ExportTransactionsRequest{Format: "jsonl"})
+		if err != nil {
+			t.Fatalf("Transactions() error = %v", err)
+		}
+		if err := export.Write(&bytes.Buffer{}); !errors.Is(err, errDB) {
+			t.Fatalf("Write() error = %v, want %v", err, errDB)
+		}
+	})
+
+	t.Run("the cursor outlives the request with a deadline of its own", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		transactions := mocks.NewMockTransactionRepository(ctrl)
+		cursor := mocks.NewMockTransactionCursor(ctrl)
+
+		var streamCtx context.Context
+		transactions.EXPECT().Stream(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ string, _ domain.TransactionFilter) (domain.TransactionCursor, error) {
+				streamCtx = ctx
+				return cursor, nil
+			})
+		expectCursor(cursor, rows, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		export, err := service.NewExportService(nil, transactions).Transactions(ctx, userId, &model.ExportTransactionsRequest{Format: "jsonl"})
+		if err != nil {
+			t.Fatalf("Transactions() error = %v", err)
+		}
+		cancel()
+
+		if err := streamCtx.Err(); err != nil {
+			t.Fatalf("cursor context ended with the request: %v", err)
+		}
+		if deadline, ok := streamCtx.Deadline(); !ok || time.Until(deadline) > 15*time.Minute {
+			t.Errorf("cursor deadline = %v, %v, want one within 15 minutes", deadline, ok)
+		}
+		if err := export.Write(&bytes.Buffer{}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if streamCtx.Err() == nil {
+			t.Error("cursor context still live after Write")
+		}
+	})
+
+	failures := []struct {
+		name    string
+		request *model.ExportTransactionsRequest
+		setup   func(transactions *mocks.MockTransactionRepository)
+		wantErr error
+	}{
+		{
+			name:    "unknown timezone",
+			request: &model.ExportTransactionsRequest{Format: "csv", Timezone: "Mars/Olympus"},
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "query failure",
+			request: &model.ExportTransactionsRequest{Format: "xlsx"},
+			setup: func(transactions *mocks.MockTransactionRepository) {
+				transactions.EXPECT().Stream(gomock.Any(), userId, gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			transactions := mocks.NewMockTransactionRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(transactions)
+			}
+
+			_, err := service.NewExportService(nil, transactions).Transactions(context.Background(), userId, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transactions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExportServiceLedger(t *testing.T) {
+	userId := uuid.NewString()
+
+	bca := &domain.Wallet{ID: uuid.New(), Name: "BCA", Type: constant.WalletTypePersonal, Currency: "IDR", Balance: 1100, CreatedAt: 1753920000}
+	visa := &domain.Wallet{ID: uuid.New(), Name: "Visa card", Type: constant.WalletTypeCredit, Currency: "IDR", Balance: 150, CreatedAt: 1753920000}
+	flows := []*domain.WalletFlowRow{
+		{WalletID: bca.ID, Net: 100, FirstDate: 1754006400},
+		{WalletID: visa.ID, Net: -150, FirstDate: 1754092800},
+	}
+
+	salary, market := uuid.New(), uuid.New()
+	rows := []domain.TransactionExportRow{
+		{ID: salary, ExternalID: "pay-1", TransactionDate: 1754006400, Type: constant.TransactionTypeIncome, Amount: 100, Note: "Gaji", WalletID: bca.ID, WalletName: "BCA", WalletType: bca.Type, Currency: "IDR"},
+		{ID: market, TransactionDate: 1754092800, Type: constant.TransactionTypeExpense, Amount: 150, Note: "Pasar", WalletID: visa.ID, WalletName: "Visa card", WalletType: visa.Type, Currency: "IDR", BudgetName: "Groceries", Category: "food"},
+	}
+
+	t.Run("beancount with opening balances", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		wallets := mocks.NewMockWalletRepository(ctrl)
+		transactions := mocks.NewMockTransactionRepository(ctrl)
+		cursor := mocks.NewMockTransactionCursor(ctrl)
+
+		wallets.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.Wallet{bca, visa}, nil)
+		transactions.EXPECT().FlowByWallet(gomock.Any(), userId, 0).Return(flows, nil)
+		transactions.EXPECT().Stream(gomock.Any(), userId, domain.TransactionFilter{}).Return(cursor, nil)
+		expectCursor(cursor, rows, nil)
+
+		export, err := service.NewExportService(wallets, transactions).Ledger(context.Background(), userId, &model.ExportLedgerRequest{Syntax: "beancount"})
+		if err != nil {
+			t.Fatalf("Ledger() error = %v", err)
+		}
+		if export.Filename != "transactions.beancount" {
+			t.Errorf("filename = %s, want transactions.beancount", export.Filename)
+		}
+
+		var out bytes.Buffer
+		if err := export.Write(&out); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		// The card owed nothing before its first transaction, so only BCA opens with a balance
+		want := "2025-07-31 open Assets:BCA IDR\n" +
+			"2025-07-31 open Equity:Opening-Balances IDR\n\n" +
+			"2025-07-31 * \"Opening balance\"\n" +
+			"    Assets:BCA                    1000.00 IDR\n" +
+			"    Equity:Opening-Balances      -1000.00 IDR\n\n" +
+			"2025-08-01 open Income:Uncategorized IDR\n\n" +
+			"2025-08-01 * \"Gaji\"\n" +
+			"    id: \"pay-1\"\n" +
+			"    Income:Uncategorized       -100.00 IDR\n" +
+			"    Assets:BCA                  100.00 IDR\n\n" +
+			"2025-08-02 open Expenses:Food:Groceries IDR\n" +
+			"2025-08-02 open Liabilities:Visa-card IDR\n\n" +
+			"2025-08-02 * \"Pasar\"\n" +
+			"    id: \"" + market.String() + "\"\n" +
+			"    Expenses:Food:Groceries        150.00 IDR\n" +
+			"    Liabilities:Visa-card         -150.00 IDR\n\n"
+		if out.String() != want {
+			t.Errorf("Write() wrote\n%s\nwant\n%s", out.String(), want)
+		}
+	})
+
+	t.Run("a period opens at its start with the balance carried in", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		wallets := mocks.NewMockWalletRepository(ctrl)
+		transactions := mocks.NewMockTransactionRepository(ctrl)
+		cursor := mocks.NewMockTransactionCursor(ctrl)
+
+		from := 1754092800
+		wallets.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.Wallet{bca, visa}, nil)
+		transactions.EXPECT().FlowByWallet(gomock.Any(), userId, from).Return(flows[1:], nil)
+		transactions.EXPECT().Stream(gomock.Any(), userId, domain.TransactionFilter{From: from, WalletID: bca.ID.String()}).Return(cursor, nil)
+		expectCursor(cursor, nil, nil)
+
+		request := &model.ExportLedgerRequest{From: from, WalletID: bca.ID.String(), Syntax: "ledger"}
+		export, err := service.NewExportService(wallets, transactions).Ledger(context.Background(), userId, request)
+		if err != nil {
+			t.Fatalf("Ledger() error = %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := export.Write(&out); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := "account Assets:BCA\n" +
+			"account Equity:Opening-Balances\n\n" +
+			"2025-08-02 * Opening balance\n" +
+			"    Assets:BCA                    1100.00 IDR\n" +
+			"    Equity:Opening-Balances      -1100.00 IDR\n\n"
+		if out.String() != want {
+			t.Errorf("Write() wrote\n%s\nwant\n%s", out.String(), want)
+		}
+	})
+
+	t.Run("the lines of a split transaction make up one entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		wallets := mocks.NewMockWalletRepository(ctrl)
+		transactions := mocks.NewMockTransactionRepository(ctrl)
+		cursor := mocks.NewMockTransactionCursor(ctrl)
+
+		wallets.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.Wallet{visa}, nil)
+		transactions.EXPECT().FlowByWallet(gomock.Any(), userId, 0).Return(flows[1:], nil)
+		transactions.EXPECT().Stream(gomock.Any(), userId, domain.TransactionFilter{}).Return(cursor, nil)
+		expectCursor(cursor, []domain.TransactionExportRow{
+			{ID: market, TransactionDate: 1754092800, Type: constant.TransactionTypeExpense, Amount: 100, Note: "Pasar", SplitNote: "Sayur", WalletID: visa.ID, WalletName: "Visa card", WalletType: visa.Type, Currency: "IDR", BudgetName: "Groceries", Category: "food"},
+			{ID: market, TransactionDate: 1754092800, Type: constant.TransactionTypeExpense, Amount: 50, Note: "Pasar", SplitNote: "Sabun", WalletID: visa.ID, WalletName: "Visa card", WalletType: visa.Type, Currency: "IDR"},
+		}, nil)
+
+		export, err := service.NewExportService(wallets, transactions).Ledger(context.Background(), userId, &model.ExportLedgerRequest{Syntax: "ledger"})
+		if err != nil {
+			t.Fatalf("Ledger() error = %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := export.Write(&out); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := "account Expenses:Food:Groceries\n" +
+			"account Expenses:Uncategorized\n" +
+			"account Liabilities:Visa-card\n\n" +
+			"2025-08-02 * Pasar\n" +
+			"    ; id: " + market.String() + "\n" +
+			"    Expenses:Food:Groceries        100.00 IDR\n" +
+			"    Expenses:Uncategorized          50.00 IDR\n" +
+			"    Liabilities:Visa-card         -150.00 IDR\n\n"
+		if out.String() != want {
+			t.Errorf("Write() wrote\n%s\nwant\n%s", out.String(), want)
+		}
+	})
+
+	t.Run("wallet failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		wallets := mocks.NewMockWalletRepository(ctrl)
+		wallets.EXPECT().GetList(gomock.Any(), userId).Return(nil, errDB)
+
+		_, err := service.NewExportService(wallets, nil).Ledger(context.Background(), userId, &model.ExportLedgerRequest{Syntax: "ledger"})
+		if !errors.Is(err, errDB) {
+			t.Fatalf("Ledger() error = %v, want %v", err, errDB)
+		}
+	})
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// forecastLookbackDays is how much history recurring items and seasonal averages are learned from
+	forecastLookbackDays = 180
+	// forecastConfidence is the coverage of the bands, forecastZ the matching normal quantile
+	forecastConfidence = 0.8
+	forecastZ          = 1.2816
+
+	// minRecurringOccurrences is how many times a note must repeat before it counts as a series
+	minRecurringOccurrences = 3
+	// recurringAmountTolerance is how far an occurrence may stray from the series' typical amount
+	recurringAmountTolerance = 0.25
+)
+
+// recurringInterval describes a cadence and the gaps in days accepted between occurrences
+type recurringInterval struct {
+	name           string
+	months, days   int
+	minGap, maxGap float64
+}
+
+var recurringIntervals = []recurringInterval{
+	{name: constant.ForecastIntervalWeekly, days: 7, minGap: 6, maxGap: 8},
+	{name: constant.ForecastIntervalBiweekly, days: 14, minGap: 12, maxGap: 16},
+	{name: constant.ForecastIntervalMonthly, months: 1, minGap: 26, maxGap: 34},
+}
+
+// recurringSeries is a detected series, its effect is signed by how it moves the wallet balance
+type recurringSeries struct {
+	item     model.RecurringItem
+	interval recurringInterval
+	last     time.Time
+	effect   float64
+	variance float64
+}
+
+// occurrence returns the k-th expected date after the last seen one
+func (r *recurringSeries) occurrence(k int) time.Time {
+	return r.last.AddDate(0, k*r.interval.months, k*r.interval.days)
+}
+
+// dailyProfile is the mean and variance of a wallet's non-recurring flow for each weekday
+type dailyProfile struct {
+	mean     [7]float64
+	variance [7]float64
+}
+
+func (s *reportService) Forecast(ctx context.Context, userId string, request *model.ForecastRequest) (*model.ForecastReport, error) {
+	ctx, span := tracing.Start(ctx, "reportService.Forecast")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	horizon := request.Horizon
+	if horizon == 0 {
+		horizon = constant.ForecastDefaultHorizon
+	}
+
+	wallets, err := s.walletRepo.GetList(ctx, userId)
+	if err != nil {
+		log.WithError(err).Error("[service - report - Forecast]: Failed to get wallets")
+		return nil, err
+	}
+
+	if request.WalletID != "" {
+		var selected []*domain.Wallet
+		for _, wallet := range wallets {
+			if wallet.ID.String() == request.WalletID {
+				selected = append(selected, wallet)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, apperror.ErrNotFound.WithMessage("wallet not found")
+		}
+		wallets = selected
+	}
+
+	now := time.Now()
+	today := utcDay(now)
+
+	rows, err := s.reportRepo.FlowHistory(ctx, userId, domain.FlowFilter{
+		From:     int(today.AddDate(0, 0, -forecastLookbackDays).Unix()),
+		To:       int(today.AddDate(0, 0, 1).Unix()),
+		WalletID: request.WalletID,
+	})
+	if err != nil {
+		log.WithError(err).Error("[service - report - Forecast]: Failed to get transaction history")
+		return nil, err
+	}
+
+	history := make(map[uuid.UUID][]*domain.FlowRow)
+	for _, row := range rows {
+		history[row.WalletID] = append(history[row.WalletID], row)
+	}
+
+	report := &model.ForecastReport{
+		GeneratedAt: int(now.Unix()),
+		Horizon:     horizon,
+		Confidence:  forecastConfidence,
+		Wallets:     make([]model.WalletForecast, 0, len(wallets)),
+	}
+
+	for _, wallet := range wallets {
+		report.Wallets = append(report.Wallets, forecastWallet(wallet, history[wallet.ID], today, horizon))
+	}
+
+	return report, nil
+}
+
+// forecastWallet projects a wallet's balance for horizon days after today from its history
+func forecastWallet(wallet *domain.Wallet, rows []*domain.FlowRow, today time.Time, horizon int) model.WalletForecast {
+	// Recurring items are whole transactions, while the lines of split ones count towards their own categories
+	whole, owners := wholeTransactions(rows)
+	series, recurring := detectRecurring(wallet, whole, today)
+
+	// Seasonal averages are learned from whole days the wallet existed, excluding recurring items
+	start := today.AddDate(0, 0, -forecastLookbackDays)
+	if created := utcDay(time.Unix(int64(wallet.CreatedAt), 0)); created.After(start) {
+		start = created
+	}
+
+	var seasonal []*domain.FlowRow
+	for _, row := range rows {
+		if !recurring[owners[row]] {
+			seasonal = append(seasonal, row)
+		}
+	}
+	profile, categories := learnProfile(wallet, seasonal, start, today)
+
+	forecast := model.WalletForecast{
+		WalletID:   wallet.ID.String(),
+		Name:       wallet.Name,
+		Currency:   wallet.Currency,
+		Liability:  wallet.IsLiability(),
+		Balance:    wallet.Balance,
+		Recurring:  make([]model.RecurringItem, 0, len(series)),
+		Categories: categories,
+		Points:     make([]model.ForecastPoint, 0, horizon+1),
+	}
+
+	next := make([]int, len(series))
+	for i, r := range series {
+		// Skip occurrences that were due by today but have not shown up, they are not projected
+		k := 1
+		for !r.occurrence(k).After(today) {
+			k++
+		}
+		next[i] = k
+		r.item.NextDate = int(r.occurrence(k).Unix())
+		forecast.Recurring = append(forecast.Recurring, r.item)
+	}
+
+	balance, variance := wallet.Balance, 0.0
+	for d := 0; d <= horizon; d++ {
+		day := today.AddDate(0, 0, d)
+
+		if d > 0 {
+			weekday := day.Weekday()
+			balance += profile.mean[weekday]
+			variance += profile.variance[weekday]
+
+			for i, r := range series {
+				for !r.occurrence(next[i]).After(day) {
+					balance += r.effect
+					variance += r.variance
+					next[i]++
+				}
+			}
+		}
+
+		band := forecastZ * math.Sqrt(variance)
+		point := model.ForecastPoint{
+			Date:    int(day.Unix()),
+			Label:   day.Format(time.DateOnly),
+			Balance: roundCents(balance),
+			Lower:   roundCents(balance - band),
+			Upper:   roundCents(balance + band),
+		}
+
+		// A liability below zero is an overpayment, only asset wallets can run out of money
+		if !wallet.IsLiability() {
+			point.Negative = point.Balance < 0
+			point.AtRisk = point.Lower < 0
+			if point.Negative && forecast.FirstNegativeDate == nil {
+				date := point.Date
+				forecast.FirstNegativeDate = &date
+			}
+		}
+
+		forecast.Points = append(forecast.Points, point)
+	}
+
+	return forecast
+}
+
+// detectRecurring finds notes that repeat on a steady cadence with a steady amount and are still active.
+// It also returns the rows that belong to a series so they are not counted again in the seasonal averages.
+func detectRecurring(wallet *domain.Wallet, rows []*domain.FlowRow, today time.Time) ([]*recurringSeries, map[*domain.FlowRow]bool) {
+	groups := make(map[string][]*domain.FlowRow)
+	var keys []string
+	for _, row := range rows {
+		note := strings.ToLower(strings.TrimSpace(row.Note))
+		if note == "" {
+			continue
+		}
+		key := row.Type + "|" + note
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	var series []*recurringSeries
+	members := make(map[*domain.FlowRow]bool)
+
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < minRecurringOccurrences {
+			continue
+		}
+
+		gaps := make([]float64, 0, len(group)-1)
+		amounts := make([]float64, 0, len(group))
+		for i, row := range group {
+			amounts = append(amounts, row.Amount)
+			if i > 0 {
+				gaps = append(gaps, float64(row.TransactionDate-group[i-1].TransactionDate)/86400)
+			}
+		}
+
+		interval, ok := matchInterval(gaps)
+		if !ok {
+			continue
+		}
+
+		typical := median(amounts)
+		steady := true
+		for _, amount := range amounts {
+			if math.Abs(amount-typical) > typical*recurringAmountTolerance {
+				steady = false
+				break
+			}
+		}
+		if !steady {
+			continue
+		}
+
+		last := group[len(group)-1]
+		r := &recurringSeries{
+			item: model.RecurringItem{
+				Note:     strings.TrimSpace(last.Note),
+				Type:     last.Type,
+				Amount:   roundCents(typical),
+				Interval: interval.name,
+			},
+			interval: interval,
+			last:     utcDay(time.Unix(int64(last.TransactionDate), 0)),
+			effect:   balanceEffect(wallet, last.Type, typical),
+			variance: populationVariance(amounts),
+		}
+
+		// A series that missed its last expected date by more than the cadence allows has stopped
+		if r.last.AddDate(0, interval.months, interval.days+int(interval.maxGap-interval.minGap)).Before(today) {
+			continue
+		}
+
+		series = append(series, r)
+		for _, row := range group {
+			members[row] = true
+		}
+	}
+
+	return series, members
+}
+
+// wholeTransactions merges the lines of each split transaction back into one row,
+// owners maps every row to the row of its whole transaction
+func wholeTransactions(rows []*domain.FlowRow) ([]*domain.FlowRow, map[*domain.FlowRow]*domain.FlowRow) {
+	whole := make([]*domain.FlowRow, 0, len(rows))
+	owners := make(map[*domain.FlowRow]*domain.FlowRow, len(rows))
+
+	for _, row := range rows {
+		if row.TransactionID != nil && len(whole) > 0 {
+			last := whole[len(whole)-1]
+			if last.TransactionID != nil && *last.TransactionID == *row.TransactionID {
+				last.Amount = roundCents(last.Amount + row.Amount)
+				owners[row] = last
+				continue
+			}
+		}
+
+		owner := row
+		if row.TransactionID != nil {
+			merged := *row
+			merged.Category = ""
+			owner = &merged
+		}
+		whole = append(whole, owner)
+		owners[row] = owner
+	}
+
+	return whole, owners
+}
+
+// matchInterval returns the cadence every gap fits into
+func matchInterval(gaps []float64) (recurringInterval, bool) {
+	for _, interval := range recurringIntervals {
+		fits := true
+		for _, gap := range gaps {
+			if gap < interval.minGap || gap > interval.maxGap {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return interval, true
+		}
+	}
+	return recurringInterval{}, false
+}
+
+// learnProfile averages the daily flow of each category per weekday over the whole days in [start, today)
+func learnProfile(wallet *domain.Wallet, rows []*domain.FlowRow, start, today time.Time) (dailyProfile, []model.CategoryAverage) {
+	var profile dailyProfile
+	categories := []model.CategoryAverage{}
+
+	days := int(today.Sub(start).Hours() / 24)
+	if days <= 0 {
+		return profile, categories
+	}
+
+	// flows[category][day index] is the net effect of that category on one day
+	flows := make(map[string][]float64)
+	for _, row := range rows {
+		index := int(utcDay(time.Unix(int64(row.TransactionDate), 0)).Sub(start).Hours() / 24)
+		if index < 0 || index >= days {
+			continue
+		}
+
+		category := row.Category
+		if category == "" {
+			category = constant.ReportCategoryUncategorized
+		}
+		if flows[category] == nil {
+			flows[category] = make([]float64, days)
+		}
+		flows[category][index] += balanceEffect(wallet, row.Type, row.Amount)
+	}
+
+	for category, daily := range flows {
+		var byWeekday [7][]float64
+		total := 0.0
+		for index, flow := range daily {
+			weekday := start.AddDate(0, 0, index).Weekday()
+			byWeekday[weekday] = append(byWeekday[weekday], flow)
+			total += flow
+		}
+
+		// Categories are assumed independent, so their variances add up
+		for weekday, values := range byWeekday {
+			if len(values) == 0 {
+				continue
+			}
+			profile.mean[weekday] += mean(values)
+			profile.variance[weekday] += populationVariance(values)
+		}
+
+		categories = append(categories, model.CategoryAverage{
+			Category:     category,
+			DailyAverage: roundCents(total / float64(days)),
+		})
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := math.Abs(categories[i].DailyAverage), math.Abs(categories[j].DailyAverage)
+		if a != b {
+			return a > b
+		}
+		return categories[i].Category < categories[j].Category
+	})
+
+	return profile, categories
+}
+
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+func populationVariance(values []float64) float64 {
+	m := mean(values)
+	total := 0.0
+	for _, value := range values {
+		total += (value - m) * (value - m)
+	}
+	return total / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
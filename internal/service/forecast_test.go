@@ -0,0 +1,258 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// forecastToday is the UTC day the forecast starts from
+func forecastToday() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+func newForecastService(t *testing.T, wallets []*domain.Wallet, rows []*domain.FlowRow) domain.ReportService {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	reportRepo := mocks.NewMockReportRepository(ctrl)
+	walletRepo := mocks.NewMockWalletRepository(ctrl)
+
+	walletRepo.EXPECT().GetList(gomock.Any(), "user").Return(wallets, nil)
+	reportRepo.EXPECT().FlowHistory(gomock.Any(), "user", gomock.Any()).Return(rows, nil)
+
+	return service.NewReportService(reportRepo, walletRepo, mocks.NewMockSnapshotRepository(ctrl), mocks.NewMockExchangeRateRepository(ctrl), "USD")
+}
+
+// dailyExpenses books amount(i) on each of the days days before today, without a note
+func dailyExpenses(walletId uuid.UUID, days int, amount func(i int) float64) []*domain.FlowRow {
+	today := forecastToday()
+
+	var rows []*domain.FlowRow
+	for i := days; i >= 1; i-- {
+		rows = append(rows, &domain.FlowRow{
+			WalletID:        walletId,
+			Type:            constant.TransactionTypeExpense,
+			Amount:          amount(i),
+			TransactionDate: int(today.AddDate(0, 0, -i).Add(12 * time.Hour).Unix()),
+		})
+	}
+	return rows
+}
+
+func TestReportServiceForecast(t *testing.T) {
+	today := forecastToday()
+	wallet := &domain.Wallet{ID: uuid.New(), Name: "Checking", Type: constant.WalletTypePersonal, Currency: "USD", Balance: 100, CreatedAt: int(today.AddDate(0, 0, -60).Unix())}
+
+	rows := dailyExpenses(wallet.ID, 60, func(int) float64 { return 10 })
+	payday := today.AddDate(0, 0, -10)
+	for k := 3; k >= 0; k-- {
+		rows = append(rows, &domain.FlowRow{
+			WalletID:        wallet.ID,
+			Type:            constant.TransactionTypeIncome,
+			Amount:          3000,
+			Note:            "Salary",
+			TransactionDate: int(payday.AddDate(0, -k, 0).Unix()),
+		})
+	}
+
+	report, err := newForecastService(t, []*domain.Wallet{wallet}, rows).Forecast(context.Background(), "user", &model.ForecastRequest{})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+
+	if report.Horizon != 30 || len(report.Wallets) != 1 {
+		t.Fatalf("report = %+v, want one wallet over the default 30 days", report)
+	}
+	forecast := report.Wallets[0]
+
+	if len(forecast.Recurring) != 1 || forecast.Recurring[0].Interval != constant.ForecastIntervalMonthly || forecast.Recurring[0].Amount != 3000 {
+		t.Fatalf("recurring = %+v, want the monthly salary", forecast.Recurring)
+	}
+	nextPayday := payday.AddDate(0, 1, 0)
+	if forecast.Recurring[0].NextDate != int(nextPayday.Unix()) {
+		t.Errorf("next salary on %d, want %d", forecast.Recurring[0].NextDate, nextPayday.Unix())
+	}
+
+	if len(forecast.Categories) != 1 || forecast.Categories[0].Category != constant.ReportCategoryUncategorized || forecast.Categories[0].DailyAverage != -10 {
+		t.Errorf("categories = %+v, want -10 a day uncategorized", forecast.Categories)
+	}
+
+	if len(forecast.Points) != 31 {
+		t.Fatalf("returned %d points, want today plus 30 days", len(forecast.Points))
+	}
+	paydayIndex := int(nextPayday.Sub(today).Hours() / 24)
+	for d, point := range forecast.Points {
+		want := 100 - 10*float64(d)
+		if d >= paydayIndex {
+			want += 3000
+		}
+		if point.Balance != want || point.Lower != want || point.Upper != want {
+			t.Fatalf("day %d = %+v, want a certain balance of %v", d, point, want)
+		}
+		if point.Negative != (want < 0) {
+			t.Errorf("day %d negative = %t, want %t", d, point.Negative, want < 0)
+		}
+	}
+
+	wantNegative := int(today.AddDate(0, 0, 11).Unix())
+	if forecast.FirstNegativeDate == nil || *forecast.FirstNegativeDate != wantNegative {
+		t.Errorf("first negative date = %v, want %d", forecast.FirstNegativeDate, wantNegative)
+	}
+}
+
+func TestReportServiceForecastSplitLines(t *testing.T) {
+	today := forecastToday()
+	wallet := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Balance: 5000, CreatedAt: int(today.AddDate(0, 0, -60).Unix())}
+
+	rows := dailyExpenses(wallet.ID, 60, func(int) float64 { return 10 })
+	rentDay := today.AddDate(0, 0, -5)
+	for k := 3; k >= 0; k-- {
+		// Each month's rent is one transaction split into two lines
+		id := uuid.New()
+		date := int(rentDay.AddDate(0, -k, 0).Unix())
+		rows = append(rows,
+			&domain.FlowRow{WalletID: wallet.ID, Type: constant.TransactionTypeExpense, Amount: 800, Note: "Rent", Category: "housing", TransactionDate: date, TransactionID: &id},
+			&domain.FlowRow{WalletID: wallet.ID, Type: constant.TransactionTypeExpense, Amount: 200, Note: "Rent", Category: "utilities", TransactionDate: date, TransactionID: &id},
+		)
+	}
+
+	report, err := newForecastService(t, []*domain.Wallet{wallet}, rows).Forecast(context.Background(), "user", &model.ForecastRequest{})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+	forecast := report.Wallets[0]
+
+	if len(forecast.Recurring) != 1 || forecast.Recurring[0].Interval != constant.ForecastIntervalMonthly || forecast.Recurring[0].Amount != 1000 {
+		t.Fatalf("recurring = %+v, want the whole monthly rent", forecast.Recurring)
+	}
+	if len(forecast.Categories) != 1 || forecast.Categories[0].Category != constant.ReportCategoryUncategorized {
+		t.Errorf("categories = %+v, want the rent lines left out as recurring", forecast.Categories)
+	}
+}
+
+func TestReportServiceForecastBands(t *testing.T) {
+	today := forecastToday()
+	wallet := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Balance: 1000, CreatedAt: int(today.AddDate(-1, 0, 0).Unix())}
+
+	// Alternating weeks of 0 and 40 make every weekday average 20 with a spread
+	rows := dailyExpenses(wallet.ID, 182, func(i int) float64 {
+		if (i/7)%2 == 0 {
+			return 40
+		}
+		return 0.01
+	})
+	// Irregular amounts under the same note are not a series
+	for k, amount := range []float64{100, 400, 100} {
+		rows = append(rows, &domain.FlowRow{WalletID: wallet.ID, Type: constant.TransactionTypeExpense, Amount: amount, Note: "Repairs", TransactionDate: int(today.AddDate(0, -3+k, 0).Unix())})
+	}
+
+	report, err := newForecastService(t, []*domain.Wallet{wallet}, rows).Forecast(context.Background(), "user", &model.ForecastRequest{Horizon: 60})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+
+	forecast := report.Wallets[0]
+	if len(forecast.Recurring) != 0 {
+		t.Fatalf("recurring = %+v, want none", forecast.Recurring)
+	}
+	if len(forecast.Points) != 61 {
+		t.Fatalf("returned %d points, want 61", len(forecast.Points))
+	}
+
+	previousWidth := 0.0
+	for d, point := range forecast.Points {
+		width := point.Upper - point.Lower
+		if point.Lower > point.Balance || point.Upper < point.Balance {
+			t.Fatalf("day %d balance %v outside its band [%v, %v]", d, point.Balance, point.Lower, point.Upper)
+		}
+		if d > 0 && width <= previousWidth {
+			t.Fatalf("day %d band %v did not widen from %v", d, width, previousWidth)
+		}
+		previousWidth = width
+	}
+}
+
+func TestReportServiceForecastLiability(t *testing.T) {
+	today := forecastToday()
+	card := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypeCredit, Balance: 50, CreatedAt: int(today.AddDate(0, 0, -30).Unix())}
+
+	// Repayments bring what is owed below zero, which is not a shortfall
+	var rows []*domain.FlowRow
+	for i := 30; i >= 1; i-- {
+		rows = append(rows, &domain.FlowRow{WalletID: card.ID, Type: constant.TransactionTypeIncome, Amount: 20, TransactionDate: int(today.AddDate(0, 0, -i).Unix())})
+	}
+
+	report, err := newForecastService(t, []*domain.Wallet{card}, rows).Forecast(context.Background(), "user", &model.ForecastRequest{})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+
+	forecast := report.Wallets[0]
+	last := forecast.Points[len(forecast.Points)-1]
+	if !forecast.Liability || last.Balance != 50-20*30 || last.Negative || forecast.FirstNegativeDate != nil {
+		t.Fatalf("liability forecast = %+v, last point %+v", forecast, last)
+	}
+}
+
+func TestReportServiceForecastErrors(t *testing.T) {
+	wallet := &domain.Wallet{ID: uuid.New()}
+
+	tests := []struct {
+		name    string
+		request *model.ForecastRequest
+		setup   func(reportRepo *mocks.MockReportRepository, walletRepo *mocks.MockWalletRepository)
+		wantErr error
+	}{
+		{
+			name:    "wallet failure",
+			request: &model.ForecastRequest{},
+			setup: func(reportRepo *mocks.MockReportRepository, walletRepo *mocks.MockWalletRepository) {
+				walletRepo.EXPECT().GetList(gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:    "unknown wallet",
+			request: &model.ForecastRequest{WalletID: uuid.NewString()},
+			setup: func(reportRepo *mocks.MockReportRepository, walletRepo *mocks.MockWalletRepository) {
+				walletRepo.EXPECT().GetList(gomock.Any(), gomock.Any()).Return([]*domain.Wallet{wallet}, nil)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:    "history failure",
+			request: &model.ForecastRequest{WalletID: wallet.ID.String()},
+			setup: func(reportRepo *mocks.MockReportRepository, walletRepo *mocks.MockWalletRepository) {
+				walletRepo.EXPECT().GetList(gomock.Any(), gomock.Any()).Return([]*domain.Wallet{wallet}, nil)
+				reportRepo.EXPECT().FlowHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reportRepo := mocks.NewMockReportRepository(ctrl)
+			walletRepo := mocks.NewMockWalletRepository(ctrl)
+			tt.setup(reportRepo, walletRepo)
+
+			reportService := service.NewReportService(reportRepo, walletRepo, mocks.NewMockSnapshotRepository(ctrl), mocks.NewMockExchangeRateRepository(ctrl), "USD")
+			_, err := reportService.Forecast(context.Background(), "user", tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Forecast() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
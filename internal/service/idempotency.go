@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type idempotencyService struct {
+	idempotencyRepo domain.IdempotencyRepository
+	// ttl is how long a key is remembered after its first use
+	ttl time.Duration
+	// lockTimeout is how long a key can stay pending before a retry takes it over
+	lockTimeout time.Duration
+}
+
+func NewIdempotencyService(idempotencyRepo domain.IdempotencyRepository, ttl time.Duration, lockTimeout time.Duration) domain.IdempotencyService {
+	return &idempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+		lockTimeout:     lockTimeout,
+	}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, userId string, key string, fingerprint string) (*domain.IdempotencyKey, error) {
+	ctx, span := tracing.Start(ctx, "idempotencyService.Begin")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	now := time.Now()
+	reserved, err := s.idempotencyRepo.Reserve(ctx, &domain.IdempotencyKey{
+		UserID:      uuid.MustParse(userId),
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   int(now.Add(s.ttl).Unix()),
+	}, int(now.Add(-s.lockTimeout).Unix()))
+	if err != nil {
+		log.WithError(err).Error("[service - idempotency - Reserve]: Failed to reserve idempotency key")
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	stored, err := s.idempotencyRepo.GetDetail(ctx, userId, key)
+	if err != nil {
+		// The first request failed and gave the key up in between
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrConflict.WithMessage("a request with this idempotency key was just retried, try again")
+		}
+		log.WithError(err).Error("[service - idempotency - GetDetail]: Failed to get idempotency key")
+		return nil, err
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return nil, apperror.ErrIdempotencyReused
+	}
+	if stored.Status == 0 {
+		return nil, apperror.ErrConflict.WithMessage("a request with this idempotency key is still in progress")
+	}
+
+	return stored, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, userId string, key string, response *domain.IdempotencyKey) error {
+	ctx, span := tracing.Start(ctx, "idempotencyService.Complete")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	err := s.idempotencyRepo.Complete(ctx, &domain.IdempotencyKey{
+		UserID:      uuid.MustParse(userId),
+		Key:         key,
+		Status:      response.Status,
+		ContentType: response.ContentType,
+		ETag:        response.ETag,
+		Location:    response.Location,
+		Body:        response.Body,
+	})
+	if err != nil {
+		log.WithError(err).Error("[service - idempotency - Complete]: Failed to store idempotent response")
+		return err
+	}
+
+	return nil
+}
+
+func (s *idempotencyService) Release(ctx context.Context, userId string, key string) error {
+	ctx, span := tracing.Start(ctx, "idempotencyService.Release")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	if err := s.idempotencyRepo.Delete(ctx, userId, key); err != nil {
+		log.WithError(err).Error("[service - idempotency - Delete]: Failed to release idempotency key")
+		return err
+	}
+
+	return nil
+}
+
+func (s *idempotencyService) Purge(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "idempotencyService.Purge")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	purged, err := s.idempotencyRepo.DeleteExpired(ctx, int(now.Unix()))
+	if err != nil {
+		log.WithError(err).Error("[service - idempotency - DeleteExpired]: Failed to delete expired idempotency keys")
+		return 0, err
+	}
+
+	return purged, nil
+}
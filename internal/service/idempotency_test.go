@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestIdempotencyServiceBegin(t *testing.T) {
+	userId := uuid.NewString()
+	completed := &domain.IdempotencyKey{Key: "k1", Fingerprint: "abc", Status: 201, ContentType: "application/json", Body: []byte(`{"success":true}`)}
+
+	tests := []struct {
+		name     string
+		reserved bool
+		stored   *domain.IdempotencyKey
+		getErr   error
+		want     *domain.IdempotencyKey
+		wantErr  error
+	}{
+		{name: "first use", reserved: true},
+		{name: "replay", stored: completed, want: completed},
+		{name: "different payload", stored: &domain.IdempotencyKey{Fingerprint: "other", Status: 201}, wantErr: apperror.ErrIdempotencyReused},
+		{name: "first request still running", stored: &domain.IdempotencyKey{Fingerprint: "abc"}, wantErr: apperror.ErrConflict},
+		{name: "released in between", getErr: gorm.ErrRecordNotFound, wantErr: apperror.ErrConflict},
+		{name: "lookup failure", getErr: errDB, wantErr: errDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockIdempotencyRepository(ctrl)
+
+			before := time.Now()
+			repo.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, key *domain.IdempotencyKey, staleBefore int) (bool, error) {
+					if key.UserID.String() != userId || key.Key != "k1" || key.Fingerprint != "abc" {
+						t.Errorf("Reserve() key = %+v, want k1 of the user", key)
+					}
+					if expires := time.Unix(int64(key.ExpiresAt), 0); expires.Before(before.Add(time.Hour - time.Second)) {
+						t.Errorf("Reserve() expires at %s, want an hour from now", expires)
+					}
+					// Keys pending since more than the lock timeout ago are taken over
+					if stale := time.Unix(int64(staleBefore), 0); stale.Before(before.Add(-time.Minute-time.Second)) || stale.After(time.Now().Add(-time.Minute)) {
+						t.Errorf("Reserve() takes over keys pending since %s, want a minute ago", stale)
+					}
+					return tt.reserved, nil
+				},
+			)
+			if !tt.reserved {
+				repo.EXPECT().GetDetail(gomock.Any(), userId, "k1").Return(tt.stored, tt.getErr)
+			}
+
+			got, err := service.NewIdempotencyService(repo, time.Hour, time.Minute).Begin(context.Background(), userId, "k1", "abc")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Begin() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Begin() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("reserve failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIdempotencyRepository(ctrl)
+		repo.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, errDB)
+
+		if _, err := service.NewIdempotencyService(repo, time.Hour, time.Minute).Begin(context.Background(), userId, "k1", "abc"); !errors.Is(err, errDB) {
+			t.Fatalf("Begin() error = %v, want %v", err, errDB)
+		}
+	})
+}
+
+func TestIdempotencyServiceComplete(t *testing.T) {
+	userId := uuid.NewString()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	repo.EXPECT().Complete(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, key *domain.IdempotencyKey) error {
+			if key.UserID.String() != userId || key.Key != "k1" || key.Status != 201 || key.ContentType != "application/json" || string(key.Body) != "{}" {
+				t.Errorf("Complete() key = %+v, want the 201 response of k1", key)
+			}
+			if key.ETag != `"1"` || key.Location != "/v1/wallet/w1" {
+				t.Errorf("Complete() headers = %q, %q, want the ETag and Location of the response", key.ETag, key.Location)
+			}
+			return nil
+		},
+	)
+	repo.EXPECT().Delete(gomock.Any(), userId, "k2").Return(errDB)
+
+	idempotencyService := service.NewIdempotencyService(repo, time.Hour, time.Minute)
+	response := &domain.IdempotencyKey{Status: 201, ContentType: "application/json", ETag: `"1"`, Location: "/v1/wallet/w1", Body: []byte("{}")}
+	if err := idempotencyService.Complete(context.Background(), userId, "k1", response); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := idempotencyService.Release(context.Background(), userId, "k2"); !errors.Is(err, errDB) {
+		t.Fatalf("Release() error = %v, want %v", err, errDB)
+	}
+}
@@ -0,0 +1,658 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/importer"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// importDuplicateWindow is how far apart in seconds a row and an existing transaction may be dated
+	// and still be the same payment, banks often book a day after the purchase
+	importDuplicateWindow = 24 * 60 * 60
+	// importNoteSimilarity is the share of words two notes must have in common to match
+	importNoteSimilarity = 0.5
+)
+
+// statementAccount is the part of an import booked to one wallet, wallet is nil when the account is not mapped
+type statementAccount struct {
+	account       string
+	currency      string
+	wallet        *domain.Wallet
+	entries       []importer.Entry
+	ledgerBalance *float64
+	// remember saves the account to wallet mapping when the import is booked
+	remember bool
+}
+
+type importService struct {
+	txManager domain.TxManager
+
+	profileRepo        domain.ImportProfileRepository
+	accountRepo        domain.ImportAccountRepository
+	walletRepo         domain.WalletRepository
+	budgetRepo         domain.BudgetRepository
+	transactionRepo    domain.TransactionRepository
+	transactionService domain.TransactionService
+}
+
+func NewImportService(txManager domain.TxManager, profileRepo domain.ImportProfileRepository, accountRepo domain.ImportAccountRepository, walletRepo domain.WalletRepository, budgetRepo domain.BudgetRepository, transactionRepo domain.TransactionRepository, transactionService domain.TransactionService) domain.ImportService {
+	return &importService{
+		txManager:          txManager,
+		profileRepo:        profileRepo,
+		accountRepo:        accountRepo,
+		walletRepo:         walletRepo,
+		budgetRepo:         budgetRepo,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+	}
+}
+
+func (s *importService) CreateProfile(ctx context.Context, userId string, request *model.CreateImportProfileRequest) (*domain.ImportProfile, error) {
+	ctx, span := tracing.Start(ctx, "importService.CreateProfile")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	if request.AmountColumn != nil && (request.DebitColumn != nil || request.CreditColumn != nil) {
+		return nil, apperror.ErrBadRequest.WithMessage("use either amount_column or debit_column and credit_column")
+	}
+
+	profile := &domain.ImportProfile{
+		Name:             request.Name,
+		Delimiter:        withDefault(request.Delimiter, ","),
+		HasHeader:        request.HasHeader,
+		DateColumn:       request.DateColumn,
+		DateFormat:       withDefault(request.DateFormat, "YYYY-MM-DD"),
+		Timezone:         withDefault(request.Timezone, "UTC"),
+		AmountColumn:     request.AmountColumn,
+		DebitColumn:      request.DebitColumn,
+		CreditColumn:     request.CreditColumn,
+		NoteColumn:       request.NoteColumn,
+		AmountSign:       withDefault(request.AmountSign, constant.ImportAmountSignNegativeExpense),
+		DecimalSeparator: withDefault(request.DecimalSeparator, "."),
+	}
+
+	if _, err := importer.DateLayout(profile.DateFormat); err != nil {
+		return nil, apperror.ErrBadRequest.WithMessage(err.Error())
+	}
+	if profile.Delimiter == profile.DecimalSeparator {
+		return nil, apperror.ErrBadRequest.WithMessage("delimiter and decimal_separator must differ")
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.profileRepo.Create(ctx, userId, profile); err != nil {
+			log.WithError(err).Error("[service - import - CreateProfile]: Failed to create import profile")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *importService) GetProfiles(ctx context.Context, userId string) ([]*domain.ImportProfile, error) {
+	ctx, span := tracing.Start(ctx, "importService.GetProfiles")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	profiles, err := s.profileRepo.GetList(ctx, userId)
+	if err != nil {
+		log.WithError(err).Error("[service - import - GetProfiles]: Failed to get import profiles")
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+func (s *importService) ImportCSV(ctx context.Context, userId string, request *model.ImportCSVRequest, file io.Reader) (*model.ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "importService.ImportCSV")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	profile, err := s.profileRepo.GetDetail(ctx, userId, request.ProfileID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("import profile not found")
+		}
+		log.WithError(err).Error("[service - import - ImportCSV]: Failed to get import profile")
+		return nil, err
+	}
+
+	wallet, err := s.getWallet(ctx, userId, request.WalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := csvMapping(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := importer.ParseCSV(file, mapping, constant.ImportMaxRows)
+	if err != nil {
+		return nil, parseError("CSV", err)
+	}
+
+	return s.book(ctx, userId, []*statementAccount{{wallet: wallet, entries: entries}}, request.DryRun)
+}
+
+func (s *importService) ImportOFX(ctx context.Context, userId string, request *model.ImportStatementRequest, file io.Reader) (*model.ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "importService.ImportOFX")
+	defer span.End()
+
+	statements, err := importer.ParseOFX(file, constant.ImportMaxRows)
+	if err != nil {
+		return nil, parseError("OFX", err)
+	}
+
+	accounts, err := s.mapAccounts(ctx, userId, request, statements)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.book(ctx, userId, accounts, request.DryRun)
+}
+
+func (s *importService) ImportQIF(ctx context.Context, userId string, request *model.ImportStatementRequest, file io.Reader) (*model.ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "importService.ImportQIF")
+	defer span.End()
+
+	statements, err := importer.ParseQIF(file, withDefault(request.DateOrder, importer.QIFDateOrderMDY), constant.ImportMaxRows)
+	if err != nil {
+		return nil, parseError("QIF", err)
+	}
+
+	accounts, err := s.mapAccounts(ctx, userId, request, statements)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.book(ctx, userId, accounts, request.DryRun)
+}
+
+// mapAccounts finds the wallet each statement in the file is booked to
+func (s *importService) mapAccounts(ctx context.Context, userId string, request *model.ImportStatementRequest, statements []importer.Statement) ([]*statementAccount, error) {
+	log := logger.WithRequestID(ctx)
+
+	explicit := make(map[string]string)
+	if request.AccountMap != "" {
+		if err := json.Unmarshal([]byte(request.AccountMap), &explicit); err != nil {
+			return nil, apperror.ErrBadRequest.WithMessage("account_map must be an object from account ids to wallet ids")
+		}
+		for account, walletId := range explicit {
+			if _, err := uuid.Parse(walletId); err != nil {
+				return nil, apperror.ErrBadRequest.WithMessage(fmt.Sprintf("account_map has an invalid wallet id for account %s", account))
+			}
+		}
+	}
+
+	saved, err := s.accountRepo.GetList(ctx, userId)
+	if err != nil {
+		log.WithError(err).Error("[service - import - GetList]: Failed to get import accounts")
+		return nil, err
+	}
+	remembered := make(map[string]string, len(saved))
+	for _, account := range saved {
+		remembered[account.AccountID] = account.WalletID.String()
+	}
+
+	accounts := make([]*statementAccount, 0, len(statements))
+	for _, statement := range statements {
+		account := &statementAccount{
+			account:       statement.Account,
+			currency:      statement.Currency,
+			entries:       statement.Entries,
+			ledgerBalance: statement.LedgerBalance,
+		}
+
+		walletId := explicit[statement.Account]
+		if walletId == "" && len(statements) == 1 {
+			walletId = request.WalletID
+		}
+
+		if walletId != "" {
+			if account.wallet, err = s.getWallet(ctx, userId, walletId); err != nil {
+				return nil, err
+			}
+			// Accounts without an id cannot be recognised in the next file
+			account.remember = statement.Account != "" && remembered[statement.Account] != walletId
+		} else if walletId = remembered[statement.Account]; walletId != "" {
+			// A wallet deleted since the last import leaves the account unmapped
+			account.wallet, err = s.getWallet(ctx, userId, walletId)
+			if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+				return nil, err
+			}
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// book matches entries against the wallets' transactions and, unless this is a dry run,
+// books the new ones in a single unit of work so a failure leaves every wallet untouched
+func (s *importService) book(ctx context.Context, userId string, accounts []*statementAccount, dryRun bool) (*model.ImportResult, error) {
+	log := logger.WithRequestID(ctx)
+
+	result := &model.ImportResult{
+		DryRun:   dryRun,
+		Accounts: make([]model.ImportAccount, 0, len(accounts)),
+		Rows:     []model.ImportRow{},
+	}
+
+	// owners[i] is the account of result.Rows[i], the rows of accounts[k] start at starts[k]
+	var owners []*statementAccount
+	starts := make([]int, 0, len(accounts)+1)
+
+	for _, account := range accounts {
+		start := len(result.Rows)
+		starts = append(starts, start)
+
+		for _, entry := range account.entries {
+			row := model.ImportRow{
+				Line:            entry.Line,
+				Account:         account.account,
+				ExternalID:      entry.ExternalID,
+				Status:          constant.ImportRowStatusNew,
+				TransactionDate: entry.Date,
+				Type:            entry.Type,
+				Amount:          entry.Amount,
+				Note:            entry.Note,
+			}
+
+			switch {
+			case entry.Error != "":
+				row.Error = entry.Error
+			case account.wallet == nil:
+				row.Error = fmt.Sprintf("account %q is not mapped to a wallet", account.account)
+			case account.currency != "" && account.currency != account.wallet.Currency:
+				row.Error = fmt.Sprintf("statement is in %s but the wallet is in %s", account.currency, account.wallet.Currency)
+			}
+			if row.Error != "" {
+				row.Status = constant.ImportRowStatusInvalid
+			}
+			if account.wallet != nil {
+				row.WalletID = account.wallet.ID.String()
+			}
+
+			result.Rows = append(result.Rows, row)
+			owners = append(owners, account)
+		}
+
+		if account.wallet != nil {
+			if err := s.markDuplicates(ctx, account.wallet, result.Rows[start:]); err != nil {
+				log.WithError(err).Error("[service - import - book]: Failed to get existing transactions")
+				return nil, err
+			}
+		}
+	}
+	starts = append(starts, len(result.Rows))
+
+	markOverdrafts(result.Rows, owners)
+	for k, account := range accounts {
+		result.Accounts = append(result.Accounts, reconcile(account, result.Rows[starts[k]:starts[k+1]]))
+	}
+
+	pending, err := tally(result)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	requests := make([]*model.CreateTransactionRequest, 0, len(pending))
+	for _, i := range pending {
+		row := result.Rows[i]
+		requests = append(requests, &model.CreateTransactionRequest{
+			Amount:          row.Amount,
+			Type:            row.Type,
+			Note:            row.Note,
+			TransactionDate: row.TransactionDate,
+			WalletID:        row.WalletID,
+			ExternalID:      row.ExternalID,
+		})
+	}
+
+	var mappings []*domain.ImportAccount
+	for _, account := range accounts {
+		if account.remember {
+			mappings = append(mappings, &domain.ImportAccount{
+				UserID:    uuid.MustParse(userId),
+				AccountID: account.account,
+				WalletID:  account.wallet.ID,
+			})
+		}
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if len(mappings) > 0 {
+			if err := s.accountRepo.Upsert(ctx, mappings); err != nil {
+				log.WithError(err).Error("[service - import - Upsert]: Failed to save import accounts")
+				return err
+			}
+		}
+
+		if len(requests) == 0 {
+			return nil
+		}
+
+		transactions, err := s.transactionService.CreateBatch(ctx, userId, requests)
+		if err != nil {
+			log.WithError(err).Error("[service - import - book]: Failed to book imported transactions")
+			return err
+		}
+
+		for k, i := range pending {
+			id := transactions[k].ID.String()
+			result.Rows[i].TransactionID = &id
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(requests)
+
+	return result, nil
+}
+
+// tally counts the rows of result by status and returns the new ones, oldest first. Outside a dry
+// run a file with invalid rows is refused as a whole.
+func tally(result *model.ImportResult) ([]int, error) {
+	result.Total = len(result.Rows)
+
+	var invalid []model.ImportRow
+	var pending []int
+	for i, row := range result.Rows {
+		switch row.Status {
+		case constant.ImportRowStatusNew:
+			result.New++
+			pending = append(pending, i)
+		case constant.ImportRowStatusDuplicate:
+			result.Duplicates++
+		case constant.ImportRowStatusInvalid:
+			result.Invalid++
+			invalid = append(invalid, row)
+		}
+	}
+
+	if !result.DryRun && len(invalid) > 0 {
+		return nil, apperror.ErrBadRequest.
+			WithMessage(fmt.Sprintf("%d rows could not be booked, fix them and preview again", len(invalid))).
+			WithDetails(invalid)
+	}
+
+	oldestFirst(result.Rows, pending)
+
+	return pending, nil
+}
+
+// oldestFirst sorts the indexes of rows by date in the order they are booked. Booking oldest first
+// lets income arrive before the spending it covers.
+func oldestFirst(rows []model.ImportRow, indexes []int) {
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return rows[indexes[a]].TransactionDate < rows[indexes[b]].TransactionDate
+	})
+}
+
+// markOverdrafts flags the new rows that would take their wallet below zero, or pay a card or loan
+// past what it owes, once the rows before them are booked. The wallet cannot cover them, so they are reported with the rest of the file
+// instead of failing the booking halfway.
+func markOverdrafts(rows []model.ImportRow, owners []*statementAccount) {
+	var pending []int
+	for i, row := range rows {
+		if row.Status == constant.ImportRowStatusNew {
+			pending = append(pending, i)
+		}
+	}
+	oldestFirst(rows, pending)
+
+	balances := make(map[string]float64)
+	for _, i := range pending {
+		wallet := owners[i].wallet
+		balance, ok := balances[wallet.ID.String()]
+		if !ok {
+			balance = wallet.Balance
+		}
+
+		balance = roundCents(balance + balanceEffect(wallet, rows[i].Type, rows[i].Amount))
+		if balance < 0 {
+			rows[i].Status = constant.ImportRowStatusInvalid
+			rows[i].Error = insufficientFunds(wallet).Message
+			continue
+		}
+		balances[wallet.ID.String()] = balance
+	}
+}
+
+// reconcile compares the closing balance reported in the file with the wallet balance once the
+// new rows are booked. Banks report what is owed on a card as a negative balance.
+func reconcile(account *statementAccount, rows []model.ImportRow) model.ImportAccount {
+	summary := model.ImportAccount{
+		Account:       account.account,
+		Currency:      account.currency,
+		Rows:          len(rows),
+		LedgerBalance: account.ledgerBalance,
+	}
+	if account.wallet == nil {
+		return summary
+	}
+
+	walletId := account.wallet.ID.String()
+	summary.WalletID = &walletId
+
+	projected := account.wallet.Balance
+	for _, row := range rows {
+		if row.Status == constant.ImportRowStatusNew {
+			projected += balanceEffect(account.wallet, row.Type, row.Amount)
+		}
+	}
+	projected = roundCents(projected)
+	summary.ProjectedBalance = &projected
+
+	if account.ledgerBalance != nil {
+		reported := *account.ledgerBalance
+		if account.wallet.IsLiability() {
+			reported = -reported
+		}
+		difference := roundCents(projected - reported)
+		reconciled := math.Abs(difference) < 0.005
+		summary.Difference = &difference
+		summary.Reconciled = &reconciled
+	}
+
+	return summary
+}
+
+// markDuplicates flags rows that are already booked on the wallet. Rows carrying the bank's id
+// match on it alone, whatever their date. Other rows match on type, amount, a date within a day
+// and similar notes, and each existing transaction can only match one row, so two equal payments
+// on the same day in a file are both kept unless both were booked before.
+func (s *importService) markDuplicates(ctx context.Context, wallet *domain.Wallet, rows []model.ImportRow) error {
+	var externalIds []string
+	for _, row := range rows {
+		if row.Status == constant.ImportRowStatusNew && row.ExternalID != "" {
+			externalIds = append(externalIds, row.ExternalID)
+		}
+	}
+
+	if len(externalIds) > 0 {
+		known, err := s.transactionRepo.GetByExternalIDs(ctx, wallet.ID.String(), externalIds)
+		if err != nil {
+			return err
+		}
+		byExternalId := make(map[string]*domain.Transaction, len(known))
+		for _, transaction := range known {
+			byExternalId[*transaction.ExternalID] = transaction
+		}
+
+		seen := make(map[string]bool)
+		for i := range rows {
+			row := &rows[i]
+			if row.Status != constant.ImportRowStatusNew || row.ExternalID == "" {
+				continue
+			}
+			if transaction, ok := byExternalId[row.ExternalID]; ok {
+				id := transaction.ID.String()
+				row.Status, row.DuplicateOf = constant.ImportRowStatusDuplicate, &id
+			} else if seen[row.ExternalID] {
+				row.Status = constant.ImportRowStatusDuplicate
+			}
+			seen[row.ExternalID] = true
+		}
+	}
+
+	from, to := math.MaxInt, math.MinInt
+	for _, row := range rows {
+		if row.Status != constant.ImportRowStatusNew {
+			continue
+		}
+		from = min(from, row.TransactionDate)
+		to = max(to, row.TransactionDate)
+	}
+	if from > to {
+		return nil
+	}
+
+	existing, err := s.transactionRepo.GetByWalletBetween(ctx, wallet.ID.String(), from-importDuplicateWindow, to+importDuplicateWindow+1)
+	if err != nil {
+		return err
+	}
+
+	claimed := make([]bool, len(existing))
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != constant.ImportRowStatusNew {
+			continue
+		}
+
+		best, bestScore, bestDistance := -1, 0.0, 0
+		for j, transaction := range existing {
+			if claimed[j] || transaction.Type != row.Type || math.Abs(transaction.Amount-row.Amount) >= 0.005 {
+				continue
+			}
+			// A transaction imported with another bank id is a different payment
+			if row.ExternalID != "" && transaction.ExternalID != nil {
+				continue
+			}
+
+			distance := abs(transaction.TransactionDate - row.TransactionDate)
+			if distance > importDuplicateWindow {
+				continue
+			}
+
+			// A note typed by hand and a bank description rarely agree, a missing one is no evidence
+			score := 1.0
+			if row.Note != "" && transaction.Note != "" {
+				score = importer.NoteSimilarity(row.Note, transaction.Note)
+			}
+			if score < importNoteSimilarity {
+				continue
+			}
+
+			if best < 0 || score > bestScore || (score == bestScore && distance < bestDistance) {
+				best, bestScore, bestDistance = j, score, distance
+			}
+		}
+
+		if best >= 0 {
+			claimed[best] = true
+			id := existing[best].ID.String()
+			row.Status = constant.ImportRowStatusDuplicate
+			row.DuplicateOf = &id
+		}
+	}
+
+	return nil
+}
+
+// getWallet loads the user's wallet an import is booked to
+func (s *importService) getWallet(ctx context.Context, userId string, walletId string) (*domain.Wallet, error) {
+	log := logger.WithRequestID(ctx)
+
+	wallet, err := s.walletRepo.GetDetail(ctx, userId, walletId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("wallet not found")
+		}
+		log.WithError(err).Error("[service - import - GetDetail]: Failed to get wallet")
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// parseError describes a file the parser gave up on
+func parseError(format string, err error) error {
+	if errors.Is(err, importer.ErrTooManyRows) {
+		return apperror.ErrBadRequest.WithMessage(fmt.Sprintf("a statement may hold at most %d rows", constant.ImportMaxRows))
+	}
+	return apperror.ErrBadRequest.WithMessage(fmt.Sprintf("file is not a readable %s statement: %s", format, err.Error())).Wrap(err)
+}
+
+// csvMapping converts a saved profile into the parser's mapping
+func csvMapping(profile *domain.ImportProfile) (importer.CSVMapping, error) {
+	location, err := time.LoadLocation(profile.Timezone)
+	if err != nil {
+		return importer.CSVMapping{}, apperror.ErrBadRequest.WithMessage("import profile has an unknown timezone").Wrap(err)
+	}
+
+	column := func(c *int) int {
+		if c == nil {
+			return -1
+		}
+		return *c
+	}
+
+	return importer.CSVMapping{
+		Delimiter:        []rune(profile.Delimiter)[0],
+		HasHeader:        profile.HasHeader,
+		DateColumn:       profile.DateColumn,
+		DateFormat:       profile.DateFormat,
+		Location:         location,
+		AmountColumn:     column(profile.AmountColumn),
+		DebitColumn:      column(profile.DebitColumn),
+		CreditColumn:     column(profile.CreditColumn),
+		NoteColumn:       column(profile.NoteColumn),
+		AmountSign:       profile.AmountSign,
+		DecimalSeparator: profile.DecimalSeparator,
+	}, nil
+}
+
+func withDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
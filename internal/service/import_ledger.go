@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/exporter"
+	"finance-backend/internal/importer"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ledgerWallet is an asset or liability account of a ledger file and the wallet it is booked to
+type ledgerWallet struct {
+	account string
+	wallet  *domain.Wallet
+	// created is set for wallets the import adds, their balance is the opening balance in the file
+	created bool
+}
+
+// ledgerBudget is an income or expense account of a ledger file and the budget it is booked to
+type ledgerBudget struct {
+	account string
+	budget  *domain.Budget
+	created bool
+}
+
+// ledgerBook resolves the accounts of a ledger file against the user's wallets and budgets
+type ledgerBook struct {
+	wallets map[string]*ledgerWallet
+	budgets map[string]*ledgerBudget
+	// created keeps the order new wallets and budgets are first named in
+	newWallets []*ledgerWallet
+	newBudgets []*ledgerBudget
+	currencies map[string]string
+}
+
+func (s *importService) ImportBeancount(ctx context.Context, userId string, request *model.ImportLedgerRequest, file io.Reader) (*model.ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "importService.ImportBeancount")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	loc, err := time.LoadLocation(withDefault(request.Timezone, "UTC"))
+	if err != nil {
+		return nil, apperror.ErrBadRequest.WithMessage("unknown timezone").Wrap(err)
+	}
+
+	ledger, err := importer.ParseBeancount(file, loc, constant.ImportMaxRows)
+	if err != nil {
+		return nil, parseError("Beancount", err)
+	}
+
+	wallets, err := s.walletRepo.GetList(ctx, userId)
+	if err != nil {
+		log.WithError(err).Error("[service - import - GetList]: Failed to get wallets")
+		return nil, err
+	}
+
+	budgets, err := s.budgetRepo.GetList(ctx, userId)
+	if err != nil {
+		log.WithError(err).Error("[service - import - GetList]: Failed to get budgets")
+		return nil, err
+	}
+
+	book := newLedgerBook(wallets, budgets)
+	for _, open := range ledger.Opens {
+		if len(open.Currencies) > 0 {
+			book.currencies[open.Account] = open.Currencies[0]
+		}
+		// Declared wallets are created even before anything is booked on them
+		if root, _ := splitAccount(open.Account); root == exporter.RootAssets || root == exporter.RootLiabilities {
+			book.wallet(open.Account, "")
+		}
+	}
+
+	result := &model.ImportResult{
+		DryRun:   request.DryRun,
+		Accounts: []model.ImportAccount{},
+		Rows:     []model.ImportRow{},
+	}
+
+	// owners[i] and categories[i] are the accounts of result.Rows[i]
+	var owners []*ledgerWallet
+	var categories []*ledgerBudget
+
+	for _, entry := range ledger.Entries {
+		if isOpening(entry) {
+			if err := book.open(entry); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		row, owner, category := book.row(entry)
+		result.Rows = append(result.Rows, row)
+		owners = append(owners, owner)
+		categories = append(categories, category)
+	}
+
+	for _, created := range book.newWallets {
+		created.wallet.Balance = roundCents(created.wallet.Balance)
+		switch {
+		case created.wallet.Currency == "":
+			return nil, apperror.ErrBadRequest.WithMessage(fmt.Sprintf("%s is opened without a currency and never used", created.account))
+		case created.wallet.Balance < 0:
+			return nil, apperror.ErrBadRequest.WithMessage(fmt.Sprintf("opening balance of %s is below zero", created.account))
+		}
+	}
+
+	if err := s.markLedgerDuplicates(ctx, userId, result.Rows, owners); err != nil {
+		log.WithError(err).Error("[service - import - ImportBeancount]: Failed to get existing transactions")
+		return nil, err
+	}
+
+	pending, err := tally(result)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only budgets something is booked to are worth creating
+	used := make(map[*ledgerBudget]bool)
+	for _, i := range pending {
+		if categories[i] != nil {
+			used[categories[i]] = true
+		}
+	}
+	var newBudgets []*ledgerBudget
+	for _, created := range book.newBudgets {
+		if used[created] {
+			newBudgets = append(newBudgets, created)
+		}
+	}
+
+	if !request.DryRun {
+		err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			for _, created := range book.newWallets {
+				if err := s.walletRepo.Create(ctx, userId, created.wallet); err != nil {
+					log.WithError(err).Error("[service - import - Create]: Failed to create wallet")
+					return err
+				}
+			}
+			for _, created := range newBudgets {
+				if err := s.budgetRepo.Create(ctx, userId, created.budget); err != nil {
+					log.WithError(err).Error("[service - import - Create]: Failed to create budget")
+					return err
+				}
+			}
+
+			if len(pending) == 0 {
+				return nil
+			}
+
+			requests := make([]*model.CreateTransactionRequest, 0, len(pending))
+			for _, i := range pending {
+				row := &result.Rows[i]
+				row.WalletID = owners[i].wallet.ID.String()
+
+				request := &model.CreateTransactionRequest{
+					Amount:          row.Amount,
+					Type:            row.Type,
+					Note:            row.Note,
+					TransactionDate: row.TransactionDate,
+					WalletID:        row.WalletID,
+					ExternalID:      row.ExternalID,
+				}
+				if categories[i] != nil {
+					row.BudgetID = categories[i].budget.ID.String()
+					request.BudgetID = &row.BudgetID
+				}
+				requests = append(requests, request)
+			}
+
+			transactions, err := s.transactionService.CreateBatch(ctx, userId, requests)
+			if err != nil {
+				log.WithError(err).Error("[service - import - ImportBeancount]: Failed to book imported transactions")
+				return err
+			}
+
+			for k, i := range pending {
+				id := transactions[k].ID.String()
+				result.Rows[i].TransactionID = &id
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Imported = len(pending)
+	}
+
+	for _, created := range book.newWallets {
+		result.Wallets = append(result.Wallets, model.ImportWallet{
+			Account:  created.account,
+			ID:       createdID(created.wallet.ID),
+			Name:     created.wallet.Name,
+			Type:     created.wallet.Type,
+			Currency: created.wallet.Currency,
+			Balance:  created.wallet.Balance,
+		})
+	}
+	for _, created := range newBudgets {
+		result.Budgets = append(result.Budgets, model.ImportBudget{
+			Account:  created.account,
+			ID:       createdID(created.budget.ID),
+			Name:     created.budget.Name,
+			Category: created.budget.Category,
+		})
+	}
+
+	result.Accounts = ledgerAccounts(book, result.Rows, owners)
+
+	return result, nil
+}
+
+// markLedgerDuplicates flags rows whose id is already booked. Exported files carry the id of each
+// transaction, or the id it was imported with, so a file can be imported again to sync it.
+// Unlike bank statements nothing is matched on amount and note.
+func (s *importService) markLedgerDuplicates(ctx context.Context, userId string, rows []model.ImportRow, owners []*ledgerWallet) error {
+	var ids []string
+	byWallet := make(map[*ledgerWallet][]string)
+	for i, row := range rows {
+		if row.Status != constant.ImportRowStatusNew {
+			continue
+		}
+		if _, err := uuid.Parse(row.ExternalID); err == nil {
+			ids = append(ids, row.ExternalID)
+		}
+		if !owners[i].created {
+			byWallet[owners[i]] = append(byWallet[owners[i]], row.ExternalID)
+		}
+	}
+
+	known := make(map[string]string)
+	if len(ids) > 0 {
+		transactions, err := s.transactionRepo.GetByIDs(ctx, userId, ids)
+		if err != nil {
+			return err
+		}
+		for _, transaction := range transactions {
+			known[transaction.ID.String()] = transaction.ID.String()
+		}
+	}
+	for owner, externalIds := range byWallet {
+		transactions, err := s.transactionRepo.GetByExternalIDs(ctx, owner.wallet.ID.String(), externalIds)
+		if err != nil {
+			return err
+		}
+		for _, transaction := range transactions {
+			known[*transaction.ExternalID] = transaction.ID.String()
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != constant.ImportRowStatusNew {
+			continue
+		}
+		if id, ok := known[row.ExternalID]; ok {
+			row.Status, row.DuplicateOf = constant.ImportRowStatusDuplicate, &id
+		} else if seen[row.ExternalID] {
+			row.Status = constant.ImportRowStatusDuplicate
+		}
+		seen[row.ExternalID] = true
+	}
+
+	return nil
+}
+
+func newLedgerBook(wallets []*domain.Wallet, budgets []*domain.Budget) *ledgerBook {
+	book := &ledgerBook{
+		wallets:    make(map[string]*ledgerWallet),
+		budgets:    make(map[string]*ledgerBudget),
+		currencies: make(map[string]string),
+	}
+
+	// The export names accounts after the wallets and budgets, so a re-import finds them again
+	for _, wallet := range wallets {
+		account := ledgerWalletAccount(wallet.Name, wallet.Type)
+		if _, ok := book.wallets[accountKey(account)]; !ok {
+			book.wallets[accountKey(account)] = &ledgerWallet{account: account, wallet: wallet}
+		}
+	}
+	for _, budget := range budgets {
+		key := accountKey(exporter.AccountComponent(budget.Category) + ":" + exporter.AccountComponent(budget.Name))
+		if _, ok := book.budgets[key]; !ok {
+			book.budgets[key] = &ledgerBudget{budget: budget}
+		}
+	}
+
+	return book
+}
+
+// wallet finds the wallet of an asset or liability account, adding one in currency when there is none
+func (b *ledgerBook) wallet(account string, currency string) *ledgerWallet {
+	root, components := splitAccount(account)
+	if found, ok := b.wallets[accountKey(account)]; ok {
+		// A wallet opened without a currency takes the one it is first used in
+		if found.created && found.wallet.Currency == "" {
+			found.wallet.Currency = currency
+		}
+		return found
+	}
+
+	walletType := constant.WalletTypePersonal
+	if root == exporter.RootLiabilities {
+		walletType = constant.WalletTypeCredit
+	}
+	if declared := b.currencies[account]; declared != "" {
+		currency = declared
+	}
+
+	created := &ledgerWallet{
+		account: account,
+		wallet:  &domain.Wallet{Name: accountTitle(components), Type: walletType, Currency: currency},
+		created: true,
+	}
+	b.wallets[accountKey(account)] = created
+	b.newWallets = append(b.newWallets, created)
+	return created
+}
+
+// budget finds the budget of an income or expense account. The first component under the root is
+// the category and the rest the budget name, accounts with no name book without a budget.
+func (b *ledgerBook) budget(account string) *ledgerBudget {
+	_, components := splitAccount(account)
+	if len(components) < 2 {
+		return nil
+	}
+
+	key := accountKey(components[0] + ":" + strings.Join(components[1:], "-"))
+	if found, ok := b.budgets[key]; ok {
+		return found
+	}
+
+	created := &ledgerBudget{
+		account: account,
+		budget: &domain.Budget{
+			Name:     accountTitle(components[1:]),
+			Type:     "monthly",
+			Category: strings.ToLower(accountTitle(components[:1])),
+		},
+		created: true,
+	}
+	b.budgets[key] = created
+	b.newBudgets = append(b.newBudgets, created)
+	return created
+}
+
+// open adds the wallet postings of an opening entry to the balance of the wallets the import creates.
+// Wallets the user already has keep their balance.
+func (b *ledgerBook) open(entry importer.LedgerEntry) error {
+	for _, posting := range entry.Postings {
+		root, _ := splitAccount(posting.Account)
+		if root == exporter.RootEquity {
+			continue
+		}
+
+		owner := b.wallet(posting.Account, posting.Currency)
+		if !owner.created {
+			continue
+		}
+		if posting.Currency != owner.wallet.Currency {
+			return apperror.ErrBadRequest.WithMessage(fmt.Sprintf("line %d: %s is in %s but opened in %s", entry.Line, posting.Account, owner.wallet.Currency, posting.Currency))
+		}
+
+		amount := posting.Amount
+		if owner.wallet.IsLiability() {
+			amount = -amount
+		}
+		owner.wallet.Balance += amount
+	}
+
+	return nil
+}
+
+// row turns an entry into a transaction of its one wallet posting, booked to the budget of its
+// one income or expense posting
+func (b *ledgerBook) row(entry importer.LedgerEntry) (model.ImportRow, *ledgerWallet, *ledgerBudget) {
+	row := model.ImportRow{
+		Line:            entry.Line,
+		Status:          constant.ImportRowStatusInvalid,
+		TransactionDate: entry.Date,
+		Note:            entry.Note,
+		Error:           entry.Error,
+	}
+	if row.Error != "" {
+		return row, nil, nil
+	}
+
+	var held, counter []importer.Posting
+	for _, posting := range entry.Postings {
+		switch root, _ := splitAccount(posting.Account); root {
+		case exporter.RootAssets, exporter.RootLiabilities:
+			held = append(held, posting)
+		case exporter.RootIncome, exporter.RootExpenses:
+			counter = append(counter, posting)
+		default:
+			row.Error = fmt.Sprintf("account %s is neither an asset, liability, income nor expense", posting.Account)
+			return row, nil, nil
+		}
+	}
+
+	switch {
+	case len(held) == 0:
+		row.Error = "entry moves no asset or liability account"
+	case len(held) > 1:
+		row.Error = "transfers between wallets are not supported"
+	case len(counter) > 1:
+		row.Error = "entries split across several categories are not supported"
+	}
+	if row.Error != "" {
+		return row, nil, nil
+	}
+
+	posting := held[0]
+	owner := b.wallet(posting.Account, posting.Currency)
+	row.Account = posting.Account
+	if !owner.created {
+		row.WalletID = owner.wallet.ID.String()
+	}
+
+	row.Type = constant.TransactionTypeIncome
+	if posting.Amount < 0 {
+		row.Type = constant.TransactionTypeExpense
+	}
+	row.Amount = math.Abs(posting.Amount)
+
+	row.ExternalID = entry.ID
+	if row.ExternalID == "" {
+		row.ExternalID = entryHash(entry)
+	}
+
+	var category *ledgerBudget
+	if len(counter) == 1 {
+		row.Counterpart = counter[0].Account
+		if category = b.budget(counter[0].Account); category != nil && !category.created {
+			row.BudgetID = category.budget.ID.String()
+		}
+	}
+
+	switch {
+	case posting.Currency != owner.wallet.Currency:
+		row.Error = fmt.Sprintf("entry is in %s but the wallet is in %s", posting.Currency, owner.wallet.Currency)
+	case roundCents(row.Amount) == 0:
+		row.Error = "amount is zero"
+	default:
+		row.Status = constant.ImportRowStatusNew
+	}
+
+	return row, owner, category
+}
+
+// ledgerAccounts summarises each wallet of the file with its balance once the new rows are booked
+func ledgerAccounts(book *ledgerBook, rows []model.ImportRow, owners []*ledgerWallet) []model.ImportAccount {
+	var order []*ledgerWallet
+	counts := make(map[*ledgerWallet]int)
+	projected := make(map[*ledgerWallet]float64)
+	for _, created := range book.newWallets {
+		order = append(order, created)
+		counts[created] = 0
+	}
+	for i, row := range rows {
+		owner := owners[i]
+		if owner == nil {
+			continue
+		}
+		if _, ok := counts[owner]; !ok {
+			order = append(order, owner)
+		}
+		counts[owner]++
+		if row.Status == constant.ImportRowStatusNew {
+			projected[owner] += balanceEffect(owner.wallet, row.Type, row.Amount)
+		}
+	}
+
+	accounts := make([]model.ImportAccount, 0, len(order))
+	for _, owner := range order {
+		balance := roundCents(owner.wallet.Balance + projected[owner])
+		accounts = append(accounts, model.ImportAccount{
+			Account:          owner.account,
+			WalletID:         createdID(owner.wallet.ID),
+			Currency:         owner.wallet.Currency,
+			Rows:             counts[owner],
+			ProjectedBalance: &balance,
+		})
+	}
+
+	return accounts
+}
+
+// isOpening tells an entry that brings balances in from equity, rather than earning or spending them
+func isOpening(entry importer.LedgerEntry) bool {
+	if entry.Error != "" {
+		return false
+	}
+
+	equity := false
+	for _, posting := range entry.Postings {
+		switch root, _ := splitAccount(posting.Account); root {
+		case exporter.RootEquity:
+			equity = true
+		case exporter.RootAssets, exporter.RootLiabilities:
+		default:
+			return false
+		}
+	}
+	return equity
+}
+
+// entryHash identifies an entry without id metadata by its content, so importing the same file twice books it once
+func entryHash(entry importer.LedgerEntry) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d|%s", entry.Date, entry.Note)
+	for _, posting := range entry.Postings {
+		fmt.Fprintf(hash, "|%s %s %s", posting.Account, strconv.FormatFloat(posting.Amount, 'f', 2, 64), posting.Currency)
+	}
+	return "beancount:" + hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+// splitAccount separates the root of an account from its components
+func splitAccount(account string) (string, []string) {
+	parts := strings.Split(account, ":")
+	return parts[0], parts[1:]
+}
+
+// accountKey compares account names regardless of case and of where components were split
+func accountKey(account string) string {
+	return strings.ToLower(strings.ReplaceAll(account, ":", "-"))
+}
+
+// accountTitle turns account components back into a name with spaces
+func accountTitle(components []string) string {
+	title := strings.ReplaceAll(strings.Join(components, " "), "-", " ")
+	if title == "" {
+		return exporter.AccountComponent("")
+	}
+	return title
+}
+
+// createdID is the id of a wallet or budget, nil until it is saved
+func createdID(id uuid.UUID) *string {
+	if id == uuid.Nil {
+		return nil
+	}
+	value := id.String()
+	return &value
+}
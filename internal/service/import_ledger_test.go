@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestImportServiceImportBeancount(t *testing.T) {
+	userId := uuid.NewString()
+
+	t.Run("seeds wallets, budgets and transactions", func(t *testing.T) {
+		file := `2025-08-01 open Assets:Bank:BCA IDR
+2025-08-01 open Liabilities:Visa
+2025-08-01 open Assets:Savings USD
+
+2025-08-01 * "Opening balance"
+  Assets:Bank:BCA          1000 IDR
+  Liabilities:Visa         -200 IDR
+  Equity:Opening-Balances
+
+2025-08-03 * "Market"
+  Expenses:Food:Groceries   150 IDR
+  Liabilities:Visa
+
+2025-08-02 * "Gift"
+  Assets:Bank:BCA            50 IDR
+  Income:Uncategorized
+`
+
+		ctrl := gomock.NewController(t)
+		importService, m, outcome := newImportService(ctrl)
+		m.wallets.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil)
+		m.budgets.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil)
+
+		var created []*domain.Wallet
+		m.wallets.EXPECT().Create(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, wallet *domain.Wallet) error {
+				wallet.ID = uuid.New()
+				created = append(created, wallet)
+				return nil
+			},
+		).Times(3)
+
+		groceries := uuid.New()
+		m.budgets.EXPECT().Create(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, budget *domain.Budget) error {
+				if budget.Name != "Groceries" || budget.Category != "food" || budget.Amount != 0 {
+					t.Errorf("Create() budget = %+v, want groceries in food", budget)
+				}
+				budget.ID = groceries
+				return nil
+			},
+		)
+
+		m.transactionService.EXPECT().CreateBatch(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, requests []*model.CreateTransactionRequest) ([]*domain.Transaction, error) {
+				if len(requests) != 2 {
+					t.Fatalf("CreateBatch() = %+v, want 2 transactions", requests)
+				}
+				gift, market := requests[0], requests[1]
+				if gift.Type != constant.TransactionTypeIncome || gift.Amount != 50 || gift.WalletID != created[0].ID.String() || gift.BudgetID != nil {
+					t.Errorf("gift = %+v, want unbudgeted income on BCA", gift)
+				}
+				if market.Type != constant.TransactionTypeExpense || market.Amount != 150 || market.WalletID != created[1].ID.String() || *market.BudgetID != groceries.String() {
+					t.Errorf("market = %+v, want a groceries expense on the card", market)
+				}
+				if !strings.HasPrefix(gift.ExternalID, "beancount:") {
+					t.Errorf("gift external id = %q, want a content hash", gift.ExternalID)
+				}
+				return []*domain.Transaction{{ID: uuid.New()}, {ID: uuid.New()}}, nil
+			},
+		)
+
+		result, err := importService.ImportBeancount(context.Background(), userId, &model.ImportLedgerRequest{}, strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("ImportBeancount() error = %v", err)
+		}
+
+		want := []struct {
+			name, walletType, currency string
+			balance                    float64
+		}{
+			{"Bank BCA", constant.WalletTypePersonal, "IDR", 1000},
+			{"Visa", constant.WalletTypeCredit, "IDR", 200},
+			{"Savings", constant.WalletTypePersonal, "USD", 0},
+		}
+		for i, w := range want {
+			wallet := result.Wallets[i]
+			if wallet.Name != w.name || wallet.Type != w.walletType || wallet.Currency != w.currency || wallet.Balance != w.balance || wallet.ID == nil {
+				t.Errorf("wallet %d = %+v, want %s %s in %s holding %.2f", i, wallet, w.name, w.walletType, w.currency, w.balance)
+			}
+		}
+		if len(result.Budgets) != 1 || *result.Budgets[0].ID != groceries.String() {
+			t.Errorf("budgets = %+v, want groceries", result.Budgets)
+		}
+		if result.Imported != 2 || *result.Accounts[1].ProjectedBalance != 350 {
+			t.Errorf("result = %+v, want 2 imported and 350 owed on the card", result)
+		}
+		outcome.assert(t, 1, 0)
+	})
+
+	t.Run("a re-exported file syncs against existing wallets", func(t *testing.T) {
+		bca := &domain.Wallet{ID: uuid.New(), Name: "BCA", Type: constant.WalletTypePersonal, Currency: "IDR", Balance: 500}
+		food := &domain.Budget{ID: uuid.New(), Name: "Groceries", Category: "food"}
+		booked := uuid.New()
+
+		file := `2025-08-01 * "Opening balance"
+  Assets:BCA               9999 IDR
+  Equity:Opening-Balances
+
+2025-08-02 * "Market"
+  id: "` + booked.String() + `"
+  Expenses:Food:Groceries   150 IDR
+  Assets:BCA
+
+2025-08-03 * "Bakery"
+  id: "bakery-1"
+  Expenses:FOOD:Groceries    20 IDR
+  Assets:Bca
+
+2025-08-04 * "Move"
+  Assets:BCA                 10 IDR
+  Assets:Cash
+`
+
+		ctrl := gomock.NewController(t)
+		importService, m, outcome := newImportService(ctrl)
+		m.wallets.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.Wallet{bca}, nil)
+		m.budgets.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.Budget{food}, nil)
+		m.transactions.EXPECT().GetByIDs(gomock.Any(), userId, []string{booked.String()}).Return([]*domain.Transaction{{ID: booked}}, nil)
+		m.transactions.EXPECT().GetByExternalIDs(gomock.Any(), bca.ID.String(), []string{booked.String(), "bakery-1"}).Return(nil, nil)
+
+		result, err := importService.ImportBeancount(context.Background(), userId, &model.ImportLedgerRequest{DryRun: true}, strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("ImportBeancount() error = %v", err)
+		}
+		if result.New != 1 || result.Duplicates != 1 || result.Invalid != 1 {
+			t.Fatalf("result = %+v, want 1 new, 1 duplicate and 1 invalid", result)
+		}
+		if *result.Rows[0].DuplicateOf != booked.String() {
+			t.Errorf("row 0 = %+v, want a duplicate of the booked transaction", result.Rows[0])
+		}
+		if row := result.Rows[1]; row.WalletID != bca.ID.String() || row.BudgetID != food.ID.String() || row.Counterpart != "Expenses:FOOD:Groceries" {
+			t.Errorf("row 1 = %+v, want the bakery on BCA and groceries", row)
+		}
+		if result.Rows[2].Error != "transfers between wallets are not supported" {
+			t.Errorf("row 2 error = %q, want transfers refused", result.Rows[2].Error)
+		}
+		// The opening entry leaves an existing wallet alone, Assets:Cash is only named by the transfer
+		if len(result.Wallets) != 0 || len(result.Budgets) != 0 || *result.Accounts[0].ProjectedBalance != 480 {
+			t.Errorf("result = %+v, want nothing created and BCA projected at 480", result)
+		}
+		outcome.assert(t, 0, 0)
+	})
+
+	failures := []struct {
+		name    string
+		file    string
+		wantErr error
+	}{
+		{
+			name:    "invalid rows are not booked",
+			file:    "2025-08-02 * \"Market\"\n  Expenses:Food  10 IDR\n  Expenses:Fun   5 IDR\n  Assets:Cash\n",
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "opening balance below zero",
+			file:    "2025-08-01 * \"Opening\"\n  Assets:Cash  -10 IDR\n  Equity:Opening-Balances\n",
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "not a ledger",
+			file:    "Date,Amount\n2025-08-01,10\n",
+			wantErr: apperror.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			importService, m, outcome := newImportService(ctrl)
+			m.wallets.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil).AnyTimes()
+			m.budgets.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil).AnyTimes()
+
+			_, err := importService.ImportBeancount(context.Background(), userId, &model.ImportLedgerRequest{}, strings.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportBeancount() error = %v, want %v", err, tt.wantErr)
+			}
+			outcome.assert(t, 0, 0)
+		})
+	}
+}
@@ -0,0 +1,595 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type importMocks struct {
+	profiles           *mocks.MockImportProfileRepository
+	accounts           *mocks.MockImportAccountRepository
+	wallets            *mocks.MockWalletRepository
+	budgets            *mocks.MockBudgetRepository
+	transactions       *mocks.MockTransactionRepository
+	transactionService *mocks.MockTransactionService
+}
+
+func newImportService(ctrl *gomock.Controller) (domain.ImportService, *importMocks, *txOutcome) {
+	txManager, outcome := newTxManager(ctrl)
+	m := &importMocks{
+		profiles:           mocks.NewMockImportProfileRepository(ctrl),
+		accounts:           mocks.NewMockImportAccountRepository(ctrl),
+		wallets:            mocks.NewMockWalletRepository(ctrl),
+		budgets:            mocks.NewMockBudgetRepository(ctrl),
+		transactions:       mocks.NewMockTransactionRepository(ctrl),
+		transactionService: mocks.NewMockTransactionService(ctrl),
+	}
+	return service.NewImportService(txManager, m.profiles, m.accounts, m.wallets, m.budgets, m.transactions, m.transactionService), m, outcome
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestImportServiceCreateProfile(t *testing.T) {
+	userId := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		request     *model.CreateImportProfileRequest
+		want        domain.ImportProfile
+		wantErr     error
+		wantCommits int
+	}{
+		{
+			name:    "defaults",
+			request: &model.CreateImportProfileRequest{Name: "Bank", AmountColumn: intPtr(2)},
+			want: domain.ImportProfile{
+				Name: "Bank", Delimiter: ",", DateFormat: "YYYY-MM-DD", Timezone: "UTC", AmountColumn: intPtr(2),
+				AmountSign: constant.ImportAmountSignNegativeExpense, DecimalSeparator: ".",
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "amount and debit columns together",
+			request: &model.CreateImportProfileRequest{Name: "Bank", AmountColumn: intPtr(2), DebitColumn: intPtr(3), CreditColumn: intPtr(4)},
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "unknown date token",
+			request: &model.CreateImportProfileRequest{Name: "Bank", AmountColumn: intPtr(2), DateFormat: "YYYY-WW-DD"},
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "decimal comma in a comma separated file",
+			request: &model.CreateImportProfileRequest{Name: "Bank", AmountColumn: intPtr(2), DecimalSeparator: ","},
+			wantErr: apperror.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			importService, m, outcome := newImportService(ctrl)
+
+			if tt.wantErr == nil {
+				m.profiles.EXPECT().Create(gomock.Any(), userId, gomock.Any()).Return(nil)
+			}
+
+			profile, err := importService.CreateProfile(context.Background(), userId, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateProfile() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				if profile.Delimiter != tt.want.Delimiter || profile.DateFormat != tt.want.DateFormat || profile.Timezone != tt.want.Timezone ||
+					profile.AmountSign != tt.want.AmountSign || profile.DecimalSeparator != tt.want.DecimalSeparator || *profile.AmountColumn != *tt.want.AmountColumn {
+					t.Errorf("CreateProfile() = %+v, want %+v", profile, tt.want)
+				}
+			}
+			outcome.assert(t, tt.wantCommits, 0)
+		})
+	}
+}
+
+func TestImportServiceImportCSV(t *testing.T) {
+	userId := uuid.NewString()
+	wallet := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal}
+	profile := &domain.ImportProfile{
+		ID: uuid.New(), Delimiter: ",", HasHeader: true, DateColumn: 0, DateFormat: "YYYY-MM-DD", Timezone: "UTC",
+		AmountColumn: intPtr(2), NoteColumn: intPtr(1),
+		AmountSign: constant.ImportAmountSignNegativeExpense, DecimalSeparator: ".",
+	}
+
+	// 2025-08-01 to 2025-08-03 at UTC midnight
+	const aug1, aug2, aug3 = 1754006400, 1754092800, 1754179200
+	statement := "Date,Description,Amount\n" +
+		"2025-08-03,POS 4411 COFFEE,-4.50\n" +
+		"2025-08-03,POS 4412 COFFEE,-4.50\n" +
+		"2025-08-02,Groceries,-80.00\n" +
+		"2025-08-01,Salary,3000.00\n"
+	existing := []*domain.Transaction{
+		// Booked by hand the day before the bank did, it matches one of the two coffees
+		{ID: uuid.New(), Type: constant.TransactionTypeExpense, Amount: 4.5, Note: "Coffee", TransactionDate: aug2 + 3600},
+		// Same amount but a different payment
+		{ID: uuid.New(), Type: constant.TransactionTypeExpense, Amount: 80, Note: "Fuel", TransactionDate: aug2},
+	}
+
+	expectLookups := func(m *importMocks) {
+		m.profiles.EXPECT().GetDetail(gomock.Any(), userId, profile.ID.String()).Return(profile, nil)
+		m.wallets.EXPECT().GetDetail(gomock.Any(), userId, wallet.ID.String()).Return(wallet, nil)
+		m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), wallet.ID.String(), aug1-86400, aug3+86401).Return(existing, nil)
+	}
+
+	t.Run("dry run previews without booking", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		expectLookups(m)
+
+		result, err := importService.ImportCSV(context.Background(), userId, &model.ImportCSVRequest{ProfileID: profile.ID.String(), WalletID: wallet.ID.String(), DryRun: true}, strings.NewReader(statement))
+		if err != nil {
+			t.Fatalf("ImportCSV() error = %v", err)
+		}
+		if result.Total != 4 || result.New != 3 || result.Duplicates != 1 || result.Invalid != 0 || result.Imported != 0 {
+			t.Fatalf("result = %+v, want 3 new and 1 duplicate", result)
+		}
+		if result.Rows[0].Status != constant.ImportRowStatusDuplicate || *result.Rows[0].DuplicateOf != existing[0].ID.String() {
+			t.Errorf("first coffee = %+v, want a duplicate of %s", result.Rows[0], existing[0].ID)
+		}
+		if result.Rows[1].Status != constant.ImportRowStatusNew || result.Rows[2].Status != constant.ImportRowStatusNew {
+			t.Errorf("second coffee and groceries = %+v, %+v, want both new", result.Rows[1], result.Rows[2])
+		}
+	})
+
+	t.Run("commit books new rows oldest first", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		expectLookups(m)
+
+		m.transactionService.EXPECT().CreateBatch(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, requests []*model.CreateTransactionRequest) ([]*domain.Transaction, error) {
+				if len(requests) != 3 {
+					t.Fatalf("booked %d requests, want 3", len(requests))
+				}
+				wantDates := []int{aug1, aug2, aug3}
+				var transactions []*domain.Transaction
+				for i, request := range requests {
+					if request.TransactionDate != wantDates[i] || request.WalletID != wallet.ID.String() {
+						t.Errorf("request %d = %+v, want dated %d on the wallet", i, request, wantDates[i])
+					}
+					transactions = append(transactions, &domain.Transaction{ID: uuid.New()})
+				}
+				return transactions, nil
+			},
+		)
+
+		result, err := importService.ImportCSV(context.Background(), userId, &model.ImportCSVRequest{ProfileID: profile.ID.String(), WalletID: wallet.ID.String()}, strings.NewReader(statement))
+		if err != nil {
+			t.Fatalf("ImportCSV() error = %v", err)
+		}
+		if result.Imported != 3 {
+			t.Fatalf("imported %d rows, want 3", result.Imported)
+		}
+		for _, row := range result.Rows {
+			if (row.TransactionID != nil) != (row.Status == constant.ImportRowStatusNew) {
+				t.Errorf("row %+v, want a transaction id only on new rows", row)
+			}
+		}
+	})
+
+	t.Run("commit with unreadable rows books nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		m.profiles.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(profile, nil)
+		m.wallets.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(wallet, nil)
+		m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		file := "Date,Description,Amount\n2025-08-01,Salary,3000.00\nyesterday,Coffee,-4.50\n"
+		_, err := importService.ImportCSV(context.Background(), userId, &model.ImportCSVRequest{ProfileID: profile.ID.String(), WalletID: wallet.ID.String()}, strings.NewReader(file))
+		if !errors.Is(err, apperror.ErrBadRequest) {
+			t.Fatalf("ImportCSV() error = %v, want %v", err, apperror.ErrBadRequest)
+		}
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || len(appErr.Details.([]model.ImportRow)) != 1 {
+			t.Errorf("error details = %+v, want the unreadable row", appErr)
+		}
+	})
+
+	t.Run("rows the wallet cannot cover are invalid", func(t *testing.T) {
+		file := "Date,Description,Amount\n2025-08-02,Rent,-250.00\n2025-08-01,Salary,100.00\n2025-08-03,Coffee,-4.50\n"
+		expectFile := func(m *importMocks) {
+			m.profiles.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(profile, nil)
+			m.wallets.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(wallet, nil)
+			m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		}
+
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		expectFile(m)
+
+		// The salary arrives first and covers the coffee but not the rent
+		result, err := importService.ImportCSV(context.Background(), userId, &model.ImportCSVRequest{ProfileID: profile.ID.String(), WalletID: wallet.ID.String(), DryRun: true}, strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("ImportCSV() error = %v", err)
+		}
+		if result.New != 2 || result.Invalid != 1 || result.Rows[0].Status != constant.ImportRowStatusInvalid || result.Rows[0].Error == "" {
+			t.Fatalf("result = %+v, want the rent invalid", result)
+		}
+		if projected := *result.Accounts[0].ProjectedBalance; projected != 95.5 {
+			t.Errorf("projected balance = %v, want 95.50 without the rent", projected)
+		}
+
+		ctrl = gomock.NewController(t)
+		importService, m, outcome := newImportService(ctrl)
+		expectFile(m)
+
+		_, err = importService.ImportCSV(context.Background(), userId, &model.ImportCSVRequest{ProfileID: profile.ID.String(), WalletID: wallet.ID.String()}, strings.NewReader(file))
+		if !errors.Is(err, apperror.ErrBadRequest) {
+			t.Fatalf("ImportCSV() error = %v, want %v", err, apperror.ErrBadRequest)
+		}
+		outcome.assert(t, 0, 0)
+	})
+
+	failures := []struct {
+		name    string
+		setup   func(m *importMocks)
+		wantErr error
+	}{
+		{
+			name: "unknown profile",
+			setup: func(m *importMocks) {
+				m.profiles.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name: "wallet of another user",
+			setup: func(m *importMocks) {
+				m.profiles.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(profile, nil)
+				m.wallets.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name: "existing transactions failure",
+			setup: func(m *importMocks) {
+				m.profiles.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(profile, nil)
+				m.wallets.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).Return(wallet, nil)
+				m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "booking failure",
+			setup: func(m *importMocks) {
+				expectLookups(m)
+				m.transactionService.EXPECT().CreateBatch(gomock.Any(), userId, gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			importService, m, _ := newImportService(ctrl)
+			tt.setup(m)
+
+			_, err := importService.ImportCSV(context.Background(), userId, &model.ImportCSVRequest{ProfileID: profile.ID.String(), WalletID: wallet.ID.String()}, strings.NewReader(statement))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportCSV() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportServiceImportOFX(t *testing.T) {
+	userId := uuid.NewString()
+	checking := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "USD", Balance: 1000}
+	savings := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "USD", Balance: 1000}
+
+	// F1 was booked by an earlier import and F2 is sent twice, the bank reports 1010.00 after all of them
+	statement := `OFXHEADER:100
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD
+<BANKACCTFROM><ACCTID>000123</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><DTPOSTED>20250801<TRNAMT>20.00<FITID>F1<NAME>Refund</STMTTRN>
+<STMTTRN><DTPOSTED>20250802<TRNAMT>-5.00<FITID>F2<NAME>Coffee</STMTTRN>
+<STMTTRN><DTPOSTED>20250802<TRNAMT>-5.00<FITID>F2<NAME>Coffee</STMTTRN>
+<STMTTRN><DTPOSTED>20250803<TRNAMT>15.00<FITID>F3<NAME>Interest</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>1010.00</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+	booked := "F1"
+	existing := []*domain.Transaction{{ID: uuid.New(), Type: constant.TransactionTypeIncome, Amount: 20, ExternalID: &booked, TransactionDate: 1754006400}}
+	saved := []*domain.ImportAccount{{AccountID: "000123", WalletID: checking.ID}}
+
+	expectMatching := func(m *importMocks, wallet *domain.Wallet) {
+		m.transactions.EXPECT().GetByExternalIDs(gomock.Any(), wallet.ID.String(), []string{"F1", "F2", "F2", "F3"}).Return(existing, nil)
+		m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), wallet.ID.String(), gomock.Any(), gomock.Any()).Return(existing, nil)
+	}
+
+	t.Run("saved mapping with bank id duplicates reconciles", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(saved, nil)
+		m.wallets.EXPECT().GetDetail(gomock.Any(), userId, checking.ID.String()).Return(checking, nil)
+		expectMatching(m, checking)
+
+		result, err := importService.ImportOFX(context.Background(), userId, &model.ImportStatementRequest{DryRun: true}, strings.NewReader(statement))
+		if err != nil {
+			t.Fatalf("ImportOFX() error = %v", err)
+		}
+		if result.New != 2 || result.Duplicates != 2 {
+			t.Fatalf("result = %+v, want 2 new and 2 duplicates", result)
+		}
+		if *result.Rows[0].DuplicateOf != existing[0].ID.String() || result.Rows[2].Status != constant.ImportRowStatusDuplicate || result.Rows[2].DuplicateOf != nil {
+			t.Errorf("rows = %+v, want F1 a duplicate of the booked transaction and the second F2 a repeat", result.Rows)
+		}
+
+		account := result.Accounts[0]
+		if account.Account != "000123" || *account.WalletID != checking.ID.String() || *account.ProjectedBalance != 1010 || *account.Difference != 0 || !*account.Reconciled {
+			t.Errorf("account = %+v, want 000123 reconciled at 1010", account)
+		}
+	})
+
+	t.Run("explicit mapping is remembered on commit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, outcome := newImportService(ctrl)
+		m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(saved, nil)
+		m.wallets.EXPECT().GetDetail(gomock.Any(), userId, savings.ID.String()).Return(savings, nil)
+		expectMatching(m, savings)
+		m.accounts.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, accounts []*domain.ImportAccount) error {
+				if len(accounts) != 1 || accounts[0].AccountID != "000123" || accounts[0].WalletID != savings.ID || accounts[0].UserID.String() != userId {
+					t.Errorf("Upsert() = %+v, want 000123 on savings", accounts)
+				}
+				return nil
+			},
+		)
+		m.transactionService.EXPECT().CreateBatch(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, requests []*model.CreateTransactionRequest) ([]*domain.Transaction, error) {
+				if len(requests) != 2 || requests[0].ExternalID != "F2" || requests[1].ExternalID != "F3" || requests[0].WalletID != savings.ID.String() {
+					t.Fatalf("CreateBatch() = %+v, want F2 and F3 on savings", requests)
+				}
+				return []*domain.Transaction{{ID: uuid.New()}, {ID: uuid.New()}}, nil
+			},
+		)
+
+		request := &model.ImportStatementRequest{AccountMap: `{"000123":"` + savings.ID.String() + `"}`}
+		result, err := importService.ImportOFX(context.Background(), userId, request, strings.NewReader(statement))
+		if err != nil {
+			t.Fatalf("ImportOFX() error = %v", err)
+		}
+		if result.Imported != 2 {
+			t.Errorf("imported %d rows, want 2", result.Imported)
+		}
+		outcome.assert(t, 1, 0)
+	})
+
+	t.Run("unmapped account is previewed as invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil)
+
+		result, err := importService.ImportOFX(context.Background(), userId, &model.ImportStatementRequest{DryRun: true}, strings.NewReader(statement))
+		if err != nil {
+			t.Fatalf("ImportOFX() error = %v", err)
+		}
+		if result.Invalid != 4 || result.Accounts[0].WalletID != nil || result.Accounts[0].Reconciled != nil {
+			t.Errorf("result = %+v, want every row invalid and no reconciliation", result)
+		}
+	})
+
+	t.Run("statement in another currency than the wallet", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, outcome := newImportService(ctrl)
+		euro := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "EUR"}
+		m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil)
+		m.wallets.EXPECT().GetDetail(gomock.Any(), userId, euro.ID.String()).Return(euro, nil)
+
+		_, err := importService.ImportOFX(context.Background(), userId, &model.ImportStatementRequest{WalletID: euro.ID.String()}, strings.NewReader(statement))
+		if !errors.Is(err, apperror.ErrBadRequest) {
+			t.Fatalf("ImportOFX() error = %v, want %v", err, apperror.ErrBadRequest)
+		}
+		outcome.assert(t, 0, 0)
+	})
+
+	t.Run("debit larger than the balance is refused", func(t *testing.T) {
+		low := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "USD", Balance: 10}
+		file := `<OFX><STMTRS><CURDEF>USD<BANKACCTFROM><ACCTID>000456</BANKACCTFROM>
+<STMTTRN><DTPOSTED>20250803<TRNAMT>-50.00<FITID>D1<NAME>Rent</STMTTRN>
+<LEDGERBAL><BALAMT>-40.00</LEDGERBAL></STMTRS></OFX>`
+		expectFile := func(m *importMocks) {
+			m.accounts.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.ImportAccount{{AccountID: "000456", WalletID: low.ID}}, nil)
+			m.wallets.EXPECT().GetDetail(gomock.Any(), userId, low.ID.String()).Return(low, nil)
+			m.transactions.EXPECT().GetByExternalIDs(gomock.Any(), low.ID.String(), []string{"D1"}).Return(nil, nil)
+			m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), low.ID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		}
+
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		expectFile(m)
+
+		// The wallet never goes below zero, so the bank's balance cannot be reached
+		result, err := importService.ImportOFX(context.Background(), userId, &model.ImportStatementRequest{DryRun: true}, strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("ImportOFX() error = %v", err)
+		}
+		account := result.Accounts[0]
+		if result.Invalid != 1 || result.Rows[0].Error == "" || *account.ProjectedBalance != 10 || *account.Reconciled {
+			t.Fatalf("result = %+v, account = %+v, want the debit invalid and 10 left unreconciled", result, account)
+		}
+
+		ctrl = gomock.NewController(t)
+		importService, m, outcome := newImportService(ctrl)
+		expectFile(m)
+
+		_, err = importService.ImportOFX(context.Background(), userId, &model.ImportStatementRequest{}, strings.NewReader(file))
+		if !errors.Is(err, apperror.ErrBadRequest) {
+			t.Fatalf("ImportOFX() error = %v, want %v", err, apperror.ErrBadRequest)
+		}
+		outcome.assert(t, 0, 0)
+	})
+
+	t.Run("card payment above what it owes is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		card := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypeCredit, Currency: "USD", Balance: 30}
+		m.accounts.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.ImportAccount{{AccountID: "4111", WalletID: card.ID}}, nil)
+		m.wallets.EXPECT().GetDetail(gomock.Any(), userId, card.ID.String()).Return(card, nil)
+		m.transactions.EXPECT().GetByExternalIDs(gomock.Any(), card.ID.String(), []string{"P1", "C1"}).Return(nil, nil)
+		m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), card.ID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		// The purchase is booked first, the payment then covers it and the 30 owed before but not more
+		file := `<OFX><CCSTMTRS><CURDEF>USD<CCACCTFROM><ACCTID>4111</CCACCTFROM>
+<STMTTRN><DTPOSTED>20250805<TRNAMT>60.00<FITID>P1<NAME>Payment</STMTTRN>
+<STMTTRN><DTPOSTED>20250803<TRNAMT>-20.00<FITID>C1<NAME>Bookshop</STMTTRN>
+</CCSTMTRS></OFX>`
+		result, err := importService.ImportOFX(context.Background(), userId, &model.ImportStatementRequest{DryRun: true}, strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("ImportOFX() error = %v", err)
+		}
+		if result.Invalid != 1 || result.Rows[0].Error != "amount is more than the wallet owes" || result.Rows[1].Status != constant.ImportRowStatusNew {
+			t.Errorf("rows = %+v, want only the payment invalid", result.Rows)
+		}
+	})
+
+	t.Run("card balance owed reconciles against a negative ledger balance", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		importService, m, _ := newImportService(ctrl)
+		card := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypeCredit, Currency: "USD", Balance: 0}
+		m.accounts.EXPECT().GetList(gomock.Any(), userId).Return([]*domain.ImportAccount{{AccountID: "4111", WalletID: card.ID}}, nil)
+		m.wallets.EXPECT().GetDetail(gomock.Any(), userId, card.ID.String()).Return(card, nil)
+		m.transactions.EXPECT().GetByExternalIDs(gomock.Any(), card.ID.String(), []string{"C1"}).Return(nil, nil)
+		m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), card.ID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		file := `<OFX><CCSTMTRS><CURDEF>USD<CCACCTFROM><ACCTID>4111</CCACCTFROM>
+<STMTTRN><DTPOSTED>20250803<TRNAMT>-12.30<FITID>C1<NAME>Bookshop</STMTTRN>
+<LEDGERBAL><BALAMT>-12.30</LEDGERBAL></CCSTMTRS></OFX>`
+		result, err := importService.ImportOFX(context.Background(), userId, &model.ImportStatementRequest{DryRun: true}, strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("ImportOFX() error = %v", err)
+		}
+		if account := result.Accounts[0]; *account.ProjectedBalance != 12.3 || !*account.Reconciled {
+			t.Errorf("account = %+v, want 12.30 owed and reconciled", account)
+		}
+	})
+
+	failures := []struct {
+		name    string
+		request *model.ImportStatementRequest
+		file    string
+		setup   func(m *importMocks)
+		wantErr error
+	}{
+		{
+			name:    "not an ofx file",
+			request: &model.ImportStatementRequest{},
+			file:    "Date,Amount\n2025-08-01,1\n",
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "account map is not an object",
+			request: &model.ImportStatementRequest{AccountMap: `["000123"]`},
+			file:    statement,
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "account map with an invalid wallet id",
+			request: &model.ImportStatementRequest{AccountMap: `{"000123":"checking"}`},
+			file:    statement,
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "mapped wallet of another user",
+			request: &model.ImportStatementRequest{WalletID: checking.ID.String()},
+			file:    statement,
+			setup: func(m *importMocks) {
+				m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil)
+				m.wallets.EXPECT().GetDetail(gomock.Any(), userId, checking.ID.String()).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:    "saved mappings failure",
+			request: &model.ImportStatementRequest{},
+			file:    statement,
+			setup: func(m *importMocks) {
+				m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:    "remembering the mapping fails",
+			request: &model.ImportStatementRequest{WalletID: savings.ID.String()},
+			file:    statement,
+			setup: func(m *importMocks) {
+				m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil)
+				m.wallets.EXPECT().GetDetail(gomock.Any(), userId, savings.ID.String()).Return(savings, nil)
+				expectMatching(m, savings)
+				m.accounts.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			importService, m, _ := newImportService(ctrl)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			_, err := importService.ImportOFX(context.Background(), userId, tt.request, strings.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportOFX() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportServiceImportQIF(t *testing.T) {
+	userId := uuid.NewString()
+	wallet := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "USD", Balance: 100}
+	file := "!Type:Bank\nD02/08/2025\nT-4.50\nPCoffee\n^\nD01/08/2025\nT20.00\nPRefund\n^\n"
+
+	ctrl := gomock.NewController(t)
+	importService, m, outcome := newImportService(ctrl)
+	m.accounts.EXPECT().GetList(gomock.Any(), userId).Return(nil, nil)
+	m.wallets.EXPECT().GetDetail(gomock.Any(), userId, wallet.ID.String()).Return(wallet, nil)
+	// 1 to 2 August 2025, the file is day first
+	m.transactions.EXPECT().GetByWalletBetween(gomock.Any(), wallet.ID.String(), 1754006400-86400, 1754092800+86401).Return(nil, nil)
+	m.transactionService.EXPECT().CreateBatch(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, requests []*model.CreateTransactionRequest) ([]*domain.Transaction, error) {
+			if len(requests) != 2 || requests[0].Note != "Refund" || requests[1].Note != "Coffee" {
+				t.Fatalf("CreateBatch() = %+v, want the refund first", requests)
+			}
+			return []*domain.Transaction{{ID: uuid.New()}, {ID: uuid.New()}}, nil
+		},
+	)
+
+	// A file without account names cannot be recognised later, so nothing is remembered
+	request := &model.ImportStatementRequest{WalletID: wallet.ID.String(), DateOrder: "dmy"}
+	result, err := importService.ImportQIF(context.Background(), userId, request, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("ImportQIF() error = %v", err)
+	}
+	if result.Imported != 2 || *result.Accounts[0].ProjectedBalance != 115.5 || result.Accounts[0].Reconciled != nil {
+		t.Errorf("result = %+v, want 2 imported and a balance of 115.50 without a ledger to reconcile", result)
+	}
+	outcome.assert(t, 1, 0)
+}
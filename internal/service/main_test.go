@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/logger"
+	"os"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+var errDB = errors.New("database unavailable")
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(config.LogConfig{Level: "PANIC", Format: "text"})
+	os.Exit(m.Run())
+}
+
+// txOutcome counts how units of work run through the mock transaction manager ended
+type txOutcome struct {
+	commits   int
+	rollbacks int
+}
+
+// newTxManager returns a transaction manager that runs units of work inline and
+// records whether each one would have committed or rolled back
+func newTxManager(ctrl *gomock.Controller) (*mocks.MockTxManager, *txOutcome) {
+	txManager := mocks.NewMockTxManager(ctrl)
+	outcome := &txOutcome{}
+
+	txManager.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			if err := fn(ctx); err != nil {
+				outcome.rollbacks++
+				return err
+			}
+			outcome.commits++
+			return nil
+		},
+	).AnyTimes()
+
+	return txManager, outcome
+}
+
+func (o *txOutcome) assert(t *testing.T, commits, rollbacks int) {
+	t.Helper()
+
+	if o.commits != commits || o.rollbacks != rollbacks {
+		t.Fatalf("transactions committed %d and rolled back %d, want %d and %d", o.commits, o.rollbacks, commits, rollbacks)
+	}
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"context"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxReportBuckets keeps a fine grouping over a long range from producing huge responses
+const maxReportBuckets = 1000
+
+type reportService struct {
+	reportRepo   domain.ReportRepository
+	walletRepo   domain.WalletRepository
+	snapshotRepo domain.SnapshotRepository
+	rateRepo     domain.ExchangeRateRepository
+
+	baseCurrency string
+}
+
+func NewReportService(reportRepo domain.ReportRepository, walletRepo domain.WalletRepository, snapshotRepo domain.SnapshotRepository, rateRepo domain.ExchangeRateRepository, baseCurrency string) domain.ReportService {
+	return &reportService{
+		reportRepo:   reportRepo,
+		walletRepo:   walletRepo,
+		snapshotRepo: snapshotRepo,
+		rateRepo:     rateRepo,
+		baseCurrency: baseCurrency,
+	}
+}
+
+func (s *reportService) Summary(ctx context.Context, userId string, request *model.SummaryReportRequest) (*model.SummaryReport, error) {
+	ctx, span := tracing.Start(ctx, "reportService.Summary")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	timezone := request.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apperror.ErrBadRequest.WithMessage("unknown timezone").Wrap(err)
+	}
+
+	buckets := summaryBuckets(time.Unix(int64(request.From), 0).In(loc), time.Unix(int64(request.To), 0).In(loc), request.GroupBy)
+	if len(buckets) > maxReportBuckets {
+		return nil, apperror.ErrBadRequest.WithMessage("range has too many buckets, use a larger group_by")
+	}
+
+	rows, err := s.reportRepo.Summary(ctx, userId, domain.SummaryFilter{
+		From:     request.From,
+		To:       request.To,
+		GroupBy:  request.GroupBy,
+		Timezone: timezone,
+		WalletID: request.WalletID,
+		BudgetID: request.BudgetID,
+	})
+	if err != nil {
+		log.WithError(err).Error("[service - report - Summary]: Failed to aggregate transactions")
+		return nil, err
+	}
+
+	// Rows carry the local wall clock, match them to buckets by their calendar date
+	byDate := make(map[string]*domain.SummaryRow, len(rows))
+	for _, row := range rows {
+		byDate[row.Bucket.Format(time.DateOnly)] = row
+	}
+
+	report := &model.SummaryReport{
+		From:     request.From,
+		To:       request.To,
+		GroupBy:  request.GroupBy,
+		Timezone: timezone,
+		Buckets:  make([]model.SummaryBucket, 0, len(buckets)),
+	}
+
+	for _, start := range buckets {
+		bucket := model.SummaryBucket{
+			Start: int(start.Unix()),
+			End:   int(nextBucket(start, request.GroupBy).Unix()),
+			Label: bucketLabel(start, request.GroupBy),
+		}
+
+		if row, ok := byDate[start.Format(time.DateOnly)]; ok {
+			bucket.Income = row.Income
+			bucket.Expense = row.Expense
+			bucket.Net = row.Income - row.Expense
+			bucket.Count = row.Count
+		}
+
+		report.Totals.Income += bucket.Income
+		report.Totals.Expense += bucket.Expense
+		report.Totals.Count += bucket.Count
+		report.Buckets = append(report.Buckets, bucket)
+	}
+	report.Totals.Net = report.Totals.Income - report.Totals.Expense
+
+	return report, nil
+}
+
+func (s *reportService) Categories(ctx context.Context, userId string, request *model.CategoryReportRequest) (*model.CategoryReport, error) {
+	ctx, span := tracing.Start(ctx, "reportService.Categories")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	top := request.Top
+	if top == 0 {
+		top = constant.ReportDefaultTopNotes
+	}
+
+	// The previous period has the same length and ends where the requested one starts
+	current := domain.SpendFilter{From: request.From, To: request.To, WalletID: request.WalletID}
+	previous := domain.SpendFilter{From: request.From - (request.To - request.From), To: request.From, WalletID: request.WalletID}
+
+	currentRows, err := s.reportRepo.SpendByBudget(ctx, userId, current)
+	if err != nil {
+		log.WithError(err).Error("[service - report - Categories]: Failed to aggregate current spend")
+		return nil, err
+	}
+
+	previousRows, err := s.reportRepo.SpendByBudget(ctx, userId, previous)
+	if err != nil {
+		log.WithError(err).Error("[service - report - Categories]: Failed to aggregate previous spend")
+		return nil, err
+	}
+
+	currentTags, err := s.reportRepo.SpendByTag(ctx, userId, current)
+	if err != nil {
+		log.WithError(err).Error("[service - report - Categories]: Failed to aggregate current tag spend")
+		return nil, err
+	}
+
+	previousTags, err := s.reportRepo.SpendByTag(ctx, userId, previous)
+	if err != nil {
+		log.WithError(err).Error("[service - report - Categories]: Failed to aggregate previous tag spend")
+		return nil, err
+	}
+
+	noteRows, err := s.reportRepo.TopNotes(ctx, userId, current, top)
+	if err != nil {
+		log.WithError(err).Error("[service - report - Categories]: Failed to aggregate top notes")
+		return nil, err
+	}
+
+	report := &model.CategoryReport{
+		From:         current.From,
+		To:           current.To,
+		PreviousFrom: previous.From,
+		PreviousTo:   previous.To,
+		Categories:   []model.CategorySpend{},
+		Tags:         []model.TagSpend{},
+		TopNotes:     make([]model.NoteSpend, 0, len(noteRows)),
+	}
+
+	categories := make(categoryIndex)
+	for _, row := range currentRows {
+		category, budget := categories.budget(row)
+		category.Amount += row.Amount
+		category.Count += row.Count
+		budget.Amount += row.Amount
+		budget.Count += row.Count
+		report.Total.Amount += row.Amount
+		report.Total.Count += row.Count
+	}
+	for _, row := range previousRows {
+		category, budget := categories.budget(row)
+		category.PreviousAmount += row.Amount
+		budget.PreviousAmount += row.Amount
+		report.Total.PreviousAmount += row.Amount
+	}
+
+	completeChange(&report.Total)
+	for _, category := range categories {
+		completeChange(&category.SpendChange)
+		category.Share = share(category.Amount, report.Total.Amount)
+
+		category.Budgets = make([]model.BudgetSpend, 0, len(category.budgets))
+		for _, budget := range category.budgets {
+			completeChange(&budget.SpendChange)
+			budget.Share = share(budget.Amount, category.Amount)
+			category.Budgets = append(category.Budgets, *budget)
+		}
+		sort.Slice(category.Budgets, func(i, j int) bool {
+			return spendsBefore(category.Budgets[i].SpendChange, category.Budgets[j].SpendChange, category.Budgets[i].Name, category.Budgets[j].Name)
+		})
+
+		report.Categories = append(report.Categories, category.CategorySpend)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return spendsBefore(report.Categories[i].SpendChange, report.Categories[j].SpendChange, report.Categories[i].Category, report.Categories[j].Category)
+	})
+
+	tags := make(map[string]*model.TagSpend)
+	tag := func(row *domain.TagSpendRow) *model.TagSpend {
+		spend, ok := tags[row.TagID.String()]
+		if !ok {
+			spend = &model.TagSpend{TagID: row.TagID.String(), Name: row.Name}
+			tags[spend.TagID] = spend
+		}
+		return spend
+	}
+	for _, row := range currentTags {
+		spend := tag(row)
+		spend.Amount += row.Amount
+		spend.Count += row.Count
+	}
+	for _, row := range previousTags {
+		tag(row).PreviousAmount += row.Amount
+	}
+	for _, spend := range tags {
+		completeChange(&spend.SpendChange)
+		spend.Share = share(spend.Amount, report.Total.Amount)
+		report.Tags = append(report.Tags, *spend)
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		return spendsBefore(report.Tags[i].SpendChange, report.Tags[j].SpendChange, report.Tags[i].Name, report.Tags[j].Name)
+	})
+
+	for _, row := range noteRows {
+		report.TopNotes = append(report.TopNotes, model.NoteSpend{
+			Note:   row.Note,
+			Amount: row.Amount,
+			Count:  row.Count,
+			Share:  share(row.Amount, report.Total.Amount),
+		})
+	}
+
+	return report, nil
+}
+
+func (s *reportService) NetWorth(ctx context.Context, userId string, request *model.NetWorthReportRequest) (*model.NetWorthReport, error) {
+	ctx, span := tracing.Start(ctx, "reportService.NetWorth")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	first := utcDay(time.Unix(int64(request.From), 0))
+	days := summaryBuckets(first, time.Unix(int64(request.To), 0), constant.ReportGroupByDay)
+	if len(days) > maxReportBuckets {
+		return nil, apperror.ErrBadRequest.WithMessage("range has too many days")
+	}
+
+	wallets, err := s.walletRepo.GetList(ctx, userId)
+	if err != nil {
+		log.WithError(err).Error("[service - report - NetWorth]: Failed to get wallets")
+		return nil, err
+	}
+
+	snapshots, err := s.snapshotRepo.GetRange(ctx, userId, int(first.Unix()), request.To)
+	if err != nil {
+		log.WithError(err).Error("[service - report - NetWorth]: Failed to get balance snapshots")
+		return nil, err
+	}
+
+	var currencies []string
+	for _, wallet := range wallets {
+		if wallet.Currency != s.baseCurrency && !slices.Contains(currencies, wallet.Currency) {
+			currencies = append(currencies, wallet.Currency)
+		}
+	}
+
+	rates, err := s.rateRepo.GetUntil(ctx, currencies, request.To)
+	if err != nil {
+		log.WithError(err).Error("[service - report - NetWorth]: Failed to get exchange rates")
+		return nil, err
+	}
+
+	// Snapshots and rates come oldest first, each series is walked forward with the days
+	history := make(map[uuid.UUID][]*domain.WalletSnapshot)
+	for _, snapshot := range snapshots {
+		history[snapshot.WalletID] = append(history[snapshot.WalletID], snapshot)
+	}
+	rateHistory := make(map[string][]*domain.ExchangeRate)
+	for _, rate := range rates {
+		rateHistory[rate.Currency] = append(rateHistory[rate.Currency], rate)
+	}
+
+	report := &model.NetWorthReport{
+		From:         request.From,
+		To:           request.To,
+		Currency:     s.baseCurrency,
+		MissingRates: []string{},
+		Points:       make([]model.NetWorthPoint, 0, len(days)),
+	}
+
+	today := utcDay(time.Now())
+	for _, day := range days {
+		date := int(day.Unix())
+		point := model.NetWorthPoint{Date: date, Label: day.Format(time.DateOnly)}
+
+		for _, wallet := range wallets {
+			balance, ok := wallet.Balance, true
+			// Today and later use the live balance, snapshots only settle at the end of a day
+			if day.Before(today) {
+				balance, ok = balanceOn(history[wallet.ID], date)
+			}
+			if !ok {
+				continue
+			}
+
+			rate := 1.0
+			if wallet.Currency != s.baseCurrency {
+				if rate, ok = rateOn(rateHistory[wallet.Currency], date); !ok {
+					if !slices.Contains(report.MissingRates, wallet.Currency) {
+						report.MissingRates = append(report.MissingRates, wallet.Currency)
+					}
+					continue
+				}
+			}
+
+			if wallet.IsLiability() {
+				point.Liabilities += balance * rate
+			} else {
+				point.Assets += balance * rate
+			}
+		}
+
+		point.Assets = roundCents(point.Assets)
+		point.Liabilities = roundCents(point.Liabilities)
+		point.NetWorth = roundCents(point.Assets - point.Liabilities)
+		report.Points = append(report.Points, point)
+	}
+	sort.Strings(report.MissingRates)
+
+	return report, nil
+}
+
+// balanceOn returns the latest snapshot balance at or before date, snapshots are oldest first
+func balanceOn(snapshots []*domain.WalletSnapshot, date int) (float64, bool) {
+	index := sort.Search(len(snapshots), func(i int) bool {
+		return snapshots[i].SnapshotDate > date
+	})
+	if index == 0 {
+		return 0, false
+	}
+	return snapshots[index-1].Balance, true
+}
+
+// rateOn returns the rate in effect on date, rates are oldest first
+func rateOn(rates []*domain.ExchangeRate, date int) (float64, bool) {
+	index := sort.Search(len(rates), func(i int) bool {
+		return rates[i].RateDate > date
+	})
+	if index == 0 {
+		return 0, false
+	}
+	return rates[index-1].Rate, true
+}
+
+// categorySpend accumulates a category and its budgets across both periods
+type categorySpend struct {
+	model.CategorySpend
+	budgets map[string]*model.BudgetSpend
+}
+
+type categoryIndex map[string]*categorySpend
+
+// budget returns the accumulators for row, unbudgeted spend is filed under ReportCategoryUncategorized
+func (index categoryIndex) budget(row *domain.BudgetSpendRow) (*categorySpend, *model.BudgetSpend) {
+	name := row.Category
+	if row.BudgetID == nil || name == "" {
+		name = constant.ReportCategoryUncategorized
+	}
+
+	category, ok := index[name]
+	if !ok {
+		category = &categorySpend{
+			CategorySpend: model.CategorySpend{Category: name},
+			budgets:       make(map[string]*model.BudgetSpend),
+		}
+		index[name] = category
+	}
+
+	var key string
+	if row.BudgetID != nil {
+		key = row.BudgetID.String()
+	}
+
+	budget, ok := category.budgets[key]
+	if !ok {
+		budget = &model.BudgetSpend{Name: row.BudgetName}
+		if row.BudgetID != nil {
+			budget.BudgetID = &key
+		}
+		category.budgets[key] = budget
+	}
+
+	return category, budget
+}
+
+// completeChange fills in the difference against the previous period
+func completeChange(spend *model.SpendChange) {
+	spend.Change = roundCents(spend.Amount - spend.PreviousAmount)
+	if spend.PreviousAmount != 0 {
+		percent := roundCents(spend.Change / spend.PreviousAmount * 100)
+		spend.ChangePercent = &percent
+	}
+}
+
+// share returns part as a percentage of total
+func share(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundCents(part / total * 100)
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// spendsBefore orders by current spend, then previous spend, then name so charts are stable
+func spendsBefore(a, b model.SpendChange, nameA, nameB string) bool {
+	if a.Amount != b.Amount {
+		return a.Amount > b.Amount
+	}
+	if a.PreviousAmount != b.PreviousAmount {
+		return a.PreviousAmount > b.PreviousAmount
+	}
+	return nameA < nameB
+}
+
+// summaryBuckets lists the start of every bucket overlapping [from, to), empty ones included
+func summaryBuckets(from, to time.Time, groupBy string) []time.Time {
+	var buckets []time.Time
+	for start := bucketStart(from, groupBy); start.Before(to); start = nextBucket(start, groupBy) {
+		buckets = append(buckets, start)
+		if len(buckets) > maxReportBuckets {
+			break
+		}
+	}
+	return buckets
+}
+
+// bucketStart truncates t to its bucket the same way Postgres date_trunc does, weeks start on Monday
+func bucketStart(t time.Time, groupBy string) time.Time {
+	year, month, day := t.Date()
+
+	switch groupBy {
+	case constant.ReportGroupByWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case constant.ReportGroupByMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucket(start time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case constant.ReportGroupByWeek:
+		return start.AddDate(0, 0, 7)
+	case constant.ReportGroupByMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func bucketLabel(start time.Time, groupBy string) string {
+	if groupBy == constant.ReportGroupByMonth {
+		return start.Format("2006-01")
+	}
+	return start.Format(time.DateOnly)
+}
@@ -0,0 +1,458 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// newReportService builds a report service for tests that only exercise the report repository
+func newReportService(ctrl *gomock.Controller, reportRepo domain.ReportRepository) domain.ReportService {
+	return service.NewReportService(reportRepo, mocks.NewMockWalletRepository(ctrl), mocks.NewMockSnapshotRepository(ctrl), mocks.NewMockExchangeRateRepository(ctrl), "USD")
+}
+
+func TestReportServiceSummary(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+
+	unix := func(year int, month time.Month, day int, loc *time.Location) int {
+		return int(time.Date(year, month, day, 0, 0, 0, 0, loc).Unix())
+	}
+	// wallClock mimics how the repository scans a local timestamp without a time zone
+	wallClock := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name         string
+		request      *model.SummaryReportRequest
+		rows         []*domain.SummaryRow
+		wantTimezone string
+		wantLabels   []string
+		wantStart    int
+		wantTotals   model.SummaryTotals
+	}{
+		{
+			name:         "days are filled in and default to UTC",
+			request:      &model.SummaryReportRequest{From: unix(2025, 8, 1, time.UTC), To: unix(2025, 8, 4, time.UTC), GroupBy: "day"},
+			rows:         []*domain.SummaryRow{{Bucket: wallClock(2025, 8, 2), Income: 500, Expense: 200, Count: 3}},
+			wantTimezone: "UTC",
+			wantLabels:   []string{"2025-08-01", "2025-08-02", "2025-08-03"},
+			wantStart:    unix(2025, 8, 1, time.UTC),
+			wantTotals:   model.SummaryTotals{Income: 500, Expense: 200, Net: 300, Count: 3},
+		},
+		{
+			name:         "bucket boundaries follow the timezone",
+			request:      &model.SummaryReportRequest{From: unix(2025, 8, 1, jakarta), To: unix(2025, 8, 3, jakarta), GroupBy: "day", Timezone: "Asia/Jakarta"},
+			rows:         []*domain.SummaryRow{{Bucket: wallClock(2025, 8, 1), Expense: 50, Count: 1}},
+			wantTimezone: "Asia/Jakarta",
+			wantLabels:   []string{"2025-08-01", "2025-08-02"},
+			wantStart:    unix(2025, 8, 1, jakarta),
+			wantTotals:   model.SummaryTotals{Expense: 50, Net: -50, Count: 1},
+		},
+		{
+			name:         "weeks start on Monday",
+			request:      &model.SummaryReportRequest{From: unix(2025, 8, 6, time.UTC), To: unix(2025, 8, 20, time.UTC), GroupBy: "week"},
+			rows:         []*domain.SummaryRow{{Bucket: wallClock(2025, 8, 11), Income: 10, Count: 1}},
+			wantTimezone: "UTC",
+			wantLabels:   []string{"2025-08-04", "2025-08-11", "2025-08-18"},
+			wantStart:    unix(2025, 8, 4, time.UTC),
+			wantTotals:   model.SummaryTotals{Income: 10, Net: 10, Count: 1},
+		},
+		{
+			name:    "months",
+			request: &model.SummaryReportRequest{From: unix(2025, 1, 15, time.UTC), To: unix(2025, 4, 1, time.UTC), GroupBy: "month"},
+			rows: []*domain.SummaryRow{
+				{Bucket: wallClock(2025, 1, 1), Income: 100, Count: 1},
+				{Bucket: wallClock(2025, 3, 1), Expense: 40, Count: 2},
+			},
+			wantTimezone: "UTC",
+			wantLabels:   []string{"2025-01", "2025-02", "2025-03"},
+			wantStart:    unix(2025, 1, 1, time.UTC),
+			wantTotals:   model.SummaryTotals{Income: 100, Expense: 40, Net: 60, Count: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reportRepo := mocks.NewMockReportRepository(ctrl)
+
+			reportRepo.EXPECT().Summary(gomock.Any(), "user", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, filter domain.SummaryFilter) ([]*domain.SummaryRow, error) {
+					if filter.Timezone != tt.wantTimezone || filter.From != tt.request.From || filter.To != tt.request.To {
+						t.Errorf("unexpected filter %+v", filter)
+					}
+					return tt.rows, nil
+				},
+			)
+
+			report, err := newReportService(ctrl, reportRepo).Summary(context.Background(), "user", tt.request)
+			if err != nil {
+				t.Fatalf("Summary() error = %v", err)
+			}
+
+			if len(report.Buckets) != len(tt.wantLabels) {
+				t.Fatalf("Summary() returned %d buckets, want %d: %+v", len(report.Buckets), len(tt.wantLabels), report.Buckets)
+			}
+			for i, label := range tt.wantLabels {
+				if report.Buckets[i].Label != label {
+					t.Errorf("bucket %d label = %q, want %q", i, report.Buckets[i].Label, label)
+				}
+				if i > 0 && report.Buckets[i].Start != report.Buckets[i-1].End {
+					t.Errorf("bucket %d does not start where the previous one ends", i)
+				}
+			}
+			if report.Buckets[0].Start != tt.wantStart {
+				t.Errorf("first bucket starts at %d, want %d", report.Buckets[0].Start, tt.wantStart)
+			}
+			if report.Totals != tt.wantTotals {
+				t.Errorf("totals = %+v, want %+v", report.Totals, tt.wantTotals)
+			}
+		})
+	}
+}
+
+func TestReportServiceSummaryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *model.SummaryReportRequest
+		repoErr  error
+		wantErr  error
+		wantRepo bool
+	}{
+		{
+			name:    "too many buckets",
+			request: &model.SummaryReportRequest{From: 0, To: 2000000000, GroupBy: "day"},
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "unknown timezone",
+			request: &model.SummaryReportRequest{From: 0, To: 86400, GroupBy: "day", Timezone: "Mars/Olympus"},
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:     "repository failure",
+			request:  &model.SummaryReportRequest{From: 0, To: 86400, GroupBy: "day"},
+			repoErr:  errDB,
+			wantErr:  errDB,
+			wantRepo: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reportRepo := mocks.NewMockReportRepository(ctrl)
+			if tt.wantRepo {
+				reportRepo.EXPECT().Summary(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tt.repoErr)
+			}
+
+			_, err := newReportService(ctrl, reportRepo).Summary(context.Background(), "user", tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Summary() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReportServiceCategories(t *testing.T) {
+	food := uuid.New()
+	groceries := uuid.New()
+	rent := uuid.New()
+
+	current := []*domain.BudgetSpendRow{
+		{BudgetID: &food, BudgetName: "Eating out", Category: "food", Amount: 300, Count: 3},
+		{BudgetID: &groceries, BudgetName: "Groceries", Category: "food", Amount: 100, Count: 1},
+		{BudgetID: nil, Amount: 100, Count: 2},
+	}
+	previous := []*domain.BudgetSpendRow{
+		{BudgetID: &food, BudgetName: "Eating out", Category: "food", Amount: 200, Count: 2},
+		{BudgetID: &rent, BudgetName: "Rent", Category: "housing", Amount: 250, Count: 1},
+	}
+
+	ctrl := gomock.NewController(t)
+	reportRepo := mocks.NewMockReportRepository(ctrl)
+	reportRepo.EXPECT().SpendByBudget(gomock.Any(), "user", domain.SpendFilter{From: 1000, To: 2000}).Return(current, nil)
+	reportRepo.EXPECT().SpendByBudget(gomock.Any(), "user", domain.SpendFilter{From: 0, To: 1000}).Return(previous, nil)
+	vacation := uuid.New()
+	reimbursable := uuid.New()
+	reportRepo.EXPECT().SpendByTag(gomock.Any(), "user", domain.SpendFilter{From: 1000, To: 2000}).Return([]*domain.TagSpendRow{
+		{TagID: reimbursable, Name: "reimbursable", Amount: 100, Count: 1},
+		{TagID: vacation, Name: "vacation-2026", Amount: 300, Count: 3},
+	}, nil)
+	reportRepo.EXPECT().SpendByTag(gomock.Any(), "user", domain.SpendFilter{From: 0, To: 1000}).Return([]*domain.TagSpendRow{
+		{TagID: reimbursable, Name: "reimbursable", Amount: 200, Count: 2},
+	}, nil)
+	reportRepo.EXPECT().TopNotes(gomock.Any(), "user", domain.SpendFilter{From: 1000, To: 2000}, 5).Return([]*domain.NoteSpendRow{{Note: "Coffee", Amount: 50, Count: 2}}, nil)
+
+	report, err := newReportService(ctrl, reportRepo).Categories(context.Background(), "user", &model.CategoryReportRequest{From: 1000, To: 2000})
+	if err != nil {
+		t.Fatalf("Categories() error = %v", err)
+	}
+
+	if report.PreviousFrom != 0 || report.PreviousTo != 1000 {
+		t.Errorf("previous period = [%d, %d), want [0, 1000)", report.PreviousFrom, report.PreviousTo)
+	}
+	if report.Total.Amount != 500 || report.Total.PreviousAmount != 450 || report.Total.Change != 50 || *report.Total.ChangePercent != 11.11 {
+		t.Errorf("total = %+v", report.Total)
+	}
+
+	wantCategories := []struct {
+		category      string
+		amount        float64
+		share         float64
+		changePercent *float64
+		budgets       []string
+	}{
+		{category: "food", amount: 400, share: 80, changePercent: ptr(100.0), budgets: []string{"Eating out", "Groceries"}},
+		{category: "uncategorized", amount: 100, share: 20, budgets: []string{""}},
+		{category: "housing", amount: 0, share: 0, changePercent: ptr(-100.0), budgets: []string{"Rent"}},
+	}
+	if len(report.Categories) != len(wantCategories) {
+		t.Fatalf("Categories() returned %d categories, want %d: %+v", len(report.Categories), len(wantCategories), report.Categories)
+	}
+	for i, want := range wantCategories {
+		got := report.Categories[i]
+		if got.Category != want.category || got.Amount != want.amount || got.Share != want.share {
+			t.Errorf("category %d = %+v, want %+v", i, got, want)
+		}
+		if (got.ChangePercent == nil) != (want.changePercent == nil) || (got.ChangePercent != nil && *got.ChangePercent != *want.changePercent) {
+			t.Errorf("category %s change percent = %v, want %v", got.Category, got.ChangePercent, want.changePercent)
+		}
+		if len(got.Budgets) != len(want.budgets) {
+			t.Fatalf("category %s has %d budgets, want %d", got.Category, len(got.Budgets), len(want.budgets))
+		}
+		for j, name := range want.budgets {
+			if got.Budgets[j].Name != name {
+				t.Errorf("category %s budget %d = %q, want %q", got.Category, j, got.Budgets[j].Name, name)
+			}
+		}
+	}
+
+	if food := report.Categories[0]; food.Budgets[0].Share != 75 || food.Budgets[1].Share != 25 {
+		t.Errorf("budget shares = %v and %v, want 75 and 25", food.Budgets[0].Share, food.Budgets[1].Share)
+	}
+	// Tags are sorted by spend like categories and compared with the previous period
+	if len(report.Tags) != 2 {
+		t.Fatalf("Categories() returned %d tags, want 2: %+v", len(report.Tags), report.Tags)
+	}
+	if got := report.Tags[0]; got.Name != "vacation-2026" || got.Amount != 300 || got.Share != 60 || got.ChangePercent != nil {
+		t.Errorf("first tag = %+v, want vacation-2026 with 300 and no previous spend", got)
+	}
+	if got := report.Tags[1]; got.Name != "reimbursable" || got.Change != -100 || *got.ChangePercent != -50 {
+		t.Errorf("second tag = %+v, want reimbursable halved", got)
+	}
+	if len(report.TopNotes) != 1 || report.TopNotes[0].Share != 10 {
+		t.Errorf("top notes = %+v", report.TopNotes)
+	}
+}
+
+func TestReportServiceCategoriesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(reportRepo *mocks.MockReportRepository)
+	}{
+		{
+			name: "current period failure",
+			setup: func(reportRepo *mocks.MockReportRepository) {
+				reportRepo.EXPECT().SpendByBudget(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+		},
+		{
+			name: "previous period failure",
+			setup: func(reportRepo *mocks.MockReportRepository) {
+				reportRepo.EXPECT().SpendByBudget(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				reportRepo.EXPECT().SpendByBudget(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+		},
+		{
+			name: "tag failure",
+			setup: func(reportRepo *mocks.MockReportRepository) {
+				reportRepo.EXPECT().SpendByBudget(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				reportRepo.EXPECT().SpendByTag(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+		},
+		{
+			name: "top notes failure",
+			setup: func(reportRepo *mocks.MockReportRepository) {
+				reportRepo.EXPECT().SpendByBudget(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				reportRepo.EXPECT().SpendByTag(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				reportRepo.EXPECT().TopNotes(gomock.Any(), gomock.Any(), gomock.Any(), 3).Return(nil, errDB)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reportRepo := mocks.NewMockReportRepository(ctrl)
+			tt.setup(reportRepo)
+
+			_, err := newReportService(ctrl, reportRepo).Categories(context.Background(), "user", &model.CategoryReportRequest{From: 1000, To: 2000, Top: 3})
+			if !errors.Is(err, errDB) {
+				t.Fatalf("Categories() error = %v, want %v", err, errDB)
+			}
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestReportServiceNetWorth(t *testing.T) {
+	day := func(d int) int {
+		return int(time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC).Unix())
+	}
+
+	checking := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "USD", Balance: 999}
+	savings := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "EUR", Balance: 999}
+	card := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypeCredit, Currency: "USD", Balance: 999}
+	pesos := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "MXN", Balance: 999}
+
+	snapshots := []*domain.WalletSnapshot{
+		{WalletID: checking.ID, SnapshotDate: day(1), Balance: 1000},
+		{WalletID: card.ID, SnapshotDate: day(1), Balance: 200},
+		{WalletID: savings.ID, SnapshotDate: day(2), Balance: 100},
+		{WalletID: pesos.ID, SnapshotDate: day(2), Balance: 500},
+		{WalletID: checking.ID, SnapshotDate: day(3), Balance: 1200},
+	}
+	rates := []*domain.ExchangeRate{
+		{Currency: "EUR", RateDate: day(1), Rate: 1.1},
+		{Currency: "EUR", RateDate: day(3), Rate: 1.2},
+	}
+
+	ctrl := gomock.NewController(t)
+	walletRepo := mocks.NewMockWalletRepository(ctrl)
+	snapshotRepo := mocks.NewMockSnapshotRepository(ctrl)
+	rateRepo := mocks.NewMockExchangeRateRepository(ctrl)
+
+	walletRepo.EXPECT().GetList(gomock.Any(), "user").Return([]*domain.Wallet{checking, savings, card, pesos}, nil)
+	snapshotRepo.EXPECT().GetRange(gomock.Any(), "user", day(1), day(4)).Return(snapshots, nil)
+	rateRepo.EXPECT().GetUntil(gomock.Any(), []string{"EUR", "MXN"}, day(4)).Return(rates, nil)
+
+	reportService := service.NewReportService(mocks.NewMockReportRepository(ctrl), walletRepo, snapshotRepo, rateRepo, "USD")
+	report, err := reportService.NetWorth(context.Background(), "user", &model.NetWorthReportRequest{From: day(1) + 3600, To: day(4)})
+	if err != nil {
+		t.Fatalf("NetWorth() error = %v", err)
+	}
+
+	want := []model.NetWorthPoint{
+		{Date: day(1), Label: "2025-03-01", Assets: 1000, Liabilities: 200, NetWorth: 800},
+		{Date: day(2), Label: "2025-03-02", Assets: 1110, Liabilities: 200, NetWorth: 910},
+		{Date: day(3), Label: "2025-03-03", Assets: 1320, Liabilities: 200, NetWorth: 1120},
+	}
+	if len(report.Points) != len(want) {
+		t.Fatalf("NetWorth() returned %d points, want %d: %+v", len(report.Points), len(want), report.Points)
+	}
+	for i := range want {
+		if report.Points[i] != want[i] {
+			t.Errorf("point %d = %+v, want %+v", i, report.Points[i], want[i])
+		}
+	}
+	if report.Currency != "USD" || len(report.MissingRates) != 1 || report.MissingRates[0] != "MXN" {
+		t.Errorf("currency = %s, missing rates = %v, want USD and [MXN]", report.Currency, report.MissingRates)
+	}
+}
+
+func TestReportServiceNetWorthUsesLiveBalanceToday(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	wallet := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Currency: "USD", Balance: 750}
+
+	ctrl := gomock.NewController(t)
+	walletRepo := mocks.NewMockWalletRepository(ctrl)
+	snapshotRepo := mocks.NewMockSnapshotRepository(ctrl)
+	rateRepo := mocks.NewMockExchangeRateRepository(ctrl)
+
+	walletRepo.EXPECT().GetList(gomock.Any(), "user").Return([]*domain.Wallet{wallet}, nil)
+	snapshotRepo.EXPECT().GetRange(gomock.Any(), "user", gomock.Any(), gomock.Any()).Return([]*domain.WalletSnapshot{
+		{WalletID: wallet.ID, SnapshotDate: int(today.AddDate(0, 0, -1).Unix()), Balance: 500},
+	}, nil)
+	rateRepo.EXPECT().GetUntil(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	reportService := service.NewReportService(mocks.NewMockReportRepository(ctrl), walletRepo, snapshotRepo, rateRepo, "USD")
+	report, err := reportService.NetWorth(context.Background(), "user", &model.NetWorthReportRequest{
+		From: int(today.AddDate(0, 0, -1).Unix()),
+		To:   int(today.AddDate(0, 0, 1).Unix()),
+	})
+	if err != nil {
+		t.Fatalf("NetWorth() error = %v", err)
+	}
+
+	if len(report.Points) != 2 || report.Points[0].NetWorth != 500 || report.Points[1].NetWorth != 750 {
+		t.Fatalf("points = %+v, want yesterday's snapshot then today's live balance", report.Points)
+	}
+}
+
+func TestReportServiceNetWorthErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *model.NetWorthReportRequest
+		setup   func(walletRepo *mocks.MockWalletRepository, snapshotRepo *mocks.MockSnapshotRepository, rateRepo *mocks.MockExchangeRateRepository)
+		wantErr error
+	}{
+		{
+			name:    "too many days",
+			request: &model.NetWorthReportRequest{From: 0, To: 2000000000},
+			setup: func(walletRepo *mocks.MockWalletRepository, snapshotRepo *mocks.MockSnapshotRepository, rateRepo *mocks.MockExchangeRateRepository) {
+			},
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name:    "wallet failure",
+			request: &model.NetWorthReportRequest{From: 0, To: 86400},
+			setup: func(walletRepo *mocks.MockWalletRepository, snapshotRepo *mocks.MockSnapshotRepository, rateRepo *mocks.MockExchangeRateRepository) {
+				walletRepo.EXPECT().GetList(gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:    "snapshot failure",
+			request: &model.NetWorthReportRequest{From: 0, To: 86400},
+			setup: func(walletRepo *mocks.MockWalletRepository, snapshotRepo *mocks.MockSnapshotRepository, rateRepo *mocks.MockExchangeRateRepository) {
+				walletRepo.EXPECT().GetList(gomock.Any(), gomock.Any()).Return(nil, nil)
+				snapshotRepo.EXPECT().GetRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:    "rate failure",
+			request: &model.NetWorthReportRequest{From: 0, To: 86400},
+			setup: func(walletRepo *mocks.MockWalletRepository, snapshotRepo *mocks.MockSnapshotRepository, rateRepo *mocks.MockExchangeRateRepository) {
+				walletRepo.EXPECT().GetList(gomock.Any(), gomock.Any()).Return(nil, nil)
+				snapshotRepo.EXPECT().GetRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				rateRepo.EXPECT().GetUntil(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			walletRepo := mocks.NewMockWalletRepository(ctrl)
+			snapshotRepo := mocks.NewMockSnapshotRepository(ctrl)
+			rateRepo := mocks.NewMockExchangeRateRepository(ctrl)
+			tt.setup(walletRepo, snapshotRepo, rateRepo)
+
+			reportService := service.NewReportService(mocks.NewMockReportRepository(ctrl), walletRepo, snapshotRepo, rateRepo, "USD")
+			_, err := reportService.NetWorth(context.Background(), "user", tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NetWorth() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"finance-backend/pkg/validator"
+	"time"
+)
+
+type snapshotService struct {
+	txManager domain.TxManager
+
+	snapshotRepo    domain.SnapshotRepository
+	rateRepo        domain.ExchangeRateRepository
+	walletRepo      domain.WalletRepository
+	transactionRepo domain.TransactionRepository
+}
+
+func NewSnapshotService(txManager domain.TxManager, snapshotRepo domain.SnapshotRepository, rateRepo domain.ExchangeRateRepository, walletRepo domain.WalletRepository, transactionRepo domain.TransactionRepository) domain.SnapshotService {
+	return &snapshotService{
+		txManager:       txManager,
+		snapshotRepo:    snapshotRepo,
+		rateRepo:        rateRepo,
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+func (s *snapshotService) Capture(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "snapshotService.Capture")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	// A snapshot is the balance at the end of its day, so the current balance closes the day that
+	// ended at the last midnight
+	closed := utcDay(now).AddDate(0, 0, -1)
+
+	captured, err := s.snapshotRepo.Capture(ctx, int(closed.Unix()))
+	if err != nil {
+		log.WithError(err).Error("[service - snapshot - Capture]: Failed to capture wallet balances")
+		return 0, err
+	}
+
+	return captured, nil
+}
+
+func (s *snapshotService) Rebuild(ctx context.Context, from time.Time, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "snapshotService.Rebuild")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	first, today := utcDay(from), utcDay(now)
+	if first.After(today) {
+		return 0, apperror.ErrBadRequest.WithMessage("rebuild must start in the past")
+	}
+
+	wallets, err := s.walletRepo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("[service - snapshot - Rebuild]: Failed to get wallets")
+		return 0, err
+	}
+
+	rebuilt := 0
+	for _, wallet := range wallets {
+		start := first
+		if created := utcDay(time.Unix(int64(wallet.CreatedAt), 0)); created.After(start) {
+			start = created
+		}
+		if start.After(today) {
+			continue
+		}
+
+		// Only transactions after the first day change the balances being rebuilt
+		transactions, err := s.transactionRepo.GetByWalletSince(ctx, wallet.ID.String(), int(start.AddDate(0, 0, 1).Unix()))
+		if err != nil {
+			log.WithError(err).Error("[service - snapshot - Rebuild]: Failed to get wallet transactions")
+			return rebuilt, err
+		}
+
+		snapshots := replayBalances(wallet, transactions, start, today)
+
+		err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			return s.snapshotRepo.Upsert(ctx, snapshots)
+		})
+		if err != nil {
+			log.WithError(err).Error("[service - snapshot - Rebuild]: Failed to save snapshots")
+			return rebuilt, err
+		}
+
+		rebuilt += len(snapshots)
+	}
+
+	return rebuilt, nil
+}
+
+func (s *snapshotService) SetRate(ctx context.Context, currency string, date time.Time, rate float64) error {
+	ctx, span := tracing.Start(ctx, "snapshotService.SetRate")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	if !validator.IsCurrency(currency) {
+		return apperror.ErrBadRequest.WithMessage("currency must be a 3-letter uppercase code")
+	}
+	if rate <= 0 {
+		return apperror.ErrBadRequest.WithMessage("rate must be positive")
+	}
+
+	err := s.rateRepo.Upsert(ctx, &domain.ExchangeRate{
+		Currency: currency,
+		RateDate: int(utcDay(date).Unix()),
+		Rate:     rate,
+	})
+	if err != nil {
+		log.WithError(err).Error("[service - snapshot - SetRate]: Failed to save exchange rate")
+		return err
+	}
+
+	return nil
+}
+
+// replayBalances walks back from the current balance, undoing newest-first transactions,
+// and returns the end of day balance for every day from start to today
+func replayBalances(wallet *domain.Wallet, transactions []*domain.Transaction, start, today time.Time) []*domain.WalletSnapshot {
+	var snapshots []*domain.WalletSnapshot
+
+	balance := wallet.Balance
+	next := 0
+	for day := today; !day.Before(start); day = day.AddDate(0, 0, -1) {
+		end := int(day.AddDate(0, 0, 1).Unix())
+		for next < len(transactions) && transactions[next].TransactionDate >= end {
+			balance -= balanceEffect(wallet, transactions[next].Type, transactions[next].Amount)
+			next++
+		}
+
+		snapshots = append(snapshots, &domain.WalletSnapshot{
+			WalletID:     wallet.ID,
+			SnapshotDate: int(day.Unix()),
+			Balance:      roundCents(balance),
+		})
+	}
+
+	return snapshots
+}
+
+// utcDay truncates t to the start of its UTC day, the granularity of balance snapshots
+func utcDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+type snapshotMocks struct {
+	snapshots    *mocks.MockSnapshotRepository
+	rates        *mocks.MockExchangeRateRepository
+	wallets      *mocks.MockWalletRepository
+	transactions *mocks.MockTransactionRepository
+}
+
+func newSnapshotService(ctrl *gomock.Controller) (domain.SnapshotService, snapshotMocks, *txOutcome) {
+	txManager, outcome := newTxManager(ctrl)
+	m := snapshotMocks{
+		snapshots:    mocks.NewMockSnapshotRepository(ctrl),
+		rates:        mocks.NewMockExchangeRateRepository(ctrl),
+		wallets:      mocks.NewMockWalletRepository(ctrl),
+		transactions: mocks.NewMockTransactionRepository(ctrl),
+	}
+	return service.NewSnapshotService(txManager, m.snapshots, m.rates, m.wallets, m.transactions), m, outcome
+}
+
+func march(day, hour int) time.Time {
+	return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestSnapshotServiceCapture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	snapshotService, m, _ := newSnapshotService(ctrl)
+
+	// Just after midnight the current balances are the end of the day that closed
+	m.snapshots.EXPECT().Capture(gomock.Any(), int(march(5, 0).Unix())).Return(int64(3), nil)
+
+	captured, err := snapshotService.Capture(context.Background(), march(6, 0).Add(time.Minute))
+	if err != nil || captured != 3 {
+		t.Fatalf("Capture() = %d, %v, want 3 snapshots", captured, err)
+	}
+}
+
+func TestSnapshotServiceRebuild(t *testing.T) {
+	checking := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypePersonal, Balance: 1000, CreatedAt: int(march(1, 9).Unix())}
+	card := &domain.Wallet{ID: uuid.New(), Type: constant.WalletTypeCredit, Balance: 300, CreatedAt: int(march(3, 9).Unix())}
+
+	ctrl := gomock.NewController(t)
+	snapshotService, m, outcome := newSnapshotService(ctrl)
+
+	m.wallets.EXPECT().GetAll(gomock.Any()).Return([]*domain.Wallet{checking, card}, nil)
+	m.transactions.EXPECT().GetByWalletSince(gomock.Any(), checking.ID.String(), int(march(3, 0).Unix())).Return([]*domain.Transaction{
+		{Amount: 50, Type: constant.TransactionTypeExpense, TransactionDate: int(march(4, 12).Unix())},
+		{Amount: 200, Type: constant.TransactionTypeIncome, TransactionDate: int(march(3, 8).Unix())},
+	}, nil)
+	// The card was created after the requested start, its history begins on the day it was opened
+	m.transactions.EXPECT().GetByWalletSince(gomock.Any(), card.ID.String(), int(march(4, 0).Unix())).Return([]*domain.Transaction{
+		{Amount: 100, Type: constant.TransactionTypeExpense, TransactionDate: int(march(4, 20).Unix())},
+	}, nil)
+
+	saved := make(map[uuid.UUID]map[string]float64)
+	m.snapshots.EXPECT().Upsert(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, snapshots []*domain.WalletSnapshot) error {
+			for _, snapshot := range snapshots {
+				if saved[snapshot.WalletID] == nil {
+					saved[snapshot.WalletID] = make(map[string]float64)
+				}
+				saved[snapshot.WalletID][time.Unix(int64(snapshot.SnapshotDate), 0).UTC().Format(time.DateOnly)] = snapshot.Balance
+			}
+			return nil
+		},
+	)
+
+	rebuilt, err := snapshotService.Rebuild(context.Background(), march(2, 15), march(4, 23))
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	if rebuilt != 5 {
+		t.Fatalf("Rebuild() wrote %d snapshots, want 5", rebuilt)
+	}
+
+	want := map[uuid.UUID]map[string]float64{
+		checking.ID: {"2025-03-02": 850, "2025-03-03": 1050, "2025-03-04": 1000},
+		card.ID:     {"2025-03-03": 200, "2025-03-04": 300},
+	}
+	for walletId, days := range want {
+		for date, balance := range days {
+			if got, ok := saved[walletId][date]; !ok || got != balance {
+				t.Errorf("wallet %s on %s = %v, want %v", walletId, date, got, balance)
+			}
+		}
+	}
+	outcome.assert(t, 2, 0)
+}
+
+func TestSnapshotServiceRebuildErrors(t *testing.T) {
+	wallet := &domain.Wallet{ID: uuid.New(), Balance: 100}
+
+	tests := []struct {
+		name          string
+		from          time.Time
+		setup         func(m snapshotMocks)
+		wantErr       error
+		wantRollbacks int
+	}{
+		{
+			name:    "start in the future",
+			from:    march(9, 0),
+			setup:   func(m snapshotMocks) {},
+			wantErr: apperror.ErrBadRequest,
+		},
+		{
+			name: "wallet failure",
+			from: march(1, 0),
+			setup: func(m snapshotMocks) {
+				m.wallets.EXPECT().GetAll(gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "transaction failure",
+			from: march(1, 0),
+			setup: func(m snapshotMocks) {
+				m.wallets.EXPECT().GetAll(gomock.Any()).Return([]*domain.Wallet{wallet}, nil)
+				m.transactions.EXPECT().GetByWalletSince(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "save failure rolls back",
+			from: march(1, 0),
+			setup: func(m snapshotMocks) {
+				m.wallets.EXPECT().GetAll(gomock.Any()).Return([]*domain.Wallet{wallet}, nil)
+				m.transactions.EXPECT().GetByWalletSince(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				m.snapshots.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			snapshotService, m, outcome := newSnapshotService(ctrl)
+			tt.setup(m)
+
+			_, err := snapshotService.Rebuild(context.Background(), tt.from, march(4, 0))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rebuild() error = %v, want %v", err, tt.wantErr)
+			}
+			outcome.assert(t, 0, tt.wantRollbacks)
+		})
+	}
+}
+
+func TestSnapshotServiceSetRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		rate     float64
+		wantErr  error
+	}{
+		{name: "saved", currency: "EUR", rate: 1.08},
+		{name: "lowercase currency", currency: "eur", rate: 1.08, wantErr: apperror.ErrBadRequest},
+		{name: "zero rate", currency: "EUR", rate: 0, wantErr: apperror.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			snapshotService, m, _ := newSnapshotService(ctrl)
+			if tt.wantErr == nil {
+				m.rates.EXPECT().Upsert(gomock.Any(), &domain.ExchangeRate{Currency: "EUR", RateDate: int(march(5, 0).Unix()), Rate: 1.08}).Return(nil)
+			}
+
+			err := snapshotService.SetRate(context.Background(), tt.currency, march(5, 13), tt.rate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetRate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type tagService struct {
+	txManager domain.TxManager
+
+	tagRepo         domain.TagRepository
+	transactionRepo domain.TransactionRepository
+}
+
+func NewTagService(txManager domain.TxManager, tagRepo domain.TagRepository, transactionRepo domain.TransactionRepository) domain.TagService {
+	return &tagService{
+		txManager:       txManager,
+		tagRepo:         tagRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+func (s *tagService) Create(ctx context.Context, userId string, request *model.CreateTagRequest) (*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "tagService.Create")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	name, err := tagName(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &domain.Tag{
+		UserID: uuid.MustParse(userId),
+		Name:   name,
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkName(ctx, userId, "", name); err != nil {
+			return err
+		}
+
+		if err := s.tagRepo.Create(ctx, tag); err != nil {
+			log.WithError(err).Error("[service - tag - Create]: Failed to create tag")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *tagService) GetList(ctx context.Context, userId string, request *model.TagListRequest) ([]*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "tagService.GetList")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = constant.TagDefaultSuggestions
+	}
+
+	tags, err := s.tagRepo.GetList(ctx, userId, strings.ToLower(strings.TrimSpace(request.Prefix)), limit)
+	if err != nil {
+		log.WithError(err).Error("[service - tag - GetList]: Failed to get tag list")
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *tagService) GetDetail(ctx context.Context, userId string, tagId string) (*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "tagService.GetDetail")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	tag, err := s.tagRepo.GetDetail(ctx, userId, tagId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("tag not found")
+		}
+		log.WithError(err).Error("[service - tag - GetDetail]: Failed to get tag")
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *tagService) Update(ctx context.Context, userId string, tagId string, request *model.UpdateTagRequest) (*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "tagService.Update")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	name, err := tagName(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag *domain.Tag
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.GetDetail(ctx, userId, tagId)
+		if err != nil {
+			return err
+		}
+		if err := s.checkName(ctx, userId, tagId, name); err != nil {
+			return err
+		}
+
+		current.Name = name
+		if err := s.tagRepo.Update(ctx, current); err != nil {
+			log.WithError(err).Error("[service - tag - Update]: Failed to update tag")
+			return err
+		}
+
+		tag = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *tagService) Delete(ctx context.Context, userId string, tagId string) error {
+	ctx, span := tracing.Start(ctx, "tagService.Delete")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		tag, err := s.GetDetail(ctx, userId, tagId)
+		if err != nil {
+			return err
+		}
+
+		if err := s.tagRepo.Delete(ctx, tag); err != nil {
+			log.WithError(err).Error("[service - tag - Delete]: Failed to delete tag")
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *tagService) Apply(ctx context.Context, userId string, request *model.TagTransactionsRequest) (*model.TagTransactionsResult, error) {
+	ctx, span := tracing.Start(ctx, "tagService.Apply")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	transactionIds := distinct(request.TransactionIDs)
+	add := distinct(request.Add)
+	remove := distinct(request.Remove)
+
+	for _, id := range remove {
+		if slices.Contains(add, id) {
+			return nil, apperror.NewValidation([]model.FieldError{{
+				Field:   "remove",
+				Rule:    "excluded_with",
+				Message: "cannot remove a tag that is added in the same request",
+			}})
+		}
+	}
+
+	result := &model.TagTransactionsResult{}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		transactions, err := s.transactionRepo.GetByIDs(ctx, userId, transactionIds)
+		if err != nil {
+			log.WithError(err).Error("[service - tag - Apply]: Failed to get transactions")
+			return err
+		}
+		if len(transactions) != len(transactionIds) {
+			return apperror.ErrNotFound.WithMessage("transaction not found")
+		}
+
+		tags, err := s.tagRepo.GetByIDs(ctx, userId, append(append([]string{}, add...), remove...))
+		if err != nil {
+			log.WithError(err).Error("[service - tag - Apply]: Failed to get tags")
+			return err
+		}
+		if len(tags) != len(add)+len(remove) {
+			return apperror.ErrNotFound.WithMessage("tag not found")
+		}
+
+		if result.Added, err = s.tagRepo.Link(ctx, transactionIds, add); err != nil {
+			log.WithError(err).Error("[service - tag - Apply]: Failed to tag transactions")
+			return err
+		}
+		if result.Removed, err = s.tagRepo.Unlink(ctx, transactionIds, remove); err != nil {
+			log.WithError(err).Error("[service - tag - Apply]: Failed to untag transactions")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkName rejects a name another of the user's tags already has, exceptId is the tag being renamed
+func (s *tagService) checkName(ctx context.Context, userId string, exceptId string, name string) error {
+	log := logger.WithRequestID(ctx)
+
+	existing, err := s.tagRepo.GetByName(ctx, userId, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		log.WithError(err).Error("[service - tag - GetByName]: Failed to check tag name")
+		return err
+	}
+
+	if existing.ID.String() != exceptId {
+		return apperror.ErrConflict.WithMessage("tag already exists")
+	}
+	return nil
+}
+
+// tagName normalises a tag name, tags are compared lowercase and without surrounding spaces
+func tagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", apperror.NewValidation([]model.FieldError{{Field: "name", Rule: "required", Message: "is required"}})
+	}
+	return name, nil
+}
+
+// distinct drops repeated ids, comparing them case-insensitively, and keeps the first order
+func distinct(ids []string) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.ToLower(id)
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
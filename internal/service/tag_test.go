@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestTagServiceCreate(t *testing.T) {
+	userId := uuid.NewString()
+
+	tests := []struct {
+		name          string
+		request       *model.CreateTagRequest
+		existing      *domain.Tag
+		wantName      string
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{name: "name is normalised", request: &model.CreateTagRequest{Name: "  Vacation-2026 "}, wantName: "vacation-2026", wantCommits: 1},
+		{name: "name taken in another case", request: &model.CreateTagRequest{Name: "Reimbursable"}, existing: &domain.Tag{ID: uuid.New()}, wantErr: apperror.ErrConflict, wantRollbacks: 1},
+		{name: "blank name", request: &model.CreateTagRequest{Name: "   "}, wantErr: apperror.NewValidation(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			tagRepo := mocks.NewMockTagRepository(ctrl)
+			transactionRepo := mocks.NewMockTransactionRepository(ctrl)
+
+			if tt.wantCommits+tt.wantRollbacks > 0 {
+				if tt.existing != nil {
+					tagRepo.EXPECT().GetByName(gomock.Any(), userId, "reimbursable").Return(tt.existing, nil)
+				} else {
+					tagRepo.EXPECT().GetByName(gomock.Any(), userId, tt.wantName).Return(nil, gorm.ErrRecordNotFound)
+					tagRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				}
+			}
+
+			tag, err := service.NewTagService(txManager, tagRepo, transactionRepo).Create(context.Background(), userId, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (tag.Name != tt.wantName || tag.UserID.String() != userId) {
+				t.Fatalf("Create() = %+v, want %q of the user", tag, tt.wantName)
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
+
+func TestTagServiceUpdate(t *testing.T) {
+	userId := uuid.NewString()
+	tagId := uuid.New()
+
+	tests := []struct {
+		name     string
+		existing *domain.Tag
+		wantErr  error
+	}{
+		{name: "renamed", existing: nil},
+		{name: "case change keeps its own name", existing: &domain.Tag{ID: tagId}},
+		{name: "name of another tag", existing: &domain.Tag{ID: uuid.New()}, wantErr: apperror.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, _ := newTxManager(ctrl)
+			tagRepo := mocks.NewMockTagRepository(ctrl)
+			transactionRepo := mocks.NewMockTransactionRepository(ctrl)
+
+			tagRepo.EXPECT().GetDetail(gomock.Any(), userId, tagId.String()).Return(&domain.Tag{ID: tagId, Name: "trip"}, nil)
+			if tt.existing != nil {
+				tagRepo.EXPECT().GetByName(gomock.Any(), userId, "travel").Return(tt.existing, nil)
+			} else {
+				tagRepo.EXPECT().GetByName(gomock.Any(), userId, "travel").Return(nil, gorm.ErrRecordNotFound)
+			}
+			if tt.wantErr == nil {
+				tagRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			tag, err := service.NewTagService(txManager, tagRepo, transactionRepo).Update(context.Background(), userId, tagId.String(), &model.UpdateTagRequest{Name: "Travel"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && tag.Name != "travel" {
+				t.Fatalf("Update() name = %q, want travel", tag.Name)
+			}
+		})
+	}
+}
+
+func TestTagServiceGetList(t *testing.T) {
+	userId := uuid.NewString()
+
+	ctrl := gomock.NewController(t)
+	txManager, _ := newTxManager(ctrl)
+	tagRepo := mocks.NewMockTagRepository(ctrl)
+	transactionRepo := mocks.NewMockTransactionRepository(ctrl)
+
+	// Prefixes match lowercase names, and autocomplete stays short without a limit
+	tagRepo.EXPECT().GetList(gomock.Any(), userId, "vac", 10).Return([]*domain.Tag{{Name: "vacation-2026"}}, nil)
+
+	tags, err := service.NewTagService(txManager, tagRepo, transactionRepo).GetList(context.Background(), userId, &model.TagListRequest{Prefix: " Vac"})
+	if err != nil || len(tags) != 1 {
+		t.Fatalf("GetList() = %v, %v, want the one suggestion", tags, err)
+	}
+}
+
+func TestTagServiceApply(t *testing.T) {
+	userId := uuid.NewString()
+	first := uuid.NewString()
+	second := uuid.NewString()
+	vacation := uuid.NewString()
+	reimbursable := uuid.NewString()
+
+	tests := []struct {
+		name          string
+		request       *model.TagTransactionsRequest
+		setup         func(tags *mocks.MockTagRepository, transactions *mocks.MockTransactionRepository)
+		want          *model.TagTransactionsResult
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{
+			name: "tags added and removed together",
+			// Repeated ids count once
+			request: &model.TagTransactionsRequest{TransactionIDs: []string{first, second, first}, Add: []string{vacation}, Remove: []string{reimbursable}},
+			setup: func(tags *mocks.MockTagRepository, transactions *mocks.MockTransactionRepository) {
+				transactions.EXPECT().GetByIDs(gomock.Any(), userId, []string{first, second}).Return([]*domain.Transaction{{}, {}}, nil)
+				tags.EXPECT().GetByIDs(gomock.Any(), userId, []string{vacation, reimbursable}).Return([]*domain.Tag{{}, {}}, nil)
+				tags.EXPECT().Link(gomock.Any(), []string{first, second}, []string{vacation}).Return(int64(2), nil)
+				tags.EXPECT().Unlink(gomock.Any(), []string{first, second}, []string{reimbursable}).Return(int64(1), nil)
+			},
+			want:        &model.TagTransactionsResult{Added: 2, Removed: 1},
+			wantCommits: 1,
+		},
+		{
+			name:    "transaction of another user",
+			request: &model.TagTransactionsRequest{TransactionIDs: []string{first, second}, Add: []string{vacation}},
+			setup: func(tags *mocks.MockTagRepository, transactions *mocks.MockTransactionRepository) {
+				transactions.EXPECT().GetByIDs(gomock.Any(), userId, []string{first, second}).Return([]*domain.Transaction{{}}, nil)
+			},
+			wantErr:       apperror.ErrNotFound,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "tag of another user",
+			request: &model.TagTransactionsRequest{TransactionIDs: []string{first}, Add: []string{vacation}},
+			setup: func(tags *mocks.MockTagRepository, transactions *mocks.MockTransactionRepository) {
+				transactions.EXPECT().GetByIDs(gomock.Any(), userId, []string{first}).Return([]*domain.Transaction{{}}, nil)
+				tags.EXPECT().GetByIDs(gomock.Any(), userId, []string{vacation}).Return(nil, nil)
+			},
+			wantErr:       apperror.ErrNotFound,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "link failure keeps nothing",
+			request: &model.TagTransactionsRequest{TransactionIDs: []string{first}, Add: []string{vacation}},
+			setup: func(tags *mocks.MockTagRepository, transactions *mocks.MockTransactionRepository) {
+				transactions.EXPECT().GetByIDs(gomock.Any(), userId, []string{first}).Return([]*domain.Transaction{{}}, nil)
+				tags.EXPECT().GetByIDs(gomock.Any(), userId, []string{vacation}).Return([]*domain.Tag{{}}, nil)
+				tags.EXPECT().Link(gomock.Any(), []string{first}, []string{vacation}).Return(int64(0), errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "same tag added and removed",
+			request: &model.TagTransactionsRequest{TransactionIDs: []string{first}, Add: []string{vacation}, Remove: []string{vacation}},
+			setup:   func(tags *mocks.MockTagRepository, transactions *mocks.MockTransactionRepository) {},
+			wantErr: apperror.NewValidation(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			tagRepo := mocks.NewMockTagRepository(ctrl)
+			transactionRepo := mocks.NewMockTransactionRepository(ctrl)
+			tt.setup(tagRepo, transactionRepo)
+
+			result, err := service.NewTagService(txManager, tagRepo, transactionRepo).Apply(context.Background(), userId, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want != nil && *result != *tt.want {
+				t.Fatalf("Apply() = %+v, want %+v", result, tt.want)
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
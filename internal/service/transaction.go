@@ -2,92 +2,416 @@ package service
 
 import (
 	"context"
+	"errors"
 	"finance-backend/internal/constant"
 	"finance-backend/internal/domain"
 	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/metrics"
+	"finance-backend/pkg/tracing"
+	"fmt"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type transactionService struct {
-	db *gorm.DB
+	txManager domain.TxManager
 
 	transactionRepo domain.TransactionRepository
 	walletRepo      domain.WalletRepository
+	budgetRepo      domain.BudgetRepository
 }
 
-func NewTransactionService(db *gorm.DB, transactionRepo domain.TransactionRepository, walletRepo domain.WalletRepository) domain.TransactionService {
+func NewTransactionService(txManager domain.TxManager, transactionRepo domain.TransactionRepository, walletRepo domain.WalletRepository, budgetRepo domain.BudgetRepository) domain.TransactionService {
 	return &transactionService{
-		db:              db,
+		txManager:       txManager,
 		transactionRepo: transactionRepo,
 		walletRepo:      walletRepo,
+		budgetRepo:      budgetRepo,
 	}
 }
 
 func (s *transactionService) Create(ctx context.Context, userId string, request *model.CreateTransactionRequest) (*domain.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "transactionService.Create")
+	defer span.End()
+
 	log := logger.WithRequestID(ctx)
 
 	log.Info("[service - transaction - Create]: Creating transaction")
-	tx := s.db.Begin()
 
-	if request.Type == constant.TransactionTypeIncome {
-		if err := s.walletRepo.IncreaseBalance(tx, ctx, request.WalletID, request.Amount); err != nil {
-			log.WithError(err).Error("[service - transaction - IncreaseBalance]: Failed to increase wallet balance")
-			tx.Rollback()
-			return nil, err
+	var transaction *domain.Transaction
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		wallet, err := s.getWallet(ctx, userId, request.WalletID)
+		if err != nil {
+			return err
 		}
-	} else if request.Type == constant.TransactionTypeExpense {
-		if err := s.walletRepo.DecreaseBalance(tx, ctx, request.WalletID, request.Amount); err != nil {
-			log.WithError(err).Error("[service - transaction - DecreaseBalance]: Failed to decrease wallet balance")
-			tx.Rollback()
-			return nil, err
+
+		created, err := s.create(ctx, userId, wallet, request, make(map[string]bool))
+		if err != nil {
+			return err
 		}
+
+		detail, err := s.transactionRepo.GetDetail(ctx, userId, created.ID.String())
+		if err != nil {
+			log.WithError(err).Error("[service - transaction - GetDetail]: Failed to get transaction detail after creation")
+			return err
+		}
+
+		transaction = detail
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	transaction := &domain.Transaction{
-		Amount:          request.Amount,
-		Type:            request.Type,
-		TransactionDate: request.TransactionDate,
-		Note:            request.Note,
-		WalletID:        uuid.MustParse(request.WalletID),
+	metrics.TransactionsCreated.WithLabelValues(transaction.Type).Inc()
+
+	return transaction, nil
+}
+
+func (s *transactionService) CreateBatch(ctx context.Context, userId string, requests []*model.CreateTransactionRequest) ([]*domain.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "transactionService.CreateBatch")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	log.WithField("count", len(requests)).Info("[service - transaction - CreateBatch]: Creating transactions")
+
+	transactions := make([]*domain.Transaction, 0, len(requests))
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		wallets := make(map[string]*domain.Wallet)
+		budgets := make(map[string]bool)
+
+		for _, request := range requests {
+			wallet, ok := wallets[request.WalletID]
+			if !ok {
+				var err error
+				if wallet, err = s.getWallet(ctx, userId, request.WalletID); err != nil {
+					return err
+				}
+				wallets[request.WalletID] = wallet
+			}
+
+			created, err := s.create(ctx, userId, wallet, request, budgets)
+			if err != nil {
+				return err
+			}
+
+			transactions = append(transactions, created)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if request.BudgetID != nil && *request.BudgetID != "" {
-		budgetID := uuid.MustParse(*request.BudgetID)
-		transaction.BudgetID = &budgetID
+	for _, transaction := range transactions {
+		metrics.TransactionsCreated.WithLabelValues(transaction.Type).Inc()
 	}
 
-	if err := s.transactionRepo.Create(tx, ctx, userId, transaction); err != nil {
-		log.WithError(err).Error("[service - transaction - Create]: Failed to create transaction")
+	return transactions, nil
+}
+
+func (s *transactionService) GetList(ctx context.Context, userId string, request *model.TransactionListRequest) ([]*domain.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "transactionService.GetList")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
 
-		tx.Rollback()
+	transactions, err := s.transactionRepo.GetList(ctx, userId, transactionFilter(request))
+	if err != nil {
+		log.WithError(err).Error("[service - transaction - GetList]: Failed to get transaction list")
 		return nil, err
 	}
 
-	transaction, err := s.transactionRepo.GetDetail(tx, ctx, userId, transaction.ID.String())
+	return transactions, nil
+}
+
+func (s *transactionService) GetDetail(ctx context.Context, userId string, transactionId string) (*domain.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "transactionService.GetDetail")
+	defer span.End()
 
+	log := logger.WithRequestID(ctx)
+
+	transaction, err := s.transactionRepo.GetDetail(ctx, userId, transactionId)
 	if err != nil {
-		log.WithError(err).Error("[service - transaction - GetDetail]: Failed to get transaction detail after creation")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("transaction not found")
+		}
+		log.WithError(err).Error("[service - transaction - GetDetail]: Failed to get transaction")
+		return nil, err
+	}
 
-		tx.Rollback()
+	return transaction, nil
+}
+
+func (s *transactionService) Update(ctx context.Context, userId string, transactionId string, version int, request *model.UpdateTransactionRequest) (*domain.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "transactionService.Update")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	log.Info("[service - transaction - Update]: Updating transaction")
+
+	splits, err := splitLines(request.Amount, request.Splits)
+	if err != nil {
 		return nil, err
 	}
 
-	tx.Commit()
+	var transaction *domain.Transaction
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.GetDetail(ctx, userId, transactionId)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, current.Version); err != nil {
+			return err
+		}
+		if err := s.checkBudgets(ctx, userId, request.BudgetID, request.Splits, make(map[string]bool)); err != nil {
+			return err
+		}
+
+		from, err := s.getWallet(ctx, userId, current.WalletID.String())
+		if err != nil {
+			return err
+		}
+		to := from
+		if request.WalletID != current.WalletID.String() {
+			if to, err = s.getWallet(ctx, userId, request.WalletID); err != nil {
+				return err
+			}
+		}
+
+		// Take the old amount off and book the new one, on the same wallet only the difference moves
+		undo := -balanceEffect(from, current.Type, current.Amount)
+		effect := balanceEffect(to, request.Type, request.Amount)
+		if from == to {
+			effect = roundCents(effect + undo)
+		} else if err := s.moveBalance(ctx, from, undo); err != nil {
+			return err
+		}
+		if err := s.moveBalance(ctx, to, effect); err != nil {
+			return err
+		}
+
+		updated := &domain.Transaction{
+			ID:              current.ID,
+			Amount:          request.Amount,
+			Type:            request.Type,
+			TransactionDate: request.TransactionDate,
+			Note:            request.Note,
+			WalletID:        to.ID,
+			Splits:          splits,
+		}
+		if request.BudgetID != nil && *request.BudgetID != "" {
+			budgetID := uuid.MustParse(*request.BudgetID)
+			updated.BudgetID = &budgetID
+		}
+
+		saved, err := s.transactionRepo.Update(ctx, updated, current.Version)
+		if err != nil {
+			log.WithError(err).Error("[service - transaction - Update]: Failed to update transaction")
+			return err
+		}
+		if !saved {
+			return apperror.ErrPreconditionFailed
+		}
+
+		transaction, err = s.GetDetail(ctx, userId, transactionId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return transaction, nil
 }
 
-func (s *transactionService) GetList(ctx context.Context, userId string) ([]*domain.Transaction, error) {
+// getWallet loads the user's wallet a transaction is booked against
+func (s *transactionService) getWallet(ctx context.Context, userId string, walletId string) (*domain.Wallet, error) {
 	log := logger.WithRequestID(ctx)
 
-	transactions, err := s.transactionRepo.GetList(s.db, ctx, userId)
+	wallet, err := s.walletRepo.GetDetail(ctx, userId, walletId)
 	if err != nil {
-		log.WithError(err).Error("[service - transaction - GetList]: Failed to get transaction list")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("wallet not found")
+		}
+		log.WithError(err).Error("[service - transaction - GetDetail]: Failed to get wallet")
 		return nil, err
 	}
 
-	return transactions, nil
+	return wallet, nil
+}
+
+// checkBudgets makes sure the budgets of a transaction and its lines belong to the user, it must run
+// within a unit of work. Budgets in checked are known to be the user's and are not looked up again.
+func (s *transactionService) checkBudgets(ctx context.Context, userId string, budgetId *string, lines []model.TransactionSplitRequest, checked map[string]bool) error {
+	log := logger.WithRequestID(ctx)
+
+	var ids []string
+	if budgetId != nil && *budgetId != "" {
+		ids = append(ids, *budgetId)
+	}
+	for _, line := range lines {
+		if line.BudgetID != nil && *line.BudgetID != "" {
+			ids = append(ids, *line.BudgetID)
+		}
+	}
+
+	for _, id := range distinct(ids) {
+		if checked[id] {
+			continue
+		}
+		if _, err := s.budgetRepo.GetDetail(ctx, userId, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrNotFound.WithMessage("budget not found")
+			}
+			log.WithError(err).Error("[service - transaction - GetDetail]: Failed to get budget")
+			return err
+		}
+		checked[id] = true
+	}
+
+	return nil
+}
+
+// create moves the wallet balance and inserts the transaction, it must run within a unit of work
+func (s *transactionService) create(ctx context.Context, userId string, wallet *domain.Wallet, request *model.CreateTransactionRequest, budgets map[string]bool) (*domain.Transaction, error) {
+	log := logger.WithRequestID(ctx)
+
+	splits, err := splitLines(request.Amount, request.Splits)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBudgets(ctx, userId, request.BudgetID, request.Splits, budgets); err != nil {
+		return nil, err
+	}
+
+	if err := s.moveBalance(ctx, wallet, balanceEffect(wallet, request.Type, request.Amount)); err != nil {
+		return nil, err
+	}
+
+	created := &domain.Transaction{
+		Amount:          request.Amount,
+		Type:            request.Type,
+		TransactionDate: request.TransactionDate,
+		Note:            request.Note,
+		WalletID:        uuid.MustParse(request.WalletID),
+		Splits:          splits,
+	}
+
+	if request.BudgetID != nil && *request.BudgetID != "" {
+		budgetID := uuid.MustParse(*request.BudgetID)
+		created.BudgetID = &budgetID
+	}
+	if request.ExternalID != "" {
+		externalID := request.ExternalID
+		created.ExternalID = &externalID
+	}
+
+	if err := s.transactionRepo.Create(ctx, userId, created); err != nil {
+		log.WithError(err).Error("[service - transaction - Create]: Failed to create transaction")
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// moveBalance adds effect to the wallet balance, it must run within a unit of work. A wallet
+// cannot go below zero, the error rolls the unit of work back.
+func (s *transactionService) moveBalance(ctx context.Context, wallet *domain.Wallet, effect float64) error {
+	log := logger.WithRequestID(ctx)
+
+	walletId := wallet.ID.String()
+
+	switch {
+	case effect > 0:
+		if err := s.walletRepo.IncreaseBalance(ctx, walletId, effect); err != nil {
+			log.WithError(err).Error("[service - transaction - IncreaseBalance]: Failed to increase wallet balance")
+			return err
+		}
+	case effect < 0:
+		moved, err := s.walletRepo.DecreaseBalance(ctx, walletId, -effect)
+		if err != nil {
+			log.WithError(err).Error("[service - transaction - DecreaseBalance]: Failed to decrease wallet balance")
+			return err
+		}
+		if !moved {
+			return insufficientFunds(wallet)
+		}
+	}
+	return nil
+}
+
+// insufficientFunds is the error for a move that would take the wallet below zero. A card or loan
+// cannot be paid past what it owes, the overpayment would be money the books lose track of.
+func insufficientFunds(wallet *domain.Wallet) *apperror.Error {
+	if wallet.IsLiability() {
+		return apperror.ErrInsufficientFunds.WithMessage("amount is more than the wallet owes")
+	}
+	return apperror.ErrInsufficientFunds
+}
+
+// splitLines turns the lines of a split transaction into its splits, they must add up to the amount
+func splitLines(amount float64, lines []model.TransactionSplitRequest) ([]domain.TransactionSplit, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	splits := make([]domain.TransactionSplit, 0, len(lines))
+	total := 0.0
+	for i, line := range lines {
+		split := domain.TransactionSplit{
+			Position: i + 1,
+			Amount:   line.Amount,
+			Note:     line.Note,
+		}
+		if line.BudgetID != nil && *line.BudgetID != "" {
+			budgetID := uuid.MustParse(*line.BudgetID)
+			split.BudgetID = &budgetID
+		}
+
+		total += line.Amount
+		splits = append(splits, split)
+	}
+
+	if roundCents(total) != roundCents(amount) {
+		return nil, apperror.NewValidation([]model.FieldError{{
+			Field:   "splits",
+			Rule:    "sum",
+			Message: fmt.Sprintf("must add up to the amount, the lines total %.2f", roundCents(total)),
+		}})
+	}
+
+	return splits, nil
+}
+
+// transactionFilter converts the query of a transaction list or export into a repository filter
+func transactionFilter(request *model.TransactionListRequest) domain.TransactionFilter {
+	return domain.TransactionFilter{
+		From:     request.From,
+		To:       request.To,
+		Type:     request.Type,
+		WalletID: request.WalletID,
+		BudgetID: request.BudgetID,
+		TagIDs:   distinct(request.TagIDs),
+	}
+}
+
+// balanceEffect returns how a transaction moves the wallet balance, liabilities move the other way
+func balanceEffect(wallet *domain.Wallet, transactionType string, amount float64) float64 {
+	effect := amount
+	if transactionType == constant.TransactionTypeExpense {
+		effect = -amount
+	}
+	if wallet.IsLiability() {
+		effect = -effect
+	}
+	return effect
 }
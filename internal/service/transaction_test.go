@@ -0,0 +1,583 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestTransactionServiceCreate(t *testing.T) {
+	userId := uuid.NewString()
+	walletId := uuid.NewString()
+	budgetId := uuid.NewString()
+
+	tests := []struct {
+		name          string
+		request       *model.CreateTransactionRequest
+		setup         func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository)
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{
+			name:    "income increases the balance",
+			request: &model.CreateTransactionRequest{Amount: 500, Type: constant.TransactionTypeIncome, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), walletId, 500.0).Return(nil)
+				expectCreate(transactions, userId, nil)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "expense decreases the balance and keeps the budget",
+			request: &model.CreateTransactionRequest{Amount: 200, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId, BudgetID: &budgetId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectBudget(budgets, userId, budgetId, nil)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 200.0).Return(true, nil)
+				transactions.EXPECT().Create(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, transaction *domain.Transaction) error {
+						if transaction.BudgetID == nil || transaction.BudgetID.String() != budgetId {
+							t.Errorf("budget id = %v, want %s", transaction.BudgetID, budgetId)
+						}
+						transaction.ID = uuid.New()
+						return nil
+					},
+				)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "expense on a credit wallet increases what is owed",
+			request: &model.CreateTransactionRequest{Amount: 200, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypeCredit)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), walletId, 200.0).Return(nil)
+				expectCreate(transactions, userId, nil)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "income on a loan wallet pays it down",
+			request: &model.CreateTransactionRequest{Amount: 500, Type: constant.TransactionTypeIncome, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypeLoan)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 500.0).Return(true, nil)
+				expectCreate(transactions, userId, nil)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name: "split expense moves the balance once and books every line",
+			request: &model.CreateTransactionRequest{Amount: 150.3, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId, Splits: []model.TransactionSplitRequest{
+				{Amount: 100.1, Note: "Groceries", BudgetID: &budgetId},
+				{Amount: 50.2, Note: "Detergent"},
+			}},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectBudget(budgets, userId, budgetId, nil)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 150.3).Return(true, nil)
+				transactions.EXPECT().Create(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, transaction *domain.Transaction) error {
+						splits := transaction.Splits
+						if transaction.BudgetID != nil || len(splits) != 2 {
+							t.Fatalf("transaction = %+v, want two lines and no budget of its own", transaction)
+						}
+						if splits[0].Position != 1 || splits[0].Amount != 100.1 || splits[0].BudgetID == nil || splits[0].BudgetID.String() != budgetId {
+							t.Errorf("first line = %+v, want the groceries budget", splits[0])
+						}
+						if splits[1].Position != 2 || splits[1].Note != "Detergent" || splits[1].BudgetID != nil {
+							t.Errorf("second line = %+v, want unbudgeted detergent", splits[1])
+						}
+						transaction.ID = uuid.New()
+						return nil
+					},
+				)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name: "split lines that miss the amount",
+			request: &model.CreateTransactionRequest{Amount: 150, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId, Splits: []model.TransactionSplitRequest{
+				{Amount: 100, BudgetID: &budgetId},
+				{Amount: 40},
+			}},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+			},
+			wantErr:       apperror.NewValidation(nil),
+			wantRollbacks: 1,
+		},
+		{
+			name: "split line on a budget of another user",
+			request: &model.CreateTransactionRequest{Amount: 150, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId, Splits: []model.TransactionSplitRequest{
+				{Amount: 100},
+				{Amount: 50, BudgetID: &budgetId},
+			}},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectBudget(budgets, userId, budgetId, gorm.ErrRecordNotFound)
+			},
+			wantErr:       apperror.ErrNotFound,
+			wantRollbacks: 1,
+		},
+		{
+			name: "budget lookup failure",
+			request: &model.CreateTransactionRequest{Amount: 150, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId, Splits: []model.TransactionSplitRequest{
+				{Amount: 100, BudgetID: &budgetId},
+				{Amount: 50, BudgetID: &budgetId},
+			}},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectBudget(budgets, userId, budgetId, errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "wallet of another user",
+			request: &model.CreateTransactionRequest{Amount: 200, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				wallets.EXPECT().GetDetail(gomock.Any(), userId, walletId).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr:       apperror.ErrNotFound,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "balance update failure rolls back",
+			request: &model.CreateTransactionRequest{Amount: 200, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 200.0).Return(false, errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "expense larger than the balance books nothing",
+			request: &model.CreateTransactionRequest{Amount: 200, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 200.0).Return(false, nil)
+			},
+			wantErr:       apperror.ErrInsufficientFunds,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "card paid past what it owes books nothing",
+			request: &model.CreateTransactionRequest{Amount: 300, Type: constant.TransactionTypeIncome, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypeCredit)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 300.0).Return(false, nil)
+			},
+			wantErr:       apperror.ErrInsufficientFunds,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "insert failure rolls back the balance update",
+			request: &model.CreateTransactionRequest{Amount: 500, Type: constant.TransactionTypeIncome, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), walletId, 500.0).Return(nil)
+				expectCreate(transactions, userId, errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "reload failure rolls back",
+			request: &model.CreateTransactionRequest{Amount: 500, Type: constant.TransactionTypeIncome, TransactionDate: 1756450000, WalletID: walletId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), walletId, 500.0).Return(nil)
+				expectCreate(transactions, userId, nil)
+				expectDetail(transactions, userId, errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			transactions := mocks.NewMockTransactionRepository(ctrl)
+			wallets := mocks.NewMockWalletRepository(ctrl)
+			budgets := mocks.NewMockBudgetRepository(ctrl)
+			tt.setup(transactions, wallets, budgets)
+
+			transactionService := service.NewTransactionService(txManager, transactions, wallets, budgets)
+			transaction, err := transactionService.Create(context.Background(), userId, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && transaction == nil {
+				t.Fatal("Create() returned no transaction")
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
+
+// expectWallet expects the wallet lookup that decides which way the balance moves
+func expectWallet(wallets *mocks.MockWalletRepository, userId, walletId, walletType string) {
+	wallets.EXPECT().GetDetail(gomock.Any(), userId, walletId).Return(&domain.Wallet{ID: uuid.MustParse(walletId), Type: walletType}, nil)
+}
+
+// expectBudget expects the single lookup of a budget a transaction or its lines are booked to
+func expectBudget(budgets *mocks.MockBudgetRepository, userId, budgetId string, err error) {
+	if err != nil {
+		budgets.EXPECT().GetDetail(gomock.Any(), userId, budgetId).Return(nil, err)
+		return
+	}
+	budgets.EXPECT().GetDetail(gomock.Any(), userId, budgetId).Return(&domain.Budget{ID: uuid.MustParse(budgetId)}, nil)
+}
+
+// expectCreate expects the transaction insert and assigns the id the database would
+func expectCreate(transactions *mocks.MockTransactionRepository, userId string, err error) {
+	transactions.EXPECT().Create(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, transaction *domain.Transaction) error {
+			transaction.ID = uuid.New()
+			return err
+		},
+	)
+}
+
+// expectDetail expects the reload that follows the insert
+func expectDetail(transactions *mocks.MockTransactionRepository, userId string, err error) {
+	transactions.EXPECT().GetDetail(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, transactionId string) (*domain.Transaction, error) {
+			if err != nil {
+				return nil, err
+			}
+			return &domain.Transaction{ID: uuid.MustParse(transactionId), Type: constant.TransactionTypeIncome}, nil
+		},
+	)
+}
+
+func TestTransactionServiceGetList(t *testing.T) {
+	userId := uuid.NewString()
+
+	tests := []struct {
+		name         string
+		transactions []*domain.Transaction
+		listErr      error
+		wantCount    int
+	}{
+		{name: "transactions", transactions: []*domain.Transaction{{Amount: 1}, {Amount: 2}}, wantCount: 2},
+		{name: "repository failure", listErr: errDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, _ := newTxManager(ctrl)
+			transactions := mocks.NewMockTransactionRepository(ctrl)
+			wallets := mocks.NewMockWalletRepository(ctrl)
+			budgets := mocks.NewMockBudgetRepository(ctrl)
+
+			request := &model.TransactionListRequest{From: 1756450000, Type: constant.TransactionTypeExpense}
+			filter := domain.TransactionFilter{From: 1756450000, Type: constant.TransactionTypeExpense}
+			transactions.EXPECT().GetList(gomock.Any(), userId, filter).Return(tt.transactions, tt.listErr)
+
+			list, err := service.NewTransactionService(txManager, transactions, wallets, budgets).GetList(context.Background(), userId, request)
+			if !errors.Is(err, tt.listErr) {
+				t.Fatalf("GetList() error = %v, want %v", err, tt.listErr)
+			}
+			if len(list) != tt.wantCount {
+				t.Fatalf("GetList() returned %d transactions, want %d", len(list), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceCreateBatch(t *testing.T) {
+	userId := uuid.NewString()
+	walletId := uuid.NewString()
+	savingsId := uuid.NewString()
+
+	requests := []*model.CreateTransactionRequest{
+		{Amount: 3000, Type: constant.TransactionTypeIncome, TransactionDate: 1756450000, WalletID: walletId},
+		{Amount: 80, Type: constant.TransactionTypeExpense, TransactionDate: 1756460000, WalletID: walletId},
+		{Amount: 500, Type: constant.TransactionTypeIncome, TransactionDate: 1756470000, WalletID: savingsId},
+	}
+
+	tests := []struct {
+		name          string
+		setup         func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository)
+		wantErr       error
+		wantCount     int
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{
+			name: "every request in one unit of work",
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				// Each wallet is loaded once however many requests it has
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectWallet(wallets, userId, savingsId, constant.WalletTypePersonal)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), walletId, 3000.0).Return(nil)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 80.0).Return(true, nil)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), savingsId, 500.0).Return(nil)
+				expectCreate(transactions, userId, nil)
+				expectCreate(transactions, userId, nil)
+				expectCreate(transactions, userId, nil)
+			},
+			wantCount:   3,
+			wantCommits: 1,
+		},
+		{
+			name: "one failure keeps none",
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), walletId, 3000.0).Return(nil)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 80.0).Return(true, nil)
+				expectCreate(transactions, userId, nil)
+				expectCreate(transactions, userId, errDB)
+			},
+			wantErr:       errDB,
+			wantRollbacks: 1,
+		},
+		{
+			name: "wallet of another user",
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				wallets.EXPECT().GetDetail(gomock.Any(), userId, walletId).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr:       apperror.ErrNotFound,
+			wantRollbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			transactions := mocks.NewMockTransactionRepository(ctrl)
+			wallets := mocks.NewMockWalletRepository(ctrl)
+			budgets := mocks.NewMockBudgetRepository(ctrl)
+			tt.setup(transactions, wallets, budgets)
+
+			created, err := service.NewTransactionService(txManager, transactions, wallets, budgets).CreateBatch(context.Background(), userId, requests)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateBatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(created) != tt.wantCount {
+				t.Fatalf("CreateBatch() returned %d transactions, want %d", len(created), tt.wantCount)
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
+
+func TestTransactionServiceUpdate(t *testing.T) {
+	userId := uuid.NewString()
+	walletId := uuid.NewString()
+	savingsId := uuid.NewString()
+	budgetId := uuid.NewString()
+	transactionId := uuid.New()
+
+	expense := func(amount float64, walletId string) *model.UpdateTransactionRequest {
+		return &model.UpdateTransactionRequest{Amount: amount, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId}
+	}
+
+	tests := []struct {
+		name          string
+		current       *domain.Transaction
+		version       int
+		request       *model.UpdateTransactionRequest
+		setup         func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository)
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{
+			name:    "larger expense moves the difference",
+			current: &domain.Transaction{Amount: 200, Type: constant.TransactionTypeExpense},
+			version: 2,
+			request: expense(250, walletId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 50.0).Return(true, nil)
+				expectUpdate(transactions, true)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "same amount leaves the balance alone",
+			current: &domain.Transaction{Amount: 250, Type: constant.TransactionTypeExpense},
+			request: expense(250, walletId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectUpdate(transactions, true)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "income turned into an expense",
+			current: &domain.Transaction{Amount: 100, Type: constant.TransactionTypeIncome},
+			request: expense(100, walletId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 200.0).Return(true, nil)
+				expectUpdate(transactions, true)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "moved off a credit card",
+			current: &domain.Transaction{Amount: 100, Type: constant.TransactionTypeExpense},
+			request: expense(100, savingsId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypeCredit)
+				expectWallet(wallets, userId, savingsId, constant.WalletTypePersonal)
+				// The card owes less and the savings pay instead
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 100.0).Return(true, nil)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), savingsId, 100.0).Return(true, nil)
+				expectUpdate(transactions, true)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "split into lines keeps the balance",
+			current: &domain.Transaction{Amount: 200, Type: constant.TransactionTypeExpense},
+			request: &model.UpdateTransactionRequest{Amount: 200, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId, Splits: []model.TransactionSplitRequest{
+				{Amount: 120, Note: "Groceries"},
+				{Amount: 80, Note: "Household"},
+			}},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				transactions.EXPECT().Update(gomock.Any(), gomock.Any(), 2).DoAndReturn(
+					func(_ context.Context, transaction *domain.Transaction, _ int) (bool, error) {
+						if len(transaction.Splits) != 2 || transaction.Splits[1].Note != "Household" {
+							t.Errorf("splits = %+v, want both lines", transaction.Splits)
+						}
+						return true, nil
+					},
+				)
+				expectDetail(transactions, userId, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:    "larger expense than the wallet holds is not saved",
+			current: &domain.Transaction{Amount: 200, Type: constant.TransactionTypeExpense},
+			version: 2,
+			request: expense(250, walletId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 50.0).Return(false, nil)
+			},
+			wantErr:       apperror.ErrInsufficientFunds,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "moved to a wallet that cannot pay",
+			current: &domain.Transaction{Amount: 100, Type: constant.TransactionTypeExpense},
+			request: expense(100, savingsId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectWallet(wallets, userId, savingsId, constant.WalletTypePersonal)
+				wallets.EXPECT().IncreaseBalance(gomock.Any(), walletId, 100.0).Return(nil)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), savingsId, 100.0).Return(false, nil)
+			},
+			wantErr:       apperror.ErrInsufficientFunds,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "income moved off a wallet that already spent it",
+			current: &domain.Transaction{Amount: 100, Type: constant.TransactionTypeIncome},
+			request: &model.UpdateTransactionRequest{Amount: 100, Type: constant.TransactionTypeIncome, TransactionDate: 1756450000, WalletID: savingsId},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				expectWallet(wallets, userId, savingsId, constant.WalletTypePersonal)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 100.0).Return(false, nil)
+			},
+			wantErr:       apperror.ErrInsufficientFunds,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "split line on an unknown budget",
+			current: &domain.Transaction{Amount: 200, Type: constant.TransactionTypeExpense},
+			request: &model.UpdateTransactionRequest{Amount: 200, Type: constant.TransactionTypeExpense, TransactionDate: 1756450000, WalletID: walletId, Splits: []model.TransactionSplitRequest{
+				{Amount: 120},
+				{Amount: 80, BudgetID: &budgetId},
+			}},
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectBudget(budgets, userId, budgetId, gorm.ErrRecordNotFound)
+			},
+			wantErr:       apperror.ErrNotFound,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "stale version",
+			current: &domain.Transaction{Amount: 200, Type: constant.TransactionTypeExpense},
+			version: 1,
+			request: expense(250, walletId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+			},
+			wantErr:       apperror.ErrPreconditionFailed,
+			wantRollbacks: 1,
+		},
+		{
+			name:    "changed in between rolls the balance back",
+			current: &domain.Transaction{Amount: 200, Type: constant.TransactionTypeExpense},
+			version: 2,
+			request: expense(250, walletId),
+			setup: func(transactions *mocks.MockTransactionRepository, wallets *mocks.MockWalletRepository, budgets *mocks.MockBudgetRepository) {
+				expectWallet(wallets, userId, walletId, constant.WalletTypePersonal)
+				wallets.EXPECT().DecreaseBalance(gomock.Any(), walletId, 50.0).Return(true, nil)
+				expectUpdate(transactions, false)
+			},
+			wantErr:       apperror.ErrPreconditionFailed,
+			wantRollbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			transactions := mocks.NewMockTransactionRepository(ctrl)
+			wallets := mocks.NewMockWalletRepository(ctrl)
+			budgets := mocks.NewMockBudgetRepository(ctrl)
+
+			tt.current.ID = transactionId
+			tt.current.WalletID = uuid.MustParse(walletId)
+			tt.current.Version = 2
+			transactions.EXPECT().GetDetail(gomock.Any(), userId, transactionId.String()).Return(tt.current, nil)
+			tt.setup(transactions, wallets, budgets)
+
+			_, err := service.NewTransactionService(txManager, transactions, wallets, budgets).Update(context.Background(), userId, transactionId.String(), tt.version, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
+
+// expectUpdate expects the guarded save of a transaction read at version 2
+func expectUpdate(transactions *mocks.MockTransactionRepository, saved bool) {
+	transactions.EXPECT().Update(gomock.Any(), gomock.Any(), 2).Return(saved, nil)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/auth"
+	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userService struct {
+	txManager domain.TxManager
+
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+}
+
+func NewUserService(txManager domain.TxManager, userRepo domain.UserRepository, sessionRepo domain.SessionRepository) domain.UserService {
+	return &userService{
+		txManager:   txManager,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (s *userService) Create(ctx context.Context, fullname, email, password string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "userService.Create")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	if err := auth.ValidatePassword(password); err != nil {
+		return nil, apperror.ErrBadRequest.WithMessage(err.Error())
+	}
+
+	existingUser, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.WithError(err).Error("[service - user - Create]: Error checking existing user")
+		return nil, err
+	}
+
+	if existingUser != nil {
+		return nil, apperror.ErrUserAlreadyExists
+	}
+
+	hashedPassword, err := auth.HashPassword(password)
+	if err != nil {
+		log.WithError(err).Error("[service - user - Create]: Error hashing password")
+		return nil, err
+	}
+
+	user := &domain.User{
+		FullName: fullname,
+		Email:    email,
+		Password: hashedPassword,
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		log.WithError(err).Error("[service - user - Create]: Failed to create user")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) Disable(ctx context.Context, email string) error {
+	ctx, span := tracing.Start(ctx, "userService.Disable")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	user, err := s.getByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if user.IsDisabled() {
+		return nil
+	}
+
+	disabledAt := int(time.Now().Unix())
+	user.DisabledAt = &disabledAt
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			log.WithError(err).Error("[service - user - Disable]: Failed to disable user")
+			return err
+		}
+
+		// Revoke every session so the account is locked out immediately
+		if err := s.sessionRepo.DeleteByUserID(ctx, user.ID.String()); err != nil {
+			log.WithError(err).Error("[service - user - Disable]: Failed to revoke sessions")
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *userService) ResetPassword(ctx context.Context, email, password string) error {
+	ctx, span := tracing.Start(ctx, "userService.ResetPassword")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	if err := auth.ValidatePassword(password); err != nil {
+		return apperror.ErrBadRequest.WithMessage(err.Error())
+	}
+
+	user, err := s.getByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := auth.HashPassword(password)
+	if err != nil {
+		log.WithError(err).Error("[service - user - ResetPassword]: Error hashing password")
+		return err
+	}
+
+	user.Password = hashedPassword
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			log.WithError(err).Error("[service - user - ResetPassword]: Failed to update password")
+			return err
+		}
+
+		// Existing sessions were issued for the old password
+		if err := s.sessionRepo.DeleteByUserID(ctx, user.ID.String()); err != nil {
+			log.WithError(err).Error("[service - user - ResetPassword]: Failed to revoke sessions")
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *userService) PurgeExpiredSessions(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "userService.PurgeExpiredSessions")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	purged, err := s.sessionRepo.DeleteExpired(ctx, int(time.Now().Unix()))
+	if err != nil {
+		log.WithError(err).Error("[service - user - PurgeExpiredSessions]: Failed to purge sessions")
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+func (s *userService) getByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("user not found")
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -2,30 +2,34 @@ package service
 
 import (
 	"context"
+	"errors"
 	"finance-backend/internal/domain"
 	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/tracing"
 
 	"gorm.io/gorm"
 )
 
 type walletService struct {
-	db *gorm.DB
+	txManager domain.TxManager
 
 	walletRepo domain.WalletRepository
 }
 
-func NewWalletService(db *gorm.DB, walletRepo domain.WalletRepository) domain.WalletService {
+func NewWalletService(txManager domain.TxManager, walletRepo domain.WalletRepository) domain.WalletService {
 	return &walletService{
-		db:         db,
+		txManager:  txManager,
 		walletRepo: walletRepo,
 	}
 }
 
 func (s *walletService) Create(ctx context.Context, userId string, request *model.CreateWalletRequest) (*domain.Wallet, error) {
-	log := logger.WithRequestID(ctx)
+	ctx, span := tracing.Start(ctx, "walletService.Create")
+	defer span.End()
 
-	tx := s.db.Begin()
+	log := logger.WithRequestID(ctx)
 
 	wallet := &domain.Wallet{
 		Name:     request.Name,
@@ -34,21 +38,27 @@ func (s *walletService) Create(ctx context.Context, userId string, request *mode
 		Balance:  request.Balance,
 	}
 
-	if err := s.walletRepo.Create(tx, ctx, userId, wallet); err != nil {
-		log.WithError(err).Error("[service - wallet - Create]: Failed to create wallet")
-		tx.Rollback()
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.walletRepo.Create(ctx, userId, wallet); err != nil {
+			log.WithError(err).Error("[service - wallet - Create]: Failed to create wallet")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	tx.Commit()
-
 	return wallet, nil
 }
 
 func (s *walletService) GetList(ctx context.Context, userId string) ([]*domain.Wallet, error) {
+	ctx, span := tracing.Start(ctx, "walletService.GetList")
+	defer span.End()
+
 	log := logger.WithRequestID(ctx)
 
-	wallets, err := s.walletRepo.GetList(s.db, ctx, userId)
+	wallets, err := s.walletRepo.GetList(ctx, userId)
 	if err != nil {
 		log.WithError(err).Error("[service - wallet - GetList]: Failed to get wallet list")
 		return nil, err
@@ -56,3 +66,68 @@ func (s *walletService) GetList(ctx context.Context, userId string) ([]*domain.W
 
 	return wallets, nil
 }
+
+func (s *walletService) GetDetail(ctx context.Context, userId string, walletId string) (*domain.Wallet, error) {
+	ctx, span := tracing.Start(ctx, "walletService.GetDetail")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	wallet, err := s.walletRepo.GetDetail(ctx, userId, walletId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.ErrNotFound.WithMessage("wallet not found")
+		}
+		log.WithError(err).Error("[service - wallet - GetDetail]: Failed to get wallet")
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+func (s *walletService) Update(ctx context.Context, userId string, walletId string, version int, request *model.UpdateWalletRequest) (*domain.Wallet, error) {
+	ctx, span := tracing.Start(ctx, "walletService.Update")
+	defer span.End()
+
+	log := logger.WithRequestID(ctx)
+
+	var wallet *domain.Wallet
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.GetDetail(ctx, userId, walletId)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, current.Version); err != nil {
+			return err
+		}
+
+		current.Name = request.Name
+
+		saved, err := s.walletRepo.Update(ctx, current, current.Version)
+		if err != nil {
+			log.WithError(err).Error("[service - wallet - Update]: Failed to update wallet")
+			return err
+		}
+		if !saved {
+			return apperror.ErrPreconditionFailed
+		}
+
+		// Reload for the new version and timestamp
+		wallet, err = s.GetDetail(ctx, userId, walletId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// checkVersion rejects a change made to another version than the current one, zero matches any version
+func checkVersion(version int, current int) error {
+	if version != 0 && version != current {
+		return apperror.ErrPreconditionFailed.WithDetails(map[string]int{"version": current})
+	}
+	return nil
+}
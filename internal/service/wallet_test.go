@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/internal/model"
+	"finance-backend/internal/service"
+	"finance-backend/pkg/apperror"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestWalletServiceCreate(t *testing.T) {
+	userId := uuid.NewString()
+	request := &model.CreateWalletRequest{Name: "Main", Type: "personal", Currency: "IDR", Balance: 1000}
+
+	tests := []struct {
+		name          string
+		createErr     error
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{name: "created", wantCommits: 1},
+		{name: "repository failure rolls back", createErr: errDB, wantErr: errDB, wantRollbacks: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			walletRepo := mocks.NewMockWalletRepository(ctrl)
+
+			walletRepo.EXPECT().Create(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, wallet *domain.Wallet) error {
+					if wallet.Name != request.Name || wallet.Currency != request.Currency || wallet.Balance != request.Balance {
+						t.Errorf("unexpected wallet %+v", wallet)
+					}
+					return tt.createErr
+				},
+			)
+
+			wallet, err := service.NewWalletService(txManager, walletRepo).Create(context.Background(), userId, request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && wallet == nil {
+				t.Fatal("Create() returned no wallet")
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
+
+func TestWalletServiceGetList(t *testing.T) {
+	userId := uuid.NewString()
+
+	tests := []struct {
+		name      string
+		wallets   []*domain.Wallet
+		listErr   error
+		wantCount int
+	}{
+		{name: "wallets", wallets: []*domain.Wallet{{Name: "A"}, {Name: "B"}}, wantCount: 2},
+		{name: "repository failure", listErr: errDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, _ := newTxManager(ctrl)
+			walletRepo := mocks.NewMockWalletRepository(ctrl)
+
+			walletRepo.EXPECT().GetList(gomock.Any(), userId).Return(tt.wallets, tt.listErr)
+
+			wallets, err := service.NewWalletService(txManager, walletRepo).GetList(context.Background(), userId)
+			if !errors.Is(err, tt.listErr) {
+				t.Fatalf("GetList() error = %v, want %v", err, tt.listErr)
+			}
+			if len(wallets) != tt.wantCount {
+				t.Fatalf("GetList() returned %d wallets, want %d", len(wallets), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestWalletServiceUpdate(t *testing.T) {
+	userId := uuid.NewString()
+	walletId := uuid.New()
+	request := &model.UpdateWalletRequest{Name: "Household"}
+
+	tests := []struct {
+		name          string
+		version       int
+		getErr        error
+		saved         bool
+		wantUpdate    bool
+		wantErr       error
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{name: "renamed", version: 3, saved: true, wantUpdate: true, wantCommits: 1},
+		{name: "any version", version: 0, saved: true, wantUpdate: true, wantCommits: 1},
+		{name: "stale version", version: 2, wantErr: apperror.ErrPreconditionFailed, wantRollbacks: 1},
+		{name: "changed in between", version: 3, wantUpdate: true, wantErr: apperror.ErrPreconditionFailed, wantRollbacks: 1},
+		{name: "wallet of another user", version: 3, getErr: gorm.ErrRecordNotFound, wantErr: apperror.ErrNotFound, wantRollbacks: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			txManager, outcome := newTxManager(ctrl)
+			walletRepo := mocks.NewMockWalletRepository(ctrl)
+
+			current := &domain.Wallet{ID: walletId, Name: "Main", Version: 3}
+			if tt.getErr != nil {
+				walletRepo.EXPECT().GetDetail(gomock.Any(), userId, walletId.String()).Return(nil, tt.getErr)
+			} else {
+				walletRepo.EXPECT().GetDetail(gomock.Any(), userId, walletId.String()).Return(current, nil)
+			}
+			if tt.wantUpdate {
+				walletRepo.EXPECT().Update(gomock.Any(), gomock.Any(), 3).DoAndReturn(
+					func(_ context.Context, wallet *domain.Wallet, _ int) (bool, error) {
+						if wallet.Name != request.Name {
+							t.Errorf("Update() name = %q, want %q", wallet.Name, request.Name)
+						}
+						return tt.saved, nil
+					},
+				)
+			}
+			if tt.saved {
+				walletRepo.EXPECT().GetDetail(gomock.Any(), userId, walletId.String()).Return(&domain.Wallet{ID: walletId, Name: request.Name, Version: 4}, nil)
+			}
+
+			wallet, err := service.NewWalletService(txManager, walletRepo).Update(context.Background(), userId, walletId.String(), tt.version, request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && wallet.Version != 4 {
+				t.Fatalf("Update() version = %d, want 4", wallet.Version)
+			}
+			outcome.assert(t, tt.wantCommits, tt.wantRollbacks)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Package testdb runs tests against a throwaway Postgres.
+//
+// Main starts a local cluster with initdb that listens only on a unix socket,
+// or uses TEST_DATABASE_URL when it is set. New gives every test its own
+// freshly migrated schema that is dropped when the test ends. Tests are
+// skipped when no Postgres is available, unless TEST_DATABASE_REQUIRED is set.
+package testdb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"finance-backend/pkg/migration"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	// dsn points at the running cluster, empty when none is available
+	dsn string
+	// unavailable explains why tests are skipped
+	unavailable = "testdb.Main was not called from TestMain"
+
+	adminOnce sync.Once
+	admin     *gorm.DB
+	adminErr  error
+)
+
+// Main runs the package's tests against a throwaway cluster. Call it from TestMain.
+func Main(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	goose.SetLogger(goose.NopLogger())
+
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		dsn = url
+		return m.Run()
+	}
+
+	binDir, err := findBinDir()
+	if err != nil {
+		unavailable = err.Error()
+		return m.Run()
+	}
+
+	cluster, err := startCluster(binDir)
+	if err != nil {
+		unavailable = err.Error()
+		return m.Run()
+	}
+	defer cluster.stop()
+
+	dsn = cluster.dsn
+	return m.Run()
+}
+
+// New returns a connection to a new schema with every migration applied
+func New(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	if dsn == "" {
+		if os.Getenv("TEST_DATABASE_REQUIRED") != "" {
+			t.Fatalf("postgres is required: %s", unavailable)
+		}
+		t.Skipf("postgres unavailable: %s", unavailable)
+	}
+
+	adminOnce.Do(func() {
+		admin, adminErr = open(dsn)
+	})
+	if adminErr != nil {
+		t.Fatalf("failed to connect to postgres: %v", adminErr)
+	}
+
+	name, err := schemaName()
+	if err != nil {
+		t.Fatalf("failed to generate schema name: %v", err)
+	}
+
+	if err := admin.Exec("CREATE SCHEMA " + name).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + name + " CASCADE")
+	})
+
+	db, err := open(withSearchPath(dsn, name))
+	if err != nil {
+		t.Fatalf("failed to connect to schema: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql db: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := migration.MigrateUp(sqlDB, MigrationsDir()); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return db
+}
+
+// MigrationsDir returns the absolute path of the repository's migrations
+func MigrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+}
+
+func open(dsn string) (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+}
+
+func schemaName() (string, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return "test_" + hex.EncodeToString(suffix), nil
+}
+
+// withSearchPath appends a search_path runtime parameter to a URL or key=value DSN
+func withSearchPath(dsn, schema string) string {
+	if strings.Contains(dsn, "://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		return dsn + separator + "search_path=" + schema
+	}
+	return dsn + " search_path=" + schema
+}
+
+// findBinDir locates the directory holding initdb and pg_ctl
+func findBinDir() (string, error) {
+	if dir := os.Getenv("PG_BIN"); dir != "" {
+		return dir, nil
+	}
+
+	if path, err := exec.LookPath("initdb"); err == nil {
+		return filepath.Dir(path), nil
+	}
+
+	// Debian and Ubuntu keep the server binaries out of PATH
+	matches, _ := filepath.Glob("/usr/lib/postgresql/*/bin/initdb")
+	if len(matches) > 0 {
+		sort.Strings(matches)
+		return filepath.Dir(matches[len(matches)-1]), nil
+	}
+
+	return "", errors.New("initdb not found, set PG_BIN or TEST_DATABASE_URL")
+}
+
+type cluster struct {
+	binDir string
+	dir    string
+	dsn    string
+}
+
+// startCluster initialises and starts a cluster in a temporary directory.
+// It listens on a unix socket only so tests never touch the network.
+func startCluster(binDir string) (*cluster, error) {
+	dir, err := os.MkdirTemp("", "pgtest")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster directory: %w", err)
+	}
+
+	c := &cluster{
+		binDir: binDir,
+		dir:    dir,
+		dsn:    fmt.Sprintf("host=%s user=postgres dbname=postgres sslmode=disable", dir),
+	}
+
+	initdb := exec.Command(filepath.Join(binDir, "initdb"),
+		"-D", c.dataDir(), "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync")
+	if output, err := initdb.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("initdb failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	start := exec.Command(filepath.Join(binDir, "pg_ctl"),
+		"-D", c.dataDir(), "-l", filepath.Join(dir, "postgres.log"), "-w",
+		"-o", fmt.Sprintf("-k %s -c listen_addresses='' -F", dir),
+		"start")
+	if output, err := start.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pg_ctl start failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return c, nil
+}
+
+func (c *cluster) dataDir() string {
+	return filepath.Join(c.dir, "data")
+}
+
+func (c *cluster) stop() {
+	exec.Command(filepath.Join(c.binDir, "pg_ctl"), "-D", c.dataDir(), "-m", "immediate", "-w", "stop").Run()
+	os.RemoveAll(c.dir)
+}
@@ -95,11 +95,12 @@ CREATE INDEX IF NOT EXISTS idx_transactions_note ON transactions(note);
 
 -- +goose Down
 -- +goose StatementBegin
-DROP TABLE IF EXISTS sessions;
-DROP TABLE IF EXISTS users;
-DROP TABLE IF EXISTS has_wallets;
-DROP TABLE IF EXISTS wallets;
+DROP TABLE IF EXISTS has_transactions;
+DROP TABLE IF EXISTS transactions;
 DROP TABLE IF EXISTS has_budgets;
 DROP TABLE IF EXISTS budgets;
-DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS has_wallets;
+DROP TABLE IF EXISTS wallets;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
 -- +goose StatementEnd
@@ -0,0 +1,95 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS sessions_user_id_fkey;
+ALTER TABLE has_wallets DROP CONSTRAINT IF EXISTS has_wallets_user_id_fkey;
+ALTER TABLE has_wallets DROP CONSTRAINT IF EXISTS has_wallets_wallet_id_fkey;
+ALTER TABLE has_budgets DROP CONSTRAINT IF EXISTS has_budgets_user_id_fkey;
+ALTER TABLE has_budgets DROP CONSTRAINT IF EXISTS has_budgets_budget_id_fkey;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_wallet_id_fkey;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_budget_id_fkey;
+ALTER TABLE has_transactions DROP CONSTRAINT IF EXISTS has_transactions_user_id_fkey;
+ALTER TABLE has_transactions DROP CONSTRAINT IF EXISTS has_transactions_transaction_id_fkey;
+
+ALTER TABLE users ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN id TYPE uuid USING id::uuid;
+ALTER TABLE users ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE sessions ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE sessions ALTER COLUMN id TYPE uuid USING id::uuid;
+ALTER TABLE sessions ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE sessions ALTER COLUMN user_id TYPE uuid USING user_id::uuid;
+ALTER TABLE wallets ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE wallets ALTER COLUMN id TYPE uuid USING id::uuid;
+ALTER TABLE wallets ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE has_wallets ALTER COLUMN user_id TYPE uuid USING user_id::uuid;
+ALTER TABLE has_wallets ALTER COLUMN wallet_id TYPE uuid USING wallet_id::uuid;
+ALTER TABLE budgets ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE budgets ALTER COLUMN id TYPE uuid USING id::uuid;
+ALTER TABLE budgets ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE has_budgets ALTER COLUMN user_id TYPE uuid USING user_id::uuid;
+ALTER TABLE has_budgets ALTER COLUMN budget_id TYPE uuid USING budget_id::uuid;
+ALTER TABLE transactions ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN id TYPE uuid USING id::uuid;
+ALTER TABLE transactions ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE transactions ALTER COLUMN wallet_id TYPE uuid USING wallet_id::uuid;
+ALTER TABLE transactions ALTER COLUMN budget_id TYPE uuid USING budget_id::uuid;
+ALTER TABLE has_transactions ALTER COLUMN user_id TYPE uuid USING user_id::uuid;
+ALTER TABLE has_transactions ALTER COLUMN transaction_id TYPE uuid USING transaction_id::uuid;
+
+ALTER TABLE sessions ADD CONSTRAINT sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_wallets ADD CONSTRAINT has_wallets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_wallets ADD CONSTRAINT has_wallets_wallet_id_fkey FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT;
+ALTER TABLE has_budgets ADD CONSTRAINT has_budgets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_budgets ADD CONSTRAINT has_budgets_budget_id_fkey FOREIGN KEY (budget_id) REFERENCES budgets(id) ON DELETE RESTRICT;
+ALTER TABLE transactions ADD CONSTRAINT transactions_wallet_id_fkey FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT;
+ALTER TABLE transactions ADD CONSTRAINT transactions_budget_id_fkey FOREIGN KEY (budget_id) REFERENCES budgets(id) ON DELETE SET NULL;
+ALTER TABLE has_transactions ADD CONSTRAINT has_transactions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_transactions ADD CONSTRAINT has_transactions_transaction_id_fkey FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS sessions_user_id_fkey;
+ALTER TABLE has_wallets DROP CONSTRAINT IF EXISTS has_wallets_user_id_fkey;
+ALTER TABLE has_wallets DROP CONSTRAINT IF EXISTS has_wallets_wallet_id_fkey;
+ALTER TABLE has_budgets DROP CONSTRAINT IF EXISTS has_budgets_user_id_fkey;
+ALTER TABLE has_budgets DROP CONSTRAINT IF EXISTS has_budgets_budget_id_fkey;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_wallet_id_fkey;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_budget_id_fkey;
+ALTER TABLE has_transactions DROP CONSTRAINT IF EXISTS has_transactions_user_id_fkey;
+ALTER TABLE has_transactions DROP CONSTRAINT IF EXISTS has_transactions_transaction_id_fkey;
+
+ALTER TABLE users ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN id TYPE VARCHAR(36) USING id::text;
+ALTER TABLE users ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE sessions ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE sessions ALTER COLUMN id TYPE VARCHAR(36) USING id::text;
+ALTER TABLE sessions ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE sessions ALTER COLUMN user_id TYPE VARCHAR(36) USING user_id::text;
+ALTER TABLE wallets ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE wallets ALTER COLUMN id TYPE VARCHAR(36) USING id::text;
+ALTER TABLE wallets ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE has_wallets ALTER COLUMN user_id TYPE VARCHAR(36) USING user_id::text;
+ALTER TABLE has_wallets ALTER COLUMN wallet_id TYPE VARCHAR(36) USING wallet_id::text;
+ALTER TABLE budgets ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE budgets ALTER COLUMN id TYPE VARCHAR(36) USING id::text;
+ALTER TABLE budgets ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE has_budgets ALTER COLUMN user_id TYPE VARCHAR(36) USING user_id::text;
+ALTER TABLE has_budgets ALTER COLUMN budget_id TYPE VARCHAR(36) USING budget_id::text;
+ALTER TABLE transactions ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN id TYPE VARCHAR(36) USING id::text;
+ALTER TABLE transactions ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE transactions ALTER COLUMN wallet_id TYPE VARCHAR(36) USING wallet_id::text;
+ALTER TABLE transactions ALTER COLUMN budget_id TYPE VARCHAR(36) USING budget_id::text;
+ALTER TABLE has_transactions ALTER COLUMN user_id TYPE VARCHAR(36) USING user_id::text;
+ALTER TABLE has_transactions ALTER COLUMN transaction_id TYPE VARCHAR(36) USING transaction_id::text;
+
+ALTER TABLE sessions ADD CONSTRAINT sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_wallets ADD CONSTRAINT has_wallets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_wallets ADD CONSTRAINT has_wallets_wallet_id_fkey FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT;
+ALTER TABLE has_budgets ADD CONSTRAINT has_budgets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_budgets ADD CONSTRAINT has_budgets_budget_id_fkey FOREIGN KEY (budget_id) REFERENCES budgets(id) ON DELETE RESTRICT;
+ALTER TABLE transactions ADD CONSTRAINT transactions_wallet_id_fkey FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT;
+ALTER TABLE transactions ADD CONSTRAINT transactions_budget_id_fkey FOREIGN KEY (budget_id) REFERENCES budgets(id) ON DELETE SET NULL;
+ALTER TABLE has_transactions ADD CONSTRAINT has_transactions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE has_transactions ADD CONSTRAINT has_transactions_transaction_id_fkey FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Transactions must move money, matching check:amount > 0 on the model
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_amount_check;
+ALTER TABLE transactions ADD CONSTRAINT chk_transactions_amount CHECK (amount > 0);
+
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_balance_check;
+ALTER TABLE wallets ADD CONSTRAINT chk_wallets_balance CHECK (balance >= 0);
+
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_amount_check;
+ALTER TABLE budgets ADD CONSTRAINT chk_budgets_amount CHECK (amount >= 0);
+
+-- Columns the models declare as not null
+UPDATE users SET full_name = '' WHERE full_name IS NULL;
+ALTER TABLE users ALTER COLUMN full_name SET NOT NULL;
+ALTER TABLE users ALTER COLUMN password SET NOT NULL;
+
+-- soft_delete treats 0 as "not deleted", rows written outside gorm must not be hidden
+UPDATE users SET deleted_at = 0 WHERE deleted_at IS NULL;
+UPDATE wallets SET deleted_at = 0 WHERE deleted_at IS NULL;
+UPDATE budgets SET deleted_at = 0 WHERE deleted_at IS NULL;
+UPDATE transactions SET deleted_at = 0 WHERE deleted_at IS NULL;
+ALTER TABLE users ALTER COLUMN deleted_at SET DEFAULT 0, ALTER COLUMN deleted_at SET NOT NULL;
+ALTER TABLE wallets ALTER COLUMN deleted_at SET DEFAULT 0, ALTER COLUMN deleted_at SET NOT NULL;
+ALTER TABLE budgets ALTER COLUMN deleted_at SET DEFAULT 0, ALTER COLUMN deleted_at SET NOT NULL;
+ALTER TABLE transactions ALTER COLUMN deleted_at SET DEFAULT 0, ALTER COLUMN deleted_at SET NOT NULL;
+
+-- Sessions are hard deleted, the model has no soft delete column
+DELETE FROM sessions WHERE deleted_at IS NOT NULL AND deleted_at <> 0;
+DROP INDEX IF EXISTS idx_sessions_deleted_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS deleted_at;
+
+-- The unique constraint already indexes these columns
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_sessions_session_token;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_sessions_session_token ON sessions(session_token);
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS deleted_at bigint;
+CREATE INDEX IF NOT EXISTS idx_sessions_deleted_at ON sessions(deleted_at);
+
+ALTER TABLE users ALTER COLUMN deleted_at DROP NOT NULL, ALTER COLUMN deleted_at DROP DEFAULT;
+ALTER TABLE wallets ALTER COLUMN deleted_at DROP NOT NULL, ALTER COLUMN deleted_at DROP DEFAULT;
+ALTER TABLE budgets ALTER COLUMN deleted_at DROP NOT NULL, ALTER COLUMN deleted_at DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN deleted_at DROP NOT NULL, ALTER COLUMN deleted_at DROP DEFAULT;
+
+ALTER TABLE users ALTER COLUMN full_name DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN password DROP NOT NULL;
+
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS chk_budgets_amount;
+ALTER TABLE budgets ADD CONSTRAINT budgets_amount_check CHECK (amount >= 0);
+
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS chk_wallets_balance;
+ALTER TABLE wallets ADD CONSTRAINT wallets_balance_check CHECK (balance >= 0);
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_transactions_amount;
+ALTER TABLE transactions ADD CONSTRAINT transactions_amount_check CHECK (amount >= 0);
+-- +goose StatementEnd
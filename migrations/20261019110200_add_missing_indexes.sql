@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ownership lookups from either side of the has_* join tables
+CREATE INDEX IF NOT EXISTS idx_has_wallets_wallet_id ON has_wallets(wallet_id);
+CREATE INDEX IF NOT EXISTS idx_has_budgets_budget_id ON has_budgets(budget_id);
+CREATE INDEX IF NOT EXISTS idx_has_transactions_transaction_id ON has_transactions(transaction_id);
+
+CREATE INDEX IF NOT EXISTS idx_has_wallets_user_id ON has_wallets(user_id);
+CREATE INDEX IF NOT EXISTS idx_has_budgets_user_id ON has_budgets(user_id);
+CREATE INDEX IF NOT EXISTS idx_has_transactions_user_id ON has_transactions(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_wallet_id ON transactions(wallet_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_budget_id ON transactions(budget_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_transaction_date ON transactions(transaction_date);
+
+CREATE INDEX IF NOT EXISTS idx_wallets_deleted_at ON wallets(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_budgets_deleted_at ON budgets(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_deleted_at;
+DROP INDEX IF EXISTS idx_budgets_deleted_at;
+DROP INDEX IF EXISTS idx_wallets_deleted_at;
+
+DROP INDEX IF EXISTS idx_transactions_transaction_date;
+DROP INDEX IF EXISTS idx_transactions_budget_id;
+DROP INDEX IF EXISTS idx_transactions_wallet_id;
+
+DROP INDEX IF EXISTS idx_sessions_expires_at;
+DROP INDEX IF EXISTS idx_sessions_user_id;
+
+DROP INDEX IF EXISTS idx_has_transactions_user_id;
+DROP INDEX IF EXISTS idx_has_budgets_user_id;
+DROP INDEX IF EXISTS idx_has_wallets_user_id;
+
+DROP INDEX IF EXISTS idx_has_transactions_transaction_id;
+DROP INDEX IF EXISTS idx_has_budgets_budget_id;
+DROP INDEX IF EXISTS idx_has_wallets_wallet_id;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- End of day balance of every wallet, snapshot_date is the unix time of UTC midnight
+CREATE TABLE IF NOT EXISTS wallet_balance_snapshots (
+    wallet_id UUID NOT NULL,
+    snapshot_date bigint NOT NULL,
+    balance DECIMAL(15,2) NOT NULL,
+
+    created_at bigint,
+    updated_at bigint,
+    PRIMARY KEY (wallet_id, snapshot_date),
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT
+);
+
+-- Value of one unit of currency in the configured base currency, effective from rate_date
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency VARCHAR(10) NOT NULL,
+    rate_date bigint NOT NULL,
+    rate DECIMAL(20,10) NOT NULL,
+
+    created_at bigint,
+    updated_at bigint,
+    PRIMARY KEY (currency, rate_date),
+    CONSTRAINT chk_exchange_rates_rate CHECK (rate > 0)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS exchange_rates;
+DROP TABLE IF EXISTS wallet_balance_snapshots;
+-- +goose StatementEnd
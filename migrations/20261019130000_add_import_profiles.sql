@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How a bank lays out its CSV statements, columns are zero-based and NULL when absent
+CREATE TABLE IF NOT EXISTS import_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    delimiter VARCHAR(1) NOT NULL,
+    has_header BOOLEAN NOT NULL,
+    date_column bigint NOT NULL,
+    date_format VARCHAR(50) NOT NULL,
+    timezone VARCHAR(100) NOT NULL,
+    amount_column bigint,
+    debit_column bigint,
+    credit_column bigint,
+    note_column bigint,
+    amount_sign VARCHAR(50) NOT NULL,
+    decimal_separator VARCHAR(1) NOT NULL,
+
+    created_at bigint,
+    updated_at bigint,
+    deleted_at bigint NOT NULL DEFAULT 0,
+    CONSTRAINT chk_import_profiles_amount CHECK (amount_column IS NOT NULL OR (debit_column IS NOT NULL AND credit_column IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_profiles_deleted_at ON import_profiles(deleted_at);
+
+CREATE TABLE IF NOT EXISTS has_import_profiles (
+    user_id UUID NOT NULL,
+    import_profile_id UUID NOT NULL,
+    PRIMARY KEY (user_id, import_profile_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    FOREIGN KEY (import_profile_id) REFERENCES import_profiles(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_has_import_profiles_user_id ON has_import_profiles(user_id);
+CREATE INDEX IF NOT EXISTS idx_has_import_profiles_import_profile_id ON has_import_profiles(import_profile_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS has_import_profiles;
+DROP TABLE IF EXISTS import_profiles;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The bank's own id for imported transactions, re-importing a statement must not book them twice
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_wallet_external_id ON transactions(wallet_id, external_id)
+    WHERE external_id IS NOT NULL AND deleted_at = 0;
+
+-- Which wallet each bank account named in a user's statement files is booked to
+CREATE TABLE IF NOT EXISTS import_accounts (
+    user_id UUID NOT NULL,
+    account_id VARCHAR(255) NOT NULL,
+    wallet_id UUID NOT NULL,
+
+    created_at bigint,
+    updated_at bigint,
+    PRIMARY KEY (user_id, account_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_accounts_wallet_id ON import_accounts(wallet_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS import_accounts;
+DROP INDEX IF EXISTS idx_transactions_wallet_external_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS external_id;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change bumps the version, updates are only applied to the version the client last read
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions DROP COLUMN IF EXISTS version;
+ALTER TABLE budgets DROP COLUMN IF EXISTS version;
+ALTER TABLE wallets DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Free-form labels on transactions, names are lowercase and unique per user
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    name VARCHAR(50) NOT NULL,
+
+    created_at bigint,
+    updated_at bigint,
+    deleted_at bigint NOT NULL DEFAULT 0,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+-- The pattern operator class lets autocomplete look names up by prefix
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, name varchar_pattern_ops) WHERE deleted_at = 0;
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags(deleted_at);
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+    transaction_id UUID NOT NULL,
+    tag_id UUID NOT NULL,
+
+    created_at bigint,
+    PRIMARY KEY (transaction_id, tag_id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag_id ON transaction_tags(tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transaction_tags;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
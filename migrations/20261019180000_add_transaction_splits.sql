@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lines of a transaction split across budgets, the transaction itself then has no budget
+CREATE TABLE IF NOT EXISTS transaction_splits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    note TEXT,
+    budget_id UUID,
+
+    created_at bigint,
+    CONSTRAINT chk_transaction_splits_amount CHECK (amount > 0),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT,
+    FOREIGN KEY (budget_id) REFERENCES budgets(id) ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transaction_splits_position ON transaction_splits(transaction_id, position);
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_budget_id ON transaction_splits(budget_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transaction_splits;
+-- +goose StatementEnd
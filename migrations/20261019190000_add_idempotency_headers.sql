@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Headers of a stored response the client needs on a replay, the ETag to send back in If-Match
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS etag VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS location TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS location;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS etag;
+-- +goose StatementEnd
@@ -0,0 +1,184 @@
+package apperror
+
+import (
+	"errors"
+	"finance-backend/internal/model"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Code is a stable, machine readable error identifier exposed to clients
+type Code string
+
+const (
+	CodeBadRequest         Code = "BAD_REQUEST"
+	CodeValidationFailed   Code = "VALIDATION_FAILED"
+	CodeUnauthorized       Code = "UNAUTHORIZED"
+	CodeInvalidCredentials Code = "INVALID_CREDENTIALS"
+	CodeInvalidToken       Code = "INVALID_TOKEN"
+	CodeForbidden          Code = "FORBIDDEN"
+	CodeAccountDisabled    Code = "ACCOUNT_DISABLED"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeMethodNotAllowed   Code = "METHOD_NOT_ALLOWED"
+	CodeConflict           Code = "CONFLICT"
+	CodeUserAlreadyExists  Code = "USER_ALREADY_EXISTS"
+	CodeIdempotencyReused  Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeInsufficientFunds  Code = "INSUFFICIENT_BALANCE"
+	CodePreconditionFailed Code = "PRECONDITION_FAILED"
+	CodePreconditionNeeded Code = "PRECONDITION_REQUIRED"
+	CodeTooManyRequests    Code = "TOO_MANY_REQUESTS"
+	CodeInternal           Code = "INTERNAL_ERROR"
+	CodeUnavailable        Code = "SERVICE_UNAVAILABLE"
+)
+
+// Error is an application error carrying everything needed to build a response.
+// Message is always safe to show to clients, the wrapped Err is only logged.
+type Error struct {
+	Code    Code
+	Status  int
+	Message string
+	Details interface{}
+	Fields  []model.FieldError
+	Err     error
+}
+
+var (
+	ErrBadRequest         = New(CodeBadRequest, http.StatusBadRequest, "invalid request")
+	ErrUnauthorized       = New(CodeUnauthorized, http.StatusUnauthorized, "unauthorized")
+	ErrInvalidCredentials = New(CodeInvalidCredentials, http.StatusUnauthorized, "invalid email or password")
+	ErrInvalidToken       = New(CodeInvalidToken, http.StatusUnauthorized, "invalid token")
+	ErrForbidden          = New(CodeForbidden, http.StatusForbidden, "forbidden")
+	ErrAccountDisabled    = New(CodeAccountDisabled, http.StatusForbidden, "account disabled")
+	ErrNotFound           = New(CodeNotFound, http.StatusNotFound, "resource not found")
+	ErrConflict           = New(CodeConflict, http.StatusConflict, "resource conflict")
+	ErrUserAlreadyExists  = New(CodeUserAlreadyExists, http.StatusConflict, "user already exists")
+	ErrIdempotencyReused  = New(CodeIdempotencyReused, http.StatusUnprocessableEntity, "idempotency key was already used for a different request")
+	ErrInsufficientFunds  = New(CodeInsufficientFunds, http.StatusUnprocessableEntity, "insufficient balance")
+	ErrPreconditionFailed = New(CodePreconditionFailed, http.StatusPreconditionFailed, "resource was changed since it was read")
+	ErrPreconditionNeeded = New(CodePreconditionNeeded, http.StatusPreconditionRequired, "If-Match header is required")
+	ErrInternal           = New(CodeInternal, http.StatusInternalServerError, "internal server error")
+	ErrUnavailable        = New(CodeUnavailable, http.StatusServiceUnavailable, "service unavailable")
+)
+
+// New creates an application error
+func New(code Code, status int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+// NewValidation creates a validation error listing the failing fields
+func NewValidation(fields []model.FieldError) *Error {
+	return &Error{
+		Code:    CodeValidationFailed,
+		Status:  http.StatusUnprocessableEntity,
+		Message: "validation failed",
+		Fields:  fields,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return string(e.Code) + ": " + e.Message + ": " + e.Err.Error()
+	}
+	return string(e.Code) + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an application error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// Wrap returns a copy of the error with err attached as the cause
+func (e *Error) Wrap(err error) *Error {
+	clone := *e
+	clone.Err = err
+	return &clone
+}
+
+// WithMessage returns a copy of the error with a different public message
+func (e *Error) WithMessage(message string) *Error {
+	clone := *e
+	clone.Message = message
+	return &clone
+}
+
+// WithDetails returns a copy of the error with additional public details
+func (e *Error) WithDetails(details interface{}) *Error {
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+// From converts any error into an application error. Unknown errors become
+// internal errors so their text never reaches the client.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return New(codeFromStatus(fiberErr.Code), fiberErr.Code, fiberErr.Message).Wrap(err)
+	}
+
+	return ErrInternal.Wrap(err)
+}
+
+// ToResponse builds the response envelope for the error
+func (e *Error) ToResponse() *model.Response {
+	response := model.NewResponseError(e.Message)
+	response.Code = string(e.Code)
+	response.Details = e.Details
+	response.Errors = e.Fields
+	return response
+}
+
+// codeFromStatus maps an HTTP status onto the closest application code
+func codeFromStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusPreconditionRequired:
+		return CodePreconditionNeeded
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
@@ -2,10 +2,11 @@ package auth
 
 import (
 	"errors"
-	"os"
+	"finance-backend/pkg/config"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // JWTClaims represents the claims in JWT token
@@ -15,29 +16,46 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
+// TokenManager signs and validates JWT tokens
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+	issuer string
+}
+
+// NewTokenManager creates a token manager from the JWT configuration
+func NewTokenManager(config config.JWTConfig) *TokenManager {
+	return &TokenManager{
+		secret: []byte(config.Secret),
+		ttl:    config.TTL,
+		issuer: config.Issuer,
+	}
+}
+
 // GenerateToken generates a JWT token for the user
-func GenerateToken(userID, email string) (string, time.Time, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", time.Time{}, errors.New("JWT_SECRET not set")
+func (m *TokenManager) GenerateToken(userID, email string) (string, time.Time, error) {
+	if len(m.secret) == 0 {
+		return "", time.Time{}, errors.New("JWT secret not set")
 	}
 
-	expirationTime := time.Now().Add(24 * time.Hour) // 24 hours
+	now := time.Now()
+	expirationTime := now.Add(m.ttl)
 
 	claims := &JWTClaims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "finance-api",
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    m.issuer,
 			Subject:   userID,
+			ID:        uuid.NewString(),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString(m.secret)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -46,16 +64,15 @@ func GenerateToken(userID, email string) (string, time.Time, error) {
 }
 
 // ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*JWTClaims, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return nil, errors.New("JWT_SECRET not set")
+func (m *TokenManager) ValidateToken(tokenString string) (*JWTClaims, error) {
+	if len(m.secret) == 0 {
+		return nil, errors.New("JWT secret not set")
 	}
 
 	claims := &JWTClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,422 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvProduction = "production"
+
+	// secretFileSuffix lets any variable be read from a file, e.g. JWT_SECRET_FILE
+	secretFileSuffix = "_FILE"
+)
+
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Config is the complete application configuration
+type Config struct {
+	Env string `yaml:"env" env:"ENV"`
+
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Migration MigrationConfig `yaml:"migration"`
+	Log       LogConfig       `yaml:"log"`
+	JWT       JWTConfig       `yaml:"jwt"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Report    ReportConfig    `yaml:"report"`
+}
+
+// ServerConfig holds HTTP server configuration
+type ServerConfig struct {
+	Port            string        `yaml:"port" env:"SERVER_PORT"`
+	RequestTimeout  time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	DrainDelay      time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	// IdempotencyTTL is how long a response is replayed for a retried Idempotency-Key
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL"`
+	// IdempotencyLockTimeout is how long a key stays claimed by a request that never finished,
+	// a retry after that takes the key over
+	IdempotencyLockTimeout time.Duration `yaml:"idempotency_lock_timeout" env:"IDEMPOTENCY_LOCK_TIMEOUT"`
+}
+
+// DatabaseConfig holds database connection and pool configuration
+type DatabaseConfig struct {
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            string        `yaml:"port" env:"DB_PORT"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD"`
+	DBName          string        `yaml:"name" env:"DB_NAME"`
+	SSLMode         string        `yaml:"ssl_mode" env:"DB_SSLMODE"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	LogLevel        string        `yaml:"log_level" env:"DB_LOG_LEVEL"`
+}
+
+// MigrationConfig holds schema migration configuration
+type MigrationConfig struct {
+	Dir string `yaml:"dir" env:"MIGRATIONS_DIR"`
+	// AutoMigrate runs pending migrations when the server boots
+	AutoMigrate bool `yaml:"auto_migrate" env:"MIGRATE_ON_START"`
+}
+
+// LogConfig holds logger configuration
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// JWTConfig holds token signing configuration
+type JWTConfig struct {
+	Secret string        `yaml:"secret" env:"JWT_SECRET"`
+	TTL    time.Duration `yaml:"ttl" env:"JWT_TTL"`
+	Issuer string        `yaml:"issuer" env:"JWT_ISSUER"`
+}
+
+// MetricsConfig holds the admin metrics server configuration
+type MetricsConfig struct {
+	Port  string `yaml:"port" env:"METRICS_PORT"`
+	Token string `yaml:"token" env:"METRICS_TOKEN"`
+}
+
+// TracingConfig holds OpenTelemetry configuration
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	FilePath     string  `yaml:"file_path" env:"TRACING_FILE"`
+	ServiceName  string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio  float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// ReportConfig holds reporting and balance history configuration
+type ReportConfig struct {
+	// BaseCurrency is the currency net worth is converted to
+	BaseCurrency string `yaml:"base_currency" env:"BASE_CURRENCY"`
+	// SnapshotJob records every wallet's balance once a day
+	SnapshotJob bool `yaml:"snapshot_job" env:"SNAPSHOT_JOB_ENABLED"`
+}
+
+// Options controls where configuration is loaded from
+type Options struct {
+	// File is an optional YAML file
+	File string
+	// EnvFile is an optional dotenv file, missing files are ignored
+	EnvFile string
+}
+
+// Default returns the configuration used when nothing overrides a value
+func Default() *Config {
+	return &Config{
+		Env: "development",
+		Server: ServerConfig{
+			Port:                   "8080",
+			RequestTimeout:         30 * time.Second,
+			ShutdownTimeout:        30 * time.Second,
+			DrainDelay:             5 * time.Second,
+			IdempotencyTTL:         24 * time.Hour,
+			IdempotencyLockTimeout: time.Minute,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			User:            "postgres",
+			DBName:          "finance_db",
+			SSLMode:         "disable",
+			MaxIdleConns:    10,
+			MaxOpenConns:    100,
+			ConnMaxLifetime: time.Hour,
+		},
+		Migration: MigrationConfig{
+			Dir:         "migrations",
+			AutoMigrate: true,
+		},
+		Log: LogConfig{
+			Level: "INFO",
+		},
+		JWT: JWTConfig{
+			TTL:    24 * time.Hour,
+			Issuer: "finance-api",
+		},
+		Metrics: MetricsConfig{
+			Port: "9090",
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "http://localhost:4318",
+			FilePath:     "traces.json",
+			ServiceName:  "finance-backend",
+			SampleRatio:  1,
+		},
+		Report: ReportConfig{
+			BaseCurrency: "USD",
+			SnapshotJob:  true,
+		},
+	}
+}
+
+// Load builds the configuration. Later sources win:
+// defaults, then the YAML file, then the dotenv file, then process environment variables.
+// Any variable can also be read from the file named by its _FILE variant.
+func Load(options Options) (*Config, error) {
+	config := Default()
+
+	if options.File != "" {
+		content, err := os.ReadFile(options.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(content, config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	dotenv := map[string]string{}
+	if options.EnvFile != "" {
+		values, err := godotenv.Read(options.EnvFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read env file: %w", err)
+		}
+		if values != nil {
+			dotenv = values
+		}
+	}
+
+	lookup := func(key string) (string, bool) {
+		if value, ok := os.LookupEnv(key); ok {
+			return value, true
+		}
+		value, ok := dotenv[key]
+		return value, ok
+	}
+
+	if err := applyEnv(reflect.ValueOf(config).Elem(), lookup); err != nil {
+		return nil, err
+	}
+
+	config.applyDerivedDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// IsProduction reports whether the app runs in production
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// Validate checks that required values are present and consistent
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.JWT.Secret == "" {
+		errs = append(errs, errors.New("JWT_SECRET is required"))
+	} else if c.IsProduction() && len(c.JWT.Secret) < 32 {
+		errs = append(errs, errors.New("JWT_SECRET must be at least 32 characters in production"))
+	}
+	if c.JWT.TTL <= 0 {
+		errs = append(errs, errors.New("JWT_TTL must be positive"))
+	}
+
+	if err := validatePort("SERVER_PORT", c.Server.Port); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validatePort("METRICS_PORT", c.Metrics.Port); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Server.Port == c.Metrics.Port {
+		errs = append(errs, errors.New("METRICS_PORT must differ from SERVER_PORT"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.Server.IdempotencyTTL <= 0 {
+		errs = append(errs, errors.New("IDEMPOTENCY_TTL must be positive"))
+	}
+	// A request still running must not lose its key to a retry
+	if c.Server.IdempotencyLockTimeout <= 0 || c.Server.IdempotencyLockTimeout <= c.Server.RequestTimeout {
+		errs = append(errs, errors.New("IDEMPOTENCY_LOCK_TIMEOUT must be positive and longer than REQUEST_TIMEOUT"))
+	}
+
+	if c.Database.Host == "" {
+		errs = append(errs, errors.New("DB_HOST is required"))
+	}
+	if c.Database.DBName == "" {
+		errs = append(errs, errors.New("DB_NAME is required"))
+	}
+	if c.Database.MaxOpenConns <= 0 {
+		errs = append(errs, errors.New("DB_MAX_OPEN_CONNS must be positive"))
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS"))
+	}
+
+	switch c.Database.LogLevel {
+	case "silent", "error", "warn", "info":
+	default:
+		errs = append(errs, fmt.Errorf("DB_LOG_LEVEL %q must be one of silent, error, warn, info", c.Database.LogLevel))
+	}
+
+	switch strings.ToUpper(c.Log.Level) {
+	case "DEBUG", "INFO", "WARN", "ERROR":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_LEVEL %q must be one of DEBUG, INFO, WARN, ERROR", c.Log.Level))
+	}
+	switch c.Log.Format {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_FORMAT %q must be json or text", c.Log.Format))
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout", "file":
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER %q must be one of none, otlp, stdout, file", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1"))
+	}
+
+	if !currencyRegex.MatchString(c.Report.BaseCurrency) {
+		errs = append(errs, fmt.Errorf("BASE_CURRENCY %q must be a 3-letter uppercase currency code", c.Report.BaseCurrency))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// applyDerivedDefaults fills values whose default depends on the environment
+func (c *Config) applyDerivedDefaults() {
+	if c.Database.LogLevel == "" {
+		c.Database.LogLevel = "info"
+		if c.IsProduction() {
+			c.Database.LogLevel = "error"
+		}
+	}
+
+	if c.Log.Format == "" {
+		c.Log.Format = "text"
+		if c.IsProduction() {
+			c.Log.Format = "json"
+		}
+	}
+}
+
+// applyEnv overrides every field tagged with `env`, recursing into nested structs
+func applyEnv(value reflect.Value, lookup func(string) (string, bool)) error {
+	valueType := value.Type()
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		structField := valueType.Field(i)
+
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Duration(0)) {
+			if err := applyEnv(field, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := structField.Tag.Get("env")
+		if key == "" {
+			continue
+		}
+
+		raw, ok, err := lookupWithFile(key, lookup)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if err := setField(field, raw); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupWithFile reads key, falling back to the contents of the file named by key_FILE
+func lookupWithFile(key string, lookup func(string) (string, bool)) (string, bool, error) {
+	if value, ok := lookup(key); ok && value != "" {
+		return value, true, nil
+	}
+
+	path, ok := lookup(key + secretFileSuffix)
+	if !ok || path == "" {
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", key+secretFileSuffix, err)
+	}
+
+	return strings.TrimSpace(string(content)), true, nil
+}
+
+// setField parses raw into the field according to its type
+func setField(field reflect.Value, raw string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		number, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(number)
+	case reflect.Float64:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(number)
+	case reflect.Bool:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(flag)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
+
+// validatePort checks that port is a valid TCP port number
+func validatePort(name, port string) error {
+	number, err := strconv.Atoi(port)
+	if err != nil || number <= 0 || number > 65535 {
+		return fmt.Errorf("%s %q must be a valid port", name, port)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlFile := writeFile(t, dir, "config.yaml", `
+server:
+  port: "7000"
+  request_timeout: 10s
+database:
+  host: yaml-host
+  name: yaml-db
+  max_open_conns: 50
+jwt:
+  secret: yaml-secret
+`)
+	envFile := writeFile(t, dir, ".env", "DB_HOST=dotenv-host\nSERVER_PORT=7100\n")
+	secretFile := writeFile(t, dir, "jwt_secret", "file-secret\n")
+
+	t.Setenv("SERVER_PORT", "7200")
+	t.Setenv("JWT_SECRET_FILE", secretFile)
+
+	cfg, err := Load(Options{File: yamlFile, EnvFile: envFile})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"default survives", cfg.Database.Port, "5432"},
+		{"yaml overrides default", cfg.Database.DBName, "yaml-db"},
+		{"yaml duration", cfg.Server.RequestTimeout, 10 * time.Second},
+		{"yaml int", cfg.Database.MaxOpenConns, 50},
+		{"dotenv overrides yaml", cfg.Database.Host, "dotenv-host"},
+		{"environment overrides dotenv", cfg.Server.Port, "7200"},
+		{"secret file overrides yaml", cfg.JWT.Secret, "file-secret"},
+		{"derived log format", cfg.Log.Format, "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "missing jwt secret",
+			env:     map[string]string{},
+			wantErr: "JWT_SECRET is required",
+		},
+		{
+			name:    "short secret in production",
+			env:     map[string]string{"JWT_SECRET": "short", "ENV": "production"},
+			wantErr: "at least 32 characters",
+		},
+		{
+			name:    "idle connections above open connections",
+			env:     map[string]string{"JWT_SECRET": "secret", "DB_MAX_IDLE_CONNS": "20", "DB_MAX_OPEN_CONNS": "10"},
+			wantErr: "DB_MAX_IDLE_CONNS",
+		},
+		{
+			name:    "malformed duration",
+			env:     map[string]string{"JWT_SECRET": "secret", "REQUEST_TIMEOUT": "soon"},
+			wantErr: "REQUEST_TIMEOUT",
+		},
+		{
+			name:    "idempotency keys that never live",
+			env:     map[string]string{"JWT_SECRET": "secret", "IDEMPOTENCY_TTL": "0s"},
+			wantErr: "IDEMPOTENCY_TTL",
+		},
+		{
+			name:    "idempotency lock shorter than a request",
+			env:     map[string]string{"JWT_SECRET": "secret", "REQUEST_TIMEOUT": "2m", "IDEMPOTENCY_LOCK_TIMEOUT": "1m"},
+			wantErr: "IDEMPOTENCY_LOCK_TIMEOUT",
+		},
+		{
+			name:    "lowercase base currency",
+			env:     map[string]string{"JWT_SECRET": "secret", "BASE_CURRENCY": "usd"},
+			wantErr: "BASE_CURRENCY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := Load(Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
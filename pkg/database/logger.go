@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"finance-backend/pkg/logger"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold marks queries that are logged as warnings
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger writes gorm logs through logrus, tagged with the request context
+type gormLogger struct {
+	level gormlogger.LogLevel
+}
+
+// NewGormLogger returns a gorm logger that correlates SQL with the request ID in ctx
+func NewGormLogger(level gormlogger.LogLevel) gormlogger.Interface {
+	return &gormLogger{level: level}
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &gormLogger{level: level}
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		logger.WithRequestID(ctx).Infof("[gorm]: "+msg, args...)
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		logger.WithRequestID(ctx).Warnf("[gorm]: "+msg, args...)
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		logger.WithRequestID(ctx).Errorf("[gorm]: "+msg, args...)
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+
+	entry := logger.WithRequestID(ctx).WithFields(logrus.Fields{
+		"sql":      sql,
+		"rows":     rows,
+		"duration": elapsed.String(),
+	})
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		entry.WithError(err).Error("[gorm]: Query failed")
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		entry.Warn(fmt.Sprintf("[gorm]: Slow query over %s", slowQueryThreshold))
+	case l.level >= gormlogger.Info:
+		entry.Info("[gorm]: Query executed")
+	}
+}
@@ -2,44 +2,35 @@ package database
 
 import (
 	"database/sql"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/metrics"
+	"finance-backend/pkg/tracing"
 	"fmt"
 	"log"
-	"os"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-// Config holds database configuration
-type Config struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-}
-
 // NewConnection creates a new database connection
-func NewConnection(config *Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
-
-	// Set log level based on environment
-	logLevel := logger.Info
-	if os.Getenv("ENV") == "production" {
-		logLevel = logger.Error
-	}
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
+func NewConnection(config config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(DSN(config)), &gorm.Config{
+		Logger: NewGormLogger(logLevel(config.LogLevel)),
 	})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.Use(metrics.NewGormPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register metrics plugin: %w", err)
+	}
+
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
 	// Get underlying sql.DB to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -47,31 +38,18 @@ func NewConnection(config *Config) (*gorm.DB, error) {
 	}
 
 	// Configure connection pool
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
 
 	log.Println("Database connection established successfully")
 	return db, nil
 }
 
-// GetConfigFromEnv returns database configuration from environment variables
-func GetConfigFromEnv() *Config {
-	return &Config{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnv("DB_PORT", "5432"),
-		User:     getEnv("DB_USER", "postgres"),
-		Password: getEnv("DB_PASSWORD", ""),
-		DBName:   getEnv("DB_NAME", "finance_db"),
-		SSLMode:  getEnv("DB_SSLMODE", "disable"),
-	}
-}
-
-// getEnv gets environment variable with fallback
-func getEnv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return fallback
+// DSN builds the postgres connection string
+func DSN(config config.DatabaseConfig) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
 }
 
 // GetSQLDB returns the underlying sql.DB instance from GORM DB
@@ -82,3 +60,16 @@ func GetSQLDB(db *gorm.DB) (*sql.DB, error) {
 	}
 	return sqlDB, nil
 }
+
+// logLevel maps the configured level onto the gorm log level
+func logLevel(level string) logger.LogLevel {
+	switch level {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "warn":
+		return logger.Warn
+	}
+	return logger.Info
+}
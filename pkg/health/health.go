@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/migration"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+
+	// checkTimeout bounds each dependency check so probes answer quickly
+	checkTimeout = 2 * time.Second
+
+	// poolSaturationThreshold is the in-use ratio reported as degraded
+	poolSaturationThreshold = 0.9
+)
+
+// Checker answers liveness and readiness probes
+type Checker struct {
+	db            *sql.DB
+	migrationsDir string
+	draining      atomic.Bool
+}
+
+// NewChecker creates a checker for the given database and migrations directory
+func NewChecker(db *sql.DB, migrationsDir string) *Checker {
+	return &Checker{
+		db:            db,
+		migrationsDir: migrationsDir,
+	}
+}
+
+// SetDraining marks the server as draining so readiness starts failing
+func (h *Checker) SetDraining(draining bool) {
+	h.draining.Store(draining)
+}
+
+// Draining reports whether the server is shutting down
+func (h *Checker) Draining() bool {
+	return h.draining.Load()
+}
+
+// Liveness reports that the process is up and able to serve requests
+func (h *Checker) Liveness(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(model.HealthReport{
+		Status: StatusOK,
+		Checks: []model.HealthCheck{},
+	}))
+}
+
+// Readiness checks every dependency and fails when the server should not receive traffic
+func (h *Checker) Readiness(c *fiber.Ctx) error {
+	report := h.Check(c.UserContext())
+
+	if report.Status == StatusFail {
+		response := model.NewResponseError("service not ready")
+		response.Code = string(apperror.CodeUnavailable)
+		response.Data = report
+		return c.Status(fiber.StatusServiceUnavailable).JSON(response)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.NewResponseSuccess(report))
+}
+
+// Check runs all readiness checks
+func (h *Checker) Check(ctx context.Context) model.HealthReport {
+	checks := []model.HealthCheck{
+		h.run(ctx, "draining", h.checkDraining),
+		h.run(ctx, "database", h.checkDatabase),
+		h.run(ctx, "migrations", h.checkMigrations),
+		h.run(ctx, "connection_pool", h.checkPool),
+	}
+
+	status := StatusOK
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			status = StatusFail
+			break
+		}
+		if check.Status == StatusDegraded {
+			status = StatusDegraded
+		}
+	}
+
+	return model.HealthReport{
+		Status: status,
+		Checks: checks,
+	}
+}
+
+// run executes a single check with a timeout and measures its latency
+func (h *Checker) run(ctx context.Context, name string, check func(context.Context) (string, string)) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	status, message := check(ctx)
+
+	return model.HealthCheck{
+		Name:      name,
+		Status:    status,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Message:   message,
+	}
+}
+
+func (h *Checker) checkDraining(context.Context) (string, string) {
+	if h.Draining() {
+		return StatusFail, "server is shutting down"
+	}
+	return StatusOK, ""
+}
+
+func (h *Checker) checkDatabase(ctx context.Context) (string, string) {
+	if err := h.db.PingContext(ctx); err != nil {
+		return StatusFail, "database unreachable"
+	}
+	return StatusOK, ""
+}
+
+func (h *Checker) checkMigrations(ctx context.Context) (string, string) {
+	current, latest, err := migration.Versions(ctx, h.db, h.migrationsDir)
+	if err != nil {
+		return StatusFail, "failed to read migration version"
+	}
+
+	if current < latest {
+		return StatusFail, fmt.Sprintf("database at version %d, latest is %d", current, latest)
+	}
+	return StatusOK, fmt.Sprintf("version %d", current)
+}
+
+func (h *Checker) checkPool(context.Context) (string, string) {
+	stats := h.db.Stats()
+	message := fmt.Sprintf("%d in use, %d idle, %d max, %d waits", stats.InUse, stats.Idle, stats.MaxOpenConnections, stats.WaitCount)
+
+	if stats.MaxOpenConnections > 0 && float64(stats.InUse)/float64(stats.MaxOpenConnections) >= poolSaturationThreshold {
+		return StatusDegraded, message
+	}
+	return StatusOK, message
+}
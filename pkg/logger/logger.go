@@ -2,7 +2,10 @@ package logger
 
 import (
 	"context"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/requestctx"
 	"os"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -10,11 +13,11 @@ import (
 var Log *logrus.Logger
 
 // InitLogger initializes the logger with proper configuration
-func InitLogger() {
+func InitLogger(config config.LogConfig) {
 	Log = logrus.New()
 
-	// Set log level based on environment
-	switch os.Getenv("LOG_LEVEL") {
+	// Set log level based on configuration
+	switch strings.ToUpper(config.Level) {
 	case "DEBUG":
 		Log.SetLevel(logrus.DebugLevel)
 	case "INFO":
@@ -28,7 +31,7 @@ func InitLogger() {
 	}
 
 	// Set log format
-	if os.Getenv("ENV") == "production" {
+	if config.Format == "json" {
 		Log.SetFormatter(&logrus.JSONFormatter{
 			TimestampFormat: "2006-01-02 15:04:05",
 		})
@@ -43,10 +46,13 @@ func InitLogger() {
 	Log.SetOutput(os.Stdout)
 }
 
-// WithRequestID creates a logger entry
+// WithRequestID creates a logger entry tagged with the request and user IDs carried by ctx
 func WithRequestID(ctx context.Context) *logrus.Entry {
-	requestID, _ := ctx.Value("request_id").(string)
-	return Log.WithField("request_id", requestID)
+	entry := Log.WithField("request_id", requestctx.RequestID(ctx))
+	if userID := requestctx.UserID(ctx); userID != "" {
+		entry = entry.WithField("user_id", userID)
+	}
+	return entry
 }
 
 // WithFields creates a logger entry with custom fields
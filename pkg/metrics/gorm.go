@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startTimeKey = "metrics:start_time"
+
+// GormPlugin records query duration and error counts for every gorm statement
+type GormPlugin struct{}
+
+// NewGormPlugin returns the gorm metrics plugin
+func NewGormPlugin() gorm.Plugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+
+	// "*" places the hooks at the very start and end of each callback chain
+	registrations := []error{
+		callback.Create().Before("*").Register("metrics:before_create", before),
+		callback.Create().After("*").Register("metrics:after_create", after("create")),
+		callback.Query().Before("*").Register("metrics:before_query", before),
+		callback.Query().After("*").Register("metrics:after_query", after("query")),
+		callback.Update().Before("*").Register("metrics:before_update", before),
+		callback.Update().After("*").Register("metrics:after_update", after("update")),
+		callback.Delete().Before("*").Register("metrics:before_delete", before),
+		callback.Delete().After("*").Register("metrics:after_delete", after("delete")),
+		callback.Row().Before("*").Register("metrics:before_row", before),
+		callback.Row().After("*").Register("metrics:after_row", after("row")),
+		callback.Raw().Before("*").Register("metrics:before_raw", before),
+		callback.Raw().After("*").Register("metrics:after_raw", after("raw")),
+	}
+
+	return errors.Join(registrations...)
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			DBQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
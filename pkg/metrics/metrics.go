@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "finance"
+
+// Registry holds every metric exposed on /metrics
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// HTTPRequestsTotal counts handled requests by route template and status
+	HTTPRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by route template and status
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration observes gorm statement latency by operation and table
+	DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Database query latency in seconds.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// DBQueryErrors counts failed gorm statements by operation and table
+	DBQueryErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Total number of failed database queries.",
+	}, []string{"operation", "table"})
+
+	// TransactionsCreated counts transactions created by type
+	TransactionsCreated = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_created_total",
+		Help:      "Total number of transactions created.",
+	}, []string{"type"})
+
+	// Registrations counts successful user registrations
+	Registrations = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Total number of successful user registrations.",
+	})
+
+	// FailedLogins counts rejected login attempts by reason
+	FailedLogins = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_logins_total",
+		Help:      "Total number of failed login attempts.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDBStats exposes the sql.DB connection pool statistics
+func RegisterDBStats(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"errors"
+	"finance-backend/pkg/apperror"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// unmatchedRoute labels requests that did not match any route, keeping label cardinality bounded
+const unmatchedRoute = "unmatched"
+
+// Middleware records request counts and latency by route template and status
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+
+		// Errors are rendered by the app error handler after the chain returns
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = apperror.From(err).Status
+		}
+
+		// The router reports a missing route as a plain fiber 404
+		route := c.Route().Path
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusNotFound {
+			route = unmatchedRoute
+		}
+
+		labels := []string{c.Method(), route, strconv.Itoa(status)}
+		HTTPRequestsTotal.WithLabelValues(labels...).Inc()
+		HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
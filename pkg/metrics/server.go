@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/auth"
+	middleware "finance-backend/pkg/midleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewAdminApp returns the app served on the admin port. When token is set,
+// scrapers must send it as a bearer token.
+func NewAdminApp(token string) *fiber.App {
+	app := fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+		ErrorHandler:          middleware.ErrorHandler,
+	})
+
+	if token != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			provided, err := auth.ExtractTokenFromBearer(c.Get("Authorization"))
+			if err != nil || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return apperror.ErrUnauthorized
+			}
+			return c.Next()
+		})
+	}
+
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})))
+
+	return app
+}
@@ -2,49 +2,39 @@ package middleware
 
 import (
 	"finance-backend/internal/domain"
+	"finance-backend/pkg/apperror"
 	"finance-backend/pkg/auth"
 	"finance-backend/pkg/logger"
-	"time"
+	"finance-backend/pkg/requestctx"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func AuthMiddleware(authService domain.AuthService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		log := logger.WithRequestID(c.Context())
+		log := logger.WithRequestID(c.UserContext())
 
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			log.Debug("[middleware - Auth]: Request attempted without authorization header")
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success":   false,
-				"message":   "Authorization header required",
-				"timestamp": time.Now().Format(time.RFC3339),
-			})
+			return apperror.ErrUnauthorized.WithMessage("authorization header required")
 		}
 
 		token, err := auth.ExtractTokenFromBearer(authHeader)
 		if err != nil {
 			log.WithError(err).Debug("[middleware - Auth]: Failed to extract token from authorization header")
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success":   false,
-				"error":     "Invalid authorization",
-				"timestamp": time.Now().Format(time.RFC3339),
-			})
+			return apperror.ErrUnauthorized.WithMessage("invalid authorization").Wrap(err)
 		}
 
-		user, err := authService.GetUserByToken(c.Context(), token)
+		user, err := authService.GetUserByToken(c.UserContext(), token)
 		if err != nil {
 			log.WithError(err).Debug("[middleware - Auth]: Failed to get user by token")
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success":   false,
-				"error":     "Invalid token",
-				"timestamp": time.Now().Format(time.RFC3339),
-			})
+			return err
 		}
 
 		c.Locals("userId", user.ID.String())
 		c.Locals("token", token)
+		c.SetUserContext(requestctx.WithUserID(c.UserContext(), user.ID.String()))
 
 		log.WithField("user_id", user.ID).Debug("[middleware - Auth]: User authenticated successfully")
 
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorHandler converts any error returned by a handler into the standard response envelope
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	log := logger.WithRequestID(c.UserContext())
+
+	appErr := apperror.From(err)
+
+	entry := log.WithError(err).WithFields(logrus.Fields{
+		"status_code": appErr.Status,
+		"error_code":  appErr.Code,
+	})
+	if appErr.Status >= fiber.StatusInternalServerError {
+		entry.Error("Request error")
+	} else {
+		entry.Info("Request error")
+	}
+
+	return c.Status(appErr.Status).JSON(appErr.ToResponse())
+}
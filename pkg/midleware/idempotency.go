@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a mutating request without it taking effect twice
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from the first request with the same key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength matches the column the key is stored in
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware replays the stored response when a POST, PUT, PATCH or DELETE is sent
+// again with the same Idempotency-Key. It runs after AuthMiddleware, keys are scoped per user.
+// The body is replayed with its Content-Type, ETag and Location. Server errors are not stored
+// so the request can be retried. A response that fails to store keeps its key pending, retries
+// are refused until the lock timeout passes instead of running the request twice.
+func IdempotencyMiddleware(idempotencyService domain.IdempotencyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Method()) {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return apperror.NewValidation([]model.FieldError{{Field: IdempotencyKeyHeader, Rule: "max", Message: "must be at most 255 characters"}})
+		}
+
+		log := logger.WithRequestID(c.UserContext())
+
+		userId := c.Locals("userId").(string)
+
+		stored, err := idempotencyService.Begin(c.UserContext(), userId, key, fingerprint(c))
+		if err != nil {
+			log.WithError(err).Debug("[middleware - Idempotency]: Failed to claim idempotency key")
+			return err
+		}
+		if stored != nil {
+			log.Debug("[middleware - Idempotency]: Replaying stored response")
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, stored.ContentType)
+			if stored.ETag != "" {
+				c.Set(fiber.HeaderETag, stored.ETag)
+			}
+			if stored.Location != "" {
+				c.Set(fiber.HeaderLocation, stored.Location)
+			}
+			return c.Status(stored.Status).Send(stored.Body)
+		}
+
+		// A handler that failed on the server, by an error or a panic, gives the key up again
+		handled := false
+		defer func() {
+			if handled {
+				return
+			}
+			if err := idempotencyService.Release(context.WithoutCancel(c.UserContext()), userId, key); err != nil {
+				log.WithError(err).Error("[middleware - Idempotency]: Failed to release idempotency key")
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			if apperror.From(err).Status >= fiber.StatusInternalServerError {
+				return err
+			}
+			// Client errors are the outcome of the request, they are rendered here so they can be replayed
+			if err := c.App().Config().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		response := c.Response()
+		if response.StatusCode() >= fiber.StatusInternalServerError {
+			return nil
+		}
+
+		// The request has taken effect, releasing the key from here on would let a retry repeat it
+		handled = true
+		if response.IsBodyStream() {
+			return nil
+		}
+
+		err = idempotencyService.Complete(context.WithoutCancel(c.UserContext()), userId, key, &domain.IdempotencyKey{
+			Status:      response.StatusCode(),
+			ContentType: string(response.Header.ContentType()),
+			ETag:        string(response.Header.Peek(fiber.HeaderETag)),
+			Location:    string(response.Header.Peek(fiber.HeaderLocation)),
+			Body:        append([]byte(nil), response.Body()...),
+		})
+		if err != nil {
+			log.WithError(err).Error("[middleware - Idempotency]: Failed to store response")
+		}
+
+		return nil
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// fingerprint identifies a request by its method, path with query and body
+func fingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + " " + c.OriginalURL() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"errors"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/domain/mocks"
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/logger"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	logger.InitLogger(config.LogConfig{Level: "ERROR", Format: "text"})
+
+	const (
+		userId = "7b0d7a52-8f43-4c4c-9d39-4ad0b0f1c5a1"
+		key    = "retry-1"
+	)
+	errStore := errors.New("connection reset")
+
+	tests := []struct {
+		name       string
+		handler    fiber.Handler
+		setup      func(idempotency *mocks.MockIdempotencyService)
+		wantStatus int
+	}{
+		{
+			name: "response is stored and the key kept",
+			handler: func(c *fiber.Ctx) error {
+				c.Set(fiber.HeaderLocation, "/v1/transaction/1")
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 1})
+			},
+			setup: func(idempotency *mocks.MockIdempotencyService) {
+				idempotency.EXPECT().Complete(gomock.Any(), userId, key, gomock.Any()).DoAndReturn(
+					func(_ any, _, _ string, response *domain.IdempotencyKey) error {
+						if response.Status != fiber.StatusCreated || response.Location != "/v1/transaction/1" || string(response.Body) != `{"id":1}` {
+							t.Errorf("stored %d %q %s, want the created response", response.Status, response.Location, response.Body)
+						}
+						return nil
+					})
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			// The transaction is already booked, giving the key up would let a retry book it again
+			name: "failed store keeps the key pending",
+			handler: func(c *fiber.Ctx) error {
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 1})
+			},
+			setup: func(idempotency *mocks.MockIdempotencyService) {
+				idempotency.EXPECT().Complete(gomock.Any(), userId, key, gomock.Any()).Return(errStore)
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "client error is stored",
+			handler: func(c *fiber.Ctx) error {
+				return apperror.ErrInsufficientFunds
+			},
+			setup: func(idempotency *mocks.MockIdempotencyService) {
+				idempotency.EXPECT().Complete(gomock.Any(), userId, key, gomock.Any()).Return(nil)
+			},
+			wantStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name: "server error releases the key",
+			handler: func(c *fiber.Ctx) error {
+				return apperror.ErrInternal
+			},
+			setup: func(idempotency *mocks.MockIdempotencyService) {
+				idempotency.EXPECT().Release(gomock.Any(), userId, key).Return(nil)
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+		{
+			name: "panic releases the key",
+			handler: func(c *fiber.Ctx) error {
+				panic("boom")
+			},
+			setup: func(idempotency *mocks.MockIdempotencyService) {
+				idempotency.EXPECT().Release(gomock.Any(), userId, key).Return(nil)
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			idempotency := mocks.NewMockIdempotencyService(ctrl)
+			idempotency.EXPECT().Begin(gomock.Any(), userId, key, gomock.Any()).Return(nil, nil)
+			tt.setup(idempotency)
+
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(recover.New())
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("userId", userId)
+				return c.Next()
+			})
+			app.Use(IdempotencyMiddleware(idempotency))
+			app.Post("/v1/transaction", tt.handler)
+
+			req := httptest.NewRequest(fiber.MethodPost, "/v1/transaction", strings.NewReader(`{"amount":10}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			req.Header.Set(IdempotencyKeyHeader, key)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"context"
 	"finance-backend/pkg/logger"
+	"finance-backend/pkg/requestctx"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,17 +11,44 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// RequestIDMiddleware adds a unique request ID to each request
+const (
+	// RequestIDHeader is the header used to receive and return the request ID
+	RequestIDHeader = "X-Request-ID"
+
+	// maxRequestIDLength bounds client supplied request IDs
+	maxRequestIDLength = 128
+)
+
+// RequestIDMiddleware adds a unique request ID to each request, honoring a valid incoming X-Request-ID
 func RequestIDMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Generate a new request ID
-		requestID := uuid.New().String()
+		requestID := c.Get(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
 
 		// Set request ID in context
 		c.Locals("request_id", requestID)
+		c.SetUserContext(requestctx.WithRequestID(c.UserContext(), requestID))
 
 		// Set request ID in response header
-		c.Set("X-Request-ID", requestID)
+		c.Set(RequestIDHeader, requestID)
+
+		return c.Next()
+	}
+}
+
+// TimeoutMiddleware gives the request context a deadline that services and repositories observe
+func TimeoutMiddleware(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+
+		c.SetUserContext(ctx)
 
 		return c.Next()
 	}
@@ -30,11 +59,8 @@ func LoggingMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
-		// Create logger entry with request ID
-		entry := logger.WithRequestID(c.Context())
-
 		// Log request
-		entry.WithFields(logrus.Fields{
+		logger.WithRequestID(c.UserContext()).WithFields(logrus.Fields{
 			"method":     c.Method(),
 			"path":       c.Path(),
 			"ip":         c.IP(),
@@ -47,13 +73,19 @@ func LoggingMiddleware() fiber.Handler {
 		// Calculate duration
 		duration := time.Since(start)
 
-		// Log response
-		entry.WithFields(logrus.Fields{
+		// Reading a streamed body would buffer all of it, its size is only known once it is sent
+		size := -1
+		if !c.Response().IsBodyStream() {
+			size = len(c.Response().Body())
+		}
+
+		// Log response, the context now also carries the user ID once authenticated
+		logger.WithRequestID(c.UserContext()).WithFields(logrus.Fields{
 			"method":   c.Method(),
 			"path":     c.Path(),
 			"status":   c.Response().StatusCode(),
 			"duration": duration.String(),
-			"size":     len(c.Response().Body()),
+			"size":     size,
 		}).Info("Request completed")
 
 		return err
@@ -67,3 +99,18 @@ func GetRequestID(c *fiber.Ctx) string {
 	}
 	return ""
 }
+
+// isValidRequestID accepts short, printable ASCII IDs so they are safe to log and echo back
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
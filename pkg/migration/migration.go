@@ -1,6 +1,7 @@
 package migration
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"path/filepath"
@@ -60,7 +61,41 @@ func MigrateReset(db *sql.DB, migrationsDir string) error {
 	return nil
 }
 
+// MigrateCreate writes a new timestamped SQL migration file.
+// Only SQL is offered, Go migrations would have to be compiled into the binary and registered to run.
+func MigrateCreate(migrationsDir, name string) error {
+	if err := goose.Create(nil, migrationsDir, name, "sql"); err != nil {
+		return fmt.Errorf("failed to create migration: %w", err)
+	}
+
+	return nil
+}
+
 // GetMigrationsDir returns the migrations directory path
 func GetMigrationsDir() string {
 	return filepath.Join(".", "migrations")
 }
+
+// Versions returns the version applied to the database and the latest version on disk
+func Versions(ctx context.Context, db *sql.DB, migrationsDir string) (int64, int64, error) {
+	if err := goose.SetDialect("postgres"); err != nil {
+		return 0, 0, fmt.Errorf("failed to set goose dialect: %w", err)
+	}
+
+	current, err := goose.GetDBVersionContext(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get database version: %w", err)
+	}
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get latest migration: %w", err)
+	}
+
+	return current, last.Version, nil
+}
@@ -0,0 +1,189 @@
+package migration_test
+
+import (
+	"finance-backend/internal/domain"
+	"finance-backend/internal/testdb"
+	"finance-backend/pkg/migration"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestMain(m *testing.M) {
+	testdb.Main(m)
+}
+
+// models lists every table the application reads or writes through gorm
+var models = []interface{}{
+	&domain.User{},
+	&domain.Session{},
+	&domain.Wallet{},
+	&domain.HasWallet{},
+	&domain.Budget{},
+	&domain.HasBudget{},
+	&domain.Transaction{},
+	&domain.HasTransaction{},
+	&domain.TransactionSplit{},
+	&domain.Tag{},
+	&domain.TransactionTag{},
+	&domain.WalletSnapshot{},
+	&domain.ExchangeRate{},
+	&domain.ImportProfile{},
+	&domain.HasImportProfile{},
+	&domain.ImportAccount{},
+	&domain.IdempotencyKey{},
+}
+
+var typeParams = regexp.MustCompile(`^([a-z ]+)(?:\((\d+)(?:,\s*(\d+))?\))?`)
+
+// normalizeType maps a gorm or postgres type name to the udt_name reported by information_schema
+func normalizeType(dataType string) (string, string) {
+	match := typeParams.FindStringSubmatch(strings.ToLower(strings.TrimSpace(dataType)))
+	if match == nil {
+		return dataType, ""
+	}
+
+	base := strings.TrimSpace(match[1])
+	switch base {
+	case "decimal":
+		base = "numeric"
+	case "bigint":
+		base = "int8"
+	case "integer", "int":
+		base = "int4"
+	case "smallint":
+		base = "int2"
+	case "boolean":
+		base = "bool"
+	case "character varying":
+		base = "varchar"
+	}
+
+	params := match[2]
+	if match[3] != "" {
+		params += "," + match[3]
+	}
+	return base, params
+}
+
+// liveParams renders the length or precision of a live column the way normalizeType does
+func liveParams(column gorm.ColumnType) string {
+	switch column.DatabaseTypeName() {
+	case "varchar":
+		if length, ok := column.Length(); ok {
+			return fmt.Sprint(length)
+		}
+	case "numeric":
+		if precision, scale, ok := column.DecimalSize(); ok {
+			return fmt.Sprintf("%d,%d", precision, scale)
+		}
+	}
+	return ""
+}
+
+func TestSchemaMatchesModels(t *testing.T) {
+	db := testdb.New(t)
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("failed to parse %T: %v", model, err)
+		}
+		table := stmt.Schema.Table
+
+		t.Run(table, func(t *testing.T) {
+			columnTypes, err := db.Migrator().ColumnTypes(model)
+			if err != nil {
+				t.Fatalf("failed to read columns: %v", err)
+			}
+			if len(columnTypes) == 0 {
+				t.Fatalf("table %s does not exist", table)
+			}
+
+			live := make(map[string]gorm.ColumnType, len(columnTypes))
+			for _, column := range columnTypes {
+				live[column.Name()] = column
+			}
+
+			fields := make(map[string]*schema.Field)
+			for _, field := range stmt.Schema.Fields {
+				if field.DBName == "" {
+					continue
+				}
+				fields[field.DBName] = field
+
+				column, ok := live[field.DBName]
+				if !ok {
+					t.Errorf("column %s.%s is missing", table, field.DBName)
+					continue
+				}
+
+				wantType, wantParams := normalizeType(db.Dialector.DataTypeOf(field))
+				gotType, gotParams := column.DatabaseTypeName(), liveParams(column)
+				if wantType != gotType || (wantParams != "" && wantParams != gotParams) {
+					t.Errorf("column %s.%s: model type %s(%s), database type %s(%s)", table, field.DBName, wantType, wantParams, gotType, gotParams)
+				}
+
+				wantNullable := !field.NotNull && !field.PrimaryKey
+				if nullable, ok := column.Nullable(); ok && nullable != wantNullable {
+					t.Errorf("column %s.%s: model nullable %t, database nullable %t", table, field.DBName, wantNullable, nullable)
+				}
+
+				if !field.PrimaryKey {
+					if unique, ok := column.Unique(); ok && unique != field.Unique {
+						t.Errorf("column %s.%s: model unique %t, database unique %t", table, field.DBName, field.Unique, unique)
+					}
+				}
+			}
+
+			for name := range live {
+				if _, ok := fields[name]; !ok {
+					t.Errorf("column %s.%s is not mapped by the model", table, name)
+				}
+			}
+
+			for _, index := range stmt.Schema.ParseIndexes() {
+				if !db.Migrator().HasIndex(model, index.Name) {
+					t.Errorf("index %s is missing", index.Name)
+				}
+			}
+
+			for _, check := range stmt.Schema.ParseCheckConstraints() {
+				if !db.Migrator().HasConstraint(model, check.Name) {
+					t.Errorf("check constraint %s is missing", check.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db := testdb.New(t)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql db: %v", err)
+	}
+
+	if err := migration.MigrateReset(sqlDB, testdb.MigrationsDir()); err != nil {
+		t.Fatalf("failed to roll back every migration: %v", err)
+	}
+
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		t.Fatalf("failed to list tables: %v", err)
+	}
+	for _, table := range tables {
+		if table != "goose_db_version" {
+			t.Errorf("table %s survived a full rollback", table)
+		}
+	}
+
+	if err := migration.MigrateUp(sqlDB, testdb.MigrationsDir()); err != nil {
+		t.Fatalf("failed to migrate up again: %v", err)
+	}
+}
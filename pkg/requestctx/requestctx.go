@@ -0,0 +1,33 @@
+package requestctx
+
+import "context"
+
+// contextKey is unexported so values can only be set through this package
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	userIDKey
+)
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or an empty string
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithUserID returns a copy of ctx carrying the authenticated user ID
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID returns the authenticated user ID stored in ctx, or an empty string
+func UserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
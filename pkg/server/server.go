@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"finance-backend/pkg/health"
+	"finance-backend/pkg/logger"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config holds shutdown behaviour
+type Config struct {
+	// ShutdownTimeout bounds how long in-flight requests, workers and hooks may take
+	ShutdownTimeout time.Duration
+	// DrainDelay keeps serving after readiness fails so load balancers can stop routing traffic
+	DrainDelay time.Duration
+}
+
+// Server runs the Fiber app and background workers and shuts them down gracefully
+type Server struct {
+	app           *fiber.App
+	healthChecker *health.Checker
+	config        Config
+
+	workersCtx    context.Context
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
+
+	hooks []func(context.Context) error
+}
+
+// New creates a server for app. healthChecker may be nil.
+func New(app *fiber.App, healthChecker *health.Checker, config Config) *Server {
+	workersCtx, cancelWorkers := context.WithCancel(context.Background())
+
+	return &Server{
+		app:           app,
+		healthChecker: healthChecker,
+		config:        config,
+		workersCtx:    workersCtx,
+		cancelWorkers: cancelWorkers,
+	}
+}
+
+// Go runs a background worker. Its context is cancelled on shutdown and the
+// server waits for it to return before running the shutdown hooks.
+func (s *Server) Go(worker func(ctx context.Context)) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		worker(s.workersCtx)
+	}()
+}
+
+// OnShutdown registers a hook run after requests and workers have finished, in registration order
+func (s *Server) OnShutdown(hook func(context.Context) error) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// ListenAndServe listens on addr and serves until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves on ln until ctx is cancelled, then shuts down gracefully
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.app.Listener(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// The listener failed on its own, still release workers and resources
+		return errors.Join(err, s.shutdown())
+	case <-ctx.Done():
+	}
+
+	err := s.shutdown()
+	return errors.Join(err, <-serveErr)
+}
+
+// shutdown drains traffic, waits for in-flight work and runs the shutdown hooks
+func (s *Server) shutdown() error {
+	log := logger.GetLogger()
+
+	log.Info("Shutting down server...")
+
+	if s.healthChecker != nil {
+		s.healthChecker.SetDraining(true)
+	}
+
+	if s.config.DrainDelay > 0 {
+		log.WithField("delay", s.config.DrainDelay.String()).Info("Waiting for load balancers to stop routing traffic")
+		time.Sleep(s.config.DrainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	// Stops accepting connections and waits for in-flight requests
+	if err := s.app.ShutdownWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down http server: %w", err))
+	}
+
+	s.cancelWorkers()
+
+	workersDone := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(workersDone)
+	}()
+
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("timed out waiting for background workers: %w", ctx.Err()))
+	}
+
+	for _, hook := range s.hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	log.Info("Server stopped")
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"finance-backend/internal/domain"
+	"finance-backend/internal/handler"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/health"
+	"finance-backend/pkg/logger"
+	middleware "finance-backend/pkg/midleware"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// blockingTransactionService holds Create open until released, simulating a slow database transaction
+type blockingTransactionService struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingTransactionService) Create(ctx context.Context, userId string, request *model.CreateTransactionRequest) (*domain.Transaction, error) {
+	close(s.started)
+	<-s.release
+
+	return &domain.Transaction{
+		ID:              uuid.New(),
+		Amount:          request.Amount,
+		Type:            request.Type,
+		TransactionDate: request.TransactionDate,
+		WalletID:        uuid.MustParse(request.WalletID),
+	}, nil
+}
+
+func (s *blockingTransactionService) CreateBatch(ctx context.Context, userId string, requests []*model.CreateTransactionRequest) ([]*domain.Transaction, error) {
+	return nil, nil
+}
+
+func (s *blockingTransactionService) GetList(ctx context.Context, userId string, request *model.TransactionListRequest) ([]*domain.Transaction, error) {
+	return nil, nil
+}
+
+func (s *blockingTransactionService) GetDetail(ctx context.Context, userId string, transactionId string) (*domain.Transaction, error) {
+	return nil, nil
+}
+
+func (s *blockingTransactionService) Update(ctx context.Context, userId string, transactionId string, version int, request *model.UpdateTransactionRequest) (*domain.Transaction, error) {
+	return nil, nil
+}
+
+func TestServeCompletesInFlightTransactionDuringShutdown(t *testing.T) {
+	logger.InitLogger(config.LogConfig{Level: "ERROR", Format: "text"})
+
+	service := &blockingTransactionService{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler:          middleware.ErrorHandler,
+		DisableStartupMessage: true,
+	})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userId", uuid.NewString())
+		return c.Next()
+	})
+	app.Post("/v1/transaction", handler.NewTransactionHandler(service).Create)
+
+	healthChecker := health.NewChecker(nil, "")
+	srv := New(app, healthChecker, Config{ShutdownTimeout: 5 * time.Second})
+
+	workerStopped := make(chan struct{})
+	srv.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerStopped)
+	})
+
+	hookRan := make(chan struct{})
+	srv.OnShutdown(func(context.Context) error {
+		close(hookRan)
+		return nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	url := "http://" + ln.Addr().String() + "/v1/transaction"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ctx, ln)
+	}()
+
+	type result struct {
+		status int
+		err    error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		body := `{"amount":150000,"type":"expense","transaction_date":1756450000,"wallet_id":"` + uuid.NewString() + `"}`
+		resp, err := http.Post(url, fiber.MIMEApplicationJSON, strings.NewReader(body))
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		resp.Body.Close()
+		responses <- result{status: resp.StatusCode}
+	}()
+
+	select {
+	case <-service.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the transaction service")
+	}
+
+	// Simulate SIGTERM while the request is still being processed
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !healthChecker.Draining() {
+		if time.Now().After(deadline) {
+			t.Fatal("readiness was not flipped to draining")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err := <-serveErr:
+		t.Fatalf("server stopped before the in-flight request finished: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(service.release)
+
+	select {
+	case res := <-responses:
+		if res.err != nil {
+			t.Fatalf("in-flight request failed: %v", res.err)
+		}
+		if res.status != fiber.StatusCreated {
+			t.Fatalf("expected status %d, got %d", fiber.StatusCreated, res.status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight request did not complete")
+	}
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			t.Fatalf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+
+	select {
+	case <-workerStopped:
+	default:
+		t.Fatal("background worker was not stopped")
+	}
+
+	select {
+	case <-hookRan:
+	default:
+		t.Fatal("shutdown hook did not run")
+	}
+
+	if _, err := http.Post(url, fiber.MIMEApplicationJSON, strings.NewReader(`{}`)); err == nil {
+		t.Fatal("expected new connections to be refused after shutdown")
+	}
+}
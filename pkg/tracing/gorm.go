@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin turns every gorm statement into a child span of the request context
+type GormPlugin struct{}
+
+// NewGormPlugin returns the gorm tracing plugin
+func NewGormPlugin() gorm.Plugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+
+	// "*" places the hooks at the very start and end of each callback chain
+	registrations := []error{
+		callback.Create().Before("*").Register("tracing:before_create", before("create")),
+		callback.Create().After("*").Register("tracing:after_create", after),
+		callback.Query().Before("*").Register("tracing:before_query", before("query")),
+		callback.Query().After("*").Register("tracing:after_query", after),
+		callback.Update().Before("*").Register("tracing:before_update", before("update")),
+		callback.Update().After("*").Register("tracing:after_update", after),
+		callback.Delete().Before("*").Register("tracing:before_delete", before("delete")),
+		callback.Delete().After("*").Register("tracing:after_delete", after),
+		callback.Row().Before("*").Register("tracing:before_row", before("row")),
+		callback.Row().After("*").Register("tracing:after_row", after),
+		callback.Raw().Before("*").Register("tracing:before_raw", before("raw")),
+		callback.Raw().After("*").Register("tracing:after_raw", after),
+	}
+
+	return errors.Join(registrations...)
+}
+
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			return
+		}
+
+		_, span := otel.Tracer(instrumentationName).Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	// Only the statement text is recorded, bound values may hold personal data
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		semconv.DBCollectionName(db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type tracedWallet struct {
+	ID   int
+	Name string
+}
+
+// dryRun opens a gorm session that builds statements without a database, with the plugin installed
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := db.Use(NewGormPlugin()); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+
+	return db
+}
+
+func TestGormPlugin(t *testing.T) {
+	errDB := errors.New("connection reset")
+
+	tests := []struct {
+		name      string
+		fail      error
+		run       func(db *gorm.DB) *gorm.DB
+		wantName  string
+		wantSQL   string
+		wantTable string
+		wantCode  codes.Code
+	}{
+		{
+			name:      "query",
+			run:       func(db *gorm.DB) *gorm.DB { return db.Where("name = ?", "BCA").Find(&[]tracedWallet{}) },
+			wantName:  "gorm.query",
+			wantSQL:   `SELECT * FROM "traced_wallets" WHERE name = $1`,
+			wantTable: "traced_wallets",
+			wantCode:  codes.Unset,
+		},
+		{
+			name:      "create",
+			run:       func(db *gorm.DB) *gorm.DB { return db.Create(&tracedWallet{ID: 1, Name: "BCA"}) },
+			wantName:  "gorm.create",
+			wantSQL:   `INSERT INTO "traced_wallets"`,
+			wantTable: "traced_wallets",
+			wantCode:  codes.Unset,
+		},
+		{
+			name:      "failed statement records the error",
+			fail:      errDB,
+			run:       func(db *gorm.DB) *gorm.DB { return db.Find(&[]tracedWallet{}) },
+			wantName:  "gorm.query",
+			wantSQL:   `SELECT * FROM "traced_wallets"`,
+			wantTable: "traced_wallets",
+			wantCode:  codes.Error,
+		},
+		{
+			name:      "missing record is not an error",
+			fail:      gorm.ErrRecordNotFound,
+			run:       func(db *gorm.DB) *gorm.DB { return db.Find(&[]tracedWallet{}) },
+			wantName:  "gorm.query",
+			wantSQL:   `SELECT * FROM "traced_wallets"`,
+			wantTable: "traced_wallets",
+			wantCode:  codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record(t)
+			db := dryRun(t)
+			if tt.fail != nil {
+				err := db.Callback().Query().After("gorm:query").Register("test:fail", func(db *gorm.DB) {
+					db.AddError(tt.fail)
+				})
+				if err != nil {
+					t.Fatalf("Register() error = %v", err)
+				}
+			}
+
+			ctx, parent := Start(context.Background(), "parent")
+			tt.run(db.WithContext(ctx))
+			parent.End()
+
+			spans := recorder.Ended()
+			if len(spans) != 2 {
+				t.Fatalf("ended %d spans, want the statement and its parent", len(spans))
+			}
+			span := spans[0]
+
+			if span.Name() != tt.wantName {
+				t.Errorf("name = %q, want %q", span.Name(), tt.wantName)
+			}
+			if span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("kind = %v, want client", span.SpanKind())
+			}
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("parent = %s, want the span of the context", span.Parent().SpanID())
+			}
+
+			attrs := attributes(span)
+			if got := attrs["db.query.text"].AsString(); !strings.HasPrefix(got, tt.wantSQL) {
+				t.Errorf("db.query.text = %q, want it to start with %q", got, tt.wantSQL)
+			}
+			if got := attrs["db.collection.name"].AsString(); got != tt.wantTable {
+				t.Errorf("db.collection.name = %q, want %q", got, tt.wantTable)
+			}
+			if attrs["db.system"] != attribute.StringValue("postgresql") {
+				t.Errorf("db.system = %v, want postgresql", attrs["db.system"].Emit())
+			}
+
+			if span.Status().Code != tt.wantCode {
+				t.Errorf("status code = %v, want %v", span.Status().Code, tt.wantCode)
+			}
+			if tt.wantCode == codes.Error {
+				if len(span.Events()) != 1 || span.Status().Description != tt.fail.Error() {
+					t.Errorf("events = %v, status = %q, want the error recorded", span.Events(), span.Status().Description)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/requestctx"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span per request, continuing any incoming W3C traceparent
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, "HTTP "+c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+				attribute.String("request_id", requestctx.RequestID(c.UserContext())),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// Errors are rendered by the app error handler after the chain returns
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = apperror.From(err).Status
+		}
+
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if userID := requestctx.UserID(c.UserContext()); userID != "" {
+			span.SetAttributes(attribute.String("user_id", userID))
+		}
+
+		if status >= fiber.StatusInternalServerError {
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.SetStatus(codes.Error, "")
+		}
+
+		return err
+	}
+}
+
+// headerCarrier adapts fiber request headers to the propagation.TextMapCarrier interface
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"finance-backend/pkg/apperror"
+	"finance-backend/pkg/config"
+	"finance-backend/pkg/logger"
+	middleware "finance-backend/pkg/midleware"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a tracer provider that keeps every finished span, restoring the globals afterwards
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return recorder
+}
+
+// attributes flattens the attributes of span for lookups by key
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestMiddleware(t *testing.T) {
+	logger.InitLogger(config.LogConfig{Level: "ERROR", Format: "text"})
+
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name       string
+		handler    fiber.Handler
+		wantStatus int
+		wantCode   codes.Code
+		wantEvent  bool
+	}{
+		{
+			name:       "success",
+			handler:    func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+			wantStatus: fiber.StatusOK,
+			wantCode:   codes.Unset,
+		},
+		{
+			name:       "client error leaves the span ok",
+			handler:    func(c *fiber.Ctx) error { return apperror.ErrNotFound },
+			wantStatus: fiber.StatusNotFound,
+			wantCode:   codes.Unset,
+		},
+		{
+			name:       "server error is recorded",
+			handler:    func(c *fiber.Ctx) error { return apperror.ErrInternal },
+			wantStatus: fiber.StatusInternalServerError,
+			wantCode:   codes.Error,
+			wantEvent:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record(t)
+
+			var handlerSpan trace.SpanContext
+			app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+			app.Use(middleware.RequestIDMiddleware())
+			app.Use(Middleware())
+			app.Get("/v1/wallet/:walletId", func(c *fiber.Ctx) error {
+				handlerSpan = trace.SpanContextFromContext(c.UserContext())
+				return tt.handler(c)
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/v1/wallet/abc", nil)
+			req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+			req.Header.Set(middleware.RequestIDHeader, "req-123")
+			req.Header.Set(fiber.HeaderUserAgent, "finance-test")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+
+			if span.Name() != "GET /v1/wallet/:walletId" {
+				t.Errorf("name = %q, want the route template", span.Name())
+			}
+			if span.SpanKind() != trace.SpanKindServer {
+				t.Errorf("kind = %v, want server", span.SpanKind())
+			}
+			if got := span.Parent().SpanID().String(); got != parentID || span.SpanContext().TraceID().String() != traceID {
+				t.Errorf("span continues %s/%s, want %s/%s", span.SpanContext().TraceID(), got, traceID, parentID)
+			}
+			if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+				t.Errorf("handler context carries span %s, want %s", handlerSpan.SpanID(), span.SpanContext().SpanID())
+			}
+
+			attrs := attributes(span)
+			want := map[attribute.Key]attribute.Value{
+				"http.request.method":       attribute.StringValue(fiber.MethodGet),
+				"url.path":                  attribute.StringValue("/v1/wallet/abc"),
+				"http.route":                attribute.StringValue("/v1/wallet/:walletId"),
+				"http.response.status_code": attribute.IntValue(tt.wantStatus),
+				"user_agent.original":       attribute.StringValue("finance-test"),
+				"request_id":                attribute.StringValue("req-123"),
+			}
+			for key, value := range want {
+				if attrs[key] != value {
+					t.Errorf("attribute %s = %v, want %v", key, attrs[key].Emit(), value.Emit())
+				}
+			}
+
+			if span.Status().Code != tt.wantCode {
+				t.Errorf("status code = %v, want %v", span.Status().Code, tt.wantCode)
+			}
+			if got := len(span.Events()) > 0; got != tt.wantEvent {
+				t.Errorf("recorded error = %v, want %v", got, tt.wantEvent)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"finance-backend/pkg/config"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	instrumentationName = "finance-backend"
+)
+
+// Init installs the global tracer provider and W3C propagator.
+// The returned function flushes and stops the exporter.
+func Init(ctx context.Context, config config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, closer, err := newExporter(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// Start starts a span named name as a child of the span in ctx
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name)
+}
+
+// newExporter builds the configured span exporter, nil means tracing is disabled
+func newExporter(ctx context.Context, config config.TracingConfig) (sdktrace.SpanExporter, io.Closer, error) {
+	switch config.Exporter {
+	case ExporterNone, "":
+		return nil, nil, nil
+	case ExporterOTLP:
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(config.OTLPEndpoint))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterFile:
+		file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		return exporter, file, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown tracing exporter %q", config.Exporter)
+}
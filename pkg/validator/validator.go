@@ -0,0 +1,180 @@
+package validator
+
+import (
+	"errors"
+	"finance-backend/internal/constant"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var (
+	validate *validator.Validate
+
+	currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+func init() {
+	validate = validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name so errors match the request payload
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	_ = validate.RegisterValidation("currency", validateCurrency)
+	_ = validate.RegisterValidation("transaction_type", validateTransactionType)
+	_ = validate.RegisterValidation("uuid", validateUUID)
+}
+
+// Struct validates a struct using its `validate` tags
+func Struct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fieldErrors := make([]model.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fieldErrors = append(fieldErrors, model.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+
+	return apperror.NewValidation(fieldErrors)
+}
+
+// ParseBody parses the request body into out and validates it
+func ParseBody(c *fiber.Ctx, out interface{}) error {
+	if err := c.BodyParser(out); err != nil {
+		return apperror.ErrBadRequest.WithMessage("invalid request body").Wrap(err)
+	}
+
+	return Struct(out)
+}
+
+// ParseQuery parses the query string into out and validates it
+func ParseQuery(c *fiber.Ctx, out interface{}) error {
+	if err := c.QueryParser(out); err != nil {
+		return apperror.ErrBadRequest.WithMessage("invalid query parameters").Wrap(err)
+	}
+
+	return Struct(out)
+}
+
+// IsCurrency reports whether code is an uppercase ISO 4217 style currency code
+func IsCurrency(code string) bool {
+	return currencyRegex.MatchString(code)
+}
+
+// validateCurrency checks for an uppercase ISO 4217 style currency code
+func validateCurrency(fl validator.FieldLevel) bool {
+	return IsCurrency(fl.Field().String())
+}
+
+// validateTransactionType checks for a supported transaction type
+func validateTransactionType(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case constant.TransactionTypeIncome, constant.TransactionTypeExpense:
+		return true
+	}
+	return false
+}
+
+// validateUUID checks that the field parses as a UUID, empty values are left to `required`
+func validateUUID(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+// message builds a human readable message for a single field error
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters long", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", fe.Param())
+	case "currency":
+		return "must be a 3-letter uppercase currency code"
+	case "transaction_type":
+		return fmt.Sprintf("must be one of [%s %s]", constant.TransactionTypeIncome, constant.TransactionTypeExpense)
+	case "uuid":
+		return "must be a valid UUID"
+	case "timezone":
+		return "must be an IANA time zone such as Asia/Jakarta"
+	case "bcp47_language_tag":
+		return "must be a language tag such as de-DE"
+	case "gtfield":
+		return fmt.Sprintf("must be greater than %s", strings.ToLower(fe.Param()))
+	case "json":
+		return "must be valid JSON"
+	case "required_with":
+		return fmt.Sprintf("is required when %s is set", fieldNames(fe.Param()))
+	case "required_without":
+		return fmt.Sprintf("is required unless %s is set", fieldNames(fe.Param()))
+	case "required_without_all":
+		return fmt.Sprintf("is required unless %s are set", fieldNames(fe.Param()))
+	case "excluded_with":
+		return fmt.Sprintf("must be left out when %s is set", fieldNames(fe.Param()))
+	}
+	return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+}
+
+// fieldNames turns a rule parameter such as "DebitColumn CreditColumn" into "debit_column and credit_column"
+func fieldNames(param string) string {
+	fields := strings.Fields(param)
+	for i, field := range fields {
+		var name strings.Builder
+		for j, r := range field {
+			if unicode.IsUpper(r) {
+				if j > 0 {
+					name.WriteByte('_')
+				}
+				r = unicode.ToLower(r)
+			}
+			name.WriteRune(r)
+		}
+		fields[i] = name.String()
+	}
+	return strings.Join(fields, " and ")
+}
@@ -0,0 +1,215 @@
+package validator
+
+import (
+	"errors"
+	"finance-backend/internal/model"
+	"finance-backend/pkg/apperror"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type walletRequest struct {
+	Name     string  `json:"name" validate:"required,min=3"`
+	Currency string  `json:"currency" validate:"omitempty,currency"`
+	Limit    float64 `json:"limit" validate:"omitempty,min=10"`
+}
+
+type entryRequest struct {
+	Type     string `json:"type" query:"type" validate:"required,transaction_type"`
+	WalletID string `json:"wallet_id" query:"wallet_id" validate:"uuid"`
+	Page     int    `json:"page" query:"page" validate:"omitempty,min=1"`
+}
+
+type columnsRequest struct {
+	AmountColumn string `json:"amount_column" validate:"required_without=DebitColumn"`
+	DebitColumn  string `json:"debit_column" validate:"excluded_with=AmountColumn,required_with=CreditColumn"`
+	CreditColumn string `json:"credit_column"`
+	Internal     string `json:"-" validate:"required"`
+}
+
+// fields returns the field errors carried by a validation error
+func fields(t *testing.T, err error) []model.FieldError {
+	t.Helper()
+
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Code != apperror.CodeValidationFailed {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	return appErr.Fields
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+		want  []model.FieldError
+	}{
+		{
+			name:  "valid",
+			input: &walletRequest{Name: "BCA", Currency: "IDR", Limit: 10},
+		},
+		{
+			name:  "fields are named by their json tag",
+			input: &walletRequest{Currency: "idr"},
+			want: []model.FieldError{
+				{Field: "name", Rule: "required", Message: "is required"},
+				{Field: "currency", Rule: "currency", Message: "must be a 3-letter uppercase currency code"},
+			},
+		},
+		{
+			name:  "min reads as a length for strings and a value for numbers",
+			input: &walletRequest{Name: "BC", Limit: 5},
+			want: []model.FieldError{
+				{Field: "name", Rule: "min", Message: "must be at least 3 characters long"},
+				{Field: "limit", Rule: "min", Message: "must be at least 10"},
+			},
+		},
+		{
+			name:  "transaction type and uuid",
+			input: &entryRequest{Type: "transfer", WalletID: "not-a-uuid"},
+			want: []model.FieldError{
+				{Field: "type", Rule: "transaction_type", Message: "must be one of [income expense]"},
+				{Field: "wallet_id", Rule: "uuid", Message: "must be a valid UUID"},
+			},
+		},
+		{
+			name:  "empty uuid is left to required",
+			input: &entryRequest{Type: "expense"},
+		},
+		{
+			name:  "cross field rules name the other fields by their json name",
+			input: &columnsRequest{AmountColumn: "Amount", DebitColumn: "Debit", CreditColumn: "Credit", Internal: "x"},
+			want: []model.FieldError{
+				{Field: "debit_column", Rule: "excluded_with", Message: "must be left out when amount_column is set"},
+			},
+		},
+		{
+			name:  "required with and without",
+			input: &columnsRequest{CreditColumn: "Credit", Internal: "x"},
+			want: []model.FieldError{
+				{Field: "amount_column", Rule: "required_without", Message: "is required unless debit_column is set"},
+				{Field: "debit_column", Rule: "required_with", Message: "is required when credit_column is set"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.input)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Struct() error = %v, want nil", err)
+				}
+				return
+			}
+
+			if got := fields(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Struct() fields = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCurrency(t *testing.T) {
+	for code, want := range map[string]bool{"IDR": true, "USD": true, "idr": false, "US": false, "USDT": false, "": false} {
+		if got := IsCurrency(code); got != want {
+			t.Errorf("IsCurrency(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	var parseErr error
+	app := fiber.New()
+	app.Post("/body", func(c *fiber.Ctx) error {
+		var request entryRequest
+		parseErr = ParseBody(c, &request)
+		return nil
+	})
+	app.Get("/query", func(c *fiber.Ctx) error {
+		var request entryRequest
+		parseErr = ParseQuery(c, &request)
+		return nil
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:   "valid body",
+			method: fiber.MethodPost,
+			target: "/body",
+			body:   `{"type":"income"}`,
+		},
+		{
+			name:        "malformed body",
+			method:      fiber.MethodPost,
+			target:      "/body",
+			body:        `{"type":`,
+			wantStatus:  fiber.StatusBadRequest,
+			wantMessage: "invalid request body",
+		},
+		{
+			name:        "body that fails its rules",
+			method:      fiber.MethodPost,
+			target:      "/body",
+			body:        `{"type":"transfer"}`,
+			wantStatus:  fiber.StatusUnprocessableEntity,
+			wantMessage: "validation failed",
+		},
+		{
+			name:   "valid query",
+			method: fiber.MethodGet,
+			target: "/query?type=expense&page=2",
+		},
+		{
+			name:        "query that does not parse",
+			method:      fiber.MethodGet,
+			target:      "/query?type=expense&page=two",
+			wantStatus:  fiber.StatusBadRequest,
+			wantMessage: "invalid query parameters",
+		},
+		{
+			name:        "query that fails its rules",
+			method:      fiber.MethodGet,
+			target:      "/query?type=transfer&page=1",
+			wantStatus:  fiber.StatusUnprocessableEntity,
+			wantMessage: "validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parseErr = nil
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			if _, err := app.Test(req); err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+
+			if tt.wantStatus == 0 {
+				if parseErr != nil {
+					t.Fatalf("parse error = %v, want nil", parseErr)
+				}
+				return
+			}
+
+			var appErr *apperror.Error
+			if !errors.As(parseErr, &appErr) {
+				t.Fatalf("parse error = %v, want an app error", parseErr)
+			}
+			if appErr.Status != tt.wantStatus || appErr.Message != tt.wantMessage {
+				t.Errorf("parse error = %d %q, want %d %q", appErr.Status, appErr.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}